@@ -24,6 +24,7 @@ import (
 	"sync"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 )
 
 const (
@@ -35,14 +36,24 @@ const (
 )
 
 var (
-	errOutOfBounds = errors.New("out of bounds")
-	errOutOfOrder  = errors.New("out of order")
+	errOutOfBounds   = errors.New("out of bounds")
+	errOutOfOrder    = errors.New("out of order")
+	errUnknownTable  = errors.New("unknown table")
+	errTablesUnequal = errors.New("tables of unequal length")
 )
 
+// AncientItem is an item written to the store by ModifyAncients.
+type AncientItem struct {
+	Kind   string        `json:"kind"`
+	Number uint64        `json:"number"`
+	Data   hexutil.Bytes `json:"data"`
+}
+
 // MemFreezerRemoteServerAPI is a mock freezer server implementation.
 type MemFreezerRemoteServerAPI struct {
 	store map[string][]byte
 	count uint64
+	tail  uint64
 	mu    sync.Mutex
 }
 
@@ -57,44 +68,62 @@ func (r *MemFreezerRemoteServerAPI) storeKey(kind string, number uint64) string
 }
 
 func (f *MemFreezerRemoteServerAPI) Reset() {
-	f.count = 0
 	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.count = 0
+	f.tail = 0
 	f.store = make(map[string][]byte)
-	f.mu.Unlock()
 }
 
 func (f *MemFreezerRemoteServerAPI) HasAncient(kind string, number uint64) (bool, error) {
-	// fmt.Println("mock server called", "method=HasAncient")
 	f.mu.Lock()
 	defer f.mu.Unlock()
 	_, ok := f.store[f.storeKey(kind, number)]
 	return ok, nil
 }
 
-func (f *MemFreezerRemoteServerAPI) Ancient(kind string, number uint64) ([]byte, error) {
-	// fmt.Println("mock server called", "method=Ancient")
+func (f *MemFreezerRemoteServerAPI) Ancient(kind string, number uint64) (hexutil.Bytes, error) {
 	f.mu.Lock()
 	defer f.mu.Unlock()
 	v, ok := f.store[f.storeKey(kind, number)]
 	if !ok {
 		return nil, errOutOfBounds
 	}
-
 	return v, nil
 }
 
 func (f *MemFreezerRemoteServerAPI) Ancients() (uint64, error) {
-	// fmt.Println("mock server called", "method=Ancients")
+	f.mu.Lock()
+	defer f.mu.Unlock()
 	return f.count, nil
 }
 
-func (f *MemFreezerRemoteServerAPI) AncientRange(kind string, start, count, maxBytes uint64) ([][]byte, error) {
-	res := make([][]byte, 0)
-	// fmt.Println("mock server called", "method=Ancients")
+func (f *MemFreezerRemoteServerAPI) Tail() (uint64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.tail, nil
+}
+
+// AncientRange returns at most count items starting at start. If maxBytes is not
+// zero, it returns at least one item, and otherwise as many as fit in maxBytes.
+func (f *MemFreezerRemoteServerAPI) AncientRange(kind string, start, count, maxBytes uint64) ([]hexutil.Bytes, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var (
+		res  = make([]hexutil.Bytes, 0)
+		size uint64
+	)
 	for i := uint64(0); i < count; i++ {
-		item := f.store[f.storeKey(kind, start+i)]
-		if len(item) > int(maxBytes) {
-			item = item[:int(maxBytes)]
+		item, ok := f.store[f.storeKey(kind, start+i)]
+		if !ok {
+			if i == 0 {
+				return nil, errOutOfBounds
+			}
+			break
+		}
+		if size += uint64(len(item)); i > 0 && maxBytes != 0 && size > maxBytes {
+			break
 		}
 		res = append(res, item)
 	}
@@ -102,10 +131,11 @@ func (f *MemFreezerRemoteServerAPI) AncientRange(kind string, start, count, maxB
 }
 
 func (f *MemFreezerRemoteServerAPI) AncientSize(kind string) (uint64, error) {
-	// fmt.Println("mock server called", "method=AncientSize")
+	f.mu.Lock()
+	defer f.mu.Unlock()
 	sum := uint64(0)
 	for k, v := range f.store {
-		if strings.HasPrefix(k, kind) {
+		if strings.HasPrefix(k, kind+"-") {
 			sum += uint64(len(v))
 		}
 	}
@@ -121,14 +151,13 @@ var fieldNames = []string{
 }
 
 func (f *MemFreezerRemoteServerAPI) AppendAncient(number uint64, hash, header, body, receipt, td []byte) error {
-	// fmt.Println("mock server called", "method=AppendAncient", "number=", number, "header", fmt.Sprintf("%x", header))
+	f.mu.Lock()
+	defer f.mu.Unlock()
 	fields := [][]byte{hash, header, body, receipt, td}
 	if number != f.count {
 		return errOutOfOrder
 	}
 	f.count = number + 1
-	f.mu.Lock()
-	defer f.mu.Unlock()
 	for i, fv := range fields {
 		kind := fieldNames[i]
 		f.store[f.storeKey(kind, number)] = fv
@@ -136,7 +165,43 @@ func (f *MemFreezerRemoteServerAPI) AppendAncient(number uint64, hash, header, b
 	return nil
 }
 
+// ModifyAncients appends the items of a write operation. The items of every
+// table must follow the current head, and all the tables must be of the same
+// length afterwards. Nothing is written if the items are invalid.
+func (f *MemFreezerRemoteServerAPI) ModifyAncients(items []AncientItem) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	next := make(map[string]uint64, len(fieldNames))
+	for _, kind := range fieldNames {
+		next[kind] = f.count
+	}
+	for _, item := range items {
+		head, ok := next[item.Kind]
+		if !ok {
+			return fmt.Errorf("%w: %s", errUnknownTable, item.Kind)
+		}
+		if item.Number != head {
+			return fmt.Errorf("%w: table=%s, num=%d, head=%d", errOutOfOrder, item.Kind, item.Number, head)
+		}
+		next[item.Kind] = head + 1
+	}
+	count := next[fieldNames[0]]
+	for _, kind := range fieldNames[1:] {
+		if next[kind] != count {
+			return fmt.Errorf("%w: table %s at item %d, want %d", errTablesUnequal, kind, next[kind], count)
+		}
+	}
+	for _, item := range items {
+		f.store[f.storeKey(item.Kind, item.Number)] = item.Data
+	}
+	f.count = count
+	return nil
+}
+
 func (f *MemFreezerRemoteServerAPI) Append(kind string, num uint64, item interface{}) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
 	if f.count != num {
 		return fmt.Errorf("%w: num=%d, count=%d", errOutOfOrder, num, f.count)
 	}
@@ -144,73 +209,70 @@ func (f *MemFreezerRemoteServerAPI) Append(kind string, num uint64, item interfa
 	// This is a really crufty thing.
 	// We need to increment the freezer counter when all of a block's data have been written.
 	// This is harder to do than AppendAncient because we're only handling one field at a time.
-	// So we look at the ModifyAncients method and use the implementation for the
-	// current AncientWriteOperator closure. In accessors_chain.go, this
-	// writes one block at time (via independent fields), and the last independent
-	// field called is 'diffs'. (See methods: WriteAncientBlocks and writeAncientBlock).
 	// As long as we assume that 'diffs' are the last-called field when writing
 	// a block, we can use it as the trigger for the count incrementing.
+	// ModifyAncients handles whole write operations instead.
 	if kind == freezerRemoteDifficultyTable {
 		f.count = num + 1
 	}
 
 	str := item.(string)
-
-	f.mu.Lock()
 	f.store[f.storeKey(kind, num)] = common.Hex2Bytes(str)
-	f.mu.Unlock()
-
 	return nil
 }
 
-func (f *MemFreezerRemoteServerAPI) AppendRaw(kind string, num uint64, item []byte) error {
+func (f *MemFreezerRemoteServerAPI) AppendRaw(kind string, num uint64, item hexutil.Bytes) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
 	if f.count != num {
 		return fmt.Errorf("%w: num=%d, count=%d", errOutOfOrder, num, f.count)
 	}
-
-	f.mu.Lock()
 	f.store[f.storeKey(kind, num)] = item
-	f.mu.Unlock()
-
 	return nil
 }
 
-func (f *MemFreezerRemoteServerAPI) TruncateTail(n uint64) error {
-	// fmt.Println("mock server called", "method=TruncateAncients")
-	if f.count <= n {
-		return nil
-	}
-	f.count = n
+// TruncateTail discards the items below n, returning the previous tail.
+func (f *MemFreezerRemoteServerAPI) TruncateTail(n uint64) (uint64, error) {
 	f.mu.Lock()
 	defer f.mu.Unlock()
-	for k := range f.store {
-		spl := strings.Split(k, "-")
-		num, err := strconv.ParseUint(spl[1], 10, 64)
-		if err != nil {
-			return err
-		}
-		if num <= n {
-			delete(f.store, k)
-		}
+	old := f.tail
+	if old >= n {
+		return old, nil
 	}
-	return nil
+	if err := f.deleteItems(func(num uint64) bool { return num < n }); err != nil {
+		return 0, err
+	}
+	f.tail = n
+	return old, nil
 }
 
-func (f *MemFreezerRemoteServerAPI) TruncateHead(n uint64) error {
-	// fmt.Println("mock server called", "method=TruncateAncients")
-	if f.count <= n {
-		return nil
-	}
-	f.count = n
+// TruncateHead discards the items from n on, returning the previous head.
+func (f *MemFreezerRemoteServerAPI) TruncateHead(n uint64) (uint64, error) {
 	f.mu.Lock()
 	defer f.mu.Unlock()
+	old := f.count
+	if old <= n {
+		return old, nil
+	}
+	if err := f.deleteItems(func(num uint64) bool { return num >= n }); err != nil {
+		return 0, err
+	}
+	f.count = n
+	if f.tail > n {
+		f.tail = n
+	}
+	return old, nil
+}
+
+// deleteItems deletes the items of all the tables with the numbers matching the
+// given filter.
+func (f *MemFreezerRemoteServerAPI) deleteItems(match func(num uint64) bool) error {
 	for k := range f.store {
-		spl := strings.Split(k, "-")
-		num, err := strconv.ParseUint(spl[1], 10, 64)
+		num, err := strconv.ParseUint(k[strings.LastIndex(k, "-")+1:], 10, 64)
 		if err != nil {
 			return err
 		}
-		if num >= n {
+		if match(num) {
 			delete(f.store, k)
 		}
 	}
@@ -218,11 +280,9 @@ func (f *MemFreezerRemoteServerAPI) TruncateHead(n uint64) error {
 }
 
 func (f *MemFreezerRemoteServerAPI) Sync() error {
-	// fmt.Println("mock server called", "method=Sync")
 	return nil
 }
 
 func (f *MemFreezerRemoteServerAPI) Close() error {
-	// fmt.Println("mock server called", "method=Close")
 	return nil
 }
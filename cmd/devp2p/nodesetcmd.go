@@ -243,6 +243,9 @@ func ethFilter(args []string) (nodeFilter, error) {
 	case "mintme":
 		gb := core.GenesisToBlock(params.DefaultMintMeGenesisBlock(), nil)
 		filter = forkid.NewStaticFilter(params.MintMeChainConfig, gb)
+	case "halo":
+		gb := core.GenesisToBlock(params.DefaultHaloGenesisBlock(), nil)
+		filter = forkid.NewStaticFilter(params.HaloChainConfig, gb)
 	default:
 		return nil, fmt.Errorf("unknown network %q", args[0])
 	}
@@ -47,6 +47,8 @@ var defaultChainspecValues = map[string]ctypes.Configurator{
 	"sepolia":    params.DefaultSepoliaGenesisBlock(),
 
 	"mintme": params.DefaultMintMeGenesisBlock(),
+
+	"halo": params.DefaultHaloGenesisBlock(),
 }
 
 var defaultChainspecNames = func() []string {
@@ -216,6 +218,7 @@ GLOBAL OPTIONS:
 		validateCommand,
 		forksCommand,
 		ipsCommand,
+		simulateDifficultyCommand,
 	}
 	app.Before = mustGetChainspecValue
 	app.Action = convertf
@@ -0,0 +1,453 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"math/big"
+	"math/rand"
+	"os"
+	"sort"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/consensus/lyra2"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params/types/ctypes"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var (
+	simBlocksFlag = cli.Uint64Flag{
+		Name:  "blocks",
+		Usage: "Number of blocks to simulate",
+		Value: 10000,
+	}
+	simStartFlag = cli.Uint64Flag{
+		Name:  "start",
+		Usage: "Number of the parent block the simulation starts from, only if the difficulty algorithm doesn't read older headers",
+	}
+	simDifficultyFlag = cli.StringFlag{
+		Name:  "difficulty",
+		Usage: "Difficulty of the starting block (default: genesis difficulty)",
+	}
+	simHashrateFlag = cli.Float64Flag{
+		Name:  "hashrate",
+		Usage: "Constant network hashrate in hashes per second, if no profile is given",
+		Value: 1e6,
+	}
+	simProfileFlag = cli.StringFlag{
+		Name:  "profile",
+		Usage: "Path to a JSON hashrate profile: [{\"at\": <seconds>, \"hashrate\": <H/s>, \"duration\": <seconds, optional>}, ...]",
+	}
+	simPropagationFlag = cli.Float64Flag{
+		Name:  "propagation",
+		Usage: "Block propagation delay in seconds, used to estimate the orphan risk",
+		Value: 1,
+	}
+	simSeedFlag = cli.Int64Flag{
+		Name:  "seed",
+		Usage: "Seed of the block time sampler",
+		Value: 1,
+	}
+	simFormatFlag = cli.StringFlag{
+		Name:  "format",
+		Usage: "Output format [csv|json]",
+		Value: "csv",
+	}
+)
+
+var simulateDifficultyCommand = cli.Command{
+	Name:  "simulate-difficulty",
+	Usage: "Simulate the difficulty adjustment over a synthetic chain",
+	Description: `Runs the difficulty algorithm of the configured consensus engine over a synthetic
+header chain, mined by a network whose hashrate follows the given profile.
+
+The profile is a list of events applied in order of time. An event sets the base
+hashrate from the given second onwards (e.g. a miner exit), or adds to it for
+a limited duration if one is set (e.g. an attacker spike).
+
+Timestamps start at the genesis timestamp. Algorithms capping future timestamps to
+the local clock see the simulated chain as historical as long as it ends in the past.
+
+The simulated chain has no history before its starting block. Starting past genesis
+is rejected if the difficulty algorithm of a simulated block reads older headers.
+
+Per block statistics are written to standard output, a summary to standard error.`,
+	Flags: []cli.Flag{
+		simBlocksFlag,
+		simStartFlag,
+		simDifficultyFlag,
+		simHashrateFlag,
+		simProfileFlag,
+		simPropagationFlag,
+		simSeedFlag,
+		simFormatFlag,
+	},
+	Action: simulateDifficulty,
+}
+
+var (
+	errInvalidSimFormat = errors.New("invalid simulation output format")
+	errSimNoHistory     = errors.New("the difficulty algorithm reads the headers before the starting block, which can't be simulated")
+)
+
+// simMaxSteps is the number of seconds the miners retarget the timestamp of a
+// block before the remaining block time is sampled at a fixed difficulty.
+const simMaxSteps = 600
+
+// hashrateEvent changes the hashrate of the simulated network.
+type hashrateEvent struct {
+	At       uint64  `json:"at"`                 // Seconds since the start of the simulation
+	Hashrate float64 `json:"hashrate"`           // Hashes per second
+	Duration uint64  `json:"duration,omitempty"` // If set, the hashrate is only added for this many seconds
+}
+
+// hashrateProfile is the hashrate of the simulated network over time.
+type hashrateProfile []hashrateEvent
+
+// at returns the network hashrate at the given second of the simulation.
+func (p hashrateProfile) at(t uint64) float64 {
+	var base, extra float64
+	for _, e := range p {
+		if e.At > t {
+			break
+		}
+		if e.Duration == 0 {
+			base = e.Hashrate
+		} else if t < e.At+e.Duration {
+			extra += e.Hashrate
+		}
+	}
+	return base + extra
+}
+
+// next returns the time of the first event after the given second of the
+// simulation, including the end of temporary ones.
+func (p hashrateProfile) next(t uint64) (uint64, bool) {
+	var (
+		next  uint64
+		found bool
+	)
+	for _, e := range p {
+		for _, at := range []uint64{e.At, e.At + e.Duration} {
+			if at > t && (!found || at < next) {
+				next, found = at, true
+			}
+		}
+	}
+	return next, found
+}
+
+// simBlock is the simulated outcome of a block.
+type simBlock struct {
+	Number     uint64  `json:"number"`
+	Timestamp  uint64  `json:"timestamp"`
+	BlockTime  uint64  `json:"blockTime"`
+	Difficulty string  `json:"difficulty"`
+	Hashrate   float64 `json:"hashrate"`
+	OrphanRisk float64 `json:"orphanRisk"`
+}
+
+// simSummary aggregates the statistics of a simulation.
+type simSummary struct {
+	Blocks          int     `json:"blocks"`
+	Duration        uint64  `json:"duration"`
+	MeanBlockTime   float64 `json:"meanBlockTime"`
+	StdDevBlockTime float64 `json:"stdDevBlockTime"`
+	MedianBlockTime uint64  `json:"medianBlockTime"`
+	P95BlockTime    uint64  `json:"p95BlockTime"`
+	MaxBlockTime    uint64  `json:"maxBlockTime"`
+	MinDifficulty   string  `json:"minDifficulty"`
+	MaxDifficulty   string  `json:"maxDifficulty"`
+	FinalDifficulty string  `json:"finalDifficulty"`
+	MeanOrphanRisk  float64 `json:"meanOrphanRisk"`
+	MaxOrphanRisk   float64 `json:"maxOrphanRisk"`
+	ExpectedOrphans float64 `json:"expectedOrphans"`
+}
+
+// simChain is an in-memory header chain, serving the history lookups of the
+// difficulty algorithms.
+type simChain struct {
+	config  ctypes.ChainConfigurator
+	headers []*types.Header // Headers by number, offset by the first one
+	hashes  map[common.Hash]*types.Header
+}
+
+func newSimChain(config ctypes.ChainConfigurator, first *types.Header) *simChain {
+	return &simChain{
+		config:  config,
+		headers: []*types.Header{first},
+		hashes:  map[common.Hash]*types.Header{first.Hash(): first},
+	}
+}
+
+func (c *simChain) append(header *types.Header) {
+	c.headers = append(c.headers, header)
+	c.hashes[header.Hash()] = header
+}
+
+func (c *simChain) Config() ctypes.ChainConfigurator { return c.config }
+
+func (c *simChain) CurrentHeader() *types.Header { return c.headers[len(c.headers)-1] }
+
+func (c *simChain) GetHeader(hash common.Hash, number uint64) *types.Header {
+	if header := c.hashes[hash]; header != nil && header.Number.Uint64() == number {
+		return header
+	}
+	return nil
+}
+
+func (c *simChain) GetHeaderByNumber(number uint64) *types.Header {
+	first := c.headers[0].Number.Uint64()
+	if number < first || number-first >= uint64(len(c.headers)) {
+		return nil
+	}
+	return c.headers[number-first]
+}
+
+func (c *simChain) GetHeaderByHash(hash common.Hash) *types.Header { return c.hashes[hash] }
+
+func (c *simChain) GetTd(hash common.Hash, number uint64) *big.Int { return nil }
+
+// simEngine creates the consensus engine of the configuration, only used to
+// compute difficulties.
+func simEngine(config ctypes.ChainConfigurator) (consensus.Engine, error) {
+	switch config.GetConsensusEngineType() {
+	case ctypes.ConsensusEngineT_Ethash:
+		return ethash.NewFaker(), nil
+	case ctypes.ConsensusEngineT_Lyra2:
+		return lyra2.New(&lyra2.Config{FakeMode: true}, nil, false), nil
+	default:
+		return nil, fmt.Errorf("unsupported consensus engine: %v", config.GetConsensusEngineType())
+	}
+}
+
+func readHashrateProfile(ctx *cli.Context) (hashrateProfile, error) {
+	if !ctx.IsSet(simProfileFlag.Name) {
+		return hashrateProfile{{Hashrate: ctx.Float64(simHashrateFlag.Name)}}, nil
+	}
+	data, err := os.ReadFile(ctx.String(simProfileFlag.Name))
+	if err != nil {
+		return nil, err
+	}
+	var profile hashrateProfile
+	if err := json.Unmarshal(data, &profile); err != nil {
+		return nil, fmt.Errorf("invalid hashrate profile: %v", err)
+	}
+	sort.SliceStable(profile, func(i, j int) bool { return profile[i].At < profile[j].At })
+	return profile, nil
+}
+
+func simulateDifficulty(ctx *cli.Context) error {
+	format := ctx.String(simFormatFlag.Name)
+	if format != "csv" && format != "json" {
+		return errInvalidSimFormat
+	}
+	profile, err := readHashrateProfile(ctx)
+	if err != nil {
+		return err
+	}
+	start, n := ctx.Uint64(simStartFlag.Name), ctx.Uint64(simBlocksFlag.Name)
+	if err := checkSimStart(globalChainspecValue, start, n); err != nil {
+		return err
+	}
+	engine, err := simEngine(globalChainspecValue)
+	if err != nil {
+		return err
+	}
+	defer engine.Close()
+
+	difficulty := globalChainspecValue.GetGenesisDifficulty()
+	if ctx.IsSet(simDifficultyFlag.Name) {
+		var ok bool
+		if difficulty, ok = new(big.Int).SetString(ctx.String(simDifficultyFlag.Name), 0); !ok {
+			return fmt.Errorf("invalid difficulty: %s", ctx.String(simDifficultyFlag.Name))
+		}
+	}
+	if difficulty == nil || difficulty.Sign() <= 0 {
+		return errors.New("starting difficulty must be positive")
+	}
+	blocks := simulate(engine, globalChainspecValue, profile, &types.Header{
+		Number:     new(big.Int).SetUint64(start),
+		Time:       globalChainspecValue.GetGenesisTimestamp(),
+		Difficulty: difficulty,
+		UncleHash:  types.EmptyUncleHash,
+	}, n, ctx.Float64(simPropagationFlag.Name), rand.New(rand.NewSource(ctx.Int64(simSeedFlag.Name))))
+
+	summary := summarize(blocks)
+	if format == "json" {
+		b, err := jsonMarshalPretty(struct {
+			Summary simSummary `json:"summary"`
+			Blocks  []simBlock `json:"blocks"`
+		}{summary, blocks})
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(b))
+		return nil
+	}
+	if err := writeSimCSV(os.Stdout, blocks); err != nil {
+		return err
+	}
+	b, err := jsonMarshalPretty(summary)
+	if err != nil {
+		return err
+	}
+	log.Println(string(b))
+	return nil
+}
+
+// checkSimStart ensures that the difficulty of the n blocks simulated on top of
+// the start block doesn't depend on the headers before it: the Halo difficulty
+// algorithms average the block times of up to the last 150 blocks.
+func checkSimStart(config ctypes.ChainConfigurator, start, n uint64) error {
+	if start == 0 {
+		return nil
+	}
+	for _, fork := range []func() *uint64{config.GetHaloDifficultyTransition, config.GetHaloDifficultyV2Transition} {
+		if block := fork(); block != nil && *block <= start+n {
+			return fmt.Errorf("%w: history read from block %d, start from genesis", errSimNoHistory, *block)
+		}
+	}
+	return nil
+}
+
+// simulate mines n blocks on top of the parent header. Each second the miners
+// retarget the next block to the current timestamp, and find it with the
+// probability given by the network hashrate and the resulting difficulty.
+func simulate(engine consensus.Engine, config ctypes.ChainConfigurator, profile hashrateProfile, parent *types.Header, n uint64, propagation float64, rng *rand.Rand) []simBlock {
+	var (
+		chain  = newSimChain(config, parent)
+		start  = parent.Time
+		blocks = make([]simBlock, 0, n)
+	)
+	for i := uint64(0); i < n; i++ {
+		var (
+			timestamp  = parent.Time
+			difficulty *big.Int
+			hashrate   float64
+		)
+		for step := 0; ; step++ {
+			timestamp++
+			difficulty = engine.CalcDifficulty(chain, timestamp, parent)
+			hashrate = profile.at(timestamp - start)
+
+			// Past the retargeting window the difficulty is assumed stable,
+			// sample the rest of the block time at once
+			rate := hashrate / toFloat(difficulty)
+			if step >= simMaxSteps {
+				if rate > 0 {
+					timestamp += uint64(rng.ExpFloat64() / rate)
+					break
+				}
+				// Without hashrate, wait for the next event of the profile
+				next, ok := profile.next(timestamp - start)
+				if !ok {
+					log.Printf("Network halted without hashrate at block %d", parent.Number.Uint64()+1)
+					return blocks
+				}
+				timestamp, step = start+next-1, 0
+				continue
+			}
+			if rng.Float64() < -math.Expm1(-rate) {
+				break
+			}
+		}
+		header := &types.Header{
+			ParentHash: parent.Hash(),
+			UncleHash:  types.EmptyUncleHash,
+			Number:     new(big.Int).Add(parent.Number, common.Big1),
+			Time:       timestamp,
+			Difficulty: difficulty,
+		}
+		chain.append(header)
+
+		blocks = append(blocks, simBlock{
+			Number:     header.Number.Uint64(),
+			Timestamp:  header.Time,
+			BlockTime:  header.Time - parent.Time,
+			Difficulty: difficulty.String(),
+			Hashrate:   hashrate,
+			// A competing block is found during the propagation of this one
+			OrphanRisk: -math.Expm1(-propagation * hashrate / toFloat(difficulty)),
+		})
+		parent = header
+	}
+	return blocks
+}
+
+func summarize(blocks []simBlock) simSummary {
+	var s simSummary
+	if len(blocks) == 0 {
+		return s
+	}
+	var (
+		times              = make([]uint64, len(blocks))
+		sum, sumSq         float64
+		minDiff, maxDiff   *big.Int
+		finalDiff          string
+		orphans, maxOrphan float64
+	)
+	for i, b := range blocks {
+		times[i] = b.BlockTime
+		sum += float64(b.BlockTime)
+		sumSq += float64(b.BlockTime) * float64(b.BlockTime)
+		orphans += b.OrphanRisk
+		maxOrphan = math.Max(maxOrphan, b.OrphanRisk)
+
+		diff, _ := new(big.Int).SetString(b.Difficulty, 10)
+		if minDiff == nil || diff.Cmp(minDiff) < 0 {
+			minDiff = diff
+		}
+		if maxDiff == nil || diff.Cmp(maxDiff) > 0 {
+			maxDiff = diff
+		}
+		finalDiff = b.Difficulty
+	}
+	sort.Slice(times, func(i, j int) bool { return times[i] < times[j] })
+
+	count := float64(len(blocks))
+	s.Blocks = len(blocks)
+	s.Duration = blocks[len(blocks)-1].Timestamp - (blocks[0].Timestamp - blocks[0].BlockTime)
+	s.MeanBlockTime = sum / count
+	s.StdDevBlockTime = math.Sqrt(math.Max(0, sumSq/count-s.MeanBlockTime*s.MeanBlockTime))
+	s.MedianBlockTime = times[len(times)/2]
+	s.P95BlockTime = times[len(times)*95/100]
+	s.MaxBlockTime = times[len(times)-1]
+	s.MinDifficulty = minDiff.String()
+	s.MaxDifficulty = maxDiff.String()
+	s.FinalDifficulty = finalDiff
+	s.MeanOrphanRisk = orphans / count
+	s.MaxOrphanRisk = maxOrphan
+	s.ExpectedOrphans = orphans
+	return s
+}
+
+func writeSimCSV(w io.Writer, blocks []simBlock) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"number", "timestamp", "blockTime", "difficulty", "hashrate", "orphanRisk"})
+	for _, b := range blocks {
+		cw.Write([]string{
+			strconv.FormatUint(b.Number, 10),
+			strconv.FormatUint(b.Timestamp, 10),
+			strconv.FormatUint(b.BlockTime, 10),
+			b.Difficulty,
+			strconv.FormatFloat(b.Hashrate, 'g', -1, 64),
+			strconv.FormatFloat(b.OrphanRisk, 'g', 6, 64),
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func toFloat(x *big.Int) float64 {
+	f, _ := new(big.Float).SetInt(x).Float64()
+	return f
+}
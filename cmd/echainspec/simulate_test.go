@@ -0,0 +1,146 @@
+package main
+
+import (
+	"errors"
+	"math/big"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/params/types/coregeth"
+	"github.com/ethereum/go-ethereum/params/types/ctypes"
+)
+
+func TestSimulate(t *testing.T) {
+	for _, c := range []struct {
+		name    string
+		config  ctypes.ChainConfigurator
+		profile hashrateProfile
+		blocks  int
+		// Bounds of the mean block time
+		minMean, maxMean float64
+	}{
+		{
+			name:    "ethash",
+			config:  params.ClassicChainConfig,
+			profile: hashrateProfile{{Hashrate: 1e6}},
+			blocks:  500,
+			minMean: 8, maxMean: 20,
+		},
+		{
+			name:    "lyra2",
+			config:  params.MintMeChainConfig,
+			profile: hashrateProfile{{Hashrate: 1e6}},
+			blocks:  500,
+			minMean: 5, maxMean: 25,
+		},
+		{
+			name:    "halted",
+			config:  params.ClassicChainConfig,
+			profile: hashrateProfile{{Hashrate: 0}},
+			blocks:  0,
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			engine, err := simEngine(c.config)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer engine.Close()
+
+			run := func() []simBlock {
+				parent := &types.Header{
+					Number:     new(big.Int),
+					Difficulty: big.NewInt(10_000_000),
+					UncleHash:  types.EmptyUncleHash,
+				}
+				return simulate(engine, c.config, c.profile, parent, 500, 1, rand.New(rand.NewSource(1)))
+			}
+			blocks := run()
+			if len(blocks) != c.blocks {
+				t.Fatalf("block count mismatch: have %d, want %d", len(blocks), c.blocks)
+			}
+			if again := run(); !reflect.DeepEqual(blocks, again) {
+				t.Fatal("simulation not reproducible with the same seed")
+			}
+			if len(blocks) == 0 {
+				return
+			}
+			if mean := summarize(blocks).MeanBlockTime; mean < c.minMean || mean > c.maxMean {
+				t.Errorf("mean block time out of bounds: have %v, want [%v, %v]", mean, c.minMean, c.maxMean)
+			}
+			for i, b := range blocks {
+				if b.Number != uint64(i+1) || b.BlockTime == 0 {
+					t.Fatalf("invalid block %d: %+v", i, b)
+				}
+			}
+		})
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	for _, c := range []struct {
+		name   string
+		blocks []simBlock
+		want   simSummary
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name: "blocks",
+			blocks: []simBlock{
+				{Number: 1, Timestamp: 110, BlockTime: 10, Difficulty: "300", OrphanRisk: 0.1},
+				{Number: 2, Timestamp: 130, BlockTime: 20, Difficulty: "100", OrphanRisk: 0.3},
+				{Number: 3, Timestamp: 160, BlockTime: 30, Difficulty: "200", OrphanRisk: 0.2},
+				{Number: 4, Timestamp: 200, BlockTime: 40, Difficulty: "250", OrphanRisk: 0.4},
+			},
+			want: simSummary{
+				Blocks:          4,
+				Duration:        100,
+				MeanBlockTime:   25,
+				StdDevBlockTime: 11.180339887498949,
+				MedianBlockTime: 30,
+				P95BlockTime:    40,
+				MaxBlockTime:    40,
+				MinDifficulty:   "100",
+				MaxDifficulty:   "300",
+				FinalDifficulty: "250",
+				MeanOrphanRisk:  0.25,
+				MaxOrphanRisk:   0.4,
+				ExpectedOrphans: 1,
+			},
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			if have := summarize(c.blocks); !reflect.DeepEqual(have, c.want) {
+				t.Errorf("summary mismatch:\nhave %+v\nwant %+v", have, c.want)
+			}
+		})
+	}
+}
+
+func TestCheckSimStart(t *testing.T) {
+	halo := &coregeth.CoreGethChainConfig{Ethash: &ctypes.EthashConfig{}}
+	fork := uint64(1000)
+	halo.SetHaloDifficultyV2Transition(&fork)
+
+	for _, c := range []struct {
+		config   ctypes.ChainConfigurator
+		start, n uint64
+		want     error
+	}{
+		{params.ClassicChainConfig, 0, 100, nil},
+		{params.ClassicChainConfig, 500, 100, nil},
+		{halo, 0, 2000, nil},
+		{halo, 500, 100, nil},
+		{halo, 500, 500, errSimNoHistory},
+		{halo, 2000, 100, errSimNoHistory},
+	} {
+		if err := checkSimStart(c.config, c.start, c.n); !errors.Is(err, c.want) {
+			t.Errorf("start %d, %d blocks: have %v, want %v", c.start, c.n, err, c.want)
+		}
+	}
+}
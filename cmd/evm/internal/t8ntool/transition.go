@@ -163,11 +163,15 @@ func Transition(ctx *cli.Context) error {
 	}
 
 	if vmConfig.EVMInterpreter != "" {
-		vm.InitEVMCEVM(vmConfig.EVMInterpreter)
+		if err := vm.InitEVMCEVM(vmConfig.EVMInterpreter); err != nil {
+			return NewError(ErrorConfig, err)
+		}
 	}
 
 	if vmConfig.EWASMInterpreter != "" {
-		vm.InitEVMCEwasm(vmConfig.EWASMInterpreter)
+		if err := vm.InitEVMCEwasm(vmConfig.EWASMInterpreter); err != nil {
+			return NewError(ErrorConfig, err)
+		}
 	}
 
 	// Construct the chainconfig
@@ -232,7 +232,9 @@ func runCmd(ctx *cli.Context) error {
 	}
 
 	if runtimeConfig.EVMConfig.EVMInterpreter != "" {
-		vm.InitEVMCEVM(runtimeConfig.EVMConfig.EVMInterpreter)
+		if err := vm.InitEVMCEVM(runtimeConfig.EVMConfig.EVMInterpreter); err != nil {
+			return err
+		}
 	}
 
 	if chainConfig != nil {
@@ -92,11 +92,15 @@ func stateTestCmd(ctx *cli.Context) error {
 
 	if cfg.EVMInterpreter != "" {
 		log.Info("Running tests with %s=%s", "evmc.evm", cfg.EVMInterpreter)
-		vm.InitEVMCEVM(cfg.EVMInterpreter)
+		if err := vm.InitEVMCEVM(cfg.EVMInterpreter); err != nil {
+			return err
+		}
 	}
 	if cfg.EWASMInterpreter != "" {
 		log.Info("Running tests with %s=%s", "evmc.ewasm", cfg.EWASMInterpreter)
-		vm.InitEVMCEwasm(cfg.EWASMInterpreter)
+		if err := vm.InitEVMCEwasm(cfg.EWASMInterpreter); err != nil {
+			return err
+		}
 	}
 
 	// Load the test content from the input file
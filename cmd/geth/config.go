@@ -39,6 +39,7 @@ import (
 	"github.com/ethereum/go-ethereum/eth/ethconfig"
 	"github.com/ethereum/go-ethereum/internal/ethapi"
 	"github.com/ethereum/go-ethereum/internal/flags"
+	"github.com/ethereum/go-ethereum/internal/telemetry"
 	"github.com/ethereum/go-ethereum/internal/version"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/metrics"
@@ -92,10 +93,11 @@ type ethstatsConfig struct {
 }
 
 type gethConfig struct {
-	Eth      ethconfig.Config
-	Node     node.Config
-	Ethstats ethstatsConfig
-	Metrics  metrics.Config
+	Eth       ethconfig.Config
+	Node      node.Config
+	Ethstats  ethstatsConfig
+	Metrics   metrics.Config
+	Telemetry telemetry.Config
 }
 
 func loadConfig(file string, cfg *gethConfig) error {
@@ -129,9 +131,10 @@ func defaultNodeConfig() node.Config {
 func loadBaseConfig(ctx *cli.Context) gethConfig {
 	// Load defaults.
 	cfg := gethConfig{
-		Eth:     ethconfig.Defaults,
-		Node:    defaultNodeConfig(),
-		Metrics: metrics.DefaultConfig,
+		Eth:       ethconfig.Defaults,
+		Node:      defaultNodeConfig(),
+		Metrics:   metrics.DefaultConfig,
+		Telemetry: telemetry.DefaultConfig,
 	}
 
 	// Load config file.
@@ -163,6 +166,7 @@ func makeConfigNode(ctx *cli.Context) (*node.Node, gethConfig) {
 		cfg.Ethstats.URL = ctx.String(utils.EthStatsURLFlag.Name)
 	}
 	applyMetricConfig(ctx, &cfg)
+	applyTelemetryConfig(ctx, &cfg)
 
 	return stack, cfg
 }
@@ -199,6 +203,10 @@ func makeFullNode(ctx *cli.Context) (*node.Node, ethapi.Backend) {
 		v := ctx.Uint64(utils.OverrideVerkle.Name)
 		cfg.Eth.OverrideVerkle = &v
 	}
+	// Register the trace exporter first, to flush the spans after the other services stopped
+	if cfg.Telemetry.Enabled {
+		utils.RegisterTelemetryService(stack, &cfg.Telemetry)
+	}
 	backend, eth := utils.RegisterEthService(stack, &cfg.Eth)
 
 	// Create gauge with geth system and build information
@@ -326,6 +334,21 @@ func applyMetricConfig(ctx *cli.Context, cfg *gethConfig) {
 	}
 }
 
+func applyTelemetryConfig(ctx *cli.Context, cfg *gethConfig) {
+	if ctx.IsSet(utils.TelemetryEnabledFlag.Name) {
+		cfg.Telemetry.Enabled = ctx.Bool(utils.TelemetryEnabledFlag.Name)
+	}
+	if ctx.IsSet(utils.TelemetryEndpointFlag.Name) {
+		cfg.Telemetry.Endpoint = ctx.String(utils.TelemetryEndpointFlag.Name)
+	}
+	if ctx.IsSet(utils.TelemetrySampleRatioFlag.Name) {
+		cfg.Telemetry.SampleRatio = ctx.Float64(utils.TelemetrySampleRatioFlag.Name)
+	}
+	if ctx.IsSet(utils.TelemetryInstanceFlag.Name) {
+		cfg.Telemetry.InstanceName = ctx.String(utils.TelemetryInstanceFlag.Name)
+	}
+}
+
 func deprecated(field string) bool {
 	switch field {
 	case "ethconfig.Config.EVMInterpreter":
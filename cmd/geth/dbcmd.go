@@ -18,6 +18,7 @@ package main
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"os"
 	"os/signal"
@@ -31,12 +32,14 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/console/prompt"
+	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/state/snapshot"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/internal/flags"
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params/vars"
 	"github.com/ethereum/go-ethereum/trie"
 	"github.com/olekukonko/tablewriter"
 	"github.com/urfave/cli/v2"
@@ -51,6 +54,10 @@ var (
 		Name:  "remove.chain",
 		Usage: "If set, selects the state data for removal",
 	}
+	logIndexResetFlag = &cli.BoolFlag{
+		Name:  "reset",
+		Usage: "If set, indexes all the sections again",
+	}
 
 	removedbCommand = &cli.Command{
 		Action:    removeDB,
@@ -78,6 +85,7 @@ Remove blockchain and state databases`,
 			dbImportCmd,
 			dbExportCmd,
 			dbMetadataCmd,
+			dbLogIndexCmd,
 			dbCheckStateContentCmd,
 		},
 	}
@@ -203,6 +211,34 @@ WARNING: This is a low-level operation which may cause database corruption!`,
 		}, utils.NetworkFlags, utils.DatabaseFlags),
 		Description: "Shows metadata about the chain status.",
 	}
+	dbLogIndexCmd = &cli.Command{
+		Name:  "logindex",
+		Usage: "Build or verify the index of log addresses and topics",
+		Subcommands: []*cli.Command{
+			{
+				Action: buildLogIndex,
+				Name:   "build",
+				Usage:  "Index the logs of the chain up to the head block",
+				Flags: flags.Merge([]cli.Flag{
+					logIndexResetFlag,
+				}, utils.NetworkFlags, utils.DatabaseFlags),
+				Description: `
+This command indexes the log addresses and topics of the sections not indexed
+yet, continuing where the node or a previous run stopped. The index is used by
+nodes running with --logindex to serve wide range log queries.`,
+			},
+			{
+				Action:    verifyLogIndex,
+				Name:      "verify",
+				Usage:     "Check the log index against the stored receipts",
+				ArgsUsage: "<first section (optional)> <last section (optional)>",
+				Flags:     flags.Merge(utils.NetworkFlags, utils.DatabaseFlags),
+				Description: `
+This command recomputes the index of the given sections, all of them by default,
+and reports the entries which are missing or differ from the stored ones.`,
+			},
+		},
+	}
 )
 
 func removeDB(ctx *cli.Context) error {
@@ -759,3 +795,93 @@ func showMetaData(ctx *cli.Context) error {
 	table.Render()
 	return nil
 }
+
+func buildLogIndex(ctx *cli.Context) error {
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+	db := utils.MakeChainDatabase(ctx, stack, false)
+	defer db.Close()
+
+	hash := rawdb.ReadHeadBlockHash(db)
+	number := rawdb.ReadHeaderNumber(db, hash)
+	if number == nil {
+		return errors.New("head block is missing")
+	}
+	indexer := core.NewLogIndexer(db, vars.LogIndexBlocks, vars.LogIndexConfirms)
+	defer indexer.Close()
+
+	if ctx.Bool(logIndexResetFlag.Name) {
+		indexer.ResetSections()
+	}
+	var (
+		start  = time.Now()
+		logged = time.Now()
+	)
+	err := indexer.IndexSections(*number, func(section uint64) {
+		if time.Since(logged) > 8*time.Second {
+			log.Info("Indexing logs", "section", section, "elapsed", common.PrettyDuration(time.Since(start)))
+			logged = time.Now()
+		}
+	})
+	if err != nil {
+		return err
+	}
+	sections, _, _ := indexer.Sections()
+	log.Info("Indexed logs", "sections", sections, "elapsed", common.PrettyDuration(time.Since(start)))
+	return nil
+}
+
+func verifyLogIndex(ctx *cli.Context) error {
+	if ctx.NArg() > 2 {
+		return fmt.Errorf("max 2 arguments: %v", ctx.Command.ArgsUsage)
+	}
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+	db := utils.MakeChainDatabase(ctx, stack, true)
+	defer db.Close()
+
+	indexer := core.NewLogIndexer(db, vars.LogIndexBlocks, vars.LogIndexConfirms)
+	sections, _, _ := indexer.Sections()
+	indexer.Close()
+
+	if sections == 0 {
+		log.Info("Log index is empty")
+		return nil
+	}
+	first, last := uint64(0), sections-1
+	if ctx.NArg() > 0 {
+		var err error
+		if first, err = strconv.ParseUint(ctx.Args().Get(0), 10, 64); err != nil {
+			return fmt.Errorf("invalid first section: %v", err)
+		}
+	}
+	if ctx.NArg() > 1 {
+		var err error
+		if last, err = strconv.ParseUint(ctx.Args().Get(1), 10, 64); err != nil {
+			return fmt.Errorf("invalid last section: %v", err)
+		}
+	}
+	if last >= sections {
+		return fmt.Errorf("section %d not indexed, %d sections available", last, sections)
+	}
+	var (
+		start    = time.Now()
+		logged   = time.Now()
+		failures int
+	)
+	for section := first; section <= last; section++ {
+		if err := core.VerifyLogIndexSection(db, vars.LogIndexBlocks, section); err != nil {
+			log.Error("Invalid log index section", "section", section, "err", err)
+			failures++
+		}
+		if time.Since(logged) > 8*time.Second {
+			log.Info("Verifying log index", "section", section, "elapsed", common.PrettyDuration(time.Since(start)))
+			logged = time.Now()
+		}
+	}
+	if failures > 0 {
+		return fmt.Errorf("%d invalid log index sections", failures)
+	}
+	log.Info("Verified log index", "sections", last-first+1, "elapsed", common.PrettyDuration(time.Since(start)))
+	return nil
+}
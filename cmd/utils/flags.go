@@ -42,6 +42,7 @@ import (
 	"github.com/ethereum/go-ethereum/common/fdlimit"
 	"github.com/ethereum/go-ethereum/consensus/ethash"
 	"github.com/ethereum/go-ethereum/consensus/lyra2"
+	"github.com/ethereum/go-ethereum/consensus/stratum"
 
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/rawdb"
@@ -62,6 +63,7 @@ import (
 	"github.com/ethereum/go-ethereum/graphql"
 	"github.com/ethereum/go-ethereum/internal/ethapi"
 	"github.com/ethereum/go-ethereum/internal/flags"
+	"github.com/ethereum/go-ethereum/internal/telemetry"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/metrics"
 	"github.com/ethereum/go-ethereum/metrics/exp"
@@ -120,6 +122,16 @@ var (
 		Usage:    "Root directory for ancient data (default = inside chaindata)",
 		Category: flags.EthCategory,
 	}
+	AncientRPCFlag = &cli.StringFlag{
+		Name:     "ancient.rpc",
+		Usage:    "IPC path or HTTP/WS URL of a remote ancient store serving the chain history (replaces the local chain freezer)",
+		Category: flags.EthCategory,
+	}
+	AncientRPCReadOnlyFlag = &cli.BoolFlag{
+		Name:     "ancient.rpc.readonly",
+		Usage:    "Only read from the remote ancient store, written by the node owning it (the chain is kept in the local database, requires --syncmode full)",
+		Category: flags.EthCategory,
+	}
 	MinFreeDiskSpaceFlag = &flags.DirectoryFlag{
 		Name:     "datadir.minfreedisk",
 		Usage:    "Minimum free disk space in MB, once reached triggers auto shut down (default = --cache.gc converted to MB, 0 = disabled)",
@@ -167,6 +179,11 @@ var (
 		Usage:    "Ethereum Classic network: pre-configured Ethereum Classic mainnet",
 		Category: flags.EthCategory,
 	}
+	HaloFlag = &cli.BoolFlag{
+		Name:     "halo",
+		Usage:    "Halo network: pre-configured Halo mainnet",
+		Category: flags.EthCategory,
+	}
 	MainnetFlag = &cli.BoolFlag{
 		Name:     "mainnet",
 		Usage:    "Ethereum mainnet",
@@ -328,6 +345,11 @@ var (
 		Value:    ethconfig.Defaults.TransactionHistory,
 		Category: flags.StateCategory,
 	}
+	LogIndexFlag = &cli.BoolFlag{
+		Name:     "logindex",
+		Usage:    "Maintain an index of log addresses and topics, speeding up wide range log queries",
+		Category: flags.StateCategory,
+	}
 	// Light server and client settings
 	LightServeFlag = &cli.IntFlag{
 		Name:     "light.serve",
@@ -499,6 +521,12 @@ var (
 		Value:    ethconfig.Defaults.TxPool.Lifetime,
 		Category: flags.TxPoolCategory,
 	}
+	TxPoolHistoryFlag = &cli.Uint64Flag{
+		Name:     "txpool.history",
+		Usage:    "Maximum number of transactions whose pool lifecycle is recorded on disk (0 = disabled)",
+		Value:    ethconfig.Defaults.TxPool.History,
+		Category: flags.TxPoolCategory,
+	}
 	// Blob transaction pool settings
 	BlobPoolDataDirFlag = &cli.StringFlag{
 		Name:     "blobpool.datadir",
@@ -599,6 +627,28 @@ var (
 		Usage:    "Notify with pending block headers instead of work packages",
 		Category: flags.MinerCategory,
 	}
+	MinerStratumFlag = &cli.StringFlag{
+		Name:     "miner.stratum",
+		Usage:    "TCP listen address of the stratum server for remote miners (e.g. 0.0.0.0:8008)",
+		Category: flags.MinerCategory,
+	}
+	MinerStratumDifficultyFlag = &cli.Uint64Flag{
+		Name:     "miner.stratum.diff",
+		Usage:    "Initial share difficulty of the stratum connections, retargeted per connection",
+		Value:    stratum.DefaultConfig.Difficulty,
+		Category: flags.MinerCategory,
+	}
+	MinerStratumMinDifficultyFlag = &cli.Uint64Flag{
+		Name:     "miner.stratum.mindiff",
+		Usage:    "Minimum share difficulty of the stratum connections",
+		Value:    stratum.DefaultConfig.MinDifficulty,
+		Category: flags.MinerCategory,
+	}
+	MinerStratumPasswordFlag = &cli.StringFlag{
+		Name:     "miner.stratum.password",
+		Usage:    "Password the stratum workers must authorize with (any password if empty)",
+		Category: flags.MinerCategory,
+	}
 	MinerGasLimitFlag = &cli.Uint64Flag{
 		Name:     "miner.gaslimit",
 		Usage:    "Target gas ceiling for mined blocks",
@@ -632,6 +682,22 @@ var (
 		Usage:    "Disable remote sealing verification",
 		Category: flags.MinerCategory,
 	}
+	MinerTxOrderingFlag = &cli.StringFlag{
+		Name:     "miner.ordering",
+		Usage:    "Transaction ordering policy of mined blocks (price, fifo or priority)",
+		Value:    miner.OrderingPrice,
+		Category: flags.MinerCategory,
+	}
+	MinerPriorityAddressesFlag = &cli.StringFlag{
+		Name:     "miner.priority",
+		Usage:    "Comma separated list of senders whose transactions are included first by the priority ordering",
+		Category: flags.MinerCategory,
+	}
+	MinerMaxSenderGasShareFlag = &cli.Uint64Flag{
+		Name:     "miner.maxsendergas",
+		Usage:    "Maximum percentage of the block gas limit a single sender may use (0 = uncapped)",
+		Category: flags.MinerCategory,
+	}
 	MinerNewPayloadTimeout = &cli.DurationFlag{
 		Name:     "miner.newpayload-timeout",
 		Usage:    "Specify the maximum time allowance for creating a new payload",
@@ -1117,6 +1183,30 @@ Please note that --` + MetricsHTTPFlag.Name + ` must be set to start the server.
 		Value:    metrics.DefaultConfig.InfluxDBOrganization,
 		Category: flags.MetricsCategory,
 	}
+
+	// Telemetry flags
+	TelemetryEnabledFlag = &cli.BoolFlag{
+		Name:     "telemetry",
+		Usage:    "Enable the OpenTelemetry tracing of block import, mining, sync and RPC handling",
+		Category: flags.MetricsCategory,
+	}
+	TelemetryEndpointFlag = &cli.StringFlag{
+		Name:     "telemetry.endpoint",
+		Usage:    "OTLP/HTTP collector URL the traces are exported to",
+		Value:    telemetry.DefaultConfig.Endpoint,
+		Category: flags.MetricsCategory,
+	}
+	TelemetrySampleRatioFlag = &cli.Float64Flag{
+		Name:     "telemetry.sampleratio",
+		Usage:    "Fraction of the traces exported, between 0 and 1",
+		Value:    telemetry.DefaultConfig.SampleRatio,
+		Category: flags.MetricsCategory,
+	}
+	TelemetryInstanceFlag = &cli.StringFlag{
+		Name:     "telemetry.instance",
+		Usage:    "Service instance name reported with the traces",
+		Category: flags.MetricsCategory,
+	}
 )
 
 var (
@@ -1131,12 +1221,15 @@ var (
 		MainnetFlag,
 		ClassicFlag,
 		MintMeFlag,
+		HaloFlag,
 	}, TestnetFlags...)
 
 	// DatabaseFlags is the flag group of all database flags.
 	DatabaseFlags = []cli.Flag{
 		DataDirFlag,
 		AncientFlag,
+		AncientRPCFlag,
+		AncientRPCReadOnlyFlag,
 		RemoteDBFlag,
 		DBEngineFlag,
 		StateSchemeFlag,
@@ -1209,6 +1302,8 @@ func setBootstrapNodes(ctx *cli.Context, cfg *p2p.Config) {
 			urls = params.ClassicBootnodes
 		case ctx.Bool(MintMeFlag.Name):
 			urls = params.MintMeBootnodes
+		case ctx.Bool(HaloFlag.Name):
+			urls = params.HaloBootnodes
 		case ctx.Bool(MordorFlag.Name):
 			urls = params.MordorBootnodes
 		case ctx.Bool(SepoliaFlag.Name):
@@ -1248,6 +1343,8 @@ func setBootstrapNodesV5(ctx *cli.Context, cfg *p2p.Config) {
 		urls = params.MordorBootnodes
 	case ctx.Bool(MintMeFlag.Name):
 		urls = params.MintMeBootnodes
+	case ctx.Bool(HaloFlag.Name):
+		urls = params.HaloBootnodes
 	case cfg.BootstrapNodesV5 != nil:
 		return // already set, don't apply defaults.
 	}
@@ -1535,6 +1632,10 @@ func SetP2PConfig(ctx *cli.Context, cfg *p2p.Config) {
 	setBootstrapNodes(ctx, cfg)
 	setBootstrapNodesV5(ctx, cfg)
 
+	// Apply the Halo peer count, unless configured otherwise
+	if ctx.Bool(HaloFlag.Name) && cfg.MaxPeers == node.DefaultConfig.P2P.MaxPeers {
+		cfg.MaxPeers = vars.HaloMaxPeers
+	}
 	if ctx.IsSet(MaxPeersFlag.Name) {
 		cfg.MaxPeers = ctx.Int(MaxPeersFlag.Name)
 	}
@@ -1693,6 +1794,8 @@ func dataDirPathForCtxChainConfig(ctx *cli.Context, baseDataDirPath string) stri
 		return filepath.Join(baseDataDirPath, "sepolia")
 	case ctx.Bool(MintMeFlag.Name):
 		return filepath.Join(baseDataDirPath, "mintme")
+	case ctx.Bool(HaloFlag.Name):
+		return filepath.Join(baseDataDirPath, "halo")
 	case ctx.Bool(HoleskyFlag.Name):
 		return filepath.Join(baseDataDirPath, "holesky")
 	}
@@ -1728,6 +1831,21 @@ func setGPO(ctx *cli.Context, cfg *gasprice.Config) {
 }
 
 func setTxPool(ctx *cli.Context, cfg *legacypool.Config) {
+	// Apply the Halo pool limits, unless configured otherwise
+	if ctx.Bool(HaloFlag.Name) {
+		if cfg.AccountSlots == legacypool.DefaultConfig.AccountSlots {
+			cfg.AccountSlots = vars.HaloAccountSlots
+		}
+		if cfg.GlobalSlots == legacypool.DefaultConfig.GlobalSlots {
+			cfg.GlobalSlots = vars.HaloGlobalSlots
+		}
+		if cfg.AccountQueue == legacypool.DefaultConfig.AccountQueue {
+			cfg.AccountQueue = vars.HaloAccountQueue
+		}
+		if cfg.GlobalQueue == legacypool.DefaultConfig.GlobalQueue {
+			cfg.GlobalQueue = vars.HaloGlobalQueue
+		}
+	}
 	if ctx.IsSet(TxPoolLocalsFlag.Name) {
 		locals := strings.Split(ctx.String(TxPoolLocalsFlag.Name), ",")
 		for _, account := range locals {
@@ -1768,6 +1886,9 @@ func setTxPool(ctx *cli.Context, cfg *legacypool.Config) {
 	if ctx.IsSet(TxPoolLifetimeFlag.Name) {
 		cfg.Lifetime = ctx.Duration(TxPoolLifetimeFlag.Name)
 	}
+	if ctx.IsSet(TxPoolHistoryFlag.Name) {
+		cfg.History = ctx.Uint64(TxPoolHistoryFlag.Name)
+	}
 }
 
 func homeDir() string {
@@ -1848,6 +1969,18 @@ func setMiner(ctx *cli.Context, cfg *miner.Config) {
 		cfg.Notify = strings.Split(ctx.String(MinerNotifyFlag.Name), ",")
 	}
 	cfg.NotifyFull = ctx.Bool(MinerNotifyFullFlag.Name)
+	if ctx.IsSet(MinerStratumFlag.Name) {
+		cfg.Stratum = ctx.String(MinerStratumFlag.Name)
+	}
+	if ctx.IsSet(MinerStratumDifficultyFlag.Name) {
+		cfg.StratumDifficulty = ctx.Uint64(MinerStratumDifficultyFlag.Name)
+	}
+	if ctx.IsSet(MinerStratumMinDifficultyFlag.Name) {
+		cfg.StratumMinDifficulty = ctx.Uint64(MinerStratumMinDifficultyFlag.Name)
+	}
+	if ctx.IsSet(MinerStratumPasswordFlag.Name) {
+		cfg.StratumPassword = ctx.String(MinerStratumPasswordFlag.Name)
+	}
 	if ctx.IsSet(MinerExtraDataFlag.Name) {
 		cfg.ExtraData = []byte(ctx.String(MinerExtraDataFlag.Name))
 	}
@@ -1871,6 +2004,29 @@ func setMiner(ctx *cli.Context, cfg *miner.Config) {
 	if ctx.IsSet(MinerNewPayloadTimeout.Name) {
 		cfg.NewPayloadTimeout = ctx.Duration(MinerNewPayloadTimeout.Name)
 	}
+	if ctx.IsSet(MinerTxOrderingFlag.Name) {
+		cfg.TxOrdering = ctx.String(MinerTxOrderingFlag.Name)
+	}
+	if ctx.IsSet(MinerPriorityAddressesFlag.Name) {
+		cfg.PriorityAddresses = nil
+		for _, addr := range strings.Split(ctx.String(MinerPriorityAddressesFlag.Name), ",") {
+			if addr = strings.TrimSpace(addr); !common.IsHexAddress(addr) {
+				Fatalf("Invalid priority address: %q", addr)
+			}
+			cfg.PriorityAddresses = append(cfg.PriorityAddresses, common.HexToAddress(addr))
+		}
+	}
+	switch cfg.TxOrdering {
+	case "", miner.OrderingPrice, miner.OrderingFIFO, miner.OrderingPriority:
+	default:
+		Fatalf("Invalid transaction ordering %q, must be %s, %s or %s", cfg.TxOrdering, miner.OrderingPrice, miner.OrderingFIFO, miner.OrderingPriority)
+	}
+	if ctx.IsSet(MinerMaxSenderGasShareFlag.Name) {
+		cfg.MaxSenderGasShare = ctx.Uint64(MinerMaxSenderGasShareFlag.Name)
+		if cfg.MaxSenderGasShare >= 100 {
+			Fatalf("Invalid sender gas share %d%%, must be below 100", cfg.MaxSenderGasShare)
+		}
+	}
 }
 
 func setRequiredBlocks(ctx *cli.Context, cfg *ethconfig.Config) {
@@ -1945,7 +2101,7 @@ func CheckExclusive(ctx *cli.Context, args ...interface{}) {
 // SetEthConfig applies eth-related command line flags to the config.
 func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *ethconfig.Config) {
 	// Avoid conflicting network flags
-	CheckExclusive(ctx, MainnetFlag, DeveloperFlag, DeveloperPoWFlag, SepoliaFlag, ClassicFlag, MordorFlag, MintMeFlag, HoleskyFlag)
+	CheckExclusive(ctx, MainnetFlag, DeveloperFlag, DeveloperPoWFlag, SepoliaFlag, ClassicFlag, MordorFlag, MintMeFlag, HaloFlag, HoleskyFlag)
 	CheckExclusive(ctx, LightServeFlag, SyncModeFlag, "light")
 	CheckExclusive(ctx, DeveloperFlag, DeveloperPoWFlag, ExternalSignerFlag) // Can't use both ephemeral unlocked and external signer
 
@@ -1983,6 +2139,18 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *ethconfig.Config) {
 		cfg.SyncMode = *flags.GlobalTextMarshaler(ctx, SyncModeFlag.Name).(*downloader.SyncMode)
 	}
 
+	// Apply the Halo cache allowances, unless configured otherwise
+	if ctx.Bool(HaloFlag.Name) {
+		if cfg.DatabaseCache == ethconfig.Defaults.DatabaseCache {
+			cfg.DatabaseCache = vars.HaloDatabaseCache
+		}
+		if cfg.TrieCleanCache == ethconfig.Defaults.TrieCleanCache {
+			cfg.TrieCleanCache = vars.HaloTrieCleanCacheSize
+		}
+		if cfg.TrieDirtyCache == ethconfig.Defaults.TrieDirtyCache {
+			cfg.TrieDirtyCache = vars.HaloTrieDirtyCacheSize
+		}
+	}
 	if ctx.IsSet(CacheFlag.Name) || ctx.IsSet(CacheDatabaseFlag.Name) {
 		cfg.DatabaseCache = ctx.Int(CacheFlag.Name) * ctx.Int(CacheDatabaseFlag.Name) / 100
 	}
@@ -1990,6 +2158,15 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *ethconfig.Config) {
 	if ctx.IsSet(AncientFlag.Name) {
 		cfg.DatabaseFreezer = ctx.String(AncientFlag.Name)
 	}
+	if ctx.IsSet(AncientRPCFlag.Name) {
+		cfg.DatabaseFreezerRemote = ctx.String(AncientRPCFlag.Name)
+	}
+	if ctx.IsSet(AncientRPCReadOnlyFlag.Name) {
+		cfg.DatabaseFreezerRemoteReadOnly = ctx.Bool(AncientRPCReadOnlyFlag.Name)
+	}
+	if cfg.DatabaseFreezerRemoteReadOnly && cfg.SyncMode != downloader.FullSync {
+		Fatalf("--%s requires --%s full, snap sync writes the ancient store", AncientRPCReadOnlyFlag.Name, SyncModeFlag.Name)
+	}
 
 	if gcmode := ctx.String(GCModeFlag.Name); gcmode != "full" && gcmode != gcModeArchive {
 		Fatalf("--%s must be either 'full' or 'archive'", GCModeFlag.Name)
@@ -2040,6 +2217,9 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *ethconfig.Config) {
 	if ctx.IsSet(CacheLogSizeFlag.Name) {
 		cfg.FilterLogCacheSize = ctx.Int(CacheLogSizeFlag.Name)
 	}
+	if ctx.IsSet(LogIndexFlag.Name) {
+		cfg.LogIndex = ctx.Bool(LogIndexFlag.Name)
+	}
 	if !ctx.Bool(SnapshotFlag.Name) || cfg.SnapshotCache == 0 {
 		// If snap-sync is requested, this flag is also required
 		if cfg.SyncMode == downloader.SnapSync {
@@ -2069,12 +2249,16 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *ethconfig.Config) {
 
 	if ctx.IsSet(EWASMInterpreterFlag.Name) {
 		cfg.EWASMInterpreter = ctx.String(EWASMInterpreterFlag.Name)
-		vm.InitEVMCEwasm(cfg.EWASMInterpreter)
+		if err := vm.InitEVMCEwasm(cfg.EWASMInterpreter); err != nil {
+			Fatalf("Failed to load the EVMC Ewasm VM: %v", err)
+		}
 	}
 
 	if ctx.IsSet(EVMInterpreterFlag.Name) {
 		cfg.EVMInterpreter = ctx.String(EVMInterpreterFlag.Name)
-		vm.InitEVMCEVM(cfg.EVMInterpreter)
+		if err := vm.InitEVMCEVM(cfg.EVMInterpreter); err != nil {
+			Fatalf("Failed to load the EVMC EVM: %v", err)
+		}
 	}
 	if ctx.IsSet(RPCGlobalGasCapFlag.Name) {
 		cfg.RPCGasCap = ctx.Uint64(RPCGlobalGasCapFlag.Name)
@@ -2438,6 +2622,9 @@ func MakeChainDatabase(ctx *cli.Context, stack *node.Node, readonly bool) ethdb.
 		chainDb = remotedb.New(client)
 	case ctx.String(SyncModeFlag.Name) == "light":
 		chainDb, err = stack.OpenDatabase("lightchaindata", cache, handles, "", readonly)
+	case ctx.IsSet(AncientRPCFlag.Name):
+		log.Info("Using remote ancient store", "endpoint", ctx.String(AncientRPCFlag.Name))
+		chainDb, err = stack.OpenDatabaseWithRemoteFreezer("chaindata", cache, handles, ctx.String(AncientFlag.Name), ctx.String(AncientRPCFlag.Name), ctx.Bool(AncientRPCReadOnlyFlag.Name), "", readonly)
 	default:
 		chainDb, err = stack.OpenDatabaseWithFreezer("chaindata", cache, handles, ctx.String(AncientFlag.Name), "", readonly)
 	}
@@ -2510,6 +2697,8 @@ func genesisForCtxChainConfig(ctx *cli.Context) *genesisT.Genesis {
 		genesis = params.DefaultSepoliaGenesisBlock()
 	case ctx.Bool(MintMeFlag.Name):
 		genesis = params.DefaultMintMeGenesisBlock()
+	case ctx.Bool(HaloFlag.Name):
+		genesis = params.DefaultHaloGenesisBlock()
 	case ctx.Bool(HoleskyFlag.Name):
 		genesis = params.DefaultHoleskyGenesisBlock()
 	case ctx.Bool(DeveloperFlag.Name):
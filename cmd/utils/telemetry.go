@@ -0,0 +1,86 @@
+// Copyright 2025 The core-geth Authors
+// This file is part of the core-geth library.
+//
+// The core-geth library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The core-geth library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the core-geth library. If not, see <http://www.gnu.org/licenses/>.
+
+package utils
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/internal/telemetry"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/node"
+	"github.com/ethereum/go-ethereum/params"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+)
+
+// telemetryShutdownTimeout is the time given to the exporter to flush the
+// pending spans on shutdown.
+const telemetryShutdownTimeout = 5 * time.Second
+
+// telemetryService installs the OpenTelemetry tracer provider exporting the
+// spans to an OTLP collector for the lifetime of the node.
+type telemetryService struct {
+	config   telemetry.Config
+	provider *sdktrace.TracerProvider
+}
+
+// RegisterTelemetryService adds the OpenTelemetry trace exporter to the node.
+func RegisterTelemetryService(stack *node.Node, cfg *telemetry.Config) {
+	if cfg.SampleRatio < 0 || cfg.SampleRatio > 1 {
+		Fatalf("Invalid telemetry sample ratio %v, must be between 0 and 1", cfg.SampleRatio)
+	}
+	stack.RegisterLifecycle(&telemetryService{config: *cfg})
+}
+
+// Start implements node.Lifecycle, installing the exporting tracer provider.
+func (s *telemetryService) Start() error {
+	exporter, err := newOTLPExporter(s.config.Endpoint)
+	if err != nil {
+		return fmt.Errorf("failed to create the telemetry exporter: %w", err)
+	}
+	attrs := []attribute.KeyValue{
+		semconv.ServiceName("geth"),
+		semconv.ServiceVersion(params.VersionWithMeta),
+	}
+	if s.config.InstanceName != "" {
+		attrs = append(attrs, semconv.ServiceInstanceID(s.config.InstanceName))
+	}
+	s.provider = sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL, attrs...)),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(s.config.SampleRatio))),
+	)
+	otel.SetTracerProvider(s.provider)
+	log.Info("Enabled telemetry tracing", "endpoint", s.config.Endpoint, "ratio", s.config.SampleRatio)
+	return nil
+}
+
+// Stop implements node.Lifecycle, flushing the pending spans to the collector.
+func (s *telemetryService) Stop() error {
+	ctx, cancel := context.WithTimeout(context.Background(), telemetryShutdownTimeout)
+	defer cancel()
+
+	if err := s.provider.Shutdown(ctx); err != nil {
+		log.Warn("Failed to flush the telemetry spans", "err", err)
+	}
+	return nil
+}
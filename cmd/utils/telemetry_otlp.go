@@ -0,0 +1,271 @@
+// Copyright 2025 The core-geth Authors
+// This file is part of the core-geth library.
+//
+// The core-geth library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The core-geth library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the core-geth library. If not, see <http://www.gnu.org/licenses/>.
+
+package utils
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/instrumentation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// otlpExportTimeout is the time given to the collector to accept a batch of spans.
+const otlpExportTimeout = 10 * time.Second
+
+// otlpExporter exports spans to an OTLP collector over HTTP, in the JSON encoding
+// of the protocol, which needs neither the protobuf nor the gRPC modules.
+type otlpExporter struct {
+	url    string
+	client *http.Client
+}
+
+// newOTLPExporter creates an exporter sending the spans to the collector at the
+// given URL, on the standard traces path unless the URL has one.
+func newOTLPExporter(endpoint string) (*otlpExporter, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("unsupported collector URL scheme %q", u.Scheme)
+	}
+	if u.Path == "" || u.Path == "/" {
+		u.Path = "/v1/traces"
+	}
+	return &otlpExporter{url: u.String(), client: &http.Client{Timeout: otlpExportTimeout}}, nil
+}
+
+// ExportSpans implements sdktrace.SpanExporter, posting the spans to the collector.
+func (e *otlpExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	if len(spans) == 0 {
+		return nil
+	}
+	body, err := json.Marshal(encodeOTLPSpans(spans))
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := e.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	io.Copy(io.Discard, res.Body)
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return fmt.Errorf("collector rejected %d spans: %s", len(spans), res.Status)
+	}
+	return nil
+}
+
+// Shutdown implements sdktrace.SpanExporter, the exporter holds no resources.
+func (e *otlpExporter) Shutdown(ctx context.Context) error {
+	return nil
+}
+
+// OTLP/JSON messages of the trace export request. The 64 bits integers are
+// encoded as strings and the identifiers in hex, as the protocol specifies.
+type (
+	otlpRequest struct {
+		ResourceSpans []*otlpResourceSpans `json:"resourceSpans"`
+	}
+	otlpResourceSpans struct {
+		Resource   otlpResource      `json:"resource"`
+		ScopeSpans []*otlpScopeSpans `json:"scopeSpans"`
+		SchemaURL  string            `json:"schemaUrl,omitempty"`
+	}
+	otlpResource struct {
+		Attributes []otlpKeyValue `json:"attributes"`
+	}
+	otlpScopeSpans struct {
+		Scope     otlpScope   `json:"scope"`
+		Spans     []*otlpSpan `json:"spans"`
+		SchemaURL string      `json:"schemaUrl,omitempty"`
+	}
+	otlpScope struct {
+		Name    string `json:"name"`
+		Version string `json:"version,omitempty"`
+	}
+	otlpSpan struct {
+		TraceID           string         `json:"traceId"`
+		SpanID            string         `json:"spanId"`
+		ParentSpanID      string         `json:"parentSpanId,omitempty"`
+		Name              string         `json:"name"`
+		Kind              int            `json:"kind"`
+		StartTimeUnixNano string         `json:"startTimeUnixNano"`
+		EndTimeUnixNano   string         `json:"endTimeUnixNano"`
+		Attributes        []otlpKeyValue `json:"attributes,omitempty"`
+		Events            []otlpEvent    `json:"events,omitempty"`
+		Status            otlpStatus     `json:"status"`
+	}
+	otlpEvent struct {
+		TimeUnixNano string         `json:"timeUnixNano"`
+		Name         string         `json:"name"`
+		Attributes   []otlpKeyValue `json:"attributes,omitempty"`
+	}
+	otlpStatus struct {
+		Code    int    `json:"code,omitempty"`
+		Message string `json:"message,omitempty"`
+	}
+	otlpKeyValue struct {
+		Key   string       `json:"key"`
+		Value otlpAnyValue `json:"value"`
+	}
+	otlpAnyValue struct {
+		StringValue *string         `json:"stringValue,omitempty"`
+		BoolValue   *bool           `json:"boolValue,omitempty"`
+		IntValue    *string         `json:"intValue,omitempty"`
+		DoubleValue *float64        `json:"doubleValue,omitempty"`
+		ArrayValue  *otlpArrayValue `json:"arrayValue,omitempty"`
+	}
+	otlpArrayValue struct {
+		Values []otlpAnyValue `json:"values"`
+	}
+)
+
+// Status codes of the protocol, ordered differently than the API ones.
+const (
+	otlpStatusOk    = 1
+	otlpStatusError = 2
+)
+
+// encodeOTLPSpans groups the spans by resource and instrumentation scope into
+// an export request.
+func encodeOTLPSpans(spans []sdktrace.ReadOnlySpan) *otlpRequest {
+	var (
+		req       = new(otlpRequest)
+		resources = make(map[*resource.Resource]*otlpResourceSpans)
+		scopes    = make(map[*otlpResourceSpans]map[instrumentation.Scope]*otlpScopeSpans)
+	)
+	for _, span := range spans {
+		rs, ok := resources[span.Resource()]
+		if !ok {
+			rs = &otlpResourceSpans{
+				Resource:  otlpResource{Attributes: encodeOTLPAttributes(span.Resource().Attributes())},
+				SchemaURL: span.Resource().SchemaURL(),
+			}
+			resources[span.Resource()] = rs
+			scopes[rs] = make(map[instrumentation.Scope]*otlpScopeSpans)
+			req.ResourceSpans = append(req.ResourceSpans, rs)
+		}
+		scope := span.InstrumentationScope()
+		ss, ok := scopes[rs][scope]
+		if !ok {
+			ss = &otlpScopeSpans{
+				Scope:     otlpScope{Name: scope.Name, Version: scope.Version},
+				SchemaURL: scope.SchemaURL,
+			}
+			scopes[rs][scope] = ss
+			rs.ScopeSpans = append(rs.ScopeSpans, ss)
+		}
+		ss.Spans = append(ss.Spans, encodeOTLPSpan(span))
+	}
+	return req
+}
+
+// encodeOTLPSpan converts a finished span into its OTLP/JSON form.
+func encodeOTLPSpan(span sdktrace.ReadOnlySpan) *otlpSpan {
+	sc := span.SpanContext()
+	out := &otlpSpan{
+		TraceID:           sc.TraceID().String(),
+		SpanID:            sc.SpanID().String(),
+		Name:              span.Name(),
+		Kind:              int(span.SpanKind()),
+		StartTimeUnixNano: strconv.FormatInt(span.StartTime().UnixNano(), 10),
+		EndTimeUnixNano:   strconv.FormatInt(span.EndTime().UnixNano(), 10),
+		Attributes:        encodeOTLPAttributes(span.Attributes()),
+	}
+	if parent := span.Parent(); parent.HasSpanID() {
+		out.ParentSpanID = parent.SpanID().String()
+	}
+	for _, event := range span.Events() {
+		out.Events = append(out.Events, otlpEvent{
+			TimeUnixNano: strconv.FormatInt(event.Time.UnixNano(), 10),
+			Name:         event.Name,
+			Attributes:   encodeOTLPAttributes(event.Attributes),
+		})
+	}
+	switch status := span.Status(); status.Code {
+	case codes.Ok:
+		out.Status = otlpStatus{Code: otlpStatusOk}
+	case codes.Error:
+		out.Status = otlpStatus{Code: otlpStatusError, Message: status.Description}
+	}
+	return out
+}
+
+// encodeOTLPAttributes converts span attributes into OTLP key-values.
+func encodeOTLPAttributes(attrs []attribute.KeyValue) []otlpKeyValue {
+	out := make([]otlpKeyValue, 0, len(attrs))
+	for _, attr := range attrs {
+		out = append(out, otlpKeyValue{Key: string(attr.Key), Value: encodeOTLPValue(attr.Value)})
+	}
+	return out
+}
+
+// encodeOTLPValue converts an attribute value into an OTLP any-value.
+func encodeOTLPValue(v attribute.Value) otlpAnyValue {
+	var (
+		str = func(s string) otlpAnyValue { return otlpAnyValue{StringValue: &s} }
+		bol = func(b bool) otlpAnyValue { return otlpAnyValue{BoolValue: &b} }
+		num = func(n int64) otlpAnyValue { s := strconv.FormatInt(n, 10); return otlpAnyValue{IntValue: &s} }
+		dbl = func(f float64) otlpAnyValue { return otlpAnyValue{DoubleValue: &f} }
+	)
+	switch v.Type() {
+	case attribute.BOOL:
+		return bol(v.AsBool())
+	case attribute.INT64:
+		return num(v.AsInt64())
+	case attribute.FLOAT64:
+		return dbl(v.AsFloat64())
+	case attribute.BOOLSLICE:
+		return encodeOTLPArray(v.AsBoolSlice(), bol)
+	case attribute.INT64SLICE:
+		return encodeOTLPArray(v.AsInt64Slice(), num)
+	case attribute.FLOAT64SLICE:
+		return encodeOTLPArray(v.AsFloat64Slice(), dbl)
+	case attribute.STRINGSLICE:
+		return encodeOTLPArray(v.AsStringSlice(), str)
+	default:
+		return str(v.Emit())
+	}
+}
+
+// encodeOTLPArray converts the items of a slice attribute into an OTLP array.
+func encodeOTLPArray[T any](items []T, encode func(T) otlpAnyValue) otlpAnyValue {
+	values := make([]otlpAnyValue, len(items))
+	for i, item := range items {
+		values[i] = encode(item)
+	}
+	return otlpAnyValue{ArrayValue: &otlpArrayValue{Values: values}}
+}
@@ -0,0 +1,108 @@
+// Copyright 2025 The core-geth Authors
+// This file is part of the core-geth library.
+//
+// The core-geth library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The core-geth library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the core-geth library. If not, see <http://www.gnu.org/licenses/>.
+
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// Tests that the spans are posted to the collector in the OTLP/JSON encoding.
+func TestOTLPExporter(t *testing.T) {
+	var (
+		requests = make(chan *otlpRequest, 1)
+		reject   atomic.Bool
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if reject.Load() {
+			http.Error(w, "overloaded", http.StatusServiceUnavailable)
+			return
+		}
+		if r.URL.Path != "/v1/traces" || r.Header.Get("Content-Type") != "application/json" {
+			http.Error(w, "unexpected request", http.StatusBadRequest)
+			return
+		}
+		req := new(otlpRequest)
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		requests <- req
+	}))
+	defer server.Close()
+
+	exporter, err := newOTLPExporter(server.URL)
+	if err != nil {
+		t.Fatalf("failed to create exporter: %v", err)
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithSyncer(exporter),
+		sdktrace.WithResource(resource.NewSchemaless(attribute.String("service.name", "geth"))),
+	)
+	defer provider.Shutdown(context.Background())
+
+	tracer := provider.Tracer("test")
+	ctx, parent := tracer.Start(context.Background(), "parent")
+	_, child := tracer.Start(ctx, "child")
+	child.SetAttributes(attribute.Int64("block.number", 1<<60), attribute.StringSlice("tags", []string{"a", "b"}))
+	child.RecordError(errors.New("failed"))
+	child.SetStatus(codes.Error, "failed")
+	child.End()
+
+	var req *otlpRequest
+	select {
+	case req = <-requests:
+	default:
+		t.Fatal("spans not exported")
+	}
+	if len(req.ResourceSpans) != 1 || len(req.ResourceSpans[0].ScopeSpans) != 1 {
+		t.Fatalf("unexpected grouping: %+v", req)
+	}
+	if attrs := req.ResourceSpans[0].Resource.Attributes; len(attrs) != 1 || *attrs[0].Value.StringValue != "geth" {
+		t.Errorf("resource mismatch: %+v", attrs)
+	}
+	scope := req.ResourceSpans[0].ScopeSpans[0]
+	if scope.Scope.Name != "test" || len(scope.Spans) != 1 {
+		t.Fatalf("scope spans mismatch: %+v", scope)
+	}
+	span := scope.Spans[0]
+	if span.Name != "child" || span.TraceID != parent.SpanContext().TraceID().String() || span.ParentSpanID != parent.SpanContext().SpanID().String() {
+		t.Errorf("span identity mismatch: %+v", span)
+	}
+	if span.Status.Code != otlpStatusError || span.Status.Message != "failed" || len(span.Events) != 1 {
+		t.Errorf("span error mismatch: status %+v, %d events", span.Status, len(span.Events))
+	}
+	if len(span.Attributes) != 2 || *span.Attributes[0].Value.IntValue != "1152921504606846976" || len(span.Attributes[1].Value.ArrayValue.Values) != 2 {
+		t.Errorf("span attributes mismatch: %+v", span.Attributes)
+	}
+	// The spans rejected by the collector fail the export
+	reject.Store(true)
+	parent.End()
+	if err := exporter.ExportSpans(context.Background(), []sdktrace.ReadOnlySpan{parent.(sdktrace.ReadOnlySpan)}); err == nil {
+		t.Error("rejected export succeeded")
+	}
+}
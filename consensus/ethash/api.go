@@ -21,6 +21,7 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/consensus/stratum"
 	"github.com/ethereum/go-ethereum/core/types"
 )
 
@@ -111,3 +112,12 @@ func (api *API) SubmitHashrate(rate hexutil.Uint64, id common.Hash) bool {
 func (api *API) GetHashrate() uint64 {
 	return uint64(api.ethash.Hashrate())
 }
+
+// GetStratumWorkers returns the statistics of the workers mining through the
+// stratum server.
+func (api *API) GetStratumWorkers() ([]stratum.WorkerStats, error) {
+	if api.ethash.remote == nil || api.ethash.remote.stratum == nil {
+		return nil, errors.New("stratum server not running")
+	}
+	return api.ethash.remote.stratum.Workers(), nil
+}
@@ -44,103 +44,45 @@ import (
 
 // Ethash proof-of-work protocol constants.
 var (
-	maxUncles              = 2                // Maximum number of uncles allowed in a single block (default for non-Halo chains)
+	maxUncles              = 2                // Maximum number of uncles allowed in a single block
+	maxUncleDepth          = 7                // Maximum depth of an uncle relative to the including block
 	allowedFutureBlockTime = 15 * time.Second // Max time from current time allowed for blocks, before they're considered future blocks
+
+	haloMaxUncles              = 1                // Maximum number of uncles allowed in a single block under the Halo uncle rules
+	haloMaxUncleDepth          = 2                // Maximum uncle depth under the Halo uncle rules
+	haloAllowedFutureBlockTime = 30 * time.Second // Max future block time under the Halo rules
 )
 
 // getAllowedFutureBlockTime returns the maximum time a block can be in the future
 // before being rejected as invalid.
 //
-// Halo chain (ChainID 12000) uses 30 seconds to handle operational clock drift
+// The Halo future block time rule uses 30 seconds to handle operational clock drift
 // between distributed mining setups (e.g., Windows miners + Linux RPC nodes).
 // Standard Ethereum uses 15 seconds.
 //
 // Note: This tolerance is for block ACCEPTANCE only. Difficulty calculations
 // use timestamp capping to prevent manipulation attacks.
-func getAllowedFutureBlockTime(chainID *big.Int) time.Duration {
-	if chainID != nil && chainID.Uint64() == 12000 {
-		return 30 * time.Second // Halo: 30s tolerance for operational clock drift
-	}
-	return allowedFutureBlockTime // Standard Ethereum: 15s
-}
-
-// getMaxUncles returns the maximum number of uncles allowed for a given chain
-// Halo chain (ChainID 12000) allows 1 uncle, others allow 2
-func getMaxUncles(chainID *big.Int) int {
-	if chainID != nil && chainID.Uint64() == 12000 {
-		return 1 // Halo chain
-	}
-	return maxUncles // Standard Ethereum (2)
-}
-
-// getMaxUncleDepth returns the maximum depth for uncles on a given chain
-// Halo chain (ChainID 12000) allows depth of 2, others allow 7
-func getMaxUncleDepth(chainID *big.Int) int {
-	if chainID != nil && chainID.Uint64() == 12000 {
-		return 2 // Halo chain - uncles can be max 2 blocks deep
+func getAllowedFutureBlockTime(config ctypes.ChainConfigurator, number *big.Int) time.Duration {
+	if config.IsEnabled(config.GetHaloFutureBlockTimeTransition, number) {
+		return haloAllowedFutureBlockTime
 	}
-	return 7 // Standard Ethereum - uncles can be max 7 blocks deep
+	return allowedFutureBlockTime
 }
 
-// verifyMedianTimePast ensures block timestamp is greater than median of last 11 blocks.
-// This prevents miners from backdating blocks to manipulate difficulty.
-//
-// This is a Bitcoin-inspired security measure that prevents:
-// - Backdating attacks (creating blocks with old timestamps)
-// - Timestamp manipulation to lower difficulty
-// - Chain reorganization attacks using timestamp gaming
-func verifyMedianTimePast(chain consensus.ChainHeaderReader, header, parent *types.Header) error {
-	// Need at least 11 blocks for meaningful MTP calculation
-	if parent.Number.Uint64() < 11 {
-		// SECURITY FIX: For early blocks, use basic timestamp validation
-		// Still must be greater than parent to prevent backdating
-		if header.Time <= parent.Time {
-			return fmt.Errorf("timestamp %d not greater than parent timestamp %d (early block validation)",
-				header.Time, parent.Time)
-		}
-		return nil
-	}
-
-	// Collect timestamps of last 11 blocks (including parent)
-	timestamps := make([]uint64, 11)
-	for i := 0; i < 11; i++ {
-		h := chain.GetHeaderByNumber(parent.Number.Uint64() - uint64(i))
-		if h == nil {
-			// Can't validate without full history, skip check
-			return nil
-		}
-		timestamps[i] = h.Time
-	}
-
-	// Sort timestamps to find median
-	sortedTimestamps := make([]uint64, 11)
-	copy(sortedTimestamps, timestamps)
-	// Simple bubble sort (only 11 elements, performance not critical)
-	for i := 0; i < 11; i++ {
-		for j := 0; j < 10-i; j++ {
-			if sortedTimestamps[j] > sortedTimestamps[j+1] {
-				sortedTimestamps[j], sortedTimestamps[j+1] = sortedTimestamps[j+1], sortedTimestamps[j]
-			}
-		}
+// getMaxUncles returns the maximum number of uncles allowed in the block with the given number.
+func getMaxUncles(config ctypes.ChainConfigurator, number *big.Int) int {
+	if config.IsEnabled(config.GetHaloUnclesTransition, number) {
+		return haloMaxUncles
 	}
-	medianTime := sortedTimestamps[5] // Middle value of 11 sorted elements
-
-	// Block timestamp must be strictly greater than median
-	if header.Time <= medianTime {
-		return fmt.Errorf("timestamp %d not greater than median time past %d (backdating prevented)",
-			header.Time, medianTime)
-	}
-
-	return nil
+	return maxUncles
 }
 
-// getMaxTimestampJump returns the maximum allowed timestamp increase per block
-// This prevents timestamp racing attacks where miners jump timestamps wildly
-func getMaxTimestampJump(chainID *big.Int) uint64 {
-	if chainID != nil && chainID.Uint64() == 12000 {
-		return 10 // Halo: max 10 seconds jump per block
+// getMaxUncleDepth returns the maximum depth of uncles included in the block with the given number.
+func getMaxUncleDepth(config ctypes.ChainConfigurator, number *big.Int) int {
+	if config.IsEnabled(config.GetHaloUnclesTransition, number) {
+		return haloMaxUncleDepth
 	}
-	return 60 // Standard Ethereum: more lenient due to 12s block time
+	return maxUncleDepth
 }
 
 // Various error messages to mark blocks invalid. These should be private to
@@ -198,6 +140,11 @@ func (ethash *Ethash) VerifyHeaders(chain consensus.ChainHeaderReader, headers [
 		return abort, results
 	}
 
+	// Make the batch visible to difficulty calculations walking the header history
+	if config := chain.Config(); config.IsEnabled(config.GetHaloDifficultyV2Transition, headers[len(headers)-1].Number) {
+		chain = newBatchHeaderReader(chain, headers)
+	}
+
 	// Spawn as many workers as allowed threads
 	workers := runtime.GOMAXPROCS(0)
 	if len(headers) < workers {
@@ -271,10 +218,9 @@ func (ethash *Ethash) VerifyUncles(chain consensus.ChainReader, block *types.Blo
 	if ethash.config.PowMode == ModeFullFake {
 		return nil
 	}
-	// Get chain-specific uncle parameters
-	chainID := chain.Config().GetChainID()
-	maxUnclesForChain := getMaxUncles(chainID)
-	maxUncleDepthForChain := getMaxUncleDepth(chainID)
+	// Get fork-specific uncle parameters
+	maxUnclesForChain := getMaxUncles(chain.Config(), block.Number())
+	maxUncleDepthForChain := getMaxUncleDepth(chain.Config(), block.Number())
 
 	// Verify that there are at most maxUnclesForChain uncles included in this block
 	if len(block.Uncles()) > maxUnclesForChain {
@@ -353,9 +299,8 @@ func (ethash *Ethash) verifyHeader(chain consensus.ChainHeaderReader, header, pa
 	}
 	// Verify the header's timestamp
 	if !uncle {
-		// Use chain-specific future block tolerance
-		chainID := chain.Config().GetChainID()
-		allowedFuture := getAllowedFutureBlockTime(chainID)
+		// Use fork-specific future block tolerance
+		allowedFuture := getAllowedFutureBlockTime(chain.Config(), header.Number)
 		if header.Time > uint64(unixNow+int64(allowedFuture.Seconds())) {
 			return consensus.ErrFutureBlock
 		}
@@ -365,12 +310,11 @@ func (ethash *Ethash) verifyHeader(chain consensus.ChainHeaderReader, header, pa
 		return errOlderBlockTime
 	}
 
-	// Halo-specific timestamp validations (ChainID 12000)
-	chainID := chain.Config().GetChainID()
-	if chainID != nil && chainID.Uint64() == 12000 && !uncle {
+	// Halo timestamp validations
+	if !uncle && chain.Config().IsEnabled(chain.Config().GetHaloMedianTimePastTransition, header.Number) {
 		// SECURITY LAYER 1: Median Time Past (MTP) validation
 		// Prevents backdating attacks by ensuring timestamp > median of last 11 blocks
-		if err := verifyMedianTimePast(chain, header, parent); err != nil {
+		if err := misc.VerifyMedianTimePast(chain, header, parent); err != nil {
 			return err
 		}
 
@@ -476,7 +420,11 @@ func (ethash *Ethash) verifyHeader(chain consensus.ChainHeaderReader, header, pa
 // given the parent block's time and difficulty.
 func (ethash *Ethash) CalcDifficulty(chain consensus.ChainHeaderReader, time uint64, parent *types.Header) *big.Int {
 	// HALO-SPECIFIC: Pass chain reader for historical difficulty lookups
-	if chain.Config().GetChainID() != nil && chain.Config().GetChainID().Uint64() == 12000 {
+	next := new(big.Int).Add(parent.Number, big1)
+	if chain.Config().IsEnabled(chain.Config().GetHaloDifficultyV2Transition, next) {
+		return calcDifficultyHaloV2(chain, time, parent)
+	}
+	if chain.Config().IsEnabled(chain.Config().GetHaloDifficultyTransition, next) {
 		return calcDifficultyHaloSecure(chain, time, parent)
 	}
 	return CalcDifficulty(chain.Config(), time, parent)
@@ -654,6 +602,9 @@ func getHaloPhaseMinimum(blockNum uint64) *big.Int {
 //
 // Algorithm:
 //   Timestamp capping → Base calculation → Average-based floors → Absolute hard floor
+//
+// Since the result depends on the local clock, it is superseded by the deterministic
+// calcDifficultyHaloV2 once HaloDifficultyV2Transition is activated.
 func calcDifficultyHaloSecure(chain consensus.ChainHeaderReader, blockTime uint64, parent *types.Header) *big.Int {
 	// ========== STEP 0: Timestamp Capping (CRITICAL SECURITY LAYER) ==========
 	// We accept blocks up to 30s in the future to handle clock drift between mining setups,
@@ -911,8 +862,8 @@ func CalcDifficulty(config ctypes.ChainConfigurator, time uint64, parent *types.
 	next := new(big.Int).Add(parent.Number, big1)
 	out := new(big.Int)
 
-	// NOTE: Halo (ChainID 12000) uses calcDifficultyHaloSecure() via the method version
-	// of CalcDifficulty, not this standalone function.
+	// NOTE: The Halo difficulty algorithm (HaloDifficultyTransition) is applied via the method
+	// version of CalcDifficulty, not this standalone function, since it requires chain history.
 
 	// TODO (meowbits): do we need this?
 	// if config.IsEnabled(config.GetEthashTerminalTotalDifficulty, next) {
@@ -1170,17 +1121,14 @@ func (ethash *Ethash) Finalize(chain consensus.ChainHeaderReader, header *types.
 	// Accumulate any block and uncle rewards and commit the final state root
 	mutations.AccumulateRewards(chain.Config(), state, header, uncles)
 
-	// Apply Halo-specific EIP-1559 fee distribution for Halo chain (ChainID 12000)
-	// This distributes base fees: 40% burn, 30% miner, 20% ecosystem, 10% reserve
-	chainID := chain.Config().GetChainID()
-	if chainID != nil && chainID.Uint64() == 12000 {
-		// Only apply if EIP-1559 is active and we have a base fee
-		if chain.Config().IsEnabled(chain.Config().GetEIP1559Transition, header.Number) && header.BaseFee != nil {
-			if err := eip1559.ApplyHaloBaseFeeDistribution(state, header, header.BaseFee, header.GasUsed); err != nil {
-				// Log error but don't fail block finalization
-				// In production, you may want to handle this differently
-				panic(fmt.Sprintf("failed to apply Halo EIP-1559 distribution: %v", err))
-			}
+	// Apply Halo EIP-1559 fee distribution if activated
+	// This distributes base fees according to the configured fee distribution schedule
+	// Only applies if EIP-1559 is active and we have a base fee
+	if eip1559.IsHaloBaseFeeDistributed(chain.Config(), header) {
+		if err := eip1559.ApplyHaloBaseFeeDistribution(chain.Config(), state, header, header.BaseFee, header.GasUsed); err != nil {
+			// Log error but don't fail block finalization
+			// In production, you may want to handle this differently
+			panic(fmt.Sprintf("failed to apply Halo EIP-1559 distribution: %v", err))
 		}
 	}
 }
@@ -0,0 +1,217 @@
+// Copyright 2025 The core-geth Authors
+// This file is part of the core-geth library.
+//
+// The core-geth library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The core-geth library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the core-geth library. If not, see <http://www.gnu.org/licenses/>.
+
+package ethash
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Halo difficulty algorithm parameters.
+const (
+	haloTargetBlockTime      = int64(4)    // Target block time in seconds
+	haloMaxTimeDelta         = int64(60)   // Block times above this are counted as this
+	haloAdjustmentDivisor    = int64(2048) // Base adjustment is parent_diff / 2048 per second off target
+	haloRampAdjustmentBound  = int64(2041) // Max adjustment of parent_diff / 2041 (~0.049%) during the gentle ramp
+	haloAdjustmentBound      = int64(5)    // Max adjustment of parent_diff / 5 (20%) after the gentle ramp
+	haloGentleRampEnd        = uint64(12500)
+	haloSymmetricWindowEnd   = uint64(12600)
+	haloSymmetricCeiling     = int64(500000)
+	haloEmergencyBlocks      = 10 // Number of blocks inspected for emergency mode
+	haloEmergencyBlockTime   = uint64(60)
+	haloRampHardFloor        = int64(1000)
+	haloHardFloor            = int64(0x10000)
+	haloRampEmergencyFloor   = int64(500)
+	haloEmergencyFloor       = int64(0x4000)
+	haloDifficultyHistoryLen = 150 // Longest averaging window, in blocks
+)
+
+// haloDifficultyWindows are the multi-window average protections: the difficulty
+// may not drop below the given percentage of the average difficulty over the
+// given number of most recent blocks.
+var haloDifficultyWindows = []struct {
+	blocks  int
+	percent int64
+}{
+	{15, 50},  // 1 minute at 4s blocks
+	{75, 40},  // 5 minutes at 4s blocks
+	{150, 30}, // 10 minutes at 4s blocks
+}
+
+// haloAncestors returns up to n headers, starting with parent and followed by
+// its ancestors. Ancestors are resolved by hash, so the result is the same for
+// canonical and side chains alike, and does not depend on the head of the chain.
+// Fewer headers are returned if genesis is reached or an ancestor is unknown.
+func haloAncestors(chain consensus.ChainHeaderReader, parent *types.Header, n int) []*types.Header {
+	headers := make([]*types.Header, 0, n)
+	for header := parent; header != nil && len(headers) < n; {
+		headers = append(headers, header)
+		if header.Number.Sign() == 0 {
+			break
+		}
+		header = chain.GetHeader(header.ParentHash, header.Number.Uint64()-1)
+	}
+	return headers
+}
+
+// haloAverageDifficulty returns the average difficulty of the first n headers,
+// or nil if fewer than n headers are available.
+func haloAverageDifficulty(headers []*types.Header, n int) *big.Int {
+	if len(headers) < n {
+		return nil
+	}
+	sum := new(big.Int)
+	for _, header := range headers[:n] {
+		sum.Add(sum, header.Difficulty)
+	}
+	return sum.Div(sum, big.NewInt(int64(n)))
+}
+
+// haloEmergencyMode reports whether the average block time over the most recent
+// blocks exceeds 60 seconds, signalling a severe hashrate shortage.
+func haloEmergencyMode(headers []*types.Header) bool {
+	if len(headers) < haloEmergencyBlocks || headers[0].Number.Uint64() < haloEmergencyBlocks {
+		return false
+	}
+	newest, oldest := headers[0], headers[haloEmergencyBlocks-1]
+	return (newest.Time-oldest.Time)/(haloEmergencyBlocks-1) > haloEmergencyBlockTime
+}
+
+// calcDifficultyHaloV2 is the deterministic Halo difficulty adjustment algorithm.
+//
+// It keeps the rules of calcDifficultyHaloSecure (±20% bounded adjustment towards a
+// 4 second target, phased minimums, emergency mode and the 1/5/10 minute average
+// floors), but the result depends only on the new block's timestamp, its parent and
+// the parent's ancestors:
+//
+//   - The wall clock is never consulted. Instead of capping future timestamps to the
+//     local time, the counted block time is bounded to 60 seconds. A miner inflating
+//     a timestamp lowers the difficulty by at most one bounded step, and since child
+//     timestamps must be greater, the gained time is paid back by the following blocks.
+//   - History is read by walking parent hashes rather than by canonical block number,
+//     so the result is the same during batch verification, on side chains and on
+//     re-import.
+func calcDifficultyHaloV2(chain consensus.ChainHeaderReader, time uint64, parent *types.Header) *big.Int {
+	blockNum := parent.Number.Uint64()
+
+	history := haloEmergencyBlocks
+	if blockNum > haloGentleRampEnd {
+		history = haloDifficultyHistoryLen
+	}
+	ancestors := haloAncestors(chain, parent, history)
+
+	// Bounded adjustment towards the target block time
+	timeDelta := int64(1)
+	if time > parent.Time {
+		timeDelta = int64(time - parent.Time)
+	}
+	if timeDelta > haloMaxTimeDelta {
+		timeDelta = haloMaxTimeDelta
+	}
+	adjustment := new(big.Int).Div(parent.Difficulty, big.NewInt(haloAdjustmentDivisor))
+	adjustment.Mul(adjustment, big.NewInt(haloTargetBlockTime-timeDelta))
+
+	bound := haloAdjustmentBound
+	if blockNum <= haloGentleRampEnd {
+		bound = haloRampAdjustmentBound
+	}
+	maxAdjustment := new(big.Int).Div(parent.Difficulty, big.NewInt(bound))
+	if adjustment.CmpAbs(maxAdjustment) > 0 {
+		if adjustment.Sign() < 0 {
+			adjustment.Neg(maxAdjustment)
+		} else {
+			adjustment.Set(maxAdjustment)
+		}
+	}
+	difficulty := new(big.Int).Add(parent.Difficulty, adjustment)
+
+	// Symmetric adjustment right after the gentle ramp, for a quick response
+	// to production hashrate
+	if blockNum > haloGentleRampEnd && blockNum < haloSymmetricWindowEnd && difficulty.Cmp(big.NewInt(haloSymmetricCeiling)) < 0 {
+		step := new(big.Int).Div(difficulty, big.NewInt(10))
+		if timeDelta < haloTargetBlockTime {
+			difficulty.Add(difficulty, step)
+		} else if timeDelta > 2*haloTargetBlockTime {
+			difficulty.Sub(difficulty, step)
+		}
+	}
+
+	// Phase minimum, relaxed by half in emergency mode
+	minimum := getHaloPhaseMinimum(blockNum)
+	if haloEmergencyMode(ancestors) {
+		floor := big.NewInt(haloEmergencyFloor)
+		if blockNum <= haloGentleRampEnd {
+			floor = big.NewInt(haloRampEmergencyFloor)
+		}
+		minimum.Div(minimum, big2)
+		if minimum.Cmp(floor) < 0 {
+			minimum = floor
+		}
+	}
+	// Multi-window average floors, inactive during the gentle ramp
+	if blockNum > haloGentleRampEnd {
+		for _, window := range haloDifficultyWindows {
+			avg := haloAverageDifficulty(ancestors, window.blocks)
+			if avg == nil {
+				continue
+			}
+			avg.Mul(avg, big.NewInt(window.percent))
+			avg.Div(avg, big.NewInt(100))
+			if avg.Cmp(minimum) > 0 {
+				minimum = avg
+			}
+		}
+	}
+	// Absolute hard floor
+	floor := big.NewInt(haloHardFloor)
+	if blockNum <= haloGentleRampEnd {
+		floor = big.NewInt(haloRampHardFloor)
+	}
+	if minimum.Cmp(floor) < 0 {
+		minimum = floor
+	}
+	if difficulty.Cmp(minimum) < 0 {
+		difficulty.Set(minimum)
+	}
+	return difficulty
+}
+
+// batchHeaderReader makes a batch of headers under verification available to
+// ancestor lookups by hash, before they are written to the database.
+type batchHeaderReader struct {
+	consensus.ChainHeaderReader
+	headers map[common.Hash]*types.Header
+}
+
+func newBatchHeaderReader(chain consensus.ChainHeaderReader, headers []*types.Header) *batchHeaderReader {
+	batch := make(map[common.Hash]*types.Header, len(headers))
+	for _, header := range headers {
+		batch[header.Hash()] = header
+	}
+	return &batchHeaderReader{ChainHeaderReader: chain, headers: batch}
+}
+
+// GetHeader retrieves a header from the batch, falling back to the chain.
+func (r *batchHeaderReader) GetHeader(hash common.Hash, number uint64) *types.Header {
+	if header, ok := r.headers[hash]; ok && header.Number.Uint64() == number {
+		return header
+	}
+	return r.ChainHeaderReader.GetHeader(hash, number)
+}
@@ -0,0 +1,247 @@
+// Copyright 2025 The core-geth Authors
+// This file is part of the core-geth library.
+//
+// The core-geth library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The core-geth library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the core-geth library. If not, see <http://www.gnu.org/licenses/>.
+
+package ethash
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params/types/coregeth"
+	"github.com/ethereum/go-ethereum/params/types/ctypes"
+)
+
+// testHeaderChain is a minimal in-memory consensus.ChainHeaderReader.
+type testHeaderChain struct {
+	config    ctypes.ChainConfigurator
+	headers   map[common.Hash]*types.Header
+	canonical map[uint64]*types.Header
+}
+
+func newTestHeaderChain(config ctypes.ChainConfigurator, genesis *types.Header) *testHeaderChain {
+	chain := &testHeaderChain{
+		config:    config,
+		headers:   make(map[common.Hash]*types.Header),
+		canonical: make(map[uint64]*types.Header),
+	}
+	chain.insert(genesis)
+	return chain
+}
+
+func (c *testHeaderChain) insert(header *types.Header) {
+	c.headers[header.Hash()] = header
+	c.canonical[header.Number.Uint64()] = header
+}
+
+func (c *testHeaderChain) Config() ctypes.ChainConfigurator { return c.config }
+func (c *testHeaderChain) CurrentHeader() *types.Header {
+	return c.canonical[uint64(len(c.canonical)-1)]
+}
+func (c *testHeaderChain) GetHeader(hash common.Hash, number uint64) *types.Header {
+	if header := c.headers[hash]; header != nil && header.Number.Uint64() == number {
+		return header
+	}
+	return nil
+}
+func (c *testHeaderChain) GetHeaderByNumber(number uint64) *types.Header  { return c.canonical[number] }
+func (c *testHeaderChain) GetHeaderByHash(hash common.Hash) *types.Header { return c.headers[hash] }
+func (c *testHeaderChain) GetTd(hash common.Hash, number uint64) *big.Int { return nil }
+
+func haloV2TestConfig() *coregeth.CoreGethChainConfig {
+	config := &coregeth.CoreGethChainConfig{
+		ChainID: big.NewInt(1337),
+		Ethash:  &ctypes.EthashConfig{},
+	}
+	zero := uint64(0)
+	config.SetHaloDifficultyV2Transition(&zero)
+	return config
+}
+
+// haloBlockTimeProfile yields the synthetic block time of the given block:
+// alternating fast and slow blocks, a hashrate drop triggering emergency mode
+// and a hashrate spike.
+func haloBlockTimeProfile(number uint64) uint64 {
+	switch {
+	case number > 12700 && number <= 12780:
+		return 90 // miners leave
+	case number > 12850 && number <= 12950:
+		return 1 // attacker hashrate spike
+	case number%100 < 50:
+		return 2
+	default:
+		return 6
+	}
+}
+
+// makeHaloTestChain generates n headers on top of genesis, sealing each with the
+// difficulty computed by the engine.
+func makeHaloTestChain(engine *Ethash, chain *testHeaderChain, genesis *types.Header, n int) []*types.Header {
+	headers := make([]*types.Header, 0, n)
+	parent := genesis
+	for i := 0; i < n; i++ {
+		number := new(big.Int).Add(parent.Number, common.Big1)
+		header := &types.Header{
+			ParentHash: parent.Hash(),
+			UncleHash:  types.EmptyUncleHash,
+			Number:     number,
+			GasLimit:   parent.GasLimit,
+			Time:       parent.Time + haloBlockTimeProfile(number.Uint64()),
+		}
+		header.Difficulty = engine.CalcDifficulty(chain, header.Time, parent)
+		chain.insert(header)
+		headers = append(headers, header)
+		parent = header
+	}
+	return headers
+}
+
+func haloTestGenesis() *types.Header {
+	return &types.Header{
+		Number:     new(big.Int),
+		UncleHash:  types.EmptyUncleHash,
+		Difficulty: big.NewInt(1000),
+		GasLimit:   8000000,
+		Time:       1600000000, // Far in the past, replayed long after it was "mined"
+	}
+}
+
+// Test vectors of the deterministic Halo difficulty algorithm over the block time profile.
+var haloV2DifficultyVectors = []struct {
+	number     uint64
+	difficulty uint64
+}{
+	{1, 1000},
+	{100, 1000},
+	{5000, 1000},
+	{12500, 1000},
+	{12501, 1000},
+	{12550, 557856},
+	{12600, 532344},
+	{12700, 532318},
+	{12720, 306190},
+	{12740, 176326},
+	{12760, 130484},
+	{12780, 113685},
+	{12800, 111721},
+	{12850, 117027},
+	{12900, 125835},
+	{12950, 135306},
+	{13000, 129154},
+}
+
+func TestHaloDifficultyV2Vectors(t *testing.T) {
+	engine := NewFaker()
+	defer engine.Close()
+
+	genesis := haloTestGenesis()
+	config := haloV2TestConfig()
+	headers := makeHaloTestChain(engine, newTestHeaderChain(config, genesis), genesis, 13000)
+
+	for _, vector := range haloV2DifficultyVectors {
+		header := headers[vector.number-1]
+		if have := header.Difficulty.Uint64(); have != vector.difficulty {
+			t.Errorf("block %d: difficulty mismatch: have %d, want %d", vector.number, have, vector.difficulty)
+		}
+	}
+	// Replay the chain through batch verification on a node that only knows genesis
+	for _, batch := range []int{len(headers), 500} {
+		chain := newTestHeaderChain(config, genesis)
+		for start := 0; start < len(headers); start += batch {
+			end := start + batch
+			if end > len(headers) {
+				end = len(headers)
+			}
+			abort, results := engine.VerifyHeaders(chain, headers[start:end], make([]bool, end-start))
+			for i := start; i < end; i++ {
+				if err := <-results; err != nil {
+					t.Fatalf("batch %d: header %d: verification failed: %v", batch, i+1, err)
+				}
+			}
+			close(abort)
+			for _, header := range headers[start:end] {
+				chain.insert(header)
+			}
+		}
+	}
+}
+
+func TestHaloDifficultyV2RejectsInvalid(t *testing.T) {
+	engine := NewFaker()
+	defer engine.Close()
+
+	genesis := haloTestGenesis()
+	config := haloV2TestConfig()
+	headers := makeHaloTestChain(engine, newTestHeaderChain(config, genesis), genesis, 12780)
+
+	// Tamper with a difficulty that depends on the averaging windows
+	tampered := types.CopyHeader(headers[len(headers)-1])
+	tampered.Difficulty = new(big.Int).Sub(tampered.Difficulty, common.Big1)
+	headers[len(headers)-1] = tampered
+
+	chain := newTestHeaderChain(config, genesis)
+	abort, results := engine.VerifyHeaders(chain, headers, make([]bool, len(headers)))
+	defer close(abort)
+	for i := range headers {
+		err := <-results
+		if i < len(headers)-1 && err != nil {
+			t.Fatalf("header %d: unexpected verification failure: %v", i+1, err)
+		}
+		if i == len(headers)-1 && err == nil {
+			t.Fatalf("header %d: tampered difficulty accepted", i+1)
+		}
+	}
+}
+
+// TestHaloDifficultyV2SideChain tests that the difficulty of a side chain block
+// is computed from its own ancestors, not from the canonical chain.
+func TestHaloDifficultyV2SideChain(t *testing.T) {
+	engine := NewFaker()
+	defer engine.Close()
+
+	genesis := haloTestGenesis()
+	config := haloV2TestConfig()
+	chain := newTestHeaderChain(config, genesis)
+	headers := makeHaloTestChain(engine, chain, genesis, 12800)
+
+	// Fork off 100 blocks back with slow blocks, making the canonical chain
+	// and the side chain disagree on recent history.
+	fork := headers[len(headers)-101]
+	side := newTestHeaderChain(config, genesis)
+	for _, header := range headers[:len(headers)-100] {
+		side.insert(header)
+	}
+	parent := fork
+	for i := 0; i < 100; i++ {
+		header := &types.Header{
+			ParentHash: parent.Hash(),
+			UncleHash:  types.EmptyUncleHash,
+			Number:     new(big.Int).Add(parent.Number, common.Big1),
+			GasLimit:   parent.GasLimit,
+			Time:       parent.Time + 30,
+		}
+		header.Difficulty = engine.CalcDifficulty(side, header.Time, parent)
+		side.insert(header)
+		// The main chain knows the side chain block by hash only
+		chain.headers[header.Hash()] = header
+		parent = header
+	}
+	want := engine.CalcDifficulty(side, parent.Time+4, parent)
+	if have := engine.CalcDifficulty(chain, parent.Time+4, parent); have.Cmp(want) != 0 {
+		t.Fatalf("side chain difficulty mismatch: have %v, want %v", have, want)
+	}
+}
@@ -28,7 +28,13 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/params/types/coregeth"
+	"github.com/ethereum/go-ethereum/params/types/ctypes"
 	"github.com/ethereum/go-ethereum/params/types/goethereum"
 	"github.com/ethereum/go-ethereum/params/vars"
 )
@@ -239,3 +245,171 @@ func TestEtchash_11700000(t *testing.T) {
 		t.Fatalf("verifySeal failed: %v", err)
 	}
 }
+
+// TestHaloRulesActivation tests that the Halo protocol rules are driven by
+// their configured fork blocks rather than by the chain ID.
+func TestHaloRulesActivation(t *testing.T) {
+	fork := uint64(10)
+	config := &coregeth.CoreGethChainConfig{
+		ChainID: big.NewInt(1337),
+		Ethash:  &ctypes.EthashConfig{},
+	}
+	config.SetHaloUnclesTransition(&fork)
+	config.SetHaloFutureBlockTimeTransition(&fork)
+
+	before, after := big.NewInt(9), big.NewInt(10)
+	if have, want := getMaxUncles(config, before), maxUncles; have != want {
+		t.Errorf("max uncles before fork: have %d, want %d", have, want)
+	}
+	if have, want := getMaxUncles(config, after), haloMaxUncles; have != want {
+		t.Errorf("max uncles after fork: have %d, want %d", have, want)
+	}
+	if have, want := getMaxUncleDepth(config, before), maxUncleDepth; have != want {
+		t.Errorf("max uncle depth before fork: have %d, want %d", have, want)
+	}
+	if have, want := getMaxUncleDepth(config, after), haloMaxUncleDepth; have != want {
+		t.Errorf("max uncle depth after fork: have %d, want %d", have, want)
+	}
+	if have, want := getAllowedFutureBlockTime(config, before), allowedFutureBlockTime; have != want {
+		t.Errorf("allowed future block time before fork: have %v, want %v", have, want)
+	}
+	if have, want := getAllowedFutureBlockTime(config, after), haloAllowedFutureBlockTime; have != want {
+		t.Errorf("allowed future block time after fork: have %v, want %v", have, want)
+	}
+}
+
+// TestHaloFutureBlockTimeActivation tests that headers up to 30s in the future
+// are only accepted from the Halo future block time fork.
+func TestHaloFutureBlockTimeActivation(t *testing.T) {
+	engine := NewFaker()
+	defer engine.Close()
+
+	for _, c := range []struct {
+		fork uint64
+		want error
+	}{
+		{fork: 2, want: consensus.ErrFutureBlock},
+		{fork: 1, want: nil},
+	} {
+		config := &coregeth.CoreGethChainConfig{
+			ChainID: big.NewInt(1337),
+			Ethash:  &ctypes.EthashConfig{},
+		}
+		config.SetHaloFutureBlockTimeTransition(&c.fork)
+
+		genesis := haloTestGenesis()
+		chain := newTestHeaderChain(config, genesis)
+		header := &types.Header{
+			ParentHash: genesis.Hash(),
+			UncleHash:  types.EmptyUncleHash,
+			Number:     big.NewInt(1),
+			GasLimit:   genesis.GasLimit,
+			Time:       genesis.Time + 20,
+		}
+		header.Difficulty = engine.CalcDifficulty(chain, header.Time, genesis)
+
+		// The header is 20s ahead of the local clock
+		if err := engine.verifyHeader(chain, header, genesis, false, false, int64(genesis.Time)); err != c.want {
+			t.Errorf("fork at %d: have %v, want %v", c.fork, err, c.want)
+		}
+	}
+}
+
+// TestHaloMedianTimePastActivation tests that a side chain header older than the
+// median time past of the canonical chain is only rejected from the Halo MTP fork.
+func TestHaloMedianTimePastActivation(t *testing.T) {
+	engine := NewFaker()
+	defer engine.Close()
+
+	for _, c := range []struct {
+		fork    uint64
+		invalid bool
+	}{
+		{fork: 22, invalid: false},
+		{fork: 21, invalid: true},
+	} {
+		config := &coregeth.CoreGethChainConfig{
+			ChainID: big.NewInt(1337),
+			Ethash:  &ctypes.EthashConfig{},
+		}
+		config.SetHaloMedianTimePastTransition(&c.fork)
+
+		genesis := haloTestGenesis()
+		chain := newTestHeaderChain(config, genesis)
+
+		// Canonical chain of 20 slow blocks, and a side chain of fast blocks
+		// forking off at block 10
+		makeBlock := func(parent *types.Header, blockTime uint64) *types.Header {
+			header := &types.Header{
+				ParentHash: parent.Hash(),
+				UncleHash:  types.EmptyUncleHash,
+				Number:     new(big.Int).Add(parent.Number, common.Big1),
+				GasLimit:   parent.GasLimit,
+				Time:       parent.Time + blockTime,
+			}
+			header.Difficulty = engine.CalcDifficulty(chain, header.Time, parent)
+			return header
+		}
+		var fork, parent *types.Header = nil, genesis
+		for i := 1; i <= 20; i++ {
+			parent = makeBlock(parent, 100)
+			chain.insert(parent)
+			if i == 10 {
+				fork = parent
+			}
+		}
+		parent = fork
+		for i := 11; i <= 20; i++ {
+			parent = makeBlock(parent, 1)
+			chain.headers[parent.Hash()] = parent
+		}
+		header := makeBlock(parent, 1)
+
+		err := engine.verifyHeader(chain, header, parent, false, false, int64(header.Time))
+		if c.invalid && err == nil {
+			t.Errorf("fork at %d: header older than the median time past accepted", c.fork)
+		}
+		if !c.invalid && err != nil {
+			t.Errorf("fork at %d: unexpected error: %v", c.fork, err)
+		}
+	}
+}
+
+// TestHaloFeeDistributionActivation tests that the base fee is only distributed
+// from the Halo fee distribution fork.
+func TestHaloFeeDistributionActivation(t *testing.T) {
+	engine := NewFaker()
+	defer engine.Close()
+
+	var (
+		zero = uint64(0)
+		fork = uint64(10)
+	)
+	config := &coregeth.CoreGethChainConfig{
+		ChainID: big.NewInt(1337),
+		Ethash:  &ctypes.EthashConfig{},
+	}
+	config.SetEIP1559Transition(&zero)
+	config.SetHaloFeeDistributionTransition(&fork)
+	chain := newTestHeaderChain(config, haloTestGenesis())
+
+	for _, c := range []struct {
+		number    uint64
+		ecosystem uint64
+	}{
+		{9, 0},
+		{10, 200},
+	} {
+		statedb, _ := state.New(types.EmptyRootHash, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+		header := &types.Header{
+			Number:   new(big.Int).SetUint64(c.number),
+			Coinbase: common.HexToAddress("0xc0ffee"),
+			BaseFee:  big.NewInt(10),
+			GasUsed:  100,
+		}
+		engine.Finalize(chain, header, statedb, nil, nil, nil)
+		if have := statedb.GetBalance(params.HaloEcosystemFundAddress).Uint64(); have != c.ecosystem {
+			t.Errorf("block %d: ecosystem fund balance mismatch: have %d, want %d", c.number, have, c.ecosystem)
+		}
+	}
+}
@@ -37,6 +37,7 @@ import (
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	lrupkg "github.com/ethereum/go-ethereum/common/lru"
 	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/consensus/stratum"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/metrics"
@@ -559,6 +560,10 @@ type Config struct {
 	// be block header JSON objects instead of work package arrays.
 	NotifyFull bool
 
+	// Stratum configures the stratum server of the remote sealer,
+	// which is only started if a listen address is set.
+	Stratum stratum.Config `toml:"-"`
+
 	Log log.Logger `toml:"-"`
 	// ECIP-1099
 	ECIP1099Block *uint64 `toml:"-"`
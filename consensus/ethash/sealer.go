@@ -33,6 +33,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/consensus/stratum"
 	"github.com/ethereum/go-ethereum/core/types"
 	exprand "golang.org/x/exp/rand"
 	"gonum.org/v1/gonum/stat/distuv"
@@ -248,6 +249,7 @@ type remoteSealer struct {
 	ethash       *Ethash
 	noverify     bool
 	notifyURLs   []string
+	stratum      *stratum.Server // Optional stratum server pushing the work to miners
 	results      chan<- *types.Block
 	workCh       chan *sealTask   // Notification channel to push new work and relative result channel to remote sealer
 	fetchWorkCh  chan *sealWork   // Channel used for remote sealer to fetch mining work
@@ -306,6 +308,14 @@ func startRemoteSealer(ethash *Ethash, urls []string, noverify bool) *remoteSeal
 		requestExit:  make(chan struct{}),
 		exitCh:       make(chan struct{}),
 	}
+	if config := ethash.config.Stratum; config.Addr != "" {
+		server, err := stratum.NewServer(config, "ethash", &stratumBackend{sealer: s}, ethash.config.Log)
+		if err != nil {
+			ethash.config.Log.Error("Failed to start stratum server", "addr", config.Addr, "err", err)
+		} else {
+			s.stratum = server
+		}
+	}
 	go s.loop()
 	return s
 }
@@ -313,6 +323,9 @@ func startRemoteSealer(ethash *Ethash, urls []string, noverify bool) *remoteSeal
 func (s *remoteSealer) loop() {
 	defer func() {
 		s.ethash.config.Log.Trace("Ethash remote sealer is exiting")
+		if s.stratum != nil {
+			s.stratum.Close()
+		}
 		s.cancelNotify()
 		s.reqWG.Wait()
 		close(s.exitCh)
@@ -358,6 +371,9 @@ func (s *remoteSealer) loop() {
 				// this could overflow
 				total += rate.rate
 			}
+			if s.stratum != nil {
+				total += s.stratum.Hashrate()
+			}
 			req <- total
 
 		case <-ticker.C:
@@ -422,6 +438,18 @@ func (s *remoteSealer) notifyWork() {
 	for _, url := range s.notifyURLs {
 		go s.sendNotification(s.notifyCtx, url, blob, work)
 	}
+	if s.stratum != nil {
+		number := s.currentBlock.NumberU64()
+		epochLength := calcEpochLength(number, s.ethash.config.ECIP1099Block)
+		epoch := calcEpoch(number, epochLength)
+		s.stratum.Notify(&stratum.Job{
+			SealHash: common.HexToHash(work[0]),
+			Seed:     SeedHash(epoch, epochLength),
+			Epoch:    epoch,
+			Number:   number,
+			Target:   new(big.Int).Div(two256, s.currentBlock.Difficulty()),
+		})
+	}
 }
 
 func (s *remoteSealer) sendNotification(ctx context.Context, url string, json []byte, work [4]string) {
@@ -497,3 +525,44 @@ func (s *remoteSealer) submitWork(nonce types.BlockNonce, mixDigest common.Hash,
 	s.ethash.config.Log.Warn("Work submitted is too old", "number", solution.NumberU64(), "sealhash", sealhash, "hash", solution.Hash())
 	return false
 }
+
+// stratumBackend verifies the shares of the stratum server and submits the
+// solutions to the remote sealer.
+type stratumBackend struct {
+	sealer *remoteSealer
+}
+
+// Hash implements stratum.Backend, computing the proof-of-work of a job.
+func (b *stratumBackend) Hash(job *stratum.Job, nonce uint64) (common.Hash, *big.Int) {
+	var (
+		ethash = b.sealer.ethash
+		digest []byte
+		result []byte
+	)
+	// Use the DAG if it is already generated, the cache otherwise
+	dataset := ethash.dataset(job.Number, true)
+	if dataset.generated() {
+		digest, result = hashimotoFull(dataset.dataset, job.SealHash.Bytes(), nonce)
+		runtime.KeepAlive(dataset)
+	} else {
+		cache := ethash.cache(job.Number)
+		size := datasetSize(job.Epoch)
+		if ethash.config.PowMode == ModeTest {
+			size = 32 * 1024
+		}
+		digest, result = hashimotoLight(size, cache.cache, job.SealHash.Bytes(), nonce)
+		runtime.KeepAlive(cache)
+	}
+	return common.BytesToHash(digest), new(big.Int).SetBytes(result)
+}
+
+// Submit implements stratum.Backend, submitting a solution to the remote sealer.
+func (b *stratumBackend) Submit(job *stratum.Job, nonce uint64, mixDigest common.Hash) error {
+	errc := make(chan error, 1)
+	select {
+	case b.sealer.submitWorkCh <- &mineResult{nonce: types.EncodeNonce(nonce), mixDigest: mixDigest, hash: job.SealHash, errc: errc}:
+	case <-b.sealer.requestExit:
+		return errEthashStopped
+	}
+	return <-errc
+}
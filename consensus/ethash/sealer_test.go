@@ -17,9 +17,13 @@
 package ethash
 
 import (
+	"bufio"
+	"encoding/hex"
 	"encoding/json"
+	"fmt"
 	"io"
 	"math/big"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"sort"
@@ -28,6 +32,7 @@ import (
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/stratum"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/internal/testlog"
 	"golang.org/x/exp/slog"
@@ -316,3 +321,92 @@ func TestStaleSubmission(t *testing.T) {
 		}
 	}
 }
+
+// Tests that miners connected to the stratum server get the work and that their
+// solutions are sealed.
+func TestRemoteStratum(t *testing.T) {
+	ethash := New(Config{PowMode: ModeTest, Stratum: stratum.Config{Addr: "127.0.0.1:0", Difficulty: 100, MinDifficulty: 1}}, nil, false)
+	defer ethash.Close()
+	ethash.SetThreads(-1)
+
+	header := &types.Header{Number: big.NewInt(1), Difficulty: big.NewInt(100)}
+	results := make(chan *types.Block, 1)
+	ethash.Seal(nil, types.NewBlockWithHeader(header), results, nil)
+
+	conn, err := net.Dial("tcp", ethash.remote.stratum.Addr().String())
+	if err != nil {
+		t.Fatalf("failed to connect to stratum server: %v", err)
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(10 * time.Second))
+
+	reader := bufio.NewReader(conn)
+	call := func(id int, method string, params ...string) {
+		blob, _ := json.Marshal(map[string]interface{}{"id": id, "method": method, "params": params})
+		if _, err := conn.Write(append(blob, '\n')); err != nil {
+			t.Fatalf("failed to send %s: %v", method, err)
+		}
+	}
+	read := func() (msg struct {
+		Method string
+		Result interface{}
+		Params []interface{}
+	}) {
+		line, err := reader.ReadBytes('\n')
+		if err != nil {
+			t.Fatalf("failed to read message: %v", err)
+		}
+		if err := json.Unmarshal(line, &msg); err != nil {
+			t.Fatalf("invalid message %s: %v", line, err)
+		}
+		return msg
+	}
+	call(1, "mining.subscribe", "test", stratum.ProtocolV1)
+	extranonce := read().Result.([]interface{})[1].(string)
+	call(2, "mining.authorize", "rig", "x")
+	if msg := read(); msg.Result != true {
+		t.Fatalf("authorization failed: %v", msg)
+	}
+	read() // mining.set_difficulty
+	work := read()
+	if sealhash := ethash.SealHash(header); work.Method != "mining.notify" || work.Params[2] != hex.EncodeToString(sealhash[:]) {
+		t.Fatalf("unexpected work: %v", work)
+	}
+
+	// Search a solution within the extranonce of the connection
+	prefix, _ := strconv.ParseUint(extranonce, 16, 64)
+	var (
+		sealhash = ethash.SealHash(header).Bytes()
+		cache    = ethash.cache(1)
+		target   = new(big.Int).Div(two256, header.Difficulty)
+		nonce    = prefix << 48
+	)
+	for ; ; nonce++ {
+		_, result := hashimotoLight(32*1024, cache.cache, sealhash, nonce)
+		if new(big.Int).SetBytes(result).Cmp(target) <= 0 {
+			break
+		}
+	}
+	call(3, "mining.submit", "rig", work.Params[0].(string), fmt.Sprintf("%012x", nonce&(1<<48-1)))
+	if msg := read(); msg.Result != true {
+		t.Fatalf("solution rejected: %v", msg)
+	}
+	select {
+	case block := <-results:
+		if block.Nonce() != nonce {
+			t.Errorf("block nonce mismatch: have %x, want %x", block.Nonce(), nonce)
+		}
+		if err := ethash.verifySeal(nil, block.Header(), false); err != nil {
+			t.Errorf("invalid seal: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("sealed block timeout")
+	}
+	workers, err := (&API{ethash}).GetStratumWorkers()
+	if err != nil || len(workers) != 1 || workers[0].Blocks != 1 {
+		t.Fatalf("unexpected workers: %v, %v", workers, err)
+	}
+	if ethash.Hashrate() == 0 {
+		t.Error("stratum hashrate not accounted")
+	}
+}
@@ -1,3 +1,5 @@
+//go:build cgo && !purego
+
 /**
  * A simple implementation of Blake2b's internal permutation
  * in the form of a sponge.
@@ -5,6 +5,7 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/consensus/stratum"
 	"github.com/ethereum/go-ethereum/core/types"
 )
 
@@ -95,3 +96,12 @@ func (api *API) SubmitHashRate(rate hexutil.Uint64, id common.Hash) bool {
 func (api *API) GetHashrate() uint64 {
 	return uint64(api.lyra2.Hashrate())
 }
+
+// GetStratumWorkers returns the statistics of the workers mining through the
+// stratum server.
+func (api *API) GetStratumWorkers() ([]stratum.WorkerStats, error) {
+	if api.lyra2.remote == nil || api.lyra2.remote.stratum == nil {
+		return nil, errors.New("stratum server not running")
+	}
+	return api.lyra2.remote.stratum.Workers(), nil
+}
@@ -11,6 +11,8 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/consensus"
 	"github.com/ethereum/go-ethereum/consensus/misc"
+	"github.com/ethereum/go-ethereum/consensus/misc/eip1559"
+	"github.com/ethereum/go-ethereum/consensus/misc/eip4844"
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/params/mutations"
@@ -18,20 +20,46 @@ import (
 	"github.com/ethereum/go-ethereum/params/vars"
 	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/ethereum/go-ethereum/trie"
-	"github.com/holiman/uint256"
 	"golang.org/x/crypto/sha3"
 )
 
 var (
 	maxUncles              = 2                // Maximum number of uncles allowed in a single block
+	maxUncleDepth          = 7                // Maximum depth of an uncle relative to the including block
 	allowedFutureBlockTime = 15 * time.Second // Max time from current time allowed for blocks, before they're considered future blocks
 
-	two256                   = new(big.Int).Exp(big.NewInt(2), big.NewInt(256), big.NewInt(0))
-	big32                    = big.NewInt(32)
-	DisinflationRateQuotient = big.NewInt(249)
-	DisinflationRateDivisor  = big.NewInt(250)
+	haloMaxUncles              = 1                // Maximum number of uncles allowed in a single block under the Halo uncle rules
+	haloMaxUncleDepth          = 2                // Maximum uncle depth under the Halo uncle rules
+	haloAllowedFutureBlockTime = 30 * time.Second // Max future block time under the Halo rules
+
+	two256 = new(big.Int).Exp(big.NewInt(2), big.NewInt(256), big.NewInt(0))
 )
 
+// getAllowedFutureBlockTime returns the maximum time a block can be in the future
+// before being rejected as invalid.
+func getAllowedFutureBlockTime(config ctypes.ChainConfigurator, number *big.Int) time.Duration {
+	if config.IsEnabled(config.GetHaloFutureBlockTimeTransition, number) {
+		return haloAllowedFutureBlockTime
+	}
+	return allowedFutureBlockTime
+}
+
+// getMaxUncles returns the maximum number of uncles allowed in the block with the given number.
+func getMaxUncles(config ctypes.ChainConfigurator, number *big.Int) int {
+	if config.IsEnabled(config.GetHaloUnclesTransition, number) {
+		return haloMaxUncles
+	}
+	return maxUncles
+}
+
+// getMaxUncleDepth returns the maximum depth of uncles included in the block with the given number.
+func getMaxUncleDepth(config ctypes.ChainConfigurator, number *big.Int) int {
+	if config.IsEnabled(config.GetHaloUnclesTransition, number) {
+		return haloMaxUncleDepth
+	}
+	return maxUncleDepth
+}
+
 // Various error messages to mark blocks invalid. These should be private to
 // prevent engine specific errors from being referenced in the remainder of the
 // codebase, inherently breaking if the engine is swapped out. Please put common
@@ -42,6 +70,7 @@ var (
 	errDuplicateUncle    = errors.New("duplicate uncle")
 	errUncleIsAncestor   = errors.New("uncle is ancestor")
 	errDanglingUncle     = errors.New("uncle's parent is not ancestor")
+	errUncleTooDeep      = errors.New("uncle depth exceeds maximum allowed")
 	errInvalidDifficulty = errors.New("non-positive difficulty")
 	errInvalidPoW        = errors.New("invalid proof-of-work")
 )
@@ -160,8 +189,12 @@ func (lyra2 *Lyra2) VerifyUncles(chain consensus.ChainReader, block *types.Block
 	if lyra2.fakeMode {
 		return nil
 	}
-	// Verify that there are at most 2 uncles included in this block
-	if len(block.Uncles()) > maxUncles {
+	// Get fork-specific uncle parameters
+	maxUnclesForChain := getMaxUncles(chain.Config(), block.Number())
+	maxUncleDepthForChain := getMaxUncleDepth(chain.Config(), block.Number())
+
+	// Verify that there are at most maxUnclesForChain uncles included in this block
+	if len(block.Uncles()) > maxUnclesForChain {
 		return errTooManyUncles
 	}
 	if len(block.Uncles()) == 0 {
@@ -171,7 +204,7 @@ func (lyra2 *Lyra2) VerifyUncles(chain consensus.ChainReader, block *types.Block
 	uncles, ancestors := mapset.NewSet(), make(map[common.Hash]*types.Header)
 
 	number, parent := block.NumberU64()-1, block.ParentHash()
-	for i := 0; i < 7; i++ {
+	for i := 0; i < maxUncleDepthForChain; i++ {
 		ancestor := chain.GetBlock(parent, number)
 		if ancestor == nil {
 			break
@@ -201,6 +234,13 @@ func (lyra2 *Lyra2) VerifyUncles(chain consensus.ChainReader, block *types.Block
 		if ancestors[uncle.ParentHash] == nil || uncle.ParentHash == block.ParentHash() {
 			return errDanglingUncle
 		}
+		// Verify the uncle depth doesn't exceed the chain-specific maximum
+		if uncle.Number.Cmp(block.Number()) >= 0 {
+			return errUncleIsAncestor
+		}
+		if depth := new(big.Int).Sub(block.Number(), uncle.Number).Uint64(); depth > uint64(maxUncleDepthForChain) {
+			return errUncleTooDeep
+		}
 		if err := lyra2.verifyHeader(chain, uncle, ancestors[uncle.ParentHash], true, true); err != nil {
 			return err
 		}
@@ -218,13 +258,19 @@ func (lyra2 *Lyra2) verifyHeader(chain consensus.ChainHeaderReader, header, pare
 	}
 	// Verify the header's timestamp
 	if !uncle {
-		if header.Time > uint64(time.Now().Add(allowedFutureBlockTime).Unix()) {
+		if header.Time > uint64(time.Now().Add(getAllowedFutureBlockTime(chain.Config(), header.Number)).Unix()) {
 			return consensus.ErrFutureBlock
 		}
 	}
 	if header.Time <= parent.Time {
 		return errOlderBlockTime
 	}
+	// Halo timestamp validations
+	if !uncle && chain.Config().IsEnabled(chain.Config().GetHaloMedianTimePastTransition, header.Number) {
+		if err := misc.VerifyMedianTimePast(chain, header, parent); err != nil {
+			return err
+		}
+	}
 	// Verify the block's difficulty based on its timestamp and parent's difficulty
 	expected := lyra2.CalcDifficulty(chain, header.Time, parent)
 
@@ -232,29 +278,74 @@ func (lyra2 *Lyra2) verifyHeader(chain consensus.ChainHeaderReader, header, pare
 		return fmt.Errorf("invalid difficulty: have %v, want %v", header.Difficulty, expected)
 	}
 	// Verify that the gas limit is <= 2^63-1
-	cap := uint64(0x7fffffffffffffff)
-	if header.GasLimit > cap {
-		return fmt.Errorf("invalid gasLimit: have %v, max %v", header.GasLimit, cap)
+	if header.GasLimit > vars.MaxGasLimit {
+		return fmt.Errorf("invalid gasLimit: have %v, max %v", header.GasLimit, vars.MaxGasLimit)
 	}
 	// Verify that the gasUsed is <= gasLimit
 	if header.GasUsed > header.GasLimit {
 		return fmt.Errorf("invalid gasUsed: have %d, gasLimit %d", header.GasUsed, header.GasLimit)
 	}
+	// Verify that the block number is parent's +1
+	if diff := new(big.Int).Sub(header.Number, parent.Number); diff.Cmp(big.NewInt(1)) != 0 {
+		return consensus.ErrInvalidNumber
+	}
+	// Verify the block's gas usage and (if applicable) verify the base fee.
+	if !chain.Config().IsEnabled(chain.Config().GetEIP1559Transition, header.Number) {
+		// Verify BaseFee not present before EIP-1559 fork.
+		if header.BaseFee != nil {
+			return fmt.Errorf("invalid baseFee before fork: have %d, expected 'nil'", header.BaseFee)
+		}
+		if err := misc.VerifyGaslimit(parent.GasLimit, header.GasLimit); err != nil {
+			return err
+		}
+	} else if err := eip1559.VerifyEIP1559Header(chain.Config(), parent, header); err != nil {
+		// Verify the header's EIP-1559 attributes.
+		return err
+	}
 
-	// Verify that the gas limit remains within allowed bounds
-	diff := int64(parent.GasLimit) - int64(header.GasLimit)
-	if diff < 0 {
-		diff *= -1
+	// Shanghai
+	// EIP-4895: Beacon chain push withdrawals as operations
+	// Verify the non-existence of withdrawalsHash (EIP-4895: Beacon chain push withdrawals as operations).
+	eip4895Enabled := chain.Config().IsEnabledByTime(chain.Config().GetEIP4895TransitionTime, &header.Time) || chain.Config().IsEnabled(chain.Config().GetEIP4895Transition, header.Number)
+	if !eip4895Enabled {
+		if header.WithdrawalsHash != nil {
+			return fmt.Errorf("invalid withdrawalsHash: have %x, expected nil", header.WithdrawalsHash)
+		}
+	} else {
+		if header.WithdrawalsHash == nil {
+			return errors.New("header is missing withdrawalsHash")
+		}
 	}
-	limit := parent.GasLimit / vars.GasLimitBoundDivisor
 
-	if uint64(diff) >= limit || header.GasLimit < vars.MinGasLimit {
-		return fmt.Errorf("invalid gas limit: have %d, want %d += %d", header.GasLimit, parent.GasLimit, limit)
+	// Cancun
+	// EIP-4844: Shard Blob Txes
+	// EIP-4788: Beacon block root in the EVM
+	eip4844Enabled := chain.Config().IsEnabledByTime(chain.Config().GetEIP4844TransitionTime, &header.Time) || chain.Config().IsEnabled(chain.Config().GetEIP4844Transition, header.Number)
+	if !eip4844Enabled {
+		switch {
+		case header.ExcessBlobGas != nil:
+			return fmt.Errorf("invalid excessBlobGas: have %d, expected nil", header.ExcessBlobGas)
+		case header.BlobGasUsed != nil:
+			return fmt.Errorf("invalid blobGasUsed: have %d, expected nil", header.BlobGasUsed)
+		}
+	} else {
+		if err := eip4844.VerifyEIP4844Header(parent, header); err != nil {
+			return err
+		}
 	}
-	// Verify that the block number is parent's +1
-	if diff := new(big.Int).Sub(header.Number, parent.Number); diff.Cmp(big.NewInt(1)) != 0 {
-		return consensus.ErrInvalidNumber
+
+	// EIP-4788: Beacon block root in the EVM
+	eip4788Enabled := chain.Config().IsEnabledByTime(chain.Config().GetEIP4788TransitionTime, &header.Time) || chain.Config().IsEnabled(chain.Config().GetEIP4788Transition, header.Number)
+	if !eip4788Enabled {
+		if header.ParentBeaconRoot != nil {
+			return fmt.Errorf("invalid parentBeaconRoot, have %#x, expected nil", header.ParentBeaconRoot)
+		}
+	} else {
+		if header.ParentBeaconRoot == nil {
+			return errors.New("header is missing beaconRoot")
+		}
 	}
+
 	// Verify the engine specific seal securing the block
 	if seal {
 		if err := lyra2.VerifySeal(chain, header); err != nil {
@@ -287,8 +378,25 @@ var (
 
 	DifficultyBoundDivisor = big.NewInt(200)   // The bound divisor of the difficulty, used in the update calculations.
 	MinimumDifficulty      = big.NewInt(10000) // The minimum that the difficulty may ever be.
+	DurationLimit          = big9              // The block time (seconds) above which the difficulty decreases.
 )
 
+// difficultyParams returns the difficulty parameters of the chain, defaulting
+// to those of the MINTME network.
+func difficultyParams(config ctypes.ChainConfigurator) (minimum, boundDivisor, durationLimit *big.Int) {
+	minimum, boundDivisor, durationLimit = MinimumDifficulty, DifficultyBoundDivisor, DurationLimit
+	if v := config.GetLyra2MinimumDifficulty(); v != nil {
+		minimum = v
+	}
+	if v := config.GetLyra2DifficultyBoundDivisor(); v != nil && v.Sign() > 0 {
+		boundDivisor = v
+	}
+	if v := config.GetLyra2DurationLimit(); v != nil && v.Sign() > 0 {
+		durationLimit = v
+	}
+	return minimum, boundDivisor, durationLimit
+}
+
 // CalcDifficulty is the difficulty adjustment algorithm. It returns
 // the difficulty that a new block should have when created at time
 // given the parent block's time and difficulty.
@@ -298,6 +406,9 @@ func CalcDifficulty(config ctypes.ChainConfigurator, time uint64, parent *types.
 	// diff = (parent_diff +
 	//         (parent_diff / 200 * max((2 if len(parent.uncles) else 1) - ((timestamp - parent.timestamp) // 9), -20))
 	//        ) + 2^(periodCount - 2)
+	// The minimum difficulty, the bound divisor (200) and the duration limit (9)
+	// are configured by the chain.
+	minimum, boundDivisor, durationLimit := difficultyParams(config)
 
 	bigTime := new(big.Int).SetUint64(time)
 	bigParentTime := new(big.Int).SetUint64(parent.Time)
@@ -308,7 +419,7 @@ func CalcDifficulty(config ctypes.ChainConfigurator, time uint64, parent *types.
 
 	// (2 if len(parent_uncles) else 1) - (block_timestamp - parent_timestamp) // 9
 	x.Sub(bigTime, bigParentTime)
-	x.Div(x, big9)
+	x.Div(x, durationLimit)
 	if parent.UncleHash == types.EmptyUncleHash {
 		x.Sub(big1, x)
 	} else {
@@ -319,13 +430,13 @@ func CalcDifficulty(config ctypes.ChainConfigurator, time uint64, parent *types.
 		x.Set(bigMinus20)
 	}
 	// parent_diff + (parent_diff / 200 * max((2 if len(parent.uncles) else 1) - ((timestamp - parent.timestamp) // 9), -20))
-	y.Div(parent.Difficulty, DifficultyBoundDivisor)
+	y.Div(parent.Difficulty, boundDivisor)
 	x.Mul(y, x)
 	x.Add(parent.Difficulty, x)
 
 	// minimum difficulty can ever be (before exponential factor)
-	if x.Cmp(MinimumDifficulty) < 0 {
-		x.Set(MinimumDifficulty)
+	if x.Cmp(minimum) < 0 {
+		x.Set(minimum)
 	}
 	return x
 }
@@ -359,7 +470,17 @@ func (lyra2 *Lyra2) verifySeal(chain consensus.ChainHeaderReader, header *types.
 	if err != nil {
 		return err
 	}
-	result := lyra2.calcHash(headerBytes, header.Nonce.Uint64(), 1)
+	// The remote sealer verifies its own work without a chain, with the time
+	// cost of the chain being sealed
+	var tcost int
+	if chain != nil {
+		tcost = chainTCost(chain.Config())
+	} else {
+		lyra2.lock.Lock()
+		tcost = lyra2.tcost
+		lyra2.lock.Unlock()
+	}
+	result := lyra2.calcHash(headerBytes, header.Nonce.Uint64(), tcost)
 
 	target := new(big.Int).Div(two256, header.Difficulty)
 	if result.Cmp(target) > 0 {
@@ -379,19 +500,29 @@ func (lyra2 *Lyra2) Prepare(chain consensus.ChainHeaderReader, header *types.Hea
 	return nil
 }
 
-// Finalize implements consensus.Engine, accumulating the block and uncle rewards,
-// setting the final state on the header
+// Finalize implements consensus.Engine, accumulating the block and uncle rewards.
 func (lyra2 *Lyra2) Finalize(chain consensus.ChainHeaderReader, header *types.Header, state *state.StateDB, txs []*types.Transaction, uncles []*types.Header, withdrawals []*types.Withdrawal) {
-	// Accumulate any block and uncle rewards and commit the final state root
-	accumulateRewards(chain.Config(), state, header, uncles)
-	header.Root = state.IntermediateRoot(chain.Config().IsEnabled(chain.Config().GetEIP161dTransition, header.Number))
+	// Accumulate any block and uncle rewards
+	mutations.AccumulateRewards(chain.Config(), state, header, uncles)
+
+	// Apply Halo EIP-1559 fee distribution if activated
+	if eip1559.IsHaloBaseFeeDistributed(chain.Config(), header) {
+		if err := eip1559.ApplyHaloBaseFeeDistribution(chain.Config(), state, header, header.BaseFee, header.GasUsed); err != nil {
+			panic(fmt.Sprintf("failed to apply Halo EIP-1559 distribution: %v", err))
+		}
+	}
 }
 
 // FinalizeAndAssemble implements consensus.Engine, accumulating the block and
 // uncle rewards, setting the final state and assembling the block.
 func (lyra2 *Lyra2) FinalizeAndAssemble(chain consensus.ChainHeaderReader, header *types.Header, state *state.StateDB, txs []*types.Transaction, uncles []*types.Header, receipts []*types.Receipt, withdrawals []*types.Withdrawal) (*types.Block, error) {
-	// Accumulate any block and uncle rewards and commit the final state root
-	accumulateRewards(chain.Config(), state, header, uncles)
+	if len(withdrawals) > 0 {
+		return nil, errors.New("lyra2 does not support withdrawals")
+	}
+	// Finalize block
+	lyra2.Finalize(chain, header, state, txs, uncles, nil)
+
+	// Assign the final state root to header.
 	header.Root = state.IntermediateRoot(chain.Config().IsEnabled(chain.Config().GetEIP161dTransition, header.Number))
 
 	// Header seems complete, assemble into a block and return
@@ -402,7 +533,7 @@ func (lyra2 *Lyra2) FinalizeAndAssemble(chain consensus.ChainHeaderReader, heade
 func (lyra2 *Lyra2) SealHash(header *types.Header) (hash common.Hash) {
 	hasher := sha3.NewLegacyKeccak256()
 
-	rlp.Encode(hasher, []interface{}{
+	enc := []interface{}{
 		header.ParentHash,
 		header.UncleHash,
 		header.Coinbase,
@@ -416,73 +547,23 @@ func (lyra2 *Lyra2) SealHash(header *types.Header) (hash common.Hash) {
 		header.GasUsed,
 		header.Time,
 		header.Extra,
-	})
-	hasher.Sum(hash[:0])
-	return hash
-}
-
-// GetRewardByEra gets a block reward at disinflation rate.
-// Constants MaxBlockReward, DisinflationRateQuotient, and DisinflationRateDivisor assumed.
-func GetBlockWinnerRewardByEra(eraOrig *big.Int) *big.Int {
-	MaximumBlockReward := big.NewInt(5e+18)                    // 5 WEB
-	MaximumBlockReward.Mul(MaximumBlockReward, big.NewInt(10)) // 50 WEB
-
-	era := new(big.Int).Set(eraOrig)
-
-	if era.Cmp(big.NewInt(0)) == 0 {
-		return new(big.Int).Set(MaximumBlockReward)
 	}
-
-	era.Add(era, big.NewInt(865)) // skip 865 eras due to reward decrease
-
-	// MaxBlockReward _r_ * (249/250)**era == MaxBlockReward * (249**era) / (250**era)
-	// since (q/d)**n == q**n / d**n
-	// qed
-	var q, d, r *big.Int = new(big.Int), new(big.Int), new(big.Int)
-
-	q.Exp(DisinflationRateQuotient, era, nil)
-	d.Exp(DisinflationRateDivisor, era, nil)
-
-	r.Mul(MaximumBlockReward, q)
-	r.Div(r, d)
-
-	return r
-}
-
-// GetBlockEra gets which "Era" a given block is within, given an era length (100,000 blocks)
-// Returns a zero-index era number, so "Era 1": 0, "Era 2": 1, "Era 3": 2 ...
-func GetBlockEra(blockNum, eraLength *big.Int) *big.Int {
-	// If genesis block or impossible negative-numbered block, return zero-val.
-	if blockNum.Sign() < 1 {
-		return new(big.Int)
+	if header.BaseFee != nil {
+		enc = append(enc, header.BaseFee)
 	}
-
-	remainder := big.NewInt(0).Mod(big.NewInt(0).Sub(blockNum, big.NewInt(1)), eraLength)
-	base := big.NewInt(0).Sub(blockNum, remainder)
-
-	d := big.NewInt(0).Div(base, eraLength)
-	dremainder := big.NewInt(0).Mod(d, big.NewInt(1))
-
-	return new(big.Int).Sub(d, dremainder)
-}
-
-// Uncle miners and winners are rewarded equally for each included block.
-// So they share this function.
-func getEraUncleBlockReward(minerReward *big.Int) *big.Int {
-	return new(big.Int).Div(minerReward, big32)
-}
-
-// accumulateRewards credits the coinbase of the given block with the mining
-// reward. The coinbase of each uncle block is also rewarded.
-func accumulateRewards(config ctypes.ChainConfigurator, state *state.StateDB, header *types.Header, uncles []*types.Header) {
-	eraLen := big.NewInt(100000)
-	era := GetBlockEra(header.Number, eraLen)
-	era = era.Add(era, big.NewInt(72))
-
-	minerReward := GetBlockWinnerRewardByEra(era)
-	uncleReward := getEraUncleBlockReward(minerReward)
-	for _, uncle := range uncles {
-		state.AddBalance(uncle.Coinbase, uint256.MustFromBig(uncleReward))
+	if header.WithdrawalsHash != nil {
+		panic("withdrawal hash set on lyra2")
 	}
-	state.AddBalance(header.Coinbase, uint256.MustFromBig(minerReward))
+	if header.ExcessBlobGas != nil {
+		panic("excess blob gas set on lyra2")
+	}
+	if header.BlobGasUsed != nil {
+		panic("blob gas used set on lyra2")
+	}
+	if header.ParentBeaconRoot != nil {
+		panic("parent beacon root set on lyra2")
+	}
+	rlp.Encode(hasher, enc)
+	hasher.Sum(hash[:0])
+	return hash
 }
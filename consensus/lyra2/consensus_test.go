@@ -0,0 +1,349 @@
+package lyra2
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/params/mutations"
+	"github.com/ethereum/go-ethereum/params/types/coregeth"
+	"github.com/ethereum/go-ethereum/params/types/ctypes"
+	"github.com/ethereum/go-ethereum/params/types/genesisT"
+	"github.com/ethereum/go-ethereum/params/vars"
+)
+
+// Tests that a lyra2 chain with the London forks enabled is built and verified
+// with the EIP-1559 rules.
+func TestLondonChain(t *testing.T) {
+	config := *params.MintMeChainConfig
+	config.Lyra2 = new(ctypes.Lyra2Config)
+	for _, block := range []**big.Int{
+		&config.EIP2565FBlock, &config.EIP2718FBlock, &config.EIP2929FBlock, &config.EIP2930FBlock,
+		&config.EIP1559FBlock, &config.EIP3198FBlock, &config.EIP3529FBlock, &config.EIP3541FBlock,
+	} {
+		*block = big.NewInt(0)
+	}
+	var (
+		key, _ = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		sender = crypto.PubkeyToAddress(key.PublicKey)
+		miner  = common.HexToAddress("0xaaaa")
+		to     = common.HexToAddress("0xcccc")
+		signer = types.LatestSigner(&config)
+		gspec  = &genesisT.Genesis{
+			Config:   &config,
+			GasLimit: 8_000_000,
+			Alloc:    genesisT.GenesisAlloc{sender: {Balance: big.NewInt(vars.Ether)}},
+		}
+	)
+	engine := NewTester(nil, false)
+	defer engine.Close()
+
+	_, blocks, receipts := core.GenerateChainWithGenesis(gspec, engine, 2, func(i int, b *core.BlockGen) {
+		b.SetCoinbase(miner)
+		tx, _ := types.SignTx(types.NewTx(&types.DynamicFeeTx{
+			ChainID:   config.GetChainID(),
+			Nonce:     uint64(i),
+			GasTipCap: big.NewInt(vars.GWei),
+			GasFeeCap: big.NewInt(10 * vars.GWei),
+			Gas:       21_000,
+			To:        &to,
+			Value:     big.NewInt(1),
+		}), signer, key)
+		b.AddTx(tx)
+	})
+	// Insert the chain without checking the seals, which the generator doesn't compute
+	faker := New(&Config{FakeMode: true}, nil, false)
+	defer faker.Close()
+	chain, _ := core.NewBlockChain(rawdb.NewMemoryDatabase(), nil, gspec, nil, faker, vm.Config{}, nil, nil)
+	defer chain.Stop()
+	if _, err := chain.InsertChain(blocks); err != nil {
+		t.Fatalf("failed to insert chain: %v", err)
+	}
+	parent := chain.Genesis().Header()
+	for i, block := range blocks {
+		header := block.Header()
+		if header.BaseFee == nil {
+			t.Fatalf("block %d: missing base fee", i+1)
+		}
+		if err := engine.verifyHeader(chain, header, parent, false, false); err != nil {
+			t.Fatalf("block %d: verification failed: %v", i+1, err)
+		}
+		// The base fee must follow the parent
+		header.BaseFee = new(big.Int).Add(header.BaseFee, common.Big1)
+		if err := engine.verifyHeader(chain, header, parent, false, false); err == nil {
+			t.Errorf("block %d: invalid base fee accepted", i+1)
+		}
+		header.BaseFee = nil
+		if err := engine.verifyHeader(chain, header, parent, false, false); err == nil {
+			t.Errorf("block %d: missing base fee accepted", i+1)
+		}
+		parent = block.Header()
+	}
+	// The miner gets the block rewards and the tips, the base fees are burned
+	want := new(big.Int)
+	for i, block := range blocks {
+		reward, _ := mutations.GetRewards(&config, block.Header(), nil)
+		want.Add(want, reward.ToBig())
+		want.Add(want, new(big.Int).Mul(big.NewInt(vars.GWei), new(big.Int).SetUint64(receipts[i][0].GasUsed)))
+	}
+	state, _ := chain.State()
+	if have := state.GetBalance(miner).ToBig(); have.Cmp(want) != 0 {
+		t.Errorf("miner balance mismatch: have %v, want %v", have, want)
+	}
+}
+
+// Tests that the seal hash and the hashed header commit to the base fee.
+func TestSealHashBaseFee(t *testing.T) {
+	engine := NewTester(nil, false)
+	defer engine.Close()
+
+	header := &types.Header{Number: big.NewInt(1), Difficulty: big.NewInt(100)}
+	legacy := engine.SealHash(header)
+
+	header.BaseFee = big.NewInt(vars.InitialBaseFee)
+	if engine.SealHash(header) == legacy {
+		t.Error("seal hash doesn't commit to the base fee")
+	}
+	enc, _ := engine.headerBytes(header)
+	header.BaseFee = new(big.Int).Add(header.BaseFee, common.Big1)
+	if enc2, _ := engine.headerBytes(header); string(enc) == string(enc2) {
+		t.Error("hashed header doesn't commit to the base fee")
+	}
+}
+
+// Tests that the difficulty parameters are sourced from the chain configuration.
+func TestCalcDifficultyConfig(t *testing.T) {
+	config := *params.MintMeChainConfig
+	config.Lyra2 = new(ctypes.Lyra2Config)
+
+	parent := &types.Header{Number: big.NewInt(1), Time: 100, Difficulty: big.NewInt(2_000_000), UncleHash: types.EmptyUncleHash}
+	if have, want := CalcDifficulty(&config, 105, parent), big.NewInt(2_010_000); have.Cmp(want) != 0 {
+		t.Errorf("default difficulty mismatch: have %v, want %v", have, want)
+	}
+	config.Lyra2.DifficultyBoundDivisor = big.NewInt(100)
+	config.Lyra2.DurationLimit = big.NewInt(3)
+	config.Lyra2.MinimumDifficulty = big.NewInt(1_950_000)
+
+	// 2000000 + 2000000/100 * max(1 - 5/3, -20) = 2000000
+	if have, want := CalcDifficulty(&config, 105, parent), big.NewInt(2_000_000); have.Cmp(want) != 0 {
+		t.Errorf("configured difficulty mismatch: have %v, want %v", have, want)
+	}
+	// 2000000 + 2000000/100 * max(1 - 9/3, -20) = 1960000, floored
+	if have, want := CalcDifficulty(&config, 109, parent), big.NewInt(1_960_000); have.Cmp(want) != 0 {
+		t.Errorf("configured difficulty mismatch: have %v, want %v", have, want)
+	}
+	if have, want := CalcDifficulty(&config, 200, parent), big.NewInt(1_950_000); have.Cmp(want) != 0 {
+		t.Errorf("minimum difficulty mismatch: have %v, want %v", have, want)
+	}
+}
+
+// testChain is a minimal in-memory consensus.ChainReader.
+type testChain struct {
+	config    ctypes.ChainConfigurator
+	blocks    map[common.Hash]*types.Block
+	canonical map[uint64]*types.Block
+}
+
+func newTestChain(config ctypes.ChainConfigurator, genesis *types.Header) *testChain {
+	chain := &testChain{
+		config:    config,
+		blocks:    make(map[common.Hash]*types.Block),
+		canonical: make(map[uint64]*types.Block),
+	}
+	chain.insert(types.NewBlockWithHeader(genesis))
+	return chain
+}
+
+func (c *testChain) insert(block *types.Block) {
+	c.blocks[block.Hash()] = block
+	c.canonical[block.NumberU64()] = block
+}
+
+// makeHeader creates a child of parent, mined blockTime seconds later.
+func (c *testChain) makeHeader(parent *types.Header, blockTime uint64, extra string) *types.Header {
+	header := &types.Header{
+		ParentHash: parent.Hash(),
+		UncleHash:  types.EmptyUncleHash,
+		Number:     new(big.Int).Add(parent.Number, common.Big1),
+		GasLimit:   parent.GasLimit,
+		Time:       parent.Time + blockTime,
+		Extra:      []byte(extra),
+	}
+	header.Difficulty = CalcDifficulty(c.config, header.Time, parent)
+	return header
+}
+
+func (c *testChain) Config() ctypes.ChainConfigurator { return c.config }
+func (c *testChain) CurrentHeader() *types.Header {
+	return c.canonical[uint64(len(c.canonical)-1)].Header()
+}
+func (c *testChain) GetHeader(hash common.Hash, number uint64) *types.Header {
+	if block := c.GetBlock(hash, number); block != nil {
+		return block.Header()
+	}
+	return nil
+}
+func (c *testChain) GetHeaderByNumber(number uint64) *types.Header {
+	if block := c.canonical[number]; block != nil {
+		return block.Header()
+	}
+	return nil
+}
+func (c *testChain) GetHeaderByHash(hash common.Hash) *types.Header {
+	if block := c.blocks[hash]; block != nil {
+		return block.Header()
+	}
+	return nil
+}
+func (c *testChain) GetTd(hash common.Hash, number uint64) *big.Int { return nil }
+func (c *testChain) GetBlock(hash common.Hash, number uint64) *types.Block {
+	if block := c.blocks[hash]; block != nil && block.NumberU64() == number {
+		return block
+	}
+	return nil
+}
+
+// haloTestConfig returns a lyra2 chain configuration with a difficulty low
+// enough for any seal to be valid.
+func haloTestConfig() *coregeth.CoreGethChainConfig {
+	return &coregeth.CoreGethChainConfig{
+		ChainID: big.NewInt(1337),
+		Lyra2:   &ctypes.Lyra2Config{MinimumDifficulty: big.NewInt(1)},
+	}
+}
+
+func haloTestGenesis(time uint64) *types.Header {
+	return &types.Header{
+		Number:     new(big.Int),
+		UncleHash:  types.EmptyUncleHash,
+		Difficulty: big.NewInt(1),
+		GasLimit:   8_000_000,
+		Time:       time,
+	}
+}
+
+// Tests that a header 20s ahead of the local clock is only accepted from the
+// Halo future block time fork.
+func TestHaloFutureBlockTime(t *testing.T) {
+	engine := NewTester(nil, false)
+	defer engine.Close()
+
+	for _, c := range []struct {
+		fork uint64
+		want error
+	}{
+		{fork: 2, want: consensus.ErrFutureBlock},
+		{fork: 1, want: nil},
+	} {
+		config := haloTestConfig()
+		config.SetHaloFutureBlockTimeTransition(&c.fork)
+
+		genesis := haloTestGenesis(uint64(time.Now().Unix()))
+		chain := newTestChain(config, genesis)
+		header := chain.makeHeader(genesis, 20, "")
+
+		if err := engine.verifyHeader(chain, header, genesis, false, false); err != c.want {
+			t.Errorf("fork at %d: have %v, want %v", c.fork, err, c.want)
+		}
+	}
+}
+
+// Tests that a side chain header older than the median time past of the
+// canonical chain is only rejected from the Halo MTP fork.
+func TestHaloMedianTimePast(t *testing.T) {
+	engine := NewTester(nil, false)
+	defer engine.Close()
+
+	for _, c := range []struct {
+		fork    uint64
+		invalid bool
+	}{
+		{fork: 22, invalid: false},
+		{fork: 21, invalid: true},
+	} {
+		config := haloTestConfig()
+		config.SetHaloMedianTimePastTransition(&c.fork)
+
+		genesis := haloTestGenesis(1600000000)
+		chain := newTestChain(config, genesis)
+
+		// Canonical chain of 20 slow blocks, and a side chain of fast blocks
+		// forking off at block 10
+		var fork, parent *types.Header = nil, genesis
+		for i := 1; i <= 20; i++ {
+			parent = chain.makeHeader(parent, 100, "")
+			chain.insert(types.NewBlockWithHeader(parent))
+			if i == 10 {
+				fork = parent
+			}
+		}
+		parent = fork
+		for i := 11; i <= 20; i++ {
+			parent = chain.makeHeader(parent, 1, "")
+			chain.blocks[parent.Hash()] = types.NewBlockWithHeader(parent)
+		}
+		header := chain.makeHeader(parent, 1, "")
+
+		err := engine.verifyHeader(chain, header, parent, false, false)
+		if c.invalid && err == nil {
+			t.Errorf("fork at %d: header older than the median time past accepted", c.fork)
+		}
+		if !c.invalid && err != nil {
+			t.Errorf("fork at %d: unexpected error: %v", c.fork, err)
+		}
+	}
+}
+
+// Tests that the number and the depth of the uncles are only limited by the
+// Halo uncle rules from their fork.
+func TestHaloUncles(t *testing.T) {
+	engine := NewTester(nil, false)
+	defer engine.Close()
+
+	for _, c := range []struct {
+		fork uint64
+		halo bool
+	}{
+		{fork: 6, halo: false},
+		{fork: 3, halo: true},
+	} {
+		config := haloTestConfig()
+		config.SetHaloUnclesTransition(&c.fork)
+
+		genesis := haloTestGenesis(1600000000)
+		chain := newTestChain(config, genesis)
+
+		// Canonical chain of 4 blocks and two siblings of block 2
+		headers := []*types.Header{genesis}
+		for i := 1; i <= 4; i++ {
+			headers = append(headers, chain.makeHeader(headers[i-1], 10, ""))
+			chain.insert(types.NewBlockWithHeader(headers[i]))
+		}
+		uncles := []*types.Header{
+			chain.makeHeader(headers[1], 10, "uncle 1"),
+			chain.makeHeader(headers[1], 10, "uncle 2"),
+		}
+		// Two uncles of depth 1
+		block := types.NewBlockWithHeader(chain.makeHeader(headers[2], 10, "two uncles")).WithBody(nil, uncles)
+		if err := engine.VerifyUncles(chain, block); c.halo && err != errTooManyUncles {
+			t.Errorf("fork at %d: two uncles: have %v, want %v", c.fork, err, errTooManyUncles)
+		} else if !c.halo && err != nil {
+			t.Errorf("fork at %d: two uncles: unexpected error: %v", c.fork, err)
+		}
+		// One uncle of depth 3
+		block = types.NewBlockWithHeader(chain.makeHeader(headers[4], 10, "deep uncle")).WithBody(nil, uncles[:1])
+		if err := engine.VerifyUncles(chain, block); c.halo && err == nil {
+			t.Errorf("fork at %d: uncle of depth 3 accepted", c.fork)
+		} else if !c.halo && err != nil {
+			t.Errorf("fork at %d: deep uncle: unexpected error: %v", c.fork, err)
+		}
+	}
+}
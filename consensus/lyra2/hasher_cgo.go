@@ -0,0 +1,46 @@
+//go:build cgo && !purego
+
+package lyra2
+
+/*
+#cgo CFLAGS: -std=gnu99
+#include "Lyra2.h"
+*/
+import "C"
+import (
+	"runtime"
+	"unsafe"
+)
+
+// cHasher computes Lyra2 hashes through the reference C implementation.
+type cHasher struct {
+	ctx unsafe.Pointer
+}
+
+// newCHasher allocates a C Lyra2 context, freed when the hasher is closed or
+// garbage collected.
+func newCHasher() *cHasher {
+	h := &cHasher{ctx: C.LYRA2_create()}
+	runtime.SetFinalizer(h, (*cHasher).close)
+	return h
+}
+
+// hash implements hasher.
+func (h *cHasher) hash(out []byte, in []byte, tcost int) {
+	C.LYRA2(h.ctx, unsafe.Pointer(&out[0]), C.int64_t(len(out)), unsafe.Pointer(&in[0]), C.int32_t(len(in)), C.int32_t(tcost))
+}
+
+// close implements hasher, releasing the C memory matrix.
+func (h *cHasher) close() {
+	if h.ctx != nil {
+		C.LYRA2_destroy(h.ctx)
+		h.ctx = nil
+	}
+	runtime.SetFinalizer(h, nil)
+}
+
+// newHasher creates a Lyra2 context using the C implementation, which is
+// selected whenever cgo is available. Build with the purego tag to opt out.
+func newHasher() hasher {
+	return newCHasher()
+}
@@ -0,0 +1,35 @@
+//go:build cgo && !purego
+
+package lyra2
+
+import (
+	"bytes"
+	"math/rand"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Tests that the pure Go hasher matches the C implementation on random inputs
+// with reused contexts.
+func TestHasherDifferential(t *testing.T) {
+	var (
+		rng  = rand.New(rand.NewSource(1))
+		c    = newCHasher()
+		pure = newGoHasher()
+	)
+	defer c.close()
+
+	for i := 0; i < 16; i++ {
+		in := make([]byte, 1+rng.Intn(1024))
+		rng.Read(in)
+		tcost := 1 + rng.Intn(3)
+
+		want, have := make([]byte, common.HashLength), make([]byte, common.HashLength)
+		c.hash(want, in, tcost)
+		pure.hash(have, in, tcost)
+		if !bytes.Equal(have, want) {
+			t.Fatalf("input %x, tcost %d: hash mismatch: have %x, want %x", in, tcost, have, want)
+		}
+	}
+}
@@ -0,0 +1,9 @@
+//go:build !cgo || purego
+
+package lyra2
+
+// newHasher creates a Lyra2 context using the pure Go implementation, which is
+// selected when cgo is disabled or the purego tag is set.
+func newHasher() hasher {
+	return newGoHasher()
+}
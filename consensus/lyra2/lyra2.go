@@ -1,26 +1,24 @@
 package lyra2
 
-/*
-#cgo CFLAGS: -std=gnu99
-#include "Lyra2.h"
-#include <stdlib.h>
-*/
-import "C"
 import (
 	"encoding/binary"
 	"math/big"
 	"math/rand"
 	"sync"
 	"time"
-	"unsafe"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/consensus/stratum"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/params/types/ctypes"
 	"github.com/ethereum/go-ethereum/rpc"
 )
 
+// defaultTCost is the time cost of the Lyra2 hash of the MINTME network.
+const defaultTCost = 1
+
 type Lyra2 struct {
 	fakeMode  bool
 	fakeFail  uint64
@@ -34,6 +32,10 @@ type Lyra2 struct {
 	update   chan struct{}
 	threads  int
 	remote   *remoteSealer
+	stratum  stratum.Config
+	tcost    int // Time cost of the Lyra2 hash of the chain being sealed
+
+	closeOnce sync.Once // Ensures exit channel will not be closed twice.
 }
 
 type Config struct {
@@ -41,6 +43,10 @@ type Config struct {
 	FakeFail  uint64
 	FakeDelay time.Duration
 
+	// Stratum configures the stratum server of the remote sealer,
+	// disabled if no listen address is set.
+	Stratum stratum.Config
+
 	Log  log.Logger
 	Rand *rand.Rand
 }
@@ -56,6 +62,8 @@ func New(config *Config, notify []string, noverify bool) *Lyra2 {
 		log:       log.Root(),
 		hashrate:  metrics.NewMeter(),
 		update:    make(chan struct{}),
+		stratum:   config.Stratum,
+		tcost:     defaultTCost,
 	}
 	if config.Log != nil {
 		lyra2.log = config.Log
@@ -75,40 +83,60 @@ func NewTester(notify []string, noverify bool) *Lyra2 {
 		log:       log.Root(),
 		hashrate:  metrics.NewMeter(),
 		update:    make(chan struct{}),
+		tcost:     defaultTCost,
 	}
 	lyra2.remote = startRemoteSealer(lyra2, notify, noverify)
 	return lyra2
 }
 
-func (lyra2 *Lyra2) calcHash(headerBytes []byte, nonce uint64, tcost int) *big.Int {
-	var lyra2_ctx unsafe.Pointer = C.LYRA2_create()
-	result := lyra2.compute(lyra2_ctx, headerBytes, nonce, tcost)
-	C.LYRA2_destroy(lyra2_ctx)
-
-	return result.Big()
+// chainTCost returns the time cost of the Lyra2 hash configured by the chain,
+// defaulting to the one of the MINTME network if unset.
+func chainTCost(config ctypes.ChainConfigurator) int {
+	if tcost := config.GetLyra2TCost(); tcost > 0 {
+		return int(tcost)
+	}
+	return defaultTCost
 }
 
-func bytesToHash(in unsafe.Pointer) common.Hash {
-	return *(*common.Hash)(in)
-}
+// hasher computes Lyra2 hashes, reusing its memory matrix between hashes. A
+// hasher is not safe for concurrent use.
+type hasher interface {
+	// hash derives len(out) bytes from in with the given time cost.
+	hash(out []byte, in []byte, tcost int)
 
-func (lyra2 *Lyra2) compute(ctx unsafe.Pointer, blockBytes []byte, nonce uint64, tcost int) common.Hash {
-	binary.BigEndian.PutUint64(blockBytes[len(blockBytes)-8:], nonce)
+	// close releases the memory matrix of the hasher.
+	close()
+}
 
-	var in unsafe.Pointer = C.CBytes(blockBytes)
-	var out unsafe.Pointer = C.malloc(common.HashLength)
+// hashers caches the Lyra2 contexts used for verification, so that the memory
+// matrix is not allocated for every hash.
+var hashers = sync.Pool{New: func() any { return newHasher() }}
 
-	C.LYRA2(ctx, out, common.HashLength, in, C.int32_t(len(blockBytes)), C.int32_t(tcost))
+func (lyra2 *Lyra2) calcHash(headerBytes []byte, nonce uint64, tcost int) *big.Int {
+	h := hashers.Get().(hasher)
+	defer hashers.Put(h)
 
-	hash := bytesToHash(out)
+	return lyra2.compute(h, headerBytes, nonce, tcost).Big()
+}
 
-	C.free(in)
-	C.free(out)
+func (lyra2 *Lyra2) compute(h hasher, blockBytes []byte, nonce uint64, tcost int) common.Hash {
+	binary.BigEndian.PutUint64(blockBytes[len(blockBytes)-8:], nonce)
 
+	var hash common.Hash
+	h.hash(hash[:], blockBytes, tcost)
 	return hash
 }
 
+// Close closes the exit channel to notify all backend threads exiting.
 func (lyra2 *Lyra2) Close() error {
+	lyra2.closeOnce.Do(func() {
+		// Short circuit if the exit channel is not allocated.
+		if lyra2.remote == nil {
+			return
+		}
+		close(lyra2.remote.requestExit)
+		<-lyra2.remote.exitCh
+	})
 	return nil
 }
 
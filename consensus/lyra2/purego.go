@@ -0,0 +1,270 @@
+package lyra2
+
+import (
+	"encoding/binary"
+	"math/bits"
+)
+
+// Parameters of the Lyra2 memory matrix, see Lyra2.h.
+const (
+	blockLenBlake2SafeInt64 = 8                           // Block length required so Blake2's IV is not overwritten
+	blockLenBlake2SafeBytes = blockLenBlake2SafeInt64 * 8 // Same as above, in bytes
+	blockLenInt64           = 12                          // Block length: 768 bits (=96 bytes, =12 uint64)
+	nRows                   = 16384                       // Number of rows of the memory matrix
+	nCols                   = 4                           // Number of columns of the memory matrix
+	rowLenInt64             = blockLenInt64 * nCols       // Length of a row of the memory matrix
+)
+
+// blake2bIV is the initialization vector of Blake2b.
+var blake2bIV = [8]uint64{
+	0x6a09e667f3bcc908, 0xbb67ae8584caa73b,
+	0x3c6ef372fe94f82b, 0xa54ff53a5f1d36f1,
+	0x510e527fade682d1, 0x9b05688c2b3e6c1f,
+	0x1f83d9abfb41bd6b, 0x5be0cd19137e2179,
+}
+
+// goHasher is a pure Go implementation of the Lyra2 hash, a port of Lyra2.c
+// and Sponge.c.
+type goHasher struct {
+	matrix []uint64 // Memory matrix of nRows rows of nCols blocks
+	input  []byte   // Padded password and basil, reused between hashes
+}
+
+// newGoHasher allocates a pure Go Lyra2 context.
+func newGoHasher() *goHasher {
+	return &goHasher{matrix: make([]uint64, nRows*rowLenInt64)}
+}
+
+// row returns the row of the memory matrix with the given index.
+func (h *goHasher) row(i int64) []uint64 {
+	return h.matrix[i*rowLenInt64 : (i+1)*rowLenInt64 : (i+1)*rowLenInt64]
+}
+
+// hash implements hasher, deriving a key of len(out) bytes from the password
+// in with the given time cost.
+func (h *goHasher) hash(out []byte, in []byte, tcost int) {
+	// Get the password and basil padded with 10*1
+	nBlocksInput := (len(in)+6*8)/blockLenBlake2SafeBytes + 1
+	size := nBlocksInput * blockLenBlake2SafeBytes
+	if cap(h.input) < size {
+		h.input = make([]byte, size)
+	}
+	input := h.input[:size]
+	clear(input)
+
+	ptr := copy(input, in)
+	for _, v := range []uint64{uint64(len(out)), uint64(len(in)), 0, uint64(tcost), nRows, nCols} {
+		binary.LittleEndian.PutUint64(input[ptr:], v)
+		ptr += 8
+	}
+	input[ptr] = 0x80
+	input[size-1] ^= 0x01
+
+	// Initialize the sponge state and absorb the password and basil
+	var (
+		state [16]uint64
+		block [blockLenBlake2SafeInt64]uint64
+	)
+	copy(state[8:], blake2bIV[:])
+	for i := 0; i < nBlocksInput; i++ {
+		for j := range block {
+			block[j] = binary.LittleEndian.Uint64(input[(i*blockLenBlake2SafeInt64+j)*8:])
+		}
+		absorbBlockBlake2Safe(&state, block[:])
+	}
+
+	// Setup phase: initialize all the rows of the memory matrix
+	var (
+		row    int64 = 2 // Index of row to be processed
+		prev   int64 = 1 // Index of prev (last row ever computed/modified)
+		rowa   int64 = 0 // Index of row* (a previous row, deterministically picked during Setup and randomly picked while Wandering)
+		step   int64 = 1 // Visitation step (used during Setup and Wandering phases)
+		window int64 = 2 // Visitation window (used to define which rows can be revisited during Setup)
+		gap    int64 = 1 // Modifier to the step, assuming the values 1 or -1
+	)
+	reducedSqueezeRow0(&state, h.row(0))
+	reducedDuplexRow1(&state, h.row(0), h.row(1))
+	for row < nRows {
+		reducedDuplexRowSetup(&state, h.row(prev), h.row(rowa), h.row(row))
+
+		rowa = (rowa + step) & (window - 1)
+		prev = row
+		row++
+
+		// Check if all rows in the window where visited
+		if rowa == 0 {
+			step = window + gap
+			window *= 2
+			gap = -gap
+		}
+	}
+
+	// Wandering phase: visit the rows pseudorandomly
+	row = 0
+	for tau := 1; tau <= tcost; tau++ {
+		// Step is approximately half the number of all rows of the memory matrix
+		// for an odd tau, otherwise it is -1
+		step = -1
+		if tau%2 != 0 {
+			step = nRows/2 - 1
+		}
+		for {
+			rowa = int64(state[0] & (nRows - 1))
+			reducedDuplexRow(&state, h.row(prev), h.row(rowa), h.row(row))
+
+			prev = row
+			row = (row + step) & (nRows - 1)
+			if row == 0 {
+				break
+			}
+		}
+	}
+
+	// Wrap-up phase: absorb the last visited row and squeeze the key
+	absorbBlock(&state, h.row(rowa))
+	squeeze(&state, out)
+}
+
+// close implements hasher, the matrix is released by the garbage collector.
+func (h *goHasher) close() {}
+
+// g is the G function of Blake2b.
+func g(a, b, c, d uint64) (uint64, uint64, uint64, uint64) {
+	a += b
+	d = bits.RotateLeft64(d^a, -32)
+	c += d
+	b = bits.RotateLeft64(b^c, -24)
+	a += b
+	d = bits.RotateLeft64(d^a, -16)
+	c += d
+	b = bits.RotateLeft64(b^c, -63)
+	return a, b, c, d
+}
+
+// roundLyra is one round of the compression function of Blake2b.
+func roundLyra(v *[16]uint64) {
+	v[0], v[4], v[8], v[12] = g(v[0], v[4], v[8], v[12])
+	v[1], v[5], v[9], v[13] = g(v[1], v[5], v[9], v[13])
+	v[2], v[6], v[10], v[14] = g(v[2], v[6], v[10], v[14])
+	v[3], v[7], v[11], v[15] = g(v[3], v[7], v[11], v[15])
+	v[0], v[5], v[10], v[15] = g(v[0], v[5], v[10], v[15])
+	v[1], v[6], v[11], v[12] = g(v[1], v[6], v[11], v[12])
+	v[2], v[7], v[8], v[13] = g(v[2], v[7], v[8], v[13])
+	v[3], v[4], v[9], v[14] = g(v[3], v[4], v[9], v[14])
+}
+
+// blake2bLyra executes the G function of Blake2b, with all 12 rounds.
+func blake2bLyra(v *[16]uint64) {
+	for i := 0; i < 12; i++ {
+		roundLyra(v)
+	}
+}
+
+// squeeze squeezes len(out) bytes out of the sponge.
+func squeeze(state *[16]uint64, out []byte) {
+	const blockLenBytes = blockLenInt64 * 8
+
+	var block [blockLenBytes]byte
+	for len(out) > 0 {
+		for i := 0; i < blockLenInt64; i++ {
+			binary.LittleEndian.PutUint64(block[i*8:], state[i])
+		}
+		n := copy(out, block[:])
+		if out = out[n:]; len(out) > 0 {
+			blake2bLyra(state)
+		}
+	}
+}
+
+// absorbBlock absorbs a single block of blockLenInt64 words.
+func absorbBlock(state *[16]uint64, in []uint64) {
+	for i := 0; i < blockLenInt64; i++ {
+		state[i] ^= in[i]
+	}
+	blake2bLyra(state)
+}
+
+// absorbBlockBlake2Safe absorbs a single block of blockLenBlake2SafeInt64 words.
+func absorbBlockBlake2Safe(state *[16]uint64, in []uint64) {
+	for i := 0; i < blockLenBlake2SafeInt64; i++ {
+		state[i] ^= in[i]
+	}
+	blake2bLyra(state)
+}
+
+// reducedSqueezeRow0 squeezes the first row, from the highest to the lowest
+// column, using the reduced-round permutation.
+func reducedSqueezeRow0(state *[16]uint64, rowOut []uint64) {
+	for i := nCols - 1; i >= 0; i-- {
+		copy(rowOut[i*blockLenInt64:(i+1)*blockLenInt64], state[:blockLenInt64])
+		roundLyra(state)
+	}
+}
+
+// reducedDuplexRow1 duplexes the first row into the second one, the latter
+// from the highest to the lowest column.
+func reducedDuplexRow1(state *[16]uint64, rowIn, rowOut []uint64) {
+	for i := 0; i < nCols; i++ {
+		in := rowIn[i*blockLenInt64 : (i+1)*blockLenInt64]
+		out := rowOut[(nCols-1-i)*blockLenInt64 : (nCols-i)*blockLenInt64]
+
+		for j := 0; j < blockLenInt64; j++ {
+			state[j] ^= in[j]
+		}
+		roundLyra(state)
+
+		// M[row][C-1-col] = M[prev][col] XOR rand
+		for j := 0; j < blockLenInt64; j++ {
+			out[j] = in[j] ^ state[j]
+		}
+	}
+}
+
+// reducedDuplexRowSetup duplexes "M[rowInOut][col] [+] M[rowIn][col]", making
+// "M[rowOut][(C-1)-col] = M[rowIn][col] XOR rand" and
+// "M[rowInOut][col] = M[rowInOut][col] XOR rotW(rand)".
+func reducedDuplexRowSetup(state *[16]uint64, rowIn, rowInOut, rowOut []uint64) {
+	for i := 0; i < nCols; i++ {
+		in := rowIn[i*blockLenInt64 : (i+1)*blockLenInt64]
+		inOut := rowInOut[i*blockLenInt64 : (i+1)*blockLenInt64]
+		out := rowOut[(nCols-1-i)*blockLenInt64 : (nCols-i)*blockLenInt64]
+
+		for j := 0; j < blockLenInt64; j++ {
+			state[j] ^= in[j] + inOut[j]
+		}
+		roundLyra(state)
+
+		for j := 0; j < blockLenInt64; j++ {
+			out[j] = in[j] ^ state[j]
+		}
+		inOut[0] ^= state[blockLenInt64-1]
+		for j := 1; j < blockLenInt64; j++ {
+			inOut[j] ^= state[j-1]
+		}
+	}
+}
+
+// reducedDuplexRow duplexes "M[rowInOut][col] [+] M[rowIn][col]", making
+// "M[rowOut][col] = M[rowOut][col] XOR rand" and
+// "M[rowInOut][col] = M[rowInOut][col] XOR rotW(rand)". The rows rowInOut and
+// rowOut may be the same.
+func reducedDuplexRow(state *[16]uint64, rowIn, rowInOut, rowOut []uint64) {
+	for i := 0; i < nCols; i++ {
+		in := rowIn[i*blockLenInt64 : (i+1)*blockLenInt64]
+		inOut := rowInOut[i*blockLenInt64 : (i+1)*blockLenInt64]
+		out := rowOut[i*blockLenInt64 : (i+1)*blockLenInt64]
+
+		for j := 0; j < blockLenInt64; j++ {
+			state[j] ^= in[j] + inOut[j]
+		}
+		roundLyra(state)
+
+		for j := 0; j < blockLenInt64; j++ {
+			out[j] ^= state[j]
+		}
+		inOut[0] ^= state[blockLenInt64-1]
+		for j := 1; j < blockLenInt64; j++ {
+			inOut[j] ^= state[j-1]
+		}
+	}
+}
@@ -18,6 +18,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/consensus/stratum"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/rlp"
 )
@@ -51,6 +52,10 @@ func (lyra2 *Lyra2) Seal(chain consensus.ChainHeaderReader, block *types.Block,
 
 	lyra2.lock.Lock()
 	threads := lyra2.threads
+	if chain != nil {
+		lyra2.tcost = chainTCost(chain.Config())
+	}
+	tcost := lyra2.tcost
 	if lyra2.rand == nil {
 		seed, err := crand.Int(crand.Reader, big.NewInt(math.MaxInt64))
 		if err != nil {
@@ -78,7 +83,7 @@ func (lyra2 *Lyra2) Seal(chain consensus.ChainHeaderReader, block *types.Block,
 		pend.Add(1)
 		go func(id int, nonce uint64) {
 			defer pend.Done()
-			lyra2.mine(block, id, nonce, abort, locals)
+			lyra2.mine(block, id, nonce, tcost, abort, locals)
 		}(i, uint64(lyra2.rand.Int63()))
 	}
 	// Wait until sealing is terminated or a nonce is found
@@ -110,7 +115,7 @@ func (lyra2 *Lyra2) Seal(chain consensus.ChainHeaderReader, block *types.Block,
 }
 
 func (lyra2 *Lyra2) headerBytes(header *types.Header) ([]byte, error) {
-	ret, err := rlp.EncodeToBytes([]interface{}{
+	enc := []interface{}{
 		header.ParentHash,
 		header.UncleHash,
 		header.Coinbase,
@@ -124,19 +129,21 @@ func (lyra2 *Lyra2) headerBytes(header *types.Header) ([]byte, error) {
 		header.GasUsed,
 		header.Time,
 		header.Extra,
-		header.Nonce,
-	})
-
+	}
+	if header.BaseFee != nil {
+		enc = append(enc, header.BaseFee)
+	}
+	// The nonce must come last, it is overwritten in place by the hashing
+	ret, err := rlp.EncodeToBytes(append(enc, header.Nonce))
 	if err != nil {
 		return nil, err
 	}
-
 	return ret, nil
 }
 
 // mine is the actual proof-of-work miner that searches for a nonce starting from
 // seed that results in correct final block difficulty.
-func (lyra2 *Lyra2) mine(block *types.Block, id int, seed uint64, abort chan struct{}, found chan *types.Block) {
+func (lyra2 *Lyra2) mine(block *types.Block, id int, seed uint64, tcost int, abort chan struct{}, found chan *types.Block) {
 	// Extract some data from the header
 	var (
 		header = block.Header()
@@ -146,7 +153,10 @@ func (lyra2 *Lyra2) mine(block *types.Block, id int, seed uint64, abort chan str
 	var (
 		attempts = int64(0)
 		nonce    = seed
+		hasher   = newHasher() // Lyra2 context reused by the whole search
 	)
+	defer hasher.close()
+
 	logger := lyra2.log.New("miner", id)
 	logger.Trace("Started lyra2 search for new nonces", "seed", seed)
 search:
@@ -171,7 +181,7 @@ search:
 				logger.Error("Cannot convert header to bytes")
 				break search
 			}
-			result := lyra2.calcHash(headerBytes, nonce, 1)
+			result := lyra2.compute(hasher, headerBytes, nonce, tcost).Big()
 			if result.Cmp(target) <= 0 {
 				// Correct nonce found, create a new header with it
 				header = types.CopyHeader(header)
@@ -207,6 +217,7 @@ type remoteSealer struct {
 	lyra2        *Lyra2
 	noverify     bool
 	notifyURLs   []string
+	stratum      *stratum.Server // Optional stratum server pushing the work to miners
 	results      chan<- *types.Block
 	workCh       chan *sealTask   // Notification channel to push new work and relative result channel to remote sealer
 	fetchWorkCh  chan *sealWork   // Channel used for remote sealer to fetch mining work
@@ -265,6 +276,14 @@ func startRemoteSealer(lyra2 *Lyra2, urls []string, noverify bool) *remoteSealer
 		requestExit:  make(chan struct{}),
 		exitCh:       make(chan struct{}),
 	}
+	if config := lyra2.stratum; config.Addr != "" {
+		server, err := stratum.NewServer(config, "lyra2", &stratumBackend{sealer: s}, lyra2.log)
+		if err != nil {
+			lyra2.log.Error("Failed to start stratum server", "addr", config.Addr, "err", err)
+		} else {
+			s.stratum = server
+		}
+	}
 	go s.loop()
 	return s
 }
@@ -272,6 +291,9 @@ func startRemoteSealer(lyra2 *Lyra2, urls []string, noverify bool) *remoteSealer
 func (s *remoteSealer) loop() {
 	defer func() {
 		s.lyra2.log.Trace("Lyra2 remote sealer is exiting")
+		if s.stratum != nil {
+			s.stratum.Close()
+		}
 		s.cancelNotify()
 		s.reqWG.Wait()
 		close(s.exitCh)
@@ -317,6 +339,9 @@ func (s *remoteSealer) loop() {
 				// this could overflow
 				total += rate.rate
 			}
+			if s.stratum != nil {
+				total += s.stratum.Hashrate()
+			}
 			req <- total
 
 		case <-ticker.C:
@@ -371,6 +396,16 @@ func (s *remoteSealer) notifyWork() {
 	for _, url := range s.notifyURLs {
 		go s.sendNotification(s.notifyCtx, url, blob, work)
 	}
+	if s.stratum != nil {
+		// Lyra2 has no dataset, the miners hash the encoded header instead
+		headerBytes, _ := s.lyra2.headerBytes(s.currentBlock.Header())
+		s.stratum.Notify(&stratum.Job{
+			SealHash: common.HexToHash(work[0]),
+			Seed:     headerBytes,
+			Number:   s.currentBlock.NumberU64(),
+			Target:   new(big.Int).Div(two256, s.currentBlock.Difficulty()),
+		})
+	}
 }
 
 func (s *remoteSealer) sendNotification(ctx context.Context, url string, json []byte, work [4]string) {
@@ -446,3 +481,31 @@ func (s *remoteSealer) submitWork(nonce types.BlockNonce, mixDigest common.Hash,
 	s.lyra2.log.Warn("Work submitted is too old", "number", solution.NumberU64(), "sealhash", sealhash, "hash", solution.Hash())
 	return false
 }
+
+// stratumBackend verifies the shares of the stratum server and submits the
+// solutions to the remote sealer.
+type stratumBackend struct {
+	sealer *remoteSealer
+}
+
+// Hash implements stratum.Backend, computing the proof-of-work of a job.
+func (b *stratumBackend) Hash(job *stratum.Job, nonce uint64) (common.Hash, *big.Int) {
+	// The nonce is written into the encoded header, work on a copy of it
+	headerBytes := common.CopyBytes(job.Seed)
+
+	b.sealer.lyra2.lock.Lock()
+	tcost := b.sealer.lyra2.tcost
+	b.sealer.lyra2.lock.Unlock()
+	return common.Hash{}, b.sealer.lyra2.calcHash(headerBytes, nonce, tcost)
+}
+
+// Submit implements stratum.Backend, submitting a solution to the remote sealer.
+func (b *stratumBackend) Submit(job *stratum.Job, nonce uint64, mixDigest common.Hash) error {
+	errc := make(chan error, 1)
+	select {
+	case b.sealer.submitWorkCh <- &mineResult{nonce: types.EncodeNonce(nonce), mixDigest: mixDigest, hash: job.SealHash, errc: errc}:
+	case <-b.sealer.requestExit:
+		return errLyra2Stopped
+	}
+	return <-errc
+}
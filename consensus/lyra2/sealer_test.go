@@ -1,11 +1,19 @@
 package lyra2
 
 import (
+	"bufio"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
 	"math/big"
+	"net"
+	"strconv"
 	"testing"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/stratum"
 	"github.com/ethereum/go-ethereum/core/types"
 )
 
@@ -92,3 +100,153 @@ func TestStaleSubmission(t *testing.T) {
 		}
 	}
 }
+
+// Tests that miners connected to the stratum server get the work and that their
+// solutions are sealed.
+func TestRemoteStratum(t *testing.T) {
+	lyra2 := New(&Config{Stratum: stratum.Config{Addr: "127.0.0.1:0", Difficulty: 2, MinDifficulty: 1}}, nil, false)
+	defer lyra2.Close()
+	lyra2.SetThreads(-1)
+
+	header := &types.Header{Number: big.NewInt(1), Difficulty: big.NewInt(16)}
+	results := make(chan *types.Block, 1)
+	lyra2.Seal(nil, types.NewBlockWithHeader(header), results, nil)
+
+	conn, err := net.Dial("tcp", lyra2.remote.stratum.Addr().String())
+	if err != nil {
+		t.Fatalf("failed to connect to stratum server: %v", err)
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(10 * time.Second))
+
+	reader := bufio.NewReader(conn)
+	call := func(id int, method string, params interface{}) {
+		blob, _ := json.Marshal(map[string]interface{}{"id": id, "method": method, "params": params})
+		if _, err := conn.Write(append(blob, '\n')); err != nil {
+			t.Fatalf("failed to send %s: %v", method, err)
+		}
+	}
+	read := func() (msg struct {
+		Method string
+		Result interface{}
+		Params interface{}
+	}) {
+		line, err := reader.ReadBytes('\n')
+		if err != nil {
+			t.Fatalf("failed to read message: %v", err)
+		}
+		if err := json.Unmarshal(line, &msg); err != nil {
+			t.Fatalf("invalid message %s: %v", line, err)
+		}
+		return msg
+	}
+	call(1, "mining.hello", map[string]string{"agent": "test", "proto": stratum.ProtocolV2})
+	read()
+	call(2, "mining.subscribe", []string{})
+	read()
+	call(3, "mining.authorize", []string{"rig", "x"})
+	worker := read().Result.(string)
+	extranonce := read().Params.(map[string]interface{})["extranonce"].(string)
+	read() // mining.set epoch
+	work := read().Params.([]interface{})
+
+	// Search a solution within the extranonce of the connection, hashing the
+	// header sent along the job
+	headerBytes, _ := hex.DecodeString(work[4].(string))
+	prefix, _ := strconv.ParseUint(extranonce, 16, 64)
+	target := new(big.Int).Div(two256, header.Difficulty)
+
+	nonce := prefix << 48
+	for ; lyra2.calcHash(headerBytes, nonce, 1).Cmp(target) > 0; nonce++ {
+	}
+	call(4, "mining.submit", []string{work[0].(string), fmt.Sprintf("%012x", nonce&(1<<48-1)), worker})
+	if msg := read(); msg.Result != true {
+		t.Fatalf("solution rejected: %v", msg)
+	}
+	select {
+	case block := <-results:
+		if block.Nonce() != nonce {
+			t.Errorf("block nonce mismatch: have %x, want %x", block.Nonce(), nonce)
+		}
+		if err := lyra2.verifySeal(nil, block.Header(), false); err != nil {
+			t.Errorf("invalid seal: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("sealed block timeout")
+	}
+	workers, err := (&API{lyra2}).GetStratumWorkers()
+	if err != nil || len(workers) != 1 || workers[0].Blocks != 1 {
+		t.Fatalf("unexpected workers: %v, %v", workers, err)
+	}
+}
+
+// Tests that the Lyra2 hashers reproduce the output of the reference C
+// implementation.
+func TestHasherVectors(t *testing.T) {
+	in := make([]byte, 540)
+	for i := range in {
+		in[i] = byte(i)
+	}
+	tests := []struct {
+		tcost int
+		want  string
+	}{
+		{1, "d0d58a229b1682ca4fb0f0667de149d7d027bbf70eae79949a41f9c224e9d5d5"},
+		{2, "5c6678f59aea2ebdc4fe3c91a48f038ba553b088d232442ba99dfd8b403f81c7"},
+		{3, "6b996a8f155e57c49c4135a21bcffc4df63b7373f58914e25afade9ad7ed7f59"},
+	}
+	for name, h := range map[string]hasher{"default": newHasher(), "go": newGoHasher()} {
+		for _, tt := range tests {
+			out := make([]byte, common.HashLength)
+			h.hash(out, in, tt.tcost)
+			if have := hex.EncodeToString(out); have != tt.want {
+				t.Errorf("%s hasher, tcost %d: hash mismatch: have %s, want %s", name, tt.tcost, have, tt.want)
+			}
+		}
+		h.close()
+	}
+}
+
+// Tests that a seal computed with the default hasher is verified, the seal
+// verification using the pooled contexts.
+func TestVerifySealHasher(t *testing.T) {
+	lyra2 := NewTester(nil, false)
+	defer lyra2.Close()
+
+	header := &types.Header{Number: big.NewInt(1), Difficulty: big.NewInt(16)}
+	headerBytes, _ := lyra2.headerBytes(header)
+
+	var (
+		target = new(big.Int).Div(two256, header.Difficulty)
+		h      = newGoHasher()
+		nonce  uint64
+	)
+	for ; lyra2.compute(h, headerBytes, nonce, lyra2.tcost).Big().Cmp(target) > 0; nonce++ {
+	}
+	header.Nonce = types.EncodeNonce(nonce)
+	if err := lyra2.verifySeal(nil, header, false); err != nil {
+		t.Fatalf("failed to verify seal: %v", err)
+	}
+}
+
+func benchmarkHasher(b *testing.B, h hasher) {
+	in := make([]byte, 540)
+	out := make([]byte, common.HashLength)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		binary.BigEndian.PutUint64(in[len(in)-8:], uint64(i))
+		h.hash(out, in, defaultTCost)
+	}
+}
+
+func BenchmarkHasher(b *testing.B) {
+	h := newHasher()
+	defer h.close()
+	benchmarkHasher(b, h)
+}
+
+func BenchmarkGoHasher(b *testing.B) {
+	benchmarkHasher(b, newGoHasher())
+}
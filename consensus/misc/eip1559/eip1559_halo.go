@@ -23,8 +23,10 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/params/types/ctypes"
 	"github.com/holiman/uint256"
 )
 
@@ -58,65 +60,206 @@ func ValidateHaloAddresses() error {
 	return nil
 }
 
-// ApplyHaloBaseFeeDistribution applies the Halo custom EIP-1559 base fee distribution
-// This function is called during block finalization to distribute base fees according to:
+// HaloDefaultFeeDistribution returns the original Halo base fee split:
 // - 40% burned (reduces total supply)
-// - 30% to miners (coinbase) - NEVER reduced by contract fee sharing
-// - 20% to ecosystem fund - MAY be reduced by contract fee sharing
-// - 10% to reserve fund - NEVER reduced by contract fee sharing
+// - 30% to miners (coinbase)
+// - 20% to ecosystem fund, which also funds per-contract fee sharing
+// - 10% to reserve fund
 //
-// Contract fee sharing (if enabled) deducts from the ecosystem fund's 20%, not from miners.
-// This ensures miner incentives remain intact while supporting dApp development.
-//
-// Priority fees (tips) go 100% to miners.
-func ApplyHaloBaseFeeDistribution(state *state.StateDB, header *types.Header, baseFee *big.Int, gasUsed uint64) error {
-	// Validate fund addresses
-	if err := ValidateHaloAddresses(); err != nil {
-		return err
+// It applies to chains activating the Halo fee distribution without configuring
+// a fee distribution schedule, and to blocks before the first scheduled split.
+func HaloDefaultFeeDistribution() *ctypes.FeeDistribution {
+	fund := params.HaloEcosystemFundAddress
+	return &ctypes.FeeDistribution{
+		Total: HaloTotalRatio,
+		Burn:  HaloBurnRatio,
+		Miner: HaloMinerRatio,
+		Shares: []ctypes.FeeDistributionShare{
+			{Recipient: params.HaloEcosystemFundAddress, Ratio: HaloEcosystemRatio},
+			{Recipient: params.HaloReserveFundAddress, Ratio: HaloReserveRatio},
+		},
+		ContractFeeShareFund: &fund,
 	}
+}
 
-	// Calculate total base fee collected
-	totalBaseFee := new(big.Int).Mul(baseFee, new(big.Int).SetUint64(gasUsed))
-	if totalBaseFee.Sign() == 0 {
-		return nil // No fees to distribute
+// HaloFeeDistribution returns the base fee split in effect at the given block.
+func HaloFeeDistribution(config ctypes.ChainConfigurator, number *big.Int) *ctypes.FeeDistribution {
+	if split := config.GetHaloFeeDistributionSchedule().ForBlock(number.Uint64()); split != nil {
+		return split
 	}
+	return HaloDefaultFeeDistribution()
+}
 
-	totalBaseFeeU256 := uint256.MustFromBig(totalBaseFee)
+// haloFeeShare returns ratio/total of amount, rounded down.
+func haloFeeShare(amount *uint256.Int, ratio, total uint64) *uint256.Int {
+	share, _ := new(uint256.Int).MulDivOverflow(amount, uint256.NewInt(ratio), uint256.NewInt(total))
+	return share
+}
 
-	// Calculate distribution amounts
-	// Burn amount: 40%
-	burnAmount := new(uint256.Int).Mul(totalBaseFeeU256, uint256.NewInt(HaloBurnRatio))
-	burnAmount.Div(burnAmount, uint256.NewInt(HaloTotalRatio))
+// HaloFeeCredit is an amount of the base fee credited to a recipient.
+type HaloFeeCredit struct {
+	Recipient common.Address
+	Amount    *uint256.Int
+}
 
-	// Miner amount: 30%
-	minerAmount := new(uint256.Int).Mul(totalBaseFeeU256, uint256.NewInt(HaloMinerRatio))
-	minerAmount.Div(minerAmount, uint256.NewInt(HaloTotalRatio))
+// HaloBaseFeeCredits is the distribution of the base fees of a block.
+type HaloBaseFeeCredits struct {
+	Total  *uint256.Int    // Base fees paid by the block's transactions
+	Miner  *uint256.Int    // Credited to the block's coinbase
+	Shares []HaloFeeCredit // Credited to the fee distribution recipients
+	Burned *uint256.Int    // Remainder, not credited to any account
+}
+
+// IsHaloContractFeeSharingEnabled reports whether the contract fee shares are paid
+// in the block. They are paid out of the base fee credit of the fund, so they
+// require the base fees of the block to be distributed.
+func IsHaloContractFeeSharingEnabled(config ctypes.ChainConfigurator, number *big.Int) bool {
+	return config.IsEnabled(config.GetHaloContractFeeSharingTransition, number) &&
+		config.IsEnabled(config.GetHaloFeeDistributionTransition, number) &&
+		config.IsEnabled(config.GetEIP1559Transition, number)
+}
 
-	// Ecosystem fund amount: 20%
-	ecosystemAmount := new(uint256.Int).Mul(totalBaseFeeU256, uint256.NewInt(HaloEcosystemRatio))
-	ecosystemAmount.Div(ecosystemAmount, uint256.NewInt(HaloTotalRatio))
+// IsHaloBaseFeeDistributed reports whether the base fees of the block are
+// distributed instead of burned.
+func IsHaloBaseFeeDistributed(config ctypes.ChainConfigurator, header *types.Header) bool {
+	return config.IsEnabled(config.GetHaloFeeDistributionTransition, header.Number) &&
+		config.IsEnabled(config.GetEIP1559Transition, header.Number) && header.BaseFee != nil
+}
 
-	// Reserve fund amount: 10%
-	reserveAmount := new(uint256.Int).Mul(totalBaseFeeU256, uint256.NewInt(HaloReserveRatio))
-	reserveAmount.Div(reserveAmount, uint256.NewInt(HaloTotalRatio))
+// CalcHaloBaseFeeCredits calculates the distribution of the given base fees of
+// a block, according to the fee distribution in effect (see HaloFeeDistribution):
+// each part receives its ratio of the base fees, rounded down, and the remainder
+// is burned. The credit of the contract fee share fund includes the contract fee
+// shares paid out of it during the block (see SettleHaloContractFeeShares).
+func CalcHaloBaseFeeCredits(config ctypes.ChainConfigurator, number *big.Int, baseFee *big.Int, gasUsed uint64) (*HaloBaseFeeCredits, error) {
+	split := HaloFeeDistribution(config, number)
+	if err := split.Validate(); err != nil {
+		return nil, err
+	}
+	total, overflow := uint256.FromBig(new(big.Int).Mul(baseFee, new(big.Int).SetUint64(gasUsed)))
+	if overflow {
+		return nil, errors.New("base fees overflow")
+	}
+	credits := &HaloBaseFeeCredits{
+		Total:  total,
+		Miner:  haloFeeShare(total, split.Miner, split.Total),
+		Burned: new(uint256.Int).Set(total),
+	}
+	credits.Burned.Sub(credits.Burned, credits.Miner)
+	for _, share := range split.Shares {
+		amount := haloFeeShare(total, share.Ratio, split.Total)
+		credits.Shares = append(credits.Shares, HaloFeeCredit{Recipient: share.Recipient, Amount: amount})
+		credits.Burned.Sub(credits.Burned, amount)
+	}
+	return credits, nil
+}
 
-	// Apply distributions
-	// 1. Burn - implicit (not added to any account, reduces total supply)
-	// 2. Miner reward - add to coinbase
-	state.AddBalance(header.Coinbase, minerAmount)
-	// 3. Ecosystem fund - add to ecosystem address
-	state.AddBalance(params.HaloEcosystemFundAddress, ecosystemAmount)
-	// 4. Reserve fund - add to reserve address
-	state.AddBalance(params.HaloReserveFundAddress, reserveAmount)
+// ApplyHaloBaseFeeDistribution applies the Halo custom EIP-1559 base fee distribution.
+// This function is called during block finalization to distribute the base fees of
+// the block according to CalcHaloBaseFeeCredits: the miner share is credited to the
+// coinbase, each share to its recipient, and the remainder is burned.
+//
+// Contract fee sharing (if enabled) deducts from the ecosystem fund, not from miners.
+// This ensures miner incentives remain intact while supporting dApp development:
+// the contract fee shares paid by the block's transactions (see
+// ApplyHaloContractFeeSharing) are deducted from the credit of the contract fee
+// share fund.
+//
+// Priority fees (tips) go 100% to miners.
+func ApplyHaloBaseFeeDistribution(config ctypes.ChainConfigurator, state *state.StateDB, header *types.Header, baseFee *big.Int, gasUsed uint64) error {
+	credits, err := CalcHaloBaseFeeCredits(config, header.Number, baseFee, gasUsed)
+	if err != nil {
+		return err
+	}
+	if credits.Total.IsZero() {
+		return nil // No fees to distribute
+	}
+	// The burned share is implicit (not added to any account, reduces total supply)
+	SettleHaloContractFeeShares(config, header.Number, credits, state.Logs())
 
+	state.AddBalance(header.Coinbase, credits.Miner)
+	for _, share := range credits.Shares {
+		state.AddBalance(share.Recipient, share.Amount)
+	}
 	return nil
 }
 
+// HaloContractFeeSharesPaid returns the sum of the contract fee shares recorded
+// by the given logs.
+func HaloContractFeeSharesPaid(logs []*types.Log) *uint256.Int {
+	paid := new(uint256.Int)
+	for _, l := range logs {
+		if l.Address != HaloFeeShareLogAddress || len(l.Topics) == 0 || l.Topics[0] != HaloFeeSharePaidTopic {
+			continue
+		}
+		paid.Add(paid, new(uint256.Int).SetBytes(l.Data))
+	}
+	return paid
+}
+
+// SettleHaloContractFeeShares deducts the contract fee shares recorded by the
+// logs of a block from the credit of the contract fee share fund, as they were
+// already paid out of its share of the base fees during the block.
+func SettleHaloContractFeeShares(config ctypes.ChainConfigurator, number *big.Int, credits *HaloBaseFeeCredits, logs []*types.Log) {
+	fund := HaloFeeDistribution(config, number).ContractFeeShareFund
+	if fund == nil {
+		return
+	}
+	paid := HaloContractFeeSharesPaid(logs)
+	if paid.IsZero() {
+		return
+	}
+	for i, share := range credits.Shares {
+		if share.Recipient != *fund {
+			continue
+		}
+		amount := new(uint256.Int)
+		if paid.Lt(share.Amount) {
+			amount.Sub(share.Amount, paid)
+		}
+		credits.Shares[i].Amount = amount
+	}
+}
+
+// HaloBlockBaseFeeCredits returns the distribution of the base fees of a block
+// as settled by its proof-of-work finalization, given the receipts of the block:
+// the contract fee shares recorded by the receipts are deducted from the credit
+// of the contract fee share fund. The base fees of blocks not distributing them,
+// including proof-of-stake blocks, are burned. It returns nil before London.
+func HaloBlockBaseFeeCredits(config ctypes.ChainConfigurator, header *types.Header, receipts types.Receipts) (*HaloBaseFeeCredits, error) {
+	if header.BaseFee == nil {
+		return nil, nil
+	}
+	if header.Difficulty.Sign() > 0 && IsHaloBaseFeeDistributed(config, header) {
+		credits, err := CalcHaloBaseFeeCredits(config, header.Number, header.BaseFee, header.GasUsed)
+		if err != nil {
+			return nil, err
+		}
+		var logs []*types.Log
+		for _, receipt := range receipts {
+			logs = append(logs, receipt.Logs...)
+		}
+		SettleHaloContractFeeShares(config, header.Number, credits, logs)
+		return credits, nil
+	}
+	total, overflow := uint256.FromBig(new(big.Int).Mul(header.BaseFee, new(big.Int).SetUint64(header.GasUsed)))
+	if overflow {
+		return nil, errors.New("base fees overflow")
+	}
+	return &HaloBaseFeeCredits{
+		Total:  total,
+		Miner:  new(uint256.Int),
+		Burned: new(uint256.Int).Set(total),
+	}, nil
+}
+
 // =============================================================================
 // PER-CONTRACT FEE SHARING (SONIC-STYLE)
 // =============================================================================
 
-// Storage slots for contract fee sharing configuration (EIP-1967 style)
+// Storage slots for contract fee sharing configuration (EIP-1967 style).
+// The contract's own storage is the only source of its fee sharing configuration,
+// so only the contract itself can opt in or change its recipient.
 var (
 	// keccak256("halo.feeshare.enabled") - 1
 	feeShareEnabledSlot = common.HexToHash("0x360894a13ba1a3210667c828492db98dca3e2076cc3735a920a3ca505d382bbc")
@@ -126,6 +269,15 @@ var (
 	feeSharePercentSlot = common.HexToHash("0x360894a13ba1a3210667c828492db98dca3e2076cc3735a920a3ca505d382bbe")
 )
 
+var (
+	// HaloFeeShareLogAddress is the system address fee share payout logs are emitted from.
+	HaloFeeShareLogAddress = common.HexToAddress("0x0000000000000000000000000000000000000FEE")
+
+	// HaloFeeSharePaidTopic is the topic of fee share payout logs, matching the event
+	// FeeSharePaid(address indexed contract, address indexed recipient, uint256 amount).
+	HaloFeeSharePaidTopic = crypto.Keccak256Hash([]byte("FeeSharePaid(address,address,uint256)"))
+)
+
 // HaloContractFeeConfig holds per-contract fee sharing configuration
 type HaloContractFeeConfig struct {
 	Enabled         bool           // Whether fee sharing is enabled for this contract
@@ -135,7 +287,7 @@ type HaloContractFeeConfig struct {
 
 // GetContractFeeConfig retrieves fee sharing configuration from contract storage
 // Reads from special storage slots in the contract's own state (EIP-1967 style)
-func GetContractFeeConfig(state *state.StateDB, contractAddr common.Address) *HaloContractFeeConfig {
+func GetContractFeeConfig(state vm.StateDB, contractAddr common.Address) *HaloContractFeeConfig {
 	// Read enabled flag from storage
 	enabledValue := state.GetState(contractAddr, feeShareEnabledSlot)
 	enabled := enabledValue != (common.Hash{}) && enabledValue.Big().Sign() != 0
@@ -154,18 +306,20 @@ func GetContractFeeConfig(state *state.StateDB, contractAddr common.Address) *Ha
 
 	// Read percentage
 	percentValue := state.GetState(contractAddr, feeSharePercentSlot)
-	percent := uint8(percentValue.Big().Uint64())
 
 	// Validate percentage
-	if percent > 100 {
-		percent = 0
-		enabled = false
+	if !percentValue.Big().IsUint64() || percentValue.Big().Uint64() > 100 {
+		return &HaloContractFeeConfig{
+			Enabled:         false,
+			FeeRecipient:    recipient,
+			FeeSharePercent: 0,
+		}
 	}
 
 	return &HaloContractFeeConfig{
 		Enabled:         enabled,
 		FeeRecipient:    recipient,
-		FeeSharePercent: percent,
+		FeeSharePercent: uint8(percentValue.Big().Uint64()),
 	}
 }
 
@@ -175,7 +329,7 @@ func GetContractFeeConfig(state *state.StateDB, contractAddr common.Address) *Ha
 // IMPORTANT: Access control must be implemented in the calling smart contract.
 // This function only handles storage - the contract must ensure only authorized
 // addresses (e.g., contract owner) can call this.
-func SetContractFeeConfig(state *state.StateDB, contractAddr common.Address, config *HaloContractFeeConfig) error {
+func SetContractFeeConfig(state vm.StateDB, contractAddr common.Address, config *HaloContractFeeConfig) error {
 	// Validate configuration
 	if config.FeeSharePercent > 100 {
 		return ErrInvalidFeePercent
@@ -200,124 +354,72 @@ func SetContractFeeConfig(state *state.StateDB, contractAddr common.Address, con
 }
 
 // ApplyHaloContractFeeSharing applies per-contract fee sharing for a transaction
-// If the transaction interacts with a contract that has fee sharing enabled,
-// a portion of the transaction fees is redirected to the contract's specified recipient
+// calling contractAddr. If the contract has fee sharing enabled, a portion of the
+// base fee paid by the transaction is redirected to the contract's specified recipient.
 //
-// IMPORTANT: Fee shares are deducted from the ECOSYSTEM FUND (20% of base fees)
-// The ecosystem fund supports development and growth, so sharing with dApp developers
-// aligns with this purpose while keeping miner incentives intact.
+// IMPORTANT: Fee shares are deducted from the contract fee share fund of the fee
+// distribution in effect (the ECOSYSTEM FUND on Halo). The ecosystem fund supports
+// development and growth, so sharing with dApp developers aligns with this purpose
+// while keeping miner incentives intact.
 //
-// Example with 50% contract fee sharing:
+// Example with 50% contract fee sharing, on the default Halo split:
 // - 40% burned
 // - 30% to miner (unchanged)
 // - 10% to ecosystem fund (reduced from 20%)
 // - 10% to contract (from ecosystem's 20%)
 // - 10% to reserve fund (unchanged)
 //
-// SECURITY: Includes balance checks to prevent underflow attacks
+// The share is credited out of the base fee burned by this transaction, so it
+// doesn't depend on the balance the fund accumulated from earlier blocks. The fund
+// is credited its share of the block's base fees at block finalization, after all
+// transactions, less the contract fee shares paid in the block.
 //
-// This is called during block finalization to distribute contract fee shares
-func ApplyHaloContractFeeSharing(state *state.StateDB, tx *types.Transaction, contractAddr common.Address, gasUsed uint64, baseFee *big.Int) error {
-	config := GetContractFeeConfig(state, contractAddr)
-	if !config.Enabled || config.FeeSharePercent == 0 {
-		return nil // Fee sharing not enabled for this contract
-	}
-
-	// Validate recipient
-	if config.FeeRecipient == (common.Address{}) {
-		return nil // No valid recipient
+// Payouts are recorded by a FeeSharePaid log of HaloFeeShareLogAddress, which is
+// part of the transaction's receipt and settles the fund's credit at finalization
+// (see ApplyHaloBaseFeeDistribution).
+//
+// This is called during the state transition of every successful transaction to
+// a contract, nothing being paid unless both HaloContractFeeSharingTransition and
+// HaloFeeDistributionTransition are activated (see IsHaloContractFeeSharingEnabled).
+func ApplyHaloContractFeeSharing(config ctypes.ChainConfigurator, state vm.StateDB, number *big.Int, contractAddr common.Address, gasUsed uint64, baseFee *big.Int) {
+	if !IsHaloContractFeeSharingEnabled(config, number) || baseFee == nil {
+		return
 	}
-
-	// Validate ecosystem fund address
-	if err := ValidateHaloAddresses(); err != nil {
-		return err
+	split := HaloFeeDistribution(config, number)
+	if split.Validate() != nil || split.ContractFeeShareFund == nil {
+		return // No fund to pay fee shares from
 	}
+	fund := *split.ContractFeeShareFund
 
-	// Calculate total base fee for this transaction
-	totalFee := new(big.Int).Mul(baseFee, new(big.Int).SetUint64(gasUsed))
-	totalFeeU256 := uint256.MustFromBig(totalFee)
-
-	// Calculate the ecosystem fund's portion (20% of total base fee)
-	ecosystemPortion := new(uint256.Int).Mul(totalFeeU256, uint256.NewInt(HaloEcosystemRatio))
-	ecosystemPortion.Div(ecosystemPortion, uint256.NewInt(HaloTotalRatio))
-
-	// Calculate contract's share (percentage of ecosystem's portion)
-	contractShare := new(uint256.Int).Mul(ecosystemPortion, uint256.NewInt(uint64(config.FeeSharePercent)))
-	contractShare.Div(contractShare, uint256.NewInt(100))
-
-	if contractShare.Sign() > 0 {
-		// SECURITY FIX: Check ecosystem fund has sufficient balance before deduction
-		ecosystemBalance := state.GetBalance(params.HaloEcosystemFundAddress)
-
-		// If ecosystem fund has insufficient balance, only transfer what's available
-		// This prevents underflow attacks and state corruption
-		if ecosystemBalance.Cmp(contractShare) < 0 {
-			// Insufficient funds - only transfer available balance
-			if ecosystemBalance.Sign() > 0 {
-				state.SubBalance(params.HaloEcosystemFundAddress, ecosystemBalance)
-				state.AddBalance(config.FeeRecipient, ecosystemBalance)
-			}
-			// Don't error - just skip/reduce the fee sharing for this tx
-			return nil
-		}
-
-		// Sufficient balance - proceed with full fee sharing
-		state.SubBalance(params.HaloEcosystemFundAddress, contractShare)
-		state.AddBalance(config.FeeRecipient, contractShare)
+	feeConfig := GetContractFeeConfig(state, contractAddr)
+	if !feeConfig.Enabled || feeConfig.FeeSharePercent == 0 {
+		return // Fee sharing not enabled for this contract
 	}
-
-	return nil
-}
-
-// =============================================================================
-// ALTERNATIVE: REGISTRY-BASED FEE SHARING (OPTIONAL)
-// =============================================================================
-
-var (
-	// System registry contract address (if using registry approach)
-	// This would be deployed at a known address
-	feeShareRegistryAddress = common.HexToAddress("0x0000000000000000000000000000000000000FEE")
-)
-
-// GetContractFeeConfigFromRegistry retrieves config from a central registry
-// This is an ALTERNATIVE implementation - use either this OR the storage-based approach
-func GetContractFeeConfigFromRegistry(state *state.StateDB, contractAddr common.Address) *HaloContractFeeConfig {
-	// Calculate storage slot for this contract's config
-	baseSlot := common.BigToHash(big.NewInt(0))
-	slot := crypto.Keccak256Hash(contractAddr.Bytes(), baseSlot.Bytes())
-
-	// Read enabled flag from registry
-	enabledValue := state.GetState(feeShareRegistryAddress, slot)
-	enabled := enabledValue != (common.Hash{}) && enabledValue.Big().Sign() != 0
-
-	if !enabled {
-		return &HaloContractFeeConfig{
-			Enabled:         false,
-			FeeRecipient:    common.Address{},
-			FeeSharePercent: 0,
-		}
+	// Validate recipient
+	if feeConfig.FeeRecipient == (common.Address{}) || feeConfig.FeeRecipient == fund {
+		return // No valid recipient
 	}
 
-	// Read recipient (slot + 1)
-	recipientSlot := common.BigToHash(new(big.Int).Add(slot.Big(), big.NewInt(1)))
-	recipientValue := state.GetState(feeShareRegistryAddress, recipientSlot)
-	recipient := common.BytesToAddress(recipientValue.Bytes())
-
-	// Read percentage (slot + 2)
-	percentSlot := common.BigToHash(new(big.Int).Add(slot.Big(), big.NewInt(2)))
-	percentValue := state.GetState(feeShareRegistryAddress, percentSlot)
-	percent := uint8(percentValue.Big().Uint64())
-
-	if percent > 100 {
-		percent = 0
-		enabled = false
+	// Calculate total base fee for this transaction
+	totalFee, overflow := uint256.FromBig(new(big.Int).Mul(baseFee, new(big.Int).SetUint64(gasUsed)))
+	if overflow {
+		return
 	}
+	// Calculate the fund's portion and the contract's share of it
+	fundPortion := haloFeeShare(totalFee, split.Share(fund), split.Total)
+	contractShare := haloFeeShare(fundPortion, uint64(feeConfig.FeeSharePercent), 100)
 
-	return &HaloContractFeeConfig{
-		Enabled:         enabled,
-		FeeRecipient:    recipient,
-		FeeSharePercent: percent,
+	if contractShare.IsZero() {
+		return
 	}
+	state.AddBalance(feeConfig.FeeRecipient, contractShare)
+
+	state.AddLog(&types.Log{
+		Address:     HaloFeeShareLogAddress,
+		Topics:      []common.Hash{HaloFeeSharePaidTopic, common.BytesToHash(contractAddr.Bytes()), common.BytesToHash(feeConfig.FeeRecipient.Bytes())},
+		Data:        contractShare.PaddedBytes(32),
+		BlockNumber: number.Uint64(),
+	})
 }
 
 /*
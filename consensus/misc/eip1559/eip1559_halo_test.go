@@ -0,0 +1,250 @@
+// Copyright 2025 The core-geth Authors
+// This file is part of the core-geth library.
+//
+// The core-geth library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The core-geth library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the core-geth library. If not, see <http://www.gnu.org/licenses/>.
+
+package eip1559
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/params/confp"
+	"github.com/ethereum/go-ethereum/params/types/coregeth"
+	"github.com/ethereum/go-ethereum/params/types/ctypes"
+	"github.com/holiman/uint256"
+)
+
+func TestApplyHaloBaseFeeDistribution(t *testing.T) {
+	var (
+		coinbase  = common.HexToAddress("0xc0ffee")
+		community = common.HexToAddress("0xc0")
+		dev       = common.HexToAddress("0xde")
+	)
+	config := &coregeth.CoreGethChainConfig{
+		HaloFeeDistributionSchedule: ctypes.FeeDistributionSchedule{
+			100: {
+				Total: 7,
+				Burn:  1,
+				Miner: 3,
+				Shares: []ctypes.FeeDistributionShare{
+					{Recipient: community, Ratio: 2},
+					{Recipient: dev, Ratio: 1},
+				},
+			},
+			200: {Total: 1, Burn: 1},
+		},
+	}
+	type balances map[common.Address]uint64
+	for _, c := range []struct {
+		number uint64
+		want   balances
+	}{
+		// Before the first scheduled split, the default Halo split applies
+		{99, balances{coinbase: 300, params.HaloEcosystemFundAddress: 200, params.HaloReserveFundAddress: 100}},
+		// Shares are rounded down, the remainder is burned
+		{100, balances{coinbase: 428, community: 285, dev: 142}},
+		{199, balances{coinbase: 428, community: 285, dev: 142}},
+		{200, balances{}},
+	} {
+		statedb, _ := state.New(types.EmptyRootHash, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+		header := &types.Header{Number: new(big.Int).SetUint64(c.number), Coinbase: coinbase}
+		if err := ApplyHaloBaseFeeDistribution(config, statedb, header, big.NewInt(10), 100); err != nil {
+			t.Fatalf("block %d: %v", c.number, err)
+		}
+		for _, addr := range []common.Address{coinbase, community, dev, params.HaloEcosystemFundAddress, params.HaloReserveFundAddress} {
+			if have, want := statedb.GetBalance(addr).Uint64(), c.want[addr]; have != want {
+				t.Errorf("block %d: balance mismatch for %v: have %d, want %d", c.number, addr, have, want)
+			}
+		}
+	}
+}
+
+func TestApplyHaloBaseFeeDistributionInvalid(t *testing.T) {
+	config := &coregeth.CoreGethChainConfig{
+		HaloFeeDistributionSchedule: ctypes.FeeDistributionSchedule{
+			0: {Total: 1000, Burn: 400, Miner: 300},
+		},
+	}
+	statedb, _ := state.New(types.EmptyRootHash, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	header := &types.Header{Number: big.NewInt(1)}
+	if err := ApplyHaloBaseFeeDistribution(config, statedb, header, big.NewInt(10), 100); err == nil {
+		t.Fatal("expected error for ratios not summing to total")
+	}
+}
+
+// newFeeSharingConfig returns a chain config with the contract fee sharing and
+// the fee distribution it requires activated at genesis.
+func newFeeSharingConfig() *coregeth.CoreGethChainConfig {
+	return &coregeth.CoreGethChainConfig{
+		EIP1559FBlock:                big.NewInt(0),
+		HaloFeeDistributionFBlock:    big.NewInt(0),
+		HaloContractFeeSharingFBlock: big.NewInt(0),
+	}
+}
+
+func TestApplyHaloContractFeeSharing(t *testing.T) {
+	var (
+		contract  = common.HexToAddress("0xc0de")
+		recipient = common.HexToAddress("0xbeef")
+		fund      = params.HaloEcosystemFundAddress
+		config    = newFeeSharingConfig()
+		number    = big.NewInt(1)
+	)
+	for _, c := range []struct {
+		percent uint8
+		balance uint64
+		want    uint64
+	}{
+		{0, 1000, 0},
+		{50, 1000, 100}, // 50% of the fund's 20% of 1000
+		{100, 1000, 200},
+		{100, 150, 200}, // Paid out of the burned base fee, not the fund
+		{100, 0, 200},
+	} {
+		statedb, _ := state.New(types.EmptyRootHash, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+		statedb.AddBalance(fund, uint256.NewInt(c.balance))
+		SetContractFeeConfig(statedb, contract, &HaloContractFeeConfig{Enabled: true, FeeRecipient: recipient, FeeSharePercent: c.percent})
+
+		ApplyHaloContractFeeSharing(config, statedb, number, contract, 100, big.NewInt(10))
+		if have := statedb.GetBalance(recipient).Uint64(); have != c.want {
+			t.Errorf("percent %d, balance %d: recipient balance mismatch: have %d, want %d", c.percent, c.balance, have, c.want)
+		}
+		if have := statedb.GetBalance(fund).Uint64(); have != c.balance {
+			t.Errorf("percent %d, balance %d: fund balance mismatch: have %d, want %d", c.percent, c.balance, have, c.balance)
+		}
+		if have := HaloContractFeeSharesPaid(statedb.Logs()).Uint64(); have != c.want {
+			t.Errorf("percent %d, balance %d: logged fee shares mismatch: have %d, want %d", c.percent, c.balance, have, c.want)
+		}
+	}
+}
+
+// Tests that the contract fee shares paid by the transactions of a block are
+// settled against the fund's credit at finalization, starting from an empty fund.
+func TestApplyHaloContractFeeSharingSettlement(t *testing.T) {
+	var (
+		coinbase  = common.HexToAddress("0xc0ffee")
+		contract  = common.HexToAddress("0xc0de")
+		recipient = common.HexToAddress("0xbeef")
+		fund      = params.HaloEcosystemFundAddress
+		config    = newFeeSharingConfig()
+		header    = &types.Header{Number: big.NewInt(1), Coinbase: coinbase}
+	)
+	statedb, _ := state.New(types.EmptyRootHash, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	SetContractFeeConfig(statedb, contract, &HaloContractFeeConfig{Enabled: true, FeeRecipient: recipient, FeeSharePercent: 50})
+
+	// Two transactions to the contract, then one to an account
+	statedb.SetTxContext(common.Hash{1}, 0)
+	ApplyHaloContractFeeSharing(config, statedb, header.Number, contract, 30, big.NewInt(10))
+	statedb.SetTxContext(common.Hash{2}, 1)
+	ApplyHaloContractFeeSharing(config, statedb, header.Number, contract, 50, big.NewInt(10))
+	if have := statedb.GetBalance(recipient).Uint64(); have != 80 {
+		t.Fatalf("recipient balance mismatch: have %d, want %d", have, 80)
+	}
+	if err := ApplyHaloBaseFeeDistribution(config, statedb, header, big.NewInt(10), 100); err != nil {
+		t.Fatal(err)
+	}
+	for addr, want := range map[common.Address]uint64{
+		coinbase:                      300,
+		fund:                          120, // 200, less the 80 paid to the contract
+		params.HaloReserveFundAddress: 100,
+		recipient:                     80,
+	} {
+		if have := statedb.GetBalance(addr).Uint64(); have != want {
+			t.Errorf("balance mismatch for %v: have %d, want %d", addr, have, want)
+		}
+	}
+}
+
+// Tests that no contract fee share is paid unless the fee distribution settling
+// it is activated too, as it would be minted otherwise.
+func TestApplyHaloContractFeeSharingWithoutDistribution(t *testing.T) {
+	var (
+		contract  = common.HexToAddress("0xc0de")
+		recipient = common.HexToAddress("0xbeef")
+		config    = newFeeSharingConfig()
+	)
+	config.HaloFeeDistributionFBlock = nil
+
+	statedb, _ := state.New(types.EmptyRootHash, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	SetContractFeeConfig(statedb, contract, &HaloContractFeeConfig{Enabled: true, FeeRecipient: recipient, FeeSharePercent: 100})
+
+	ApplyHaloContractFeeSharing(config, statedb, big.NewInt(1), contract, 100, big.NewInt(10))
+	if have := statedb.GetBalance(recipient); !have.IsZero() {
+		t.Errorf("fee share paid without fee distribution: %v", have)
+	}
+	if len(statedb.Logs()) != 0 {
+		t.Errorf("fee share logged without fee distribution")
+	}
+	if err := confp.ValidateHaloContractFeeSharing(config); err == nil {
+		t.Error("contract fee sharing without fee distribution accepted")
+	}
+	// The sharing can't precede the distribution either
+	config.HaloFeeDistributionFBlock = big.NewInt(2)
+	if err := confp.ValidateHaloContractFeeSharing(config); err == nil {
+		t.Error("contract fee sharing before the fee distribution accepted")
+	}
+	config.HaloContractFeeSharingFBlock = big.NewInt(2)
+	if err := confp.ValidateHaloContractFeeSharing(config); err != nil {
+		t.Errorf("contract fee sharing with the fee distribution rejected: %v", err)
+	}
+}
+
+// Tests that the distribution of the base fees of a block is settled from the
+// logs of its receipts, and that proof-of-stake blocks burn their base fees.
+func TestHaloBlockBaseFeeCredits(t *testing.T) {
+	var (
+		contract  = common.HexToAddress("0xc0de")
+		recipient = common.HexToAddress("0xbeef")
+		fund      = params.HaloEcosystemFundAddress
+		config    = newFeeSharingConfig()
+		header    = &types.Header{Number: big.NewInt(1), Difficulty: big.NewInt(1), BaseFee: big.NewInt(10), GasUsed: 100}
+	)
+	statedb, _ := state.New(types.EmptyRootHash, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	SetContractFeeConfig(statedb, contract, &HaloContractFeeConfig{Enabled: true, FeeRecipient: recipient, FeeSharePercent: 50})
+	statedb.SetTxContext(common.Hash{1}, 0)
+	ApplyHaloContractFeeSharing(config, statedb, header.Number, contract, 100, header.BaseFee)
+	receipts := types.Receipts{{Logs: statedb.Logs()}}
+
+	credits, err := HaloBlockBaseFeeCredits(config, header, receipts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if credits.Total.Uint64() != 1000 || credits.Miner.Uint64() != 300 || credits.Burned.Uint64() != 400 {
+		t.Fatalf("wrong credits: total %v, miner %v, burned %v", credits.Total, credits.Miner, credits.Burned)
+	}
+	for _, share := range credits.Shares {
+		if share.Recipient == fund && share.Amount.Uint64() != 100 {
+			t.Errorf("fund credit mismatch: have %v, want %d", share.Amount, 100) // 200, less the 100 paid to the contract
+		}
+	}
+	// Proof-of-stake blocks are not finalized by ethash
+	header.Difficulty = new(big.Int)
+	if credits, err = HaloBlockBaseFeeCredits(config, header, receipts); err != nil {
+		t.Fatal(err)
+	}
+	if credits.Burned.Uint64() != 1000 || !credits.Miner.IsZero() || len(credits.Shares) != 0 {
+		t.Errorf("proof-of-stake base fees not burned: miner %v, burned %v, shares %v", credits.Miner, credits.Burned, credits.Shares)
+	}
+	// No base fees before London
+	header.BaseFee = nil
+	if credits, err = HaloBlockBaseFeeCredits(config, header, receipts); credits != nil || err != nil {
+		t.Errorf("unexpected credits before London: %v, %v", credits, err)
+	}
+}
@@ -0,0 +1,76 @@
+// Copyright 2025 The core-geth Authors
+// This file is part of the core-geth library.
+//
+// The core-geth library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The core-geth library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the core-geth library. If not, see <http://www.gnu.org/licenses/>.
+
+package misc
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// VerifyMedianTimePast ensures block timestamp is greater than median of last 11 blocks.
+// This prevents miners from backdating blocks to manipulate difficulty.
+//
+// This is a Bitcoin-inspired security measure that prevents:
+// - Backdating attacks (creating blocks with old timestamps)
+// - Timestamp manipulation to lower difficulty
+// - Chain reorganization attacks using timestamp gaming
+func VerifyMedianTimePast(chain consensus.ChainHeaderReader, header, parent *types.Header) error {
+	// Need at least 11 blocks for meaningful MTP calculation
+	if parent.Number.Uint64() < 11 {
+		// SECURITY FIX: For early blocks, use basic timestamp validation
+		// Still must be greater than parent to prevent backdating
+		if header.Time <= parent.Time {
+			return fmt.Errorf("timestamp %d not greater than parent timestamp %d (early block validation)",
+				header.Time, parent.Time)
+		}
+		return nil
+	}
+
+	// Collect timestamps of last 11 blocks (including parent)
+	timestamps := make([]uint64, 11)
+	for i := 0; i < 11; i++ {
+		h := chain.GetHeaderByNumber(parent.Number.Uint64() - uint64(i))
+		if h == nil {
+			// Can't validate without full history, skip check
+			return nil
+		}
+		timestamps[i] = h.Time
+	}
+
+	// Sort timestamps to find median
+	sortedTimestamps := make([]uint64, 11)
+	copy(sortedTimestamps, timestamps)
+	// Simple bubble sort (only 11 elements, performance not critical)
+	for i := 0; i < 11; i++ {
+		for j := 0; j < 10-i; j++ {
+			if sortedTimestamps[j] > sortedTimestamps[j+1] {
+				sortedTimestamps[j], sortedTimestamps[j+1] = sortedTimestamps[j+1], sortedTimestamps[j]
+			}
+		}
+	}
+	medianTime := sortedTimestamps[5] // Middle value of 11 sorted elements
+
+	// Block timestamp must be strictly greater than median
+	if header.Time <= medianTime {
+		return fmt.Errorf("timestamp %d not greater than median time past %d (backdating prevented)",
+			header.Time, medianTime)
+	}
+
+	return nil
+}
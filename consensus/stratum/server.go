@@ -0,0 +1,413 @@
+// Copyright 2025 The core-geth Authors
+// This file is part of the core-geth library.
+//
+// The core-geth library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The core-geth library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the core-geth library. If not, see <http://www.gnu.org/licenses/>.
+
+package stratum
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+var (
+	errTooManySessions = errors.New("no extranonce available")
+	errServerClosed    = errors.New("stratum server closed")
+)
+
+// Server is a stratum server feeding the jobs of a remote sealer to the miners
+// connected over TCP.
+type Server struct {
+	config   Config
+	algo     string
+	backend  Backend
+	listener net.Listener
+	log      log.Logger
+
+	lock        sync.Mutex
+	current     *Job
+	jobs        map[string]*jobEntry // Recent jobs accepting shares
+	jobOrder    []string             // Identifiers of the recent jobs, oldest first
+	sessions    map[*session]struct{}
+	extranonces map[uint16]struct{} // Extranonces in use
+	extranonce  uint16              // Next extranonce to assign
+	sessionID   uint64              // Identifier of the last session
+	workers     map[string]*worker
+
+	quit      chan struct{}
+	closeOnce sync.Once
+	wg        sync.WaitGroup
+}
+
+// jobEntry is a recent job and the nonces already submitted for it.
+type jobEntry struct {
+	job    *Job
+	nonces map[uint64]struct{}
+}
+
+// NewServer creates a stratum server for the given proof-of-work algorithm and
+// starts listening on the configured address.
+func NewServer(config Config, algo string, backend Backend, logger log.Logger) (*Server, error) {
+	if logger == nil {
+		logger = log.Root()
+	}
+	listener, err := net.Listen("tcp", config.Addr)
+	if err != nil {
+		return nil, err
+	}
+	s := &Server{
+		config:      config.sanitize(),
+		algo:        algo,
+		backend:     backend,
+		listener:    listener,
+		log:         logger.New("stratum", listener.Addr()),
+		jobs:        make(map[string]*jobEntry),
+		sessions:    make(map[*session]struct{}),
+		extranonces: make(map[uint16]struct{}),
+		workers:     make(map[string]*worker),
+		quit:        make(chan struct{}),
+	}
+	s.wg.Add(1)
+	go s.accept()
+
+	s.log.Info("Stratum server started", "algo", algo)
+	return s, nil
+}
+
+// Addr returns the listening address of the server.
+func (s *Server) Addr() net.Addr {
+	return s.listener.Addr()
+}
+
+// Close stops the server and disconnects all the miners.
+func (s *Server) Close() error {
+	var err error
+	s.closeOnce.Do(func() {
+		s.lock.Lock()
+		close(s.quit)
+		for sess := range s.sessions {
+			sess.conn.Close()
+		}
+		s.lock.Unlock()
+
+		err = s.listener.Close()
+		s.wg.Wait()
+	})
+	return err
+}
+
+// accept accepts the incoming connections until the server is closed.
+func (s *Server) accept() {
+	defer s.wg.Done()
+
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			select {
+			case <-s.quit:
+				return
+			default:
+			}
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				time.Sleep(100 * time.Millisecond)
+				continue
+			}
+			s.log.Warn("Stratum server failed to accept connection", "err", err)
+			return
+		}
+		sess, err := s.newSession(conn)
+		if err != nil {
+			s.log.Warn("Rejected stratum connection", "remote", conn.RemoteAddr(), "err", err)
+			conn.Close()
+			continue
+		}
+		s.wg.Add(2)
+		go sess.readLoop()
+		go sess.writeLoop()
+	}
+}
+
+// newSession registers a new connection, assigning it an unused extranonce.
+func (s *Server) newSession(conn net.Conn) (*session, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	select {
+	case <-s.quit:
+		return nil, errServerClosed
+	default:
+	}
+	if len(s.extranonces) > 0xffff {
+		return nil, errTooManySessions
+	}
+	for {
+		if _, ok := s.extranonces[s.extranonce]; !ok {
+			break
+		}
+		s.extranonce++
+	}
+	s.sessionID++
+	sess := newSession(s, conn, fmt.Sprintf("%016x", s.sessionID), s.extranonce)
+	s.extranonces[s.extranonce] = struct{}{}
+	s.extranonce++
+	s.sessions[sess] = struct{}{}
+
+	s.log.Debug("Stratum connection opened", "remote", conn.RemoteAddr(), "session", sess.id)
+	return sess, nil
+}
+
+// removeSession unregisters a closed connection.
+func (s *Server) removeSession(sess *session) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	delete(s.sessions, sess)
+	delete(s.extranonces, sess.extranonce)
+	for _, name := range sess.workerNames() {
+		if w := s.workers[name]; w != nil {
+			w.connections--
+			w.active = time.Now()
+		}
+	}
+	s.log.Debug("Stratum connection closed", "remote", sess.conn.RemoteAddr(), "session", sess.id)
+}
+
+// Notify pushes a new job to all the miners. It never blocks.
+func (s *Server) Notify(job *Job) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	// The same work is pushed again when the local miner threads change
+	job.ID = fmt.Sprintf("%x", job.SealHash[:8])
+	if s.current != nil && s.current.ID == job.ID {
+		return
+	}
+	s.current = job
+	if _, ok := s.jobs[job.ID]; !ok {
+		s.jobs[job.ID] = &jobEntry{job: job, nonces: make(map[uint64]struct{})}
+		s.jobOrder = append(s.jobOrder, job.ID)
+	}
+	for len(s.jobOrder) > recentJobs {
+		delete(s.jobs, s.jobOrder[0])
+		s.jobOrder = s.jobOrder[1:]
+	}
+	for sess := range s.sessions {
+		sess.post(job)
+	}
+}
+
+// currentJob returns the job being mined, if any.
+func (s *Server) currentJob() *Job {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.current
+}
+
+// authorize registers a worker connected over the given session.
+func (s *Server) authorize(name string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	w := s.workers[name]
+	if w == nil {
+		w = &worker{name: name, created: time.Now()}
+		s.workers[name] = w
+	}
+	w.connections++
+	w.active = time.Now()
+}
+
+// reportHashrate records the hashrate reported by a worker.
+func (s *Server) reportHashrate(name string, rate uint64) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if w := s.workers[name]; w != nil {
+		w.reported = rate
+		w.active = time.Now()
+	}
+}
+
+// submitShare verifies a share of a worker, and submits it to the backend if
+// it meets the block target.
+func (s *Server) submitShare(name string, jobID string, nonce uint64, difficulty uint64) error {
+	s.lock.Lock()
+	w, entry := s.workers[name], s.jobs[jobID]
+	if w == nil {
+		s.lock.Unlock()
+		return errUnauthorized
+	}
+	w.active = time.Now()
+	if entry == nil {
+		w.stale++
+		s.lock.Unlock()
+		return errJobNotFound
+	}
+	if _, ok := entry.nonces[nonce]; ok {
+		w.invalid++
+		s.lock.Unlock()
+		return errDuplicateShare
+	}
+	entry.nonces[nonce] = struct{}{}
+	s.lock.Unlock()
+
+	// Verify the share outside of the lock, hashing may be slow
+	job := entry.job
+	mixDigest, result := s.backend.Hash(job, nonce)
+	if result.Cmp(difficultyToTarget(difficulty)) > 0 {
+		s.lock.Lock()
+		w.invalid++
+		s.lock.Unlock()
+		return errLowDifficulty
+	}
+	var err error
+	found := result.Cmp(job.Target) <= 0
+	if found {
+		s.log.Info("Stratum worker found a block", "worker", name, "number", job.Number, "sealhash", job.SealHash)
+		err = s.backend.Submit(job, nonce, mixDigest)
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if err != nil {
+		w.stale++
+		return &Error{Code: errJobNotFound.Code, Message: fmt.Sprintf("Block rejected: %v", err)}
+	}
+	if found {
+		w.blocks++
+	}
+	w.record(time.Now(), difficulty)
+	return nil
+}
+
+// Hashrate returns the total hashrate of the workers, estimated from their
+// accepted shares.
+func (s *Server) Hashrate() uint64 {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	var (
+		now   = time.Now()
+		total uint64
+	)
+	for _, w := range s.workers {
+		total += w.hashrate(now)
+	}
+	return total
+}
+
+// WorkerStats is the mining activity of a worker.
+type WorkerStats struct {
+	Name          string         `json:"name"`
+	Connections   int            `json:"connections"`
+	Hashrate      hexutil.Uint64 `json:"hashrate"`         // Estimated from the accepted shares
+	Reported      hexutil.Uint64 `json:"reportedHashrate"` // Reported by the miner itself
+	ValidShares   hexutil.Uint64 `json:"validShares"`
+	StaleShares   hexutil.Uint64 `json:"staleShares"`
+	InvalidShares hexutil.Uint64 `json:"invalidShares"`
+	Blocks        hexutil.Uint64 `json:"blocks"`
+	LastShare     hexutil.Uint64 `json:"lastShare"` // Unix time of the last valid share
+}
+
+// Workers returns the statistics of the known workers, sorted by name. The
+// workers disconnected for a while are forgotten.
+func (s *Server) Workers() []WorkerStats {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	now := time.Now()
+	stats := make([]WorkerStats, 0, len(s.workers))
+	for name, w := range s.workers {
+		if w.connections == 0 && now.Sub(w.active) > workerExpiry {
+			delete(s.workers, name)
+			continue
+		}
+		stat := WorkerStats{
+			Name:          name,
+			Connections:   w.connections,
+			Hashrate:      hexutil.Uint64(w.hashrate(now)),
+			Reported:      hexutil.Uint64(w.reported),
+			ValidShares:   hexutil.Uint64(w.valid),
+			StaleShares:   hexutil.Uint64(w.stale),
+			InvalidShares: hexutil.Uint64(w.invalid),
+			Blocks:        hexutil.Uint64(w.blocks),
+		}
+		if !w.lastShare.IsZero() {
+			stat.LastShare = hexutil.Uint64(w.lastShare.Unix())
+		}
+		stats = append(stats, stat)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].Name < stats[j].Name
+	})
+	return stats
+}
+
+// worker accounts the shares of a named miner, possibly using several
+// connections.
+type worker struct {
+	name        string
+	created     time.Time // Time the worker was first authorized
+	active      time.Time // Time of the last activity of the worker
+	connections int
+
+	valid, stale, invalid, blocks uint64
+	reported                      uint64
+
+	lastShare time.Time
+	shares    []share // Accepted shares within the hashrate window
+}
+
+// share is an accepted share of a worker.
+type share struct {
+	time       time.Time
+	difficulty uint64
+}
+
+// record accounts an accepted share.
+func (w *worker) record(now time.Time, difficulty uint64) {
+	w.valid++
+	w.lastShare = now
+	w.shares = append(w.shares, share{time: now, difficulty: difficulty})
+}
+
+// hashrate estimates the hashrate of the worker from the shares accepted within
+// the hashrate window.
+func (w *worker) hashrate(now time.Time) uint64 {
+	start := now.Add(-hashrateWindow)
+	for len(w.shares) > 0 && w.shares[0].time.Before(start) {
+		w.shares = w.shares[1:]
+	}
+	if w.created.After(start) {
+		start = w.created
+	}
+	elapsed := now.Sub(start).Seconds()
+	if elapsed < 1 {
+		elapsed = 1
+	}
+	var hashes float64
+	for _, share := range w.shares {
+		hashes += float64(share.difficulty)
+	}
+	return uint64(hashes / elapsed)
+}
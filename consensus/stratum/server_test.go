@@ -0,0 +1,365 @@
+// Copyright 2025 The core-geth Authors
+// This file is part of the core-geth library.
+//
+// The core-geth library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The core-geth library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the core-geth library. If not, see <http://www.gnu.org/licenses/>.
+
+package stratum
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// testBackend is a backend whose proof-of-work meets the difficulty given by the
+// low 32 bits of the nonce, plus one.
+type testBackend struct {
+	lock      sync.Mutex
+	submitted []uint64
+}
+
+func (b *testBackend) Hash(job *Job, nonce uint64) (common.Hash, *big.Int) {
+	return common.Hash{0x01}, difficultyToTarget(nonce&0xffffffff + 1)
+}
+
+func (b *testBackend) Submit(job *Job, nonce uint64, mixDigest common.Hash) error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.submitted = append(b.submitted, nonce)
+	return nil
+}
+
+func newTestServer(t *testing.T, algo string, config Config) (*Server, *testBackend) {
+	config.Addr = "127.0.0.1:0"
+	backend := new(testBackend)
+	server, err := NewServer(config, algo, backend, nil)
+	if err != nil {
+		t.Fatalf("failed to start server: %v", err)
+	}
+	t.Cleanup(func() { server.Close() })
+	return server, backend
+}
+
+func testJob(number uint64, difficulty uint64) *Job {
+	var sealHash common.Hash
+	binary.BigEndian.PutUint64(sealHash[:], number<<32|difficulty)
+	return &Job{
+		SealHash: sealHash,
+		Seed:     []byte{0xde, 0xad},
+		Epoch:    number / 100,
+		Number:   number,
+		Target:   difficultyToTarget(difficulty),
+	}
+}
+
+// testClient is a loopback stratum miner.
+type testClient struct {
+	t      *testing.T
+	conn   net.Conn
+	reader *bufio.Reader
+	id     int
+	queue  []map[string]interface{} // Notifications received while waiting for a response
+}
+
+func dialTestClient(t *testing.T, server *Server) *testClient {
+	conn, err := net.Dial("tcp", server.Addr().String())
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return &testClient{t: t, conn: conn, reader: bufio.NewReader(conn)}
+}
+
+func (c *testClient) read() map[string]interface{} {
+	c.conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	line, err := c.reader.ReadBytes('\n')
+	if err != nil {
+		c.t.Fatalf("failed to read message: %v", err)
+	}
+	var msg map[string]interface{}
+	if err := json.Unmarshal(line, &msg); err != nil {
+		c.t.Fatalf("invalid message %s: %v", line, err)
+	}
+	return msg
+}
+
+// call sends a request, and returns the result and the error of its response.
+func (c *testClient) call(method string, params interface{}) (interface{}, interface{}) {
+	c.id++
+	blob, _ := json.Marshal(map[string]interface{}{"id": c.id, "method": method, "params": params})
+	if _, err := c.conn.Write(append(blob, '\n')); err != nil {
+		c.t.Fatalf("failed to send request: %v", err)
+	}
+	for {
+		msg := c.read()
+		if id, ok := msg["id"].(float64); ok && int(id) == c.id {
+			return msg["result"], msg["error"]
+		}
+		c.queue = append(c.queue, msg)
+	}
+}
+
+// expect returns the params of the next notification, which must be of the given method.
+func (c *testClient) expect(method string) interface{} {
+	var msg map[string]interface{}
+	if len(c.queue) > 0 {
+		msg, c.queue = c.queue[0], c.queue[1:]
+	} else {
+		msg = c.read()
+	}
+	if msg["method"] != method {
+		c.t.Fatalf("unexpected notification: have %v, want %s", msg, method)
+	}
+	return msg["params"]
+}
+
+// errorCode returns the code of an EthereumStratum/1.0.0 or 2.0.0 error.
+func errorCode(err interface{}) int {
+	switch err := err.(type) {
+	case []interface{}:
+		return int(err[0].(float64))
+	case map[string]interface{}:
+		return int(err["code"].(float64))
+	}
+	return 0
+}
+
+func TestServerV1(t *testing.T) {
+	server, backend := newTestServer(t, "ethash", Config{Difficulty: 10, MinDifficulty: 1, RetargetTime: time.Hour})
+	job := testJob(1, 1000)
+	server.Notify(job)
+
+	client := dialTestClient(t, server)
+	result, err := client.call("mining.subscribe", []string{"test/1.0", "EthereumStratum/1.0.0"})
+	if err != nil {
+		t.Fatalf("subscribe failed: %v", err)
+	}
+	subscription := result.([]interface{})
+	extranonce := subscription[1].(string)
+	if len(extranonce) != 2*extranonceSize {
+		t.Fatalf("invalid extranonce %q", extranonce)
+	}
+	if _, err := client.call("mining.submit", []string{"rig", job.ID, "000000000010"}); errorCode(err) != errUnauthorized.Code {
+		t.Fatalf("unauthorized submit: have error %v", err)
+	}
+	if result, err := client.call("mining.authorize", []string{"rig", "x"}); result != true {
+		t.Fatalf("authorize failed: %v", err)
+	}
+	if params := client.expect("mining.set_difficulty").([]interface{}); params[0].(float64) != 10.0/(1<<32) {
+		t.Fatalf("unexpected difficulty: %v", params)
+	}
+	params := client.expect("mining.notify").([]interface{})
+	if params[0] != job.ID || params[1] != "dead" || params[2] != fmt.Sprintf("%x", job.SealHash) || params[3] != true {
+		t.Fatalf("unexpected job: %v", params)
+	}
+
+	for i, c := range []struct {
+		job   string
+		nonce string
+		code  int
+	}{
+		{job.ID, "000000000001", errLowDifficulty.Code},
+		{job.ID, "000000000010", 0},
+		{job.ID, "000000000010", errDuplicateShare.Code},
+		{job.ID, extranonce + "000000000011", 0},           // Full nonce
+		{job.ID, "ffff000000000012", errInvalidNonce.Code}, // Foreign extranonce
+		{"0000", "000000000012", errJobNotFound.Code},
+		{job.ID, "0x0000000003e8", 0}, // Meets the block target
+	} {
+		result, err := client.call("mining.submit", []string{"rig", c.job, c.nonce})
+		if code := errorCode(err); code != c.code || (code == 0 && result != true) {
+			t.Errorf("submit %d: have result %v, error %v, want code %d", i, result, err, c.code)
+		}
+	}
+	if len(backend.submitted) != 1 || fmt.Sprintf("%016x", backend.submitted[0]) != extranonce+"0000000003e8" {
+		t.Fatalf("unexpected block submissions: %x", backend.submitted)
+	}
+	workers := server.Workers()
+	if len(workers) != 1 {
+		t.Fatalf("unexpected workers: %v", workers)
+	}
+	if w := workers[0]; w.Name != "rig" || w.ValidShares != 3 || w.InvalidShares != 2 || w.StaleShares != 1 || w.Blocks != 1 || w.Hashrate == 0 {
+		t.Fatalf("unexpected worker stats: %+v", w)
+	}
+	if server.Hashrate() == 0 {
+		t.Fatal("missing hashrate")
+	}
+
+	// New jobs are pushed, and a second connection gets another extranonce
+	next := testJob(2, 1000)
+	server.Notify(next)
+	if params := client.expect("mining.notify").([]interface{}); params[0] != next.ID || params[3] != true {
+		t.Fatalf("unexpected job: %v", params)
+	}
+	other := dialTestClient(t, server)
+	result, _ = other.call("mining.subscribe", []string{"test/1.0", "EthereumStratum/1.0.0"})
+	if result.([]interface{})[1] == extranonce {
+		t.Fatal("extranonce reused")
+	}
+}
+
+func TestServerV2(t *testing.T) {
+	server, backend := newTestServer(t, "lyra2", Config{Difficulty: 10, MinDifficulty: 1, RetargetTime: time.Hour})
+	job := testJob(1, 1000)
+	server.Notify(job)
+
+	client := dialTestClient(t, server)
+	if _, err := client.call("mining.hello", map[string]string{"agent": "test/1.0", "host": "localhost", "port": "0", "proto": ProtocolV2}); err != nil {
+		t.Fatalf("hello failed: %v", err)
+	}
+	if result, err := client.call("mining.subscribe", []string{}); err != nil || result == "" {
+		t.Fatalf("subscribe failed: %v", err)
+	}
+	result, err := client.call("mining.authorize", []string{"rig", "x"})
+	workerID, ok := result.(string)
+	if !ok {
+		t.Fatalf("authorize failed: %v", err)
+	}
+	set := client.expect("mining.set").(map[string]interface{})
+	if set["algo"] != "lyra2" || set["target"] != fmt.Sprintf("%064x", difficultyToTarget(10)) {
+		t.Fatalf("unexpected mining parameters: %v", set)
+	}
+	extranonce := set["extranonce"].(string)
+	if set := client.expect("mining.set").(map[string]interface{}); set["epoch"] != "0" {
+		t.Fatalf("unexpected epoch: %v", set)
+	}
+	// The seed is sent along the job for algorithms without epochs
+	params := client.expect("mining.notify").([]interface{})
+	if params[0] != job.ID || params[1] != "1" || params[3] != true || params[4] != "dead" {
+		t.Fatalf("unexpected job: %v", params)
+	}
+
+	if result, err := client.call("mining.submit", []string{job.ID, "0000000003e8", workerID}); result != true {
+		t.Fatalf("submit failed: %v", err)
+	}
+	if len(backend.submitted) != 1 || fmt.Sprintf("%016x", backend.submitted[0]) != extranonce+"0000000003e8" {
+		t.Fatalf("unexpected block submissions: %x", backend.submitted)
+	}
+	if _, err := client.call("mining.submit", []string{job.ID, "000000000010", "ff"}); errorCode(err) != errUnauthorized.Code {
+		t.Fatalf("unknown worker submit: have error %v", err)
+	}
+	if result, err := client.call("mining.hashrate", []string{"500", workerID}); result != true {
+		t.Fatalf("hashrate failed: %v", err)
+	}
+	if w := server.Workers()[0]; w.Reported != 0x500 || w.Blocks != 1 {
+		t.Fatalf("unexpected worker stats: %+v", w)
+	}
+}
+
+func TestServerVardiff(t *testing.T) {
+	server, _ := newTestServer(t, "ethash", Config{Difficulty: 1 << 34, ShareTime: time.Hour, RetargetTime: 50 * time.Millisecond})
+	server.Notify(testJob(1, 1<<40))
+
+	client := dialTestClient(t, server)
+	client.call("mining.subscribe", []string{"test/1.0", "EthereumStratum/1.0.0"})
+	client.call("mining.authorize", []string{"rig", "x"})
+	client.expect("mining.set_difficulty")
+	client.expect("mining.notify")
+
+	// Without shares, the difficulty is lowered as much as allowed
+	for _, want := range []float64{1, 0.25} {
+		if params := client.expect("mining.set_difficulty").([]interface{}); params[0].(float64) != want {
+			t.Fatalf("unexpected difficulty: have %v, want %v", params[0], want)
+		}
+		// The job is sent again to apply the difficulty
+		if params := client.expect("mining.notify").([]interface{}); params[3] != false {
+			t.Fatalf("unexpected job: %v", params)
+		}
+	}
+}
+
+func TestServerMinDifficulty(t *testing.T) {
+	server, _ := newTestServer(t, "ethash", Config{Difficulty: 1 << 34, MinDifficulty: 1 << 33, ShareTime: time.Hour, RetargetTime: 50 * time.Millisecond, Password: "secret"})
+	server.Notify(testJob(1, 1<<40))
+
+	// Workers authorizing with a wrong password are dropped
+	client := dialTestClient(t, server)
+	client.call("mining.subscribe", []string{"test/1.0", "EthereumStratum/1.0.0"})
+	if _, err := client.call("mining.authorize", []string{"rig", "x"}); errorCode(err) != errUnauthorized.Code {
+		t.Fatalf("authorize with wrong password: have error %v", err)
+	}
+	if _, err := client.reader.ReadBytes('\n'); err == nil {
+		t.Fatal("connection not dropped")
+	}
+	client = dialTestClient(t, server)
+	client.call("mining.subscribe", []string{"test/1.0", "EthereumStratum/1.0.0"})
+	if result, err := client.call("mining.authorize", []string{"rig", "secret"}); result != true {
+		t.Fatalf("authorize failed: %v", err)
+	}
+	client.expect("mining.set_difficulty")
+	client.expect("mining.notify")
+
+	// Without shares, the difficulty is lowered down to the minimum only
+	if params := client.expect("mining.set_difficulty").([]interface{}); params[0].(float64) != 2 {
+		t.Fatalf("unexpected difficulty: have %v, want %v", params[0], 2)
+	}
+	client.expect("mining.notify")
+	time.Sleep(200 * time.Millisecond)
+
+	server.lock.Lock()
+	defer server.lock.Unlock()
+	for sess := range server.sessions {
+		if difficulty := sess.shareDifficulty(); difficulty != 1<<33 {
+			t.Fatalf("difficulty retargeted below the minimum: %d", difficulty)
+		}
+	}
+}
+
+func TestServerShareLimits(t *testing.T) {
+	server, _ := newTestServer(t, "ethash", Config{Difficulty: 100, MinDifficulty: 1, RetargetTime: time.Hour})
+	job := testJob(1, 1<<40)
+	server.Notify(job)
+
+	// Shares submitted faster than allowed are rejected
+	client := dialTestClient(t, server)
+	client.call("mining.subscribe", []string{"test/1.0", "EthereumStratum/1.0.0"})
+	client.call("mining.authorize", []string{"rig", "x"})
+	var accepted, limited int
+	for i := 0; i < 2*submitBurst; i++ {
+		result, err := client.call("mining.submit", []string{"rig", job.ID, fmt.Sprintf("%012x", 0x100+i)})
+		switch {
+		case result == true:
+			accepted++
+		case errorCode(err) == errRateLimited.Code:
+			limited++
+		default:
+			t.Fatalf("submit %d: have result %v, error %v", i, result, err)
+		}
+	}
+	if accepted < submitBurst || limited == 0 {
+		t.Fatalf("shares not rate limited: %d accepted, %d limited", accepted, limited)
+	}
+
+	// Connections submitting mostly invalid shares are dropped
+	client = dialTestClient(t, server)
+	client.call("mining.subscribe", []string{"test/1.0", "EthereumStratum/1.0.0"})
+	client.call("mining.authorize", []string{"rig", "x"})
+	for i := 0; i < minRatioShares; i++ {
+		if _, err := client.call("mining.submit", []string{"rig", job.ID, fmt.Sprintf("%012x", i)}); errorCode(err) != errLowDifficulty.Code {
+			t.Fatalf("submit %d: have error %v, want low difficulty", i, err)
+		}
+	}
+	if _, err := client.reader.ReadBytes('\n'); err == nil {
+		t.Fatal("connection not dropped")
+	}
+}
@@ -0,0 +1,617 @@
+// Copyright 2025 The core-geth Authors
+// This file is part of the core-geth library.
+//
+// The core-geth library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The core-geth library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the core-geth library. If not, see <http://www.gnu.org/licenses/>.
+
+package stratum
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"golang.org/x/time/rate"
+)
+
+// Error is an error of the stratum protocol returned to the miners.
+type Error struct {
+	Code    int
+	Message string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("stratum error %d: %s", e.Code, e.Message)
+}
+
+var (
+	errUnknown        = &Error{Code: 20, Message: "Other/Unknown"}
+	errJobNotFound    = &Error{Code: 21, Message: "Job not found"}
+	errDuplicateShare = &Error{Code: 22, Message: "Duplicate share"}
+	errLowDifficulty  = &Error{Code: 23, Message: "Low difficulty share"}
+	errUnauthorized   = &Error{Code: 24, Message: "Unauthorized worker"}
+	errNotSubscribed  = &Error{Code: 25, Message: "Not subscribed"}
+	errInvalidNonce   = &Error{Code: 20, Message: "Invalid nonce"}
+	errInvalidParams  = &Error{Code: -32602, Message: "Invalid params"}
+	errMethodNotFound = &Error{Code: -32601, Message: "Method not found"}
+	errRateLimited    = &Error{Code: 20, Message: "Too many shares"}
+
+	errBye           = errors.New("connection closed by miner")
+	errBadPassword   = errors.New("invalid worker password")
+	errInvalidShares = errors.New("too many invalid shares")
+)
+
+// request is a request of a miner.
+type request struct {
+	ID     json.RawMessage   `json:"id"`
+	Method string            `json:"method"`
+	Params json.RawMessage   `json:"params"`
+	Worker string            `json:"worker,omitempty"` // Set by some EthereumStratum/1.0.0 miners
+	params []json.RawMessage // Positional parameters, if any
+}
+
+// param returns the positional string parameter at the given index.
+func (req *request) param(i int) (string, bool) {
+	if i >= len(req.params) {
+		return "", false
+	}
+	var s string
+	if err := json.Unmarshal(req.params[i], &s); err != nil {
+		return "", false
+	}
+	return s, true
+}
+
+// response is the response to a request.
+type response struct {
+	Version string          `json:"jsonrpc,omitempty"`
+	ID      json.RawMessage `json:"id"`
+	Result  interface{}     `json:"result"`
+	Error   interface{}     `json:"error"`
+}
+
+// notification is a message pushed to the miners.
+type notification struct {
+	Version string      `json:"jsonrpc,omitempty"`
+	ID      interface{} `json:"id"`
+	Method  string      `json:"method"`
+	Params  interface{} `json:"params"`
+}
+
+// session is a miner connection.
+type session struct {
+	server     *Server
+	conn       net.Conn
+	id         string
+	extranonce uint16
+
+	jobCh   chan *Job // Latest job to send, older ones are dropped
+	closed  chan struct{}
+	limiter *rate.Limiter // Limiter of the submitted shares
+
+	sendLock sync.Mutex // Serializes the writes to the connection
+
+	lock       sync.Mutex
+	proto      string
+	subscribed bool
+	ready      bool              // Set once a worker is authorized, jobs are only sent to ready sessions
+	workers    map[string]string // Names of the authorized workers, by identifier
+	difficulty uint64            // Share difficulty
+	previous   uint64            // Share difficulty before the last retarget, accepted until the next job
+	shares     int               // Number of shares accepted since the last retarget
+	retargeted time.Time         // Time of the last retarget
+	submitted  int               // Number of shares submitted
+	invalid    int               // Number of invalid shares submitted
+
+	sent       bool   // Whether a job was sent
+	sentNumber uint64 // Block number of the last sent job
+	sentEpoch  uint64 // Epoch of the last sent job
+}
+
+func newSession(server *Server, conn net.Conn, id string, extranonce uint16) *session {
+	return &session{
+		server:     server,
+		conn:       conn,
+		id:         id,
+		extranonce: extranonce,
+		jobCh:      make(chan *Job, 1),
+		closed:     make(chan struct{}),
+		limiter:    rate.NewLimiter(submitRate, submitBurst),
+		workers:    make(map[string]string),
+		difficulty: server.config.Difficulty,
+		retargeted: time.Now(),
+	}
+}
+
+// workerNames returns the names of the workers authorized on the session.
+func (sess *session) workerNames() []string {
+	sess.lock.Lock()
+	defer sess.lock.Unlock()
+
+	names := make([]string, 0, len(sess.workers))
+	for _, name := range sess.workers {
+		names = append(names, name)
+	}
+	return names
+}
+
+// post queues a job to be sent, replacing any job not sent yet. It never blocks.
+func (sess *session) post(job *Job) {
+	if job == nil {
+		return
+	}
+	for {
+		select {
+		case sess.jobCh <- job:
+			return
+		default:
+		}
+		select {
+		case <-sess.jobCh:
+		default:
+		}
+	}
+}
+
+// readLoop handles the requests of the miner until the connection is closed.
+func (sess *session) readLoop() {
+	defer sess.server.wg.Done()
+	defer func() {
+		sess.conn.Close()
+		close(sess.closed)
+		sess.server.removeSession(sess)
+	}()
+
+	scanner := bufio.NewScanner(sess.conn)
+	scanner.Buffer(make([]byte, 0, 512), maxRequestSize)
+	for {
+		sess.conn.SetReadDeadline(time.Now().Add(idleTimeout))
+		if !scanner.Scan() {
+			return
+		}
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		req := new(request)
+		if err := json.Unmarshal(line, req); err != nil {
+			sess.server.log.Debug("Invalid stratum request", "session", sess.id, "err", err)
+			return
+		}
+		// Positional parameters are optional, mining.hello uses an object
+		json.Unmarshal(req.Params, &req.params)
+
+		if err := sess.handle(req); err != nil {
+			sess.server.log.Debug("Stratum connection dropped", "session", sess.id, "err", err)
+			return
+		}
+	}
+}
+
+// writeLoop sends the jobs and the share difficulty changes to the miner.
+func (sess *session) writeLoop() {
+	defer sess.server.wg.Done()
+
+	ticker := time.NewTicker(sess.server.config.RetargetTime)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case job := <-sess.jobCh:
+			if err := sess.sendJob(job); err != nil {
+				sess.conn.Close()
+				return
+			}
+		case <-ticker.C:
+			if !sess.retarget(sess.server.currentJob()) {
+				continue
+			}
+			if err := sess.sendTarget(false); err != nil {
+				sess.conn.Close()
+				return
+			}
+			// EthereumStratum/1.0.0 miners apply the difficulty to the next job
+			if sess.protocol() == ProtocolV1 {
+				sess.post(sess.server.currentJob())
+			}
+		case <-sess.closed:
+			return
+		}
+	}
+}
+
+// protocol returns the stratum dialect of the session.
+func (sess *session) protocol() string {
+	sess.lock.Lock()
+	defer sess.lock.Unlock()
+
+	return sess.proto
+}
+
+// send writes a message to the connection.
+func (sess *session) send(msg interface{}) error {
+	blob, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	sess.sendLock.Lock()
+	defer sess.sendLock.Unlock()
+
+	sess.conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+	_, err = sess.conn.Write(append(blob, '\n'))
+	return err
+}
+
+// reply sends the response to a request.
+func (sess *session) reply(req *request, result interface{}, err error) error {
+	proto := sess.protocol()
+	resp := &response{ID: req.ID, Result: result}
+	if proto == ProtocolV2 {
+		resp.Version = "2.0"
+	}
+	if err != nil {
+		var serr *Error
+		if !errors.As(err, &serr) {
+			serr = &Error{Code: errUnknown.Code, Message: err.Error()}
+		}
+		resp.Result = nil
+		if proto == ProtocolV2 {
+			resp.Error = map[string]interface{}{"code": serr.Code, "message": serr.Message}
+		} else {
+			resp.Error = []interface{}{serr.Code, serr.Message, nil}
+		}
+	}
+	return sess.send(resp)
+}
+
+// notify sends a notification to the miner.
+func (sess *session) notify(method string, params interface{}) error {
+	msg := &notification{Method: method, Params: params}
+	if sess.protocol() == ProtocolV2 {
+		msg.Version = "2.0"
+	}
+	return sess.send(msg)
+}
+
+// handle processes a request of the miner. An error is only returned if the
+// connection must be closed.
+func (sess *session) handle(req *request) error {
+	switch req.Method {
+	case "mining.hello":
+		var hello struct {
+			Proto string `json:"proto"`
+		}
+		if err := json.Unmarshal(req.Params, &hello); err != nil || hello.Proto != ProtocolV2 {
+			return sess.reply(req, nil, errInvalidParams)
+		}
+		sess.lock.Lock()
+		if sess.proto != "" {
+			sess.lock.Unlock()
+			return sess.reply(req, nil, errUnknown)
+		}
+		sess.proto = ProtocolV2
+		sess.lock.Unlock()
+
+		return sess.reply(req, map[string]interface{}{
+			"proto":    ProtocolV2,
+			"encoding": "plain",
+			"resume":   "0",
+			"timeout":  fmt.Sprintf("%x", int(idleTimeout.Seconds())),
+		}, nil)
+
+	case "mining.subscribe":
+		sess.lock.Lock()
+		if sess.proto == "" {
+			// The dialect of EthereumStratum/1.0.0 miners is given by the subscription
+			if proto, _ := req.param(1); !strings.HasPrefix(proto, "EthereumStratum/1.") {
+				sess.lock.Unlock()
+				return sess.reply(req, nil, &Error{Code: errInvalidParams.Code, Message: "Unsupported protocol"})
+			}
+			sess.proto = ProtocolV1
+		}
+		sess.subscribed = true
+		proto := sess.proto
+		sess.lock.Unlock()
+
+		if proto == ProtocolV2 {
+			return sess.reply(req, sess.id, nil)
+		}
+		return sess.reply(req, []interface{}{
+			[]string{"mining.notify", sess.id, ProtocolV1},
+			fmt.Sprintf("%04x", sess.extranonce),
+		}, nil)
+
+	case "mining.extranonce.subscribe":
+		return sess.reply(req, true, nil)
+
+	case "mining.authorize":
+		login, ok := req.param(0)
+		if !ok || login == "" {
+			return sess.reply(req, nil, errInvalidParams)
+		}
+		// Connections failing to authorize are dropped, to slow down guessing
+		if password := sess.server.config.Password; password != "" {
+			if given, _ := req.param(1); subtle.ConstantTimeCompare([]byte(given), []byte(password)) != 1 {
+				if err := sess.reply(req, nil, errUnauthorized); err != nil {
+					return err
+				}
+				return errBadPassword
+			}
+		}
+		sess.lock.Lock()
+		if !sess.subscribed {
+			sess.lock.Unlock()
+			return sess.reply(req, nil, errNotSubscribed)
+		}
+		var (
+			start  = !sess.ready
+			known  bool
+			result interface{}
+		)
+		if sess.proto == ProtocolV2 {
+			id := strconv.FormatUint(uint64(len(sess.workers)+1), 16)
+			sess.workers[id] = login
+			result = id
+		} else {
+			_, known = sess.workers[login]
+			sess.workers[login] = login
+			result = true
+		}
+		sess.ready = true
+		sess.lock.Unlock()
+
+		if !known {
+			sess.server.authorize(login)
+		}
+		if err := sess.reply(req, result, nil); err != nil {
+			return err
+		}
+		if start {
+			if err := sess.sendTarget(true); err != nil {
+				return err
+			}
+			sess.post(sess.server.currentJob())
+		}
+		return nil
+
+	case "mining.submit":
+		// Throttled shares are rejected before being parsed or hashed
+		if !sess.limiter.Allow() {
+			return sess.rejectShare(req, errRateLimited)
+		}
+		var workerParam, jobParam, nonceParam int
+		if sess.protocol() == ProtocolV2 {
+			jobParam, nonceParam, workerParam = 0, 1, 2
+		} else {
+			workerParam, jobParam, nonceParam = 0, 1, 2
+		}
+		workerID, ok1 := req.param(workerParam)
+		jobID, ok2 := req.param(jobParam)
+		nonceHex, ok3 := req.param(nonceParam)
+		if !ok1 || !ok2 || !ok3 {
+			return sess.reply(req, nil, errInvalidParams)
+		}
+		sess.lock.Lock()
+		name, ok := sess.workers[workerID]
+		sess.lock.Unlock()
+		if !ok {
+			return sess.rejectShare(req, errUnauthorized)
+		}
+		nonce, err := sess.parseNonce(nonceHex)
+		if err != nil {
+			return sess.rejectShare(req, err)
+		}
+		if err := sess.server.submitShare(name, jobID, nonce, sess.shareDifficulty()); err != nil {
+			sess.server.log.Debug("Stratum share rejected", "worker", name, "job", jobID, "nonce", nonceHex, "err", err)
+			return sess.rejectShare(req, err)
+		}
+		sess.lock.Lock()
+		sess.submitted++
+		sess.shares++
+		sess.lock.Unlock()
+		return sess.reply(req, true, nil)
+
+	case "mining.hashrate", "eth_submitHashrate":
+		rateHex, ok := req.param(0)
+		if !ok {
+			return sess.reply(req, nil, errInvalidParams)
+		}
+		rate, err := hexutil.DecodeUint64(rateHex)
+		if err != nil {
+			// EthereumStratum/2.0.0 does not prefix the rate with 0x
+			if rate, err = strconv.ParseUint(rateHex, 16, 64); err != nil {
+				return sess.reply(req, nil, errInvalidParams)
+			}
+		}
+		sess.lock.Lock()
+		name, ok := sess.workers[req.Worker]
+		if workerID, _ := req.param(1); sess.proto == ProtocolV2 {
+			name, ok = sess.workers[workerID]
+		} else if !ok && len(sess.workers) == 1 {
+			for _, name = range sess.workers {
+				ok = true
+			}
+		}
+		sess.lock.Unlock()
+		if !ok {
+			return sess.reply(req, nil, errUnauthorized)
+		}
+		sess.server.reportHashrate(name, rate)
+		return sess.reply(req, true, nil)
+
+	case "mining.noop":
+		return sess.reply(req, true, nil)
+
+	case "mining.bye":
+		return errBye
+
+	default:
+		return sess.reply(req, nil, errMethodNotFound)
+	}
+}
+
+// rejectShare replies to a rejected share. Stale shares aside, the rejected
+// shares are invalid, an error is returned to drop the connection once they
+// exceed maxInvalidRatio of its shares.
+func (sess *session) rejectShare(req *request, err error) error {
+	sess.lock.Lock()
+	sess.submitted++
+	switch err {
+	case errLowDifficulty, errDuplicateShare, errInvalidNonce, errUnauthorized, errRateLimited:
+		sess.invalid++
+	}
+	drop := sess.submitted >= minRatioShares && float64(sess.invalid) > float64(sess.submitted)*maxInvalidRatio
+	sess.lock.Unlock()
+
+	if err := sess.reply(req, nil, err); err != nil {
+		return err
+	}
+	if drop {
+		return errInvalidShares
+	}
+	return nil
+}
+
+// parseNonce decodes a submitted nonce, either the full nonce prefixed by the
+// extranonce of the session, or only the part searched by the miner.
+func (sess *session) parseNonce(input string) (uint64, error) {
+	var (
+		nonce  = strings.TrimPrefix(input, "0x")
+		prefix = fmt.Sprintf("%04x", sess.extranonce)
+	)
+	switch len(nonce) {
+	case 16:
+		if !strings.EqualFold(nonce[:2*extranonceSize], prefix) {
+			return 0, errInvalidNonce
+		}
+	case 16 - 2*extranonceSize:
+		nonce = prefix + nonce
+	default:
+		return 0, errInvalidNonce
+	}
+	n, err := strconv.ParseUint(nonce, 16, 64)
+	if err != nil {
+		return 0, errInvalidNonce
+	}
+	return n, nil
+}
+
+// shareDifficulty returns the difficulty the shares of the session must meet.
+func (sess *session) shareDifficulty() uint64 {
+	sess.lock.Lock()
+	defer sess.lock.Unlock()
+
+	if sess.previous != 0 && sess.previous < sess.difficulty {
+		return sess.previous
+	}
+	return sess.difficulty
+}
+
+// retarget adjusts the share difficulty of the session to the rate of its shares,
+// returning whether it changed.
+func (sess *session) retarget(job *Job) bool {
+	sess.lock.Lock()
+	defer sess.lock.Unlock()
+
+	now := time.Now()
+	elapsed, shares := now.Sub(sess.retargeted), sess.shares
+	sess.retargeted, sess.shares = now, 0
+	if !sess.ready || elapsed <= 0 {
+		return false
+	}
+	current := float64(sess.difficulty)
+	ideal := current * float64(shares) * float64(sess.server.config.ShareTime) / float64(elapsed)
+	ideal = math.Max(ideal, current/maxRetargetRatio)
+	ideal = math.Min(ideal, current*maxRetargetRatio)
+	ideal = math.Max(ideal, float64(sess.server.config.MinDifficulty))
+
+	// Shares harder than the blocks are pointless, and would miss blocks
+	if job != nil {
+		if max := new(big.Int).Div(two256, job.Target); max.IsUint64() {
+			ideal = math.Min(ideal, float64(max.Uint64()))
+		}
+	}
+	ideal = math.Max(ideal, 1)
+	if math.Abs(ideal-current) < current*minRetargetRatio {
+		return false
+	}
+	sess.previous, sess.difficulty = sess.difficulty, uint64(ideal)
+
+	sess.server.log.Trace("Retargeted stratum share difficulty", "session", sess.id, "shares", shares, "elapsed", elapsed, "difficulty", sess.difficulty)
+	return true
+}
+
+// sendTarget sends the share difficulty to the miner. For EthereumStratum/2.0.0
+// miners, all the mining parameters are sent if full is set.
+func (sess *session) sendTarget(full bool) error {
+	sess.lock.Lock()
+	proto, difficulty := sess.proto, sess.difficulty
+	sess.lock.Unlock()
+
+	if proto == ProtocolV1 {
+		// A difficulty of 1 is 2^32 hashes
+		return sess.notify("mining.set_difficulty", []interface{}{float64(difficulty) / (1 << 32)})
+	}
+	params := map[string]interface{}{
+		"target": fmt.Sprintf("%064x", difficultyToTarget(difficulty)),
+	}
+	if full {
+		params["algo"] = sess.server.algo
+		params["extranonce"] = fmt.Sprintf("%04x", sess.extranonce)
+	}
+	return sess.notify("mining.set", params)
+}
+
+// sendJob sends a job to the miner, if the session is ready.
+func (sess *session) sendJob(job *Job) error {
+	sess.lock.Lock()
+	if !sess.ready {
+		sess.lock.Unlock()
+		return nil
+	}
+	var (
+		proto    = sess.proto
+		clean    = !sess.sent || sess.sentNumber != job.Number
+		newEpoch = !sess.sent || sess.sentEpoch != job.Epoch
+	)
+	sess.sent, sess.sentNumber, sess.sentEpoch = true, job.Number, job.Epoch
+	sess.previous = 0
+	sess.lock.Unlock()
+
+	if proto == ProtocolV1 {
+		return sess.notify("mining.notify", []interface{}{job.ID, hex.EncodeToString(job.Seed), hex.EncodeToString(job.SealHash[:]), clean})
+	}
+	if newEpoch {
+		if err := sess.notify("mining.set", map[string]interface{}{"epoch": fmt.Sprintf("%x", job.Epoch)}); err != nil {
+			return err
+		}
+	}
+	params := []interface{}{job.ID, fmt.Sprintf("%x", job.Number), hex.EncodeToString(job.SealHash[:]), clean}
+	// Without an epoch to derive it from, the seed is sent with the job
+	if sess.server.algo != "ethash" {
+		params = append(params, hex.EncodeToString(job.Seed))
+	}
+	return sess.notify("mining.notify", params)
+}
@@ -0,0 +1,127 @@
+// Copyright 2025 The core-geth Authors
+// This file is part of the core-geth library.
+//
+// The core-geth library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The core-geth library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the core-geth library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package stratum implements a Stratum mining server for the remote sealers of
+// the proof-of-work engines. Both the EthereumStratum/1.0.0 (NiceHash) and the
+// EthereumStratum/2.0.0 dialects are supported, the dialect of a connection is
+// chosen by its first request.
+//
+// Every connection gets a distinct 2 bytes extranonce, which prefixes the nonces
+// searched by its miners, and a share difficulty retargeted so that it submits
+// a share every Config.ShareTime, never below Config.MinDifficulty. Shares meeting
+// the block target are submitted to the engine as solutions. The shares of a
+// connection are rate limited, and connections submitting mostly invalid shares
+// are dropped.
+package stratum
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	// ProtocolV1 is the EthereumStratum/1.0.0 protocol, as specified by NiceHash.
+	ProtocolV1 = "EthereumStratum/1.0.0"
+
+	// ProtocolV2 is the EthereumStratum/2.0.0 protocol.
+	ProtocolV2 = "EthereumStratum/2.0.0"
+)
+
+const (
+	extranonceSize = 2 // Size in bytes of the nonce prefix assigned to a connection
+
+	maxRequestSize   = 4096             // Maximum size of a request line
+	idleTimeout      = 10 * time.Minute // Connections without requests for this long are dropped
+	writeTimeout     = 10 * time.Second // Timeout of writing a message to a connection
+	recentJobs       = 16               // Number of recent jobs accepting shares
+	hashrateWindow   = 10 * time.Minute // Window of shares used to estimate the hashrate of workers
+	workerExpiry     = time.Hour        // Disconnected workers are forgotten after this long
+	minRetargetRatio = 0.1              // Minimum relative change of a retargeted share difficulty
+	maxRetargetRatio = 4                // Maximum factor of change of a retargeted share difficulty
+
+	submitRate      = 20  // Shares a connection may submit per second
+	submitBurst     = 100 // Shares a connection may submit at once
+	minRatioShares  = 50  // Number of shares a connection submits before its invalid ratio is checked
+	maxInvalidRatio = 0.5 // Maximum ratio of invalid shares of a connection before it is dropped
+)
+
+// Config are the configuration parameters of the stratum server.
+type Config struct {
+	Addr          string        // TCP listen address, the server is disabled if empty
+	Difficulty    uint64        // Initial share difficulty of connections, in hashes per share
+	MinDifficulty uint64        // Minimum share difficulty of connections, never retargeted below
+	ShareTime     time.Duration // Target time between the shares of a connection
+	RetargetTime  time.Duration // Interval of the share difficulty adjustments
+	Password      string        // Password the workers must authorize with, any if empty
+}
+
+// DefaultConfig contains the default settings of the stratum server.
+var DefaultConfig = Config{
+	Difficulty:    1 << 32,
+	MinDifficulty: 1 << 16,
+	ShareTime:     10 * time.Second,
+	RetargetTime:  time.Minute,
+}
+
+// sanitize returns a copy of the config with the unset fields set to their
+// default values.
+func (c Config) sanitize() Config {
+	if c.Difficulty == 0 {
+		c.Difficulty = DefaultConfig.Difficulty
+	}
+	if c.MinDifficulty == 0 {
+		c.MinDifficulty = DefaultConfig.MinDifficulty
+	}
+	if c.Difficulty < c.MinDifficulty {
+		c.Difficulty = c.MinDifficulty
+	}
+	if c.ShareTime <= 0 {
+		c.ShareTime = DefaultConfig.ShareTime
+	}
+	if c.RetargetTime <= 0 {
+		c.RetargetTime = DefaultConfig.RetargetTime
+	}
+	return c
+}
+
+// Job is a unit of work of the remote sealer.
+type Job struct {
+	ID       string      // Identifier of the job, set by the server
+	SealHash common.Hash // Hash of the header to seal
+	Seed     []byte      // Seed hash of the DAG for ethash, encoded header for lyra2
+	Epoch    uint64      // Epoch of the DAG, only used by ethash
+	Number   uint64      // Number of the block to seal
+	Target   *big.Int    // Boundary condition of the block, 2^256/difficulty
+}
+
+// Backend is the proof-of-work engine behind the stratum server.
+type Backend interface {
+	// Hash computes the proof-of-work of the job with the given nonce, returning
+	// the mix digest and the result compared to the targets.
+	Hash(job *Job, nonce uint64) (common.Hash, *big.Int)
+
+	// Submit submits a solution meeting the block target of the job.
+	Submit(job *Job, nonce uint64, mixDigest common.Hash) error
+}
+
+var two256 = new(big.Int).Exp(big.NewInt(2), big.NewInt(256), big.NewInt(0))
+
+// difficultyToTarget returns the boundary condition of a difficulty.
+func difficultyToTarget(difficulty uint64) *big.Int {
+	return new(big.Int).Div(two256, new(big.Int).SetUint64(difficulty))
+}
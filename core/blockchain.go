@@ -18,6 +18,7 @@
 package core
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io"
@@ -42,6 +43,7 @@ import (
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/event"
 	"github.com/ethereum/go-ethereum/internal/syncx"
+	"github.com/ethereum/go-ethereum/internal/telemetry"
 	"github.com/ethereum/go-ethereum/internal/version"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/metrics"
@@ -283,6 +285,9 @@ func NewBlockChain(db ethdb.Database, cacheConfig *CacheConfig, genesis *genesis
 	if _, ok := genesisErr.(*confp.ConfigCompatError); genesisErr != nil && !ok {
 		return nil, genesisErr
 	}
+	if err := confp.ValidateHaloContractFeeSharing(chainConfig); err != nil {
+		return nil, err
+	}
 	log.Info("")
 	log.Info(strings.Repeat("-", 153))
 	// TODO meowsbits implement prettier Strings (aka 'Description()') for chain configurator implementations.
@@ -382,8 +387,10 @@ func NewBlockChain(db ethdb.Database, cacheConfig *CacheConfig, genesis *genesis
 			}
 		}
 	}
-	// Ensure that a previous crash in SetHead doesn't leave extra ancients
-	if frozen, err := bc.db.Ancients(); err == nil && frozen > 0 {
+	// Ensure that a previous crash in SetHead doesn't leave extra ancients. A
+	// read-only ancient store is written by the node owning it, and may run ahead
+	// of the local chain.
+	if frozen, err := bc.db.Ancients(); err == nil && frozen > 0 && !rawdb.ReadOnlyAncients(bc.db) {
 		var (
 			needRewind bool
 			low        uint64
@@ -663,6 +670,13 @@ func (bc *BlockChain) setHeadBeyondRoot(head uint64, time uint64, root common.Ha
 	pivot := rawdb.ReadLastPivotNumber(bc.db)
 	frozen, _ := bc.db.Ancients()
 
+	// The chain is kept in the key-value store if the ancient store is read-only,
+	// rewinding never truncates the ancients then
+	readonly := rawdb.ReadOnlyAncients(bc.db)
+	if readonly {
+		frozen = 0
+	}
+
 	updateFn := func(db ethdb.KeyValueWriter, header *types.Header) (*types.Header, bool) {
 		// Rewind the blockchain, ensuring we don't end up with a stateless head
 		// block. Note, depth equality is permitted to allow using SetHead as a
@@ -763,7 +777,7 @@ func (bc *BlockChain) setHeadBeyondRoot(head uint64, time uint64, root common.Ha
 	delFn := func(db ethdb.KeyValueWriter, hash common.Hash, num uint64) {
 		// Ignore the error here since light client won't hit this path
 		frozen, _ := bc.db.Ancients()
-		if num+1 <= frozen {
+		if num+1 <= frozen && !readonly {
 			// Truncate all relative data(header, total difficulty, body, receipt
 			// and canonical hash) from ancient store.
 			if _, err := bc.db.TruncateHead(num); err != nil {
@@ -1560,11 +1574,13 @@ func (bc *BlockChain) InsertChain(chain types.Blocks) (int, error) {
 // racey behaviour. If a sidechain import is in progress, and the historic state
 // is imported, but then new canon-head is added before the actual sidechain
 // completes, then the historic state could be pruned again
-func (bc *BlockChain) insertChain(chain types.Blocks, verifySeals, setHead bool) (int, error) {
+func (bc *BlockChain) insertChain(chain types.Blocks, verifySeals, setHead bool) (_ int, err error) {
 	// If the chain is terminating, don't even bother starting up.
 	if bc.insertStopped() {
 		return 0, nil
 	}
+	ctx, endChain := telemetry.StartSpan(context.Background(), "core.insertChain", telemetry.ChainLength(len(chain)))
+	defer endChain(&err)
 
 	// Start a parallel signature recovery (signer will fluke on fork transition, minimal perf loss)
 	SenderCacher.RecoverFromBlocks(types.MakeSigner(bc.chainConfig, chain[0].Number(), chain[0].Time()), chain)
@@ -1687,7 +1703,15 @@ func (bc *BlockChain) insertChain(chain types.Blocks, verifySeals, setHead bool)
 		return it.index, err
 	}
 	// No validation errors for the first block (or chain prefix skipped)
-	var activeState *state.StateDB
+	var (
+		activeState *state.StateDB
+		endBlock    func(*error) // Ends the span of the block being imported, if any
+	)
+	defer func() {
+		if endBlock != nil {
+			endBlock(&err)
+		}
+	}()
 	defer func() {
 		// The chain importer is starting and stopping trie prefetchers. If a bad
 		// block or other error is hit however, an early return may not properly
@@ -1749,6 +1773,15 @@ func (bc *BlockChain) insertChain(chain types.Blocks, verifySeals, setHead bool)
 			continue
 		}
 
+		// Trace the import phases of the block, the verification running from the
+		// time the iterator awaited its result
+		var blockCtx context.Context
+		blockCtx, endBlock = telemetry.StartSpanAt(ctx, "core.insertBlock", it.verifyStart,
+			telemetry.BlockNumber(block.Number().Int64()), telemetry.BlockHash(block.Hash().Hex()),
+			telemetry.BlockTxs(len(block.Transactions())), telemetry.BlockGas(int64(block.GasUsed())))
+		_, endVerify := telemetry.StartSpanAt(blockCtx, "core.verify", it.verifyStart)
+		endVerify(nil)
+
 		// Retrieve the parent block and it's state to execute on top
 		start := time.Now()
 		parent := it.previous()
@@ -1784,7 +1817,9 @@ func (bc *BlockChain) insertChain(chain types.Blocks, verifySeals, setHead bool)
 
 		// Process block using the parent state as reference point
 		pstart := time.Now()
+		_, endExecute := telemetry.StartSpan(blockCtx, "core.execute")
 		receipts, logs, usedGas, err := bc.processor.Process(block, statedb, bc.vmConfig)
+		endExecute(&err)
 		if err != nil {
 			bc.reportBlock(block, receipts, err)
 			followupInterrupt.Store(true)
@@ -1793,7 +1828,10 @@ func (bc *BlockChain) insertChain(chain types.Blocks, verifySeals, setHead bool)
 		ptime := time.Since(pstart)
 
 		vstart := time.Now()
-		if err := bc.validator.ValidateState(block, statedb, receipts, usedGas); err != nil {
+		_, endValidate := telemetry.StartSpan(blockCtx, "core.validate")
+		err = bc.validator.ValidateState(block, statedb, receipts, usedGas)
+		endValidate(&err)
+		if err != nil {
 			bc.reportBlock(block, receipts, err)
 			followupInterrupt.Store(true)
 			return it.index, err
@@ -1822,12 +1860,14 @@ func (bc *BlockChain) insertChain(chain types.Blocks, verifySeals, setHead bool)
 			wstart = time.Now()
 			status WriteStatus
 		)
+		_, endCommit := telemetry.StartSpan(blockCtx, "core.commit")
 		if !setHead {
 			// Don't set the head, only insert the block
 			err = bc.writeBlockWithState(block, receipts, statedb)
 		} else {
 			status, err = bc.writeBlockAndSetHead(block, receipts, logs, statedb, false)
 		}
+		endCommit(&err)
 		followupInterrupt.Store(true)
 		if err != nil {
 			return it.index, err
@@ -1885,6 +1925,8 @@ func (bc *BlockChain) insertChain(chain types.Blocks, verifySeals, setHead bool)
 				"txs", len(block.Transactions()), "gas", block.GasUsed(), "uncles", len(block.Uncles()),
 				"root", block.Root())
 		}
+		endBlock(nil)
+		endBlock = nil
 	}
 
 	// Any blocks remaining here? The only ones we care about are the future ones
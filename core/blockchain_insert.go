@@ -104,6 +104,8 @@ type insertIterator struct {
 
 	index     int       // Current offset of the iterator
 	validator Validator // Validator to run if verification succeeds
+
+	verifyStart time.Time // Time the verification of the current block was awaited from
 }
 
 // newInsertIterator creates a new iterator based on the given blocks, which are
@@ -128,6 +130,7 @@ func (it *insertIterator) next() (*types.Block, error) {
 	}
 	// Advance the iterator and wait for verification result if not yet done
 	it.index++
+	it.verifyStart = time.Now()
 	if len(it.errors) <= it.index {
 		it.errors = append(it.errors, <-it.results)
 	}
@@ -356,6 +356,17 @@ func (bc *BlockChain) StateAt(root common.Hash) (*state.StateDB, error) {
 	return state.New(root, bc.stateCache, bc.snaps)
 }
 
+// HistoricState returns a new state older than the persisted one, resolved from
+// the state histories of the path-based trie database. The state can't be
+// committed.
+func (bc *BlockChain) HistoricState(root common.Hash) (*state.StateDB, error) {
+	reader, err := bc.triedb.HistoricReader(root)
+	if err != nil {
+		return nil, err
+	}
+	return state.New(root, state.NewHistoricDatabase(bc.stateCache, reader), nil)
+}
+
 // Config retrieves the chain's fork configuration.
 func (bc *BlockChain) Config() ctypes.ChainConfigurator { return bc.chainConfig }
 
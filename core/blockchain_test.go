@@ -21,11 +21,13 @@ import (
 	"fmt"
 	"math/big"
 	"math/rand"
+	"net/http/httptest"
 	"os"
 	"sync"
 	"testing"
 	"time"
 
+	"github.com/ethereum/go-ethereum/cmd/ancient-store-mem/lib"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/math"
 	"github.com/ethereum/go-ethereum/consensus"
@@ -38,11 +40,13 @@ import (
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/eth/tracers/logger"
 	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/ethdb/memorydb"
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/params/types/ctypes"
 	"github.com/ethereum/go-ethereum/params/types/genesisT"
 	"github.com/ethereum/go-ethereum/params/types/goethereum"
 	"github.com/ethereum/go-ethereum/params/vars"
+	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/ethereum/go-ethereum/trie"
 	"github.com/ethereum/go-ethereum/triedb"
 	"github.com/holiman/uint256"
@@ -2062,6 +2066,80 @@ func testInsertReceiptChainRollback(t *testing.T, scheme string) {
 	}
 }
 
+// Tests that a chain opened on a read-only remote ancient store, populated by the
+// node owning it ahead of the local head, is neither rewound nor truncates the
+// ancients.
+func TestReadOnlyRemoteAncients(t *testing.T) {
+	server := rpc.NewServer()
+	defer server.Stop()
+	if err := server.RegisterName("freezer", lib.NewMemFreezerRemoteServerAPI()); err != nil {
+		t.Fatal(err)
+	}
+	httpsrv := httptest.NewServer(server)
+	defer httpsrv.Close()
+
+	gspec := &genesisT.Genesis{
+		Config:  params.TestChainConfig,
+		BaseFee: big.NewInt(vars.InitialBaseFee),
+	}
+	_, blocks, receipts := GenerateChainWithGenesis(gspec, ethash.NewFaker(), 10, func(i int, b *BlockGen) {})
+
+	// The owner of the store snap syncs the first blocks into the ancients
+	ownerDb, err := rawdb.NewDatabaseWithRemoteFreezer(memorydb.New(), httpsrv.URL, "", false)
+	if err != nil {
+		t.Fatalf("failed to open owner database: %v", err)
+	}
+	defer ownerDb.Close()
+	owner, _ := NewBlockChain(ownerDb, nil, gspec, nil, ethash.NewFaker(), vm.Config{}, nil, nil)
+	headers := make([]*types.Header, len(blocks))
+	for i, block := range blocks {
+		headers[i] = block.Header()
+	}
+	if _, err := owner.InsertHeaderChain(headers, 1); err != nil {
+		t.Fatalf("failed to insert owner headers: %v", err)
+	}
+	if _, err := owner.InsertReceiptChain(blocks, receipts, 8); err != nil {
+		t.Fatalf("failed to insert owner receipts: %v", err)
+	}
+	owner.Stop()
+
+	// The follower imported fewer blocks into its own database
+	kvdb := memorydb.New()
+	follower, _ := NewBlockChain(rawdb.NewDatabase(kvdb), nil, gspec, nil, ethash.NewFaker(), vm.Config{}, nil, nil)
+	if _, err := follower.InsertChain(blocks[:3]); err != nil {
+		t.Fatalf("failed to insert follower blocks: %v", err)
+	}
+	follower.Stop()
+
+	db, err := rawdb.NewDatabaseWithRemoteFreezer(kvdb, httpsrv.URL, "", true)
+	if err != nil {
+		t.Fatalf("failed to open follower database: %v", err)
+	}
+	defer db.Close()
+	frozen, _ := db.Ancients()
+	if frozen != 9 {
+		t.Fatalf("frozen items mismatch: have %d, want %d", frozen, 9)
+	}
+	chain, err := NewBlockChain(db, nil, gspec, nil, ethash.NewFaker(), vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to open follower chain: %v", err)
+	}
+	defer chain.Stop()
+	if head := chain.CurrentBlock().Number.Uint64(); head != 3 {
+		t.Fatalf("head block mismatch: have %d, want %d", head, 3)
+	}
+	// Rewinding the chain below the frozen items keeps the ancients of the owner
+	if err := chain.SetHead(2); err != nil {
+		t.Fatalf("failed to rewind chain: %v", err)
+	}
+	if head := chain.CurrentBlock().Number.Uint64(); head != 2 {
+		t.Fatalf("head block mismatch: have %d, want %d", head, 2)
+	}
+	if have, _ := db.Ancients(); have != frozen {
+		t.Fatalf("frozen items mismatch: have %d, want %d", have, frozen)
+	}
+}
+
 // Tests that importing a very large side fork, which is larger than the canon chain,
 // but where the difficulty per block is kept low: this means that it will not
 // overtake the 'canon' chain until after it's passed canon by about 200 blocks.
@@ -4393,3 +4471,77 @@ func TestEIP3651(t *testing.T) {
 		t.Fatalf("sender balance incorrect: expected %d, got %d", expected, actual)
 	}
 }
+
+// Tests that the states older than the persisted one are resolved from the
+// state histories in path scheme.
+func TestHistoricState(t *testing.T) {
+	var (
+		engine    = ethash.NewFaker()
+		key, _    = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		address   = crypto.PubkeyToAddress(key.PublicKey)
+		recipient = common.HexToAddress("0xbbbb")
+		counter   = common.HexToAddress("0xcccc")
+		gspec     = &genesisT.Genesis{
+			Config: params.TestChainConfig,
+			Alloc: genesisT.GenesisAlloc{
+				address: {Balance: big.NewInt(1000000000000000000)},
+				// Increments slot 0 on every call
+				counter: {Code: common.FromHex("600054600101600055")},
+			},
+			BaseFee: big.NewInt(vars.InitialBaseFee),
+		}
+		signer = types.LatestSigner(gspec.Config)
+	)
+	// Send 1 wei to the recipient and call the counter in every block
+	_, blocks, _ := GenerateChainWithGenesis(gspec, engine, 200, func(i int, gen *BlockGen) {
+		for _, to := range []common.Address{recipient, counter} {
+			tx, _ := types.SignTx(types.NewTransaction(gen.TxNonce(address), to, big.NewInt(1), 50000, gen.BaseFee(), nil), signer, key)
+			gen.AddTx(tx)
+		}
+	})
+	db, err := rawdb.NewDatabaseWithFreezer(rawdb.NewMemoryDatabase(), t.TempDir(), "", false)
+	if err != nil {
+		t.Fatalf("failed to create database: %v", err)
+	}
+	defer db.Close()
+	chain, err := NewBlockChain(db, DefaultCacheConfigWithScheme(rawdb.PathScheme), gspec, nil, engine, vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to create tester chain: %v", err)
+	}
+	defer chain.Stop()
+	if n, err := chain.InsertChain(blocks); err != nil {
+		t.Fatalf("block %d: failed to insert into chain: %v", n, err)
+	}
+	for _, number := range []uint64{0, 1, 10, 50} {
+		root := chain.GetHeaderByNumber(number).Root
+		if _, err := chain.StateAt(root); err == nil {
+			t.Fatalf("block %d: state available in the layers", number)
+		}
+		statedb, err := chain.HistoricState(root)
+		if err != nil {
+			t.Fatalf("block %d: failed to resolve historic state: %v", number, err)
+		}
+		if have := statedb.GetBalance(recipient).Uint64(); have != number {
+			t.Errorf("block %d: recipient balance mismatch: have %d, want %d", number, have, number)
+		}
+		if exist := statedb.Exist(recipient); exist != (number > 0) {
+			t.Errorf("block %d: recipient existence mismatch: have %t, want %t", number, exist, number > 0)
+		}
+		if have := statedb.GetNonce(address); have != 2*number {
+			t.Errorf("block %d: sender nonce mismatch: have %d, want %d", number, have, 2*number)
+		}
+		if have := statedb.GetState(counter, common.Hash{}).Big().Uint64(); have != number {
+			t.Errorf("block %d: counter mismatch: have %d, want %d", number, have, number)
+		}
+		if have := statedb.GetState(counter, common.Hash{1}); have != (common.Hash{}) {
+			t.Errorf("block %d: unset slot mismatch: have %x, want zero", number, have)
+		}
+		if code := statedb.GetCode(counter); len(code) == 0 {
+			t.Errorf("block %d: counter code missing", number)
+		}
+	}
+	// The states in the layers are not historic
+	if _, err := chain.HistoricState(chain.CurrentBlock().Root); err == nil {
+		t.Fatal("head state resolved from the state histories")
+	}
+}
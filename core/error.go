@@ -101,6 +101,9 @@ var (
 	// ErrSenderNoEOA is returned if the sender of a transaction is a contract.
 	ErrSenderNoEOA = errors.New("sender not an eoa")
 
+	// ErrSponsorNoEOA is returned if the sponsor of a transaction is a contract.
+	ErrSponsorNoEOA = errors.New("sponsor not an eoa")
+
 	// ErrBlobFeeCapTooLow is returned if the transaction fee cap is less than the
 	// blob gas fee of the block.
 	ErrBlobFeeCapTooLow = errors.New("max fee per blob gas less than block blob gas fee")
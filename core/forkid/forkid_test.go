@@ -210,6 +210,16 @@ func TestCreation(t *testing.T) {
 				{8_784_700, 0, ID{Hash: checksumToBytes(0x0a67075a), Next: 0}},
 			},
 		},
+		// Halo test cases
+		{
+			"halo",
+			params.HaloChainConfig,
+			core.GenesisToBlock(params.DefaultHaloGenesisBlock(), nil),
+			[]testcase{
+				{0, 1700000000, ID{Hash: checksumToBytes(0xc5efaa15), Next: 0}},
+				{1_000_000, 1800000000, ID{Hash: checksumToBytes(0xc5efaa15), Next: 0}},
+			},
+		},
 	}
 	for i, tt := range tests {
 		for j, ttt := range tt.cases {
@@ -507,6 +517,12 @@ func TestGatherForks(t *testing.T) {
 			[]uint64{252_500, 8_784_700},
 			[]uint64{},
 		},
+		{
+			"halo",
+			params.HaloChainConfig,
+			[]uint64{},
+			[]uint64{},
+		},
 	}
 	sliceContains := func(sl []uint64, u uint64) bool {
 		for _, s := range sl {
@@ -574,6 +590,11 @@ func TestGenerateSpecificationCases(t *testing.T) {
 			params.MintMeChainConfig,
 			core.GenesisToBlock(params.DefaultMintMeGenesisBlock(), nil),
 		},
+		{
+			"Halo",
+			params.HaloChainConfig,
+			core.GenesisToBlock(params.DefaultHaloGenesisBlock(), nil),
+		},
 	}
 	for _, tt := range tests {
 		cs := []uint64{0}
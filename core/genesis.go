@@ -321,6 +321,8 @@ func configOrDefault(g *genesisT.Genesis, ghash common.Hash) ctypes.ChainConfigu
 		return params.SepoliaChainConfig
 	case ghash == params.MintMeGenesisHash:
 		return params.MintMeChainConfig
+	case ghash == params.HaloGenesisHash:
+		return params.HaloChainConfig
 	default:
 		return params.AllEthashProtocolChanges
 	}
@@ -431,6 +433,8 @@ func CommitGenesisState(db ethdb.Database, triedb *triedb.Database, blockhash co
 			genesis = params.DefaultMordorGenesisBlock()
 		case params.MintMeGenesisHash:
 			genesis = params.DefaultMintMeGenesisBlock()
+		case params.HaloGenesisHash:
+			genesis = params.DefaultHaloGenesisBlock()
 		case params.HoleskyGenesisHash:
 			genesis = params.DefaultHoleskyGenesisBlock()
 		}
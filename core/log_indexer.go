@@ -0,0 +1,238 @@
+// Copyright 2025 The core-geth Authors
+// This file is part of the core-geth library.
+//
+// The core-geth library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The core-geth library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the core-geth library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/bitutil"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb"
+)
+
+// LogIndexer implements a core.ChainIndexerBackend, building up a secondary log
+// index of the canonical chain. For every address and topic of the logs of a
+// section, the index holds the bitmap of the blocks containing them, permitting
+// to find the matching blocks of wide ranges without scanning the bloom bits.
+type LogIndexer struct {
+	size    uint64         // section size to generate the index for
+	db      ethdb.Database // database instance to read receipts from and write index data into
+	section uint64         // Section is the section number being processed currently
+	head    common.Hash    // Head is the hash of the last header processed
+
+	addresses map[common.Address][]byte // Bitmaps of the blocks by log address
+	topics    map[common.Hash][]byte    // Bitmaps of the blocks by log topic
+}
+
+// NewLogIndexer returns a chain indexer that generates the log index of the
+// canonical chain.
+func NewLogIndexer(db ethdb.Database, size, confirms uint64) *ChainIndexer {
+	backend := &LogIndexer{
+		db:   db,
+		size: size,
+	}
+	table := rawdb.NewTable(db, string(rawdb.LogIndexIndexPrefix))
+
+	return NewChainIndexer(db, table, backend, size, confirms, bloomThrottling, "logindex")
+}
+
+// Reset implements core.ChainIndexerBackend, starting a new log index section.
+func (l *LogIndexer) Reset(ctx context.Context, section uint64, lastSectionHead common.Hash) error {
+	l.section, l.head = section, common.Hash{}
+	l.addresses = make(map[common.Address][]byte)
+	l.topics = make(map[common.Hash][]byte)
+	return nil
+}
+
+// Process implements core.ChainIndexerBackend, adding the addresses and topics of
+// the logs of a new header into the index.
+func (l *LogIndexer) Process(ctx context.Context, header *types.Header) error {
+	l.head = header.Hash()
+
+	// Skip reading the receipts of the blocks without logs
+	if header.Bloom == (types.Bloom{}) {
+		return nil
+	}
+	number := header.Number.Uint64()
+	receipts := rawdb.ReadRawReceipts(l.db, l.head, number)
+	if receipts == nil {
+		return fmt.Errorf("receipts of block #%d [%x..] not found", number, l.head[:4])
+	}
+	offset := number - l.section*l.size
+	for _, receipt := range receipts {
+		for _, log := range receipt.Logs {
+			markLogIndexBlock(l.addresses, log.Address, offset, l.size)
+			for _, topic := range log.Topics {
+				markLogIndexBlock(l.topics, topic, offset, l.size)
+			}
+		}
+	}
+	return nil
+}
+
+// Commit implements core.ChainIndexerBackend, finalizing the log index section
+// and writing it out into the database, replacing any previous version. The
+// section is marked as indexed last, so an interrupted commit leaves it unindexed.
+func (l *LogIndexer) Commit() error {
+	batch := l.db.NewBatch()
+	rawdb.DeleteLogIndexSection(l.db, batch, l.section)
+
+	flush := func() error {
+		if batch.ValueSize() < ethdb.IdealBatchSize {
+			return nil
+		}
+		if err := batch.Write(); err != nil {
+			return err
+		}
+		batch.Reset()
+		return nil
+	}
+	for address, bits := range l.addresses {
+		rawdb.WriteLogIndexAddress(batch, l.section, l.head, address, bitutil.CompressBytes(bits))
+		if err := flush(); err != nil {
+			return err
+		}
+	}
+	for topic, bits := range l.topics {
+		rawdb.WriteLogIndexTopic(batch, l.section, l.head, topic, bitutil.CompressBytes(bits))
+		if err := flush(); err != nil {
+			return err
+		}
+	}
+	rawdb.WriteLogIndexSection(batch, l.section, l.head)
+	return batch.Write()
+}
+
+// Prune returns an empty error since we don't support pruning here.
+func (l *LogIndexer) Prune(threshold uint64) error {
+	return nil
+}
+
+// markLogIndexBlock sets the bit of a block in the bitmap of the given key,
+// creating the bitmap if needed. The bits are ordered most significant first,
+// like the bloom bits vectors.
+func markLogIndexBlock[K comparable](bitmaps map[K][]byte, key K, offset uint64, size uint64) {
+	bits, ok := bitmaps[key]
+	if !ok {
+		bits = make([]byte, size/8)
+		bitmaps[key] = bits
+	}
+	bits[offset/8] |= 1 << (7 - offset%8)
+}
+
+// IndexSections synchronously processes the sections of the canonical chain up to
+// the given head which are not indexed yet, invoking the callback after each one.
+// It allows building an index offline, while the indexer is not started.
+func (c *ChainIndexer) IndexSections(head uint64, progress func(section uint64)) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.verifyLastHead()
+	if head+1 < c.sectionSize+c.confirmsReq {
+		return nil
+	}
+	sections := (head + 1 - c.confirmsReq) / c.sectionSize
+	if sections > c.knownSections {
+		c.knownSections = sections
+	}
+	for c.storedSections < sections {
+		section := c.storedSections
+		var lastHead common.Hash
+		if section > 0 {
+			lastHead = c.SectionHead(section - 1)
+		}
+		newHead, err := c.processSection(section, lastHead)
+		if err != nil {
+			return err
+		}
+		c.setSectionHead(section, newHead)
+		c.setValidSections(section + 1)
+		if progress != nil {
+			progress(section)
+		}
+	}
+	return nil
+}
+
+// ResetSections marks all the sections as not indexed, so they are processed
+// again.
+func (c *ChainIndexer) ResetSections() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.setValidSections(0)
+}
+
+// VerifyLogIndexSection checks the log index of a section of the canonical chain
+// against the logs of its blocks.
+func VerifyLogIndexSection(db ethdb.Database, size, section uint64) error {
+	expected := &LogIndexer{db: db, size: size}
+	expected.Reset(context.Background(), section, common.Hash{})
+
+	for number := section * size; number < (section+1)*size; number++ {
+		hash := rawdb.ReadCanonicalHash(db, number)
+		header := rawdb.ReadHeader(db, hash, number)
+		if header == nil {
+			return fmt.Errorf("canonical block #%d unknown", number)
+		}
+		if err := expected.Process(context.Background(), header); err != nil {
+			return err
+		}
+	}
+	if !rawdb.HasLogIndexSection(db, section, expected.head) {
+		return fmt.Errorf("section %d not indexed", section)
+	}
+	addresses, topics, err := rawdb.ReadLogIndexSection(db, section, expected.head)
+	if err != nil {
+		return err
+	}
+	if err := verifyLogIndexBitmaps(expected.addresses, addresses, size); err != nil {
+		return fmt.Errorf("section %d: address %w", section, err)
+	}
+	if err := verifyLogIndexBitmaps(expected.topics, topics, size); err != nil {
+		return fmt.Errorf("section %d: topic %w", section, err)
+	}
+	return nil
+}
+
+// verifyLogIndexBitmaps compares the expected bitmaps of a section with the
+// compressed ones stored.
+func verifyLogIndexBitmaps[K comparable](expected map[K][]byte, stored map[K][]byte, size uint64) error {
+	for key, bits := range expected {
+		data, ok := stored[key]
+		if !ok {
+			return fmt.Errorf("%v not indexed", key)
+		}
+		have, err := bitutil.DecompressBytes(data, int(size/8))
+		if err != nil {
+			return fmt.Errorf("%v corrupted: %v", key, err)
+		}
+		if !bytes.Equal(have, bits) {
+			return fmt.Errorf("%v blocks mismatch", key)
+		}
+	}
+	for key := range stored {
+		if _, ok := expected[key]; !ok {
+			return fmt.Errorf("%v indexed without logs", key)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,153 @@
+// Copyright 2025 The core-geth Authors
+// This file is part of the core-geth library.
+//
+// The core-geth library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The core-geth library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the core-geth library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/bitutil"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/params/types/genesisT"
+	"github.com/ethereum/go-ethereum/params/vars"
+)
+
+// Tests that the log index is built offline for the confirmed sections, matches
+// the logs of the chain and that corruptions are detected by the verification.
+func TestLogIndexer(t *testing.T) {
+	var (
+		key, _  = crypto.GenerateKey()
+		address = crypto.PubkeyToAddress(key.PublicKey)
+
+		// Contracts emitting LOG1(0x2a) and LOG2(0x2a, 0x2b)
+		emitter1 = common.HexToAddress("0xe1")
+		emitter2 = common.HexToAddress("0xe2")
+		topic1   = common.BigToHash(big.NewInt(0x2a))
+		topic2   = common.BigToHash(big.NewInt(0x2b))
+
+		gspec = &genesisT.Genesis{
+			Config: params.TestChainConfig,
+			Alloc: genesisT.GenesisAlloc{
+				address:  {Balance: big.NewInt(1000000000000000000)},
+				emitter1: {Code: common.FromHex("602a60006000a100")},
+				emitter2: {Code: common.FromHex("602b602a60006000a200")},
+			},
+			BaseFee: big.NewInt(vars.InitialBaseFee),
+		}
+		engine = ethash.NewFaker()
+		signer = types.LatestSigner(gspec.Config)
+	)
+	// Call the first emitter in every 3rd block and the second one in every 5th
+	_, blocks, _ := GenerateChainWithGenesis(gspec, engine, 20, func(i int, gen *BlockGen) {
+		for _, emitter := range []common.Address{emitter1, emitter2} {
+			if (emitter == emitter1 && i%3 != 0) || (emitter == emitter2 && i%5 != 0) {
+				continue
+			}
+			tx, _ := types.SignTx(types.NewTransaction(gen.TxNonce(address), emitter, nil, 50000, gen.BaseFee(), nil), signer, key)
+			gen.AddTx(tx)
+		}
+	})
+	db := rawdb.NewMemoryDatabase()
+	chain, err := NewBlockChain(db, nil, gspec, nil, engine, vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to create chain: %v", err)
+	}
+	defer chain.Stop()
+	if _, err := chain.InsertChain(blocks); err != nil {
+		t.Fatalf("failed to insert chain: %v", err)
+	}
+	// Index the sections of 8 blocks with 2 confirmations, leaving the last
+	// section unprocessed
+	indexer := NewLogIndexer(db, 8, 2)
+	defer indexer.Close()
+
+	var processed []uint64
+	if err := indexer.IndexSections(chain.CurrentBlock().Number.Uint64(), func(section uint64) {
+		processed = append(processed, section)
+	}); err != nil {
+		t.Fatalf("failed to index sections: %v", err)
+	}
+	if sections, _, _ := indexer.Sections(); sections != 2 || len(processed) != 2 {
+		t.Fatalf("indexed sections mismatch: have %d (processed %v), want 2", sections, processed)
+	}
+	for section := uint64(0); section < 2; section++ {
+		if err := VerifyLogIndexSection(db, 8, section); err != nil {
+			t.Fatalf("section %d: verification failed: %v", section, err)
+		}
+	}
+	// Block n (1-based) contains the logs of the generator iteration n-1
+	head := indexer.SectionHead(1)
+	want := func(every int) []byte {
+		bits := make([]byte, 1)
+		for offset := 0; offset < 8; offset++ {
+			if number := 8 + offset; (number-1)%every == 0 {
+				bits[0] |= 1 << (7 - offset)
+			}
+		}
+		return bits
+	}
+	for _, tt := range []struct {
+		name string
+		data []byte
+		want []byte
+	}{
+		{"emitter1", rawdb.ReadLogIndexAddress(db, 1, head, emitter1), want(3)},
+		{"emitter2", rawdb.ReadLogIndexAddress(db, 1, head, emitter2), want(5)},
+		{"topic1", rawdb.ReadLogIndexTopic(db, 1, head, topic1), []byte{want(3)[0] | want(5)[0]}},
+		{"topic2", rawdb.ReadLogIndexTopic(db, 1, head, topic2), want(5)},
+	} {
+		have, err := bitutil.DecompressBytes(tt.data, 1)
+		if err != nil {
+			t.Fatalf("%s: invalid bitmap: %v", tt.name, err)
+		}
+		if !bytes.Equal(have, tt.want) {
+			t.Errorf("%s: blocks mismatch: have %08b, want %08b", tt.name, have, tt.want)
+		}
+	}
+	// Corrupt an entry and check the verification fails
+	rawdb.WriteLogIndexAddress(db, 1, head, emitter1, bitutil.CompressBytes([]byte{0xff}))
+	if err := VerifyLogIndexSection(db, 8, 1); err == nil {
+		t.Fatal("corrupted section verified")
+	}
+	// Index all the sections again, repairing the corruption
+	indexer.ResetSections()
+	if err := indexer.IndexSections(chain.CurrentBlock().Number.Uint64(), nil); err != nil {
+		t.Fatalf("failed to index sections again: %v", err)
+	}
+	if err := VerifyLogIndexSection(db, 8, 1); err != nil {
+		t.Fatalf("reindexed section verification failed: %v", err)
+	}
+	// Delete a section and check it is not reported as indexed anymore
+	batch := db.NewBatch()
+	rawdb.DeleteLogIndexSection(db, batch, 1)
+	if err := batch.Write(); err != nil {
+		t.Fatal(err)
+	}
+	if rawdb.HasLogIndexSection(db, 1, head) {
+		t.Fatal("deleted section still indexed")
+	}
+	if err := VerifyLogIndexSection(db, 8, 1); err == nil {
+		t.Fatal("deleted section verified")
+	}
+}
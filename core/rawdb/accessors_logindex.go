@@ -0,0 +1,121 @@
+// Copyright 2025 The core-geth Authors
+// This file is part of the core-geth library.
+//
+// The core-geth library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The core-geth library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the core-geth library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// Kinds of the values indexed by the log index.
+const (
+	logIndexAddressKind = byte('a')
+	logIndexTopicKind   = byte('t')
+	logIndexHeadKind    = byte('h') // Marker of the sections completely indexed
+)
+
+// HasLogIndexSection reports whether the log index of a section, identified by
+// its last block hash, was completely written. Only then the addresses and the
+// topics without a bitmap have no logs in the section.
+func HasLogIndexSection(db ethdb.KeyValueReader, section uint64, head common.Hash) bool {
+	ok, _ := db.Has(logIndexKey(section, head, logIndexHeadKind, nil))
+	return ok
+}
+
+// WriteLogIndexSection marks the log index of a section as completely written,
+// once all its bitmaps are stored.
+func WriteLogIndexSection(db ethdb.KeyValueWriter, section uint64, head common.Hash) {
+	if err := db.Put(logIndexKey(section, head, logIndexHeadKind, nil), []byte{0x01}); err != nil {
+		log.Crit("Failed to store log index", "err", err)
+	}
+}
+
+// ReadLogIndexAddress retrieves the compressed bitmap of the blocks of a section
+// containing logs emitted by the given address. The section is identified by its
+// last block hash, nil is returned if no block of the section matches or if the
+// section is not indexed.
+func ReadLogIndexAddress(db ethdb.KeyValueReader, section uint64, head common.Hash, address common.Address) []byte {
+	data, _ := db.Get(logIndexKey(section, head, logIndexAddressKind, address.Bytes()))
+	return data
+}
+
+// WriteLogIndexAddress stores the compressed bitmap of the blocks of a section
+// containing logs emitted by the given address.
+func WriteLogIndexAddress(db ethdb.KeyValueWriter, section uint64, head common.Hash, address common.Address, bits []byte) {
+	if err := db.Put(logIndexKey(section, head, logIndexAddressKind, address.Bytes()), bits); err != nil {
+		log.Crit("Failed to store log index", "err", err)
+	}
+}
+
+// ReadLogIndexTopic retrieves the compressed bitmap of the blocks of a section
+// containing logs with the given topic, at any position. The section is identified
+// by its last block hash, nil is returned if no block of the section matches or
+// if the section is not indexed.
+func ReadLogIndexTopic(db ethdb.KeyValueReader, section uint64, head common.Hash, topic common.Hash) []byte {
+	data, _ := db.Get(logIndexKey(section, head, logIndexTopicKind, topic.Bytes()))
+	return data
+}
+
+// WriteLogIndexTopic stores the compressed bitmap of the blocks of a section
+// containing logs with the given topic.
+func WriteLogIndexTopic(db ethdb.KeyValueWriter, section uint64, head common.Hash, topic common.Hash, bits []byte) {
+	if err := db.Put(logIndexKey(section, head, logIndexTopicKind, topic.Bytes()), bits); err != nil {
+		log.Crit("Failed to store log index", "err", err)
+	}
+}
+
+// ReadLogIndexSection retrieves all the compressed bitmaps of a section, by
+// address and by topic.
+func ReadLogIndexSection(db ethdb.Iteratee, section uint64, head common.Hash) (map[common.Address][]byte, map[common.Hash][]byte, error) {
+	var (
+		prefix    = logIndexSectionKey(section, head)
+		addresses = make(map[common.Address][]byte)
+		topics    = make(map[common.Hash][]byte)
+	)
+	it := db.NewIterator(prefix, nil)
+	defer it.Release()
+
+	for it.Next() {
+		key := it.Key()[len(prefix):]
+		switch {
+		case len(key) == 1+common.AddressLength && key[0] == logIndexAddressKind:
+			addresses[common.BytesToAddress(key[1:])] = common.CopyBytes(it.Value())
+		case len(key) == 1+common.HashLength && key[0] == logIndexTopicKind:
+			topics[common.BytesToHash(key[1:])] = common.CopyBytes(it.Value())
+		}
+	}
+	return addresses, topics, it.Error()
+}
+
+// DeleteLogIndexSection removes all the bitmaps of a section, whatever its last
+// block hash, into the given batch.
+func DeleteLogIndexSection(db ethdb.Iteratee, batch ethdb.KeyValueWriter, section uint64) {
+	prefix := append(append([]byte{}, logIndexPrefix...), encodeBlockNumber(section)...)
+
+	it := db.NewIterator(prefix, nil)
+	defer it.Release()
+
+	for it.Next() {
+		if err := batch.Delete(it.Key()); err != nil {
+			log.Crit("Failed to delete log index", "err", err)
+		}
+	}
+	if it.Error() != nil {
+		log.Crit("Failed to delete log index", "err", it.Error())
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright 2025 The core-geth Authors
+// This file is part of the core-geth library.
+//
+// The core-geth library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The core-geth library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the core-geth library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"encoding/binary"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// ReadTxPoolHistory retrieves the serialized transaction pool lifecycle events
+// of a transaction.
+func ReadTxPoolHistory(db ethdb.KeyValueReader, hash common.Hash) []byte {
+	data, _ := db.Get(txPoolHistoryKey(hash))
+	return data
+}
+
+// WriteTxPoolHistory stores the serialized transaction pool lifecycle events of
+// a transaction.
+func WriteTxPoolHistory(db ethdb.KeyValueWriter, hash common.Hash, events []byte) {
+	if err := db.Put(txPoolHistoryKey(hash), events); err != nil {
+		log.Crit("Failed to store txpool history", "err", err)
+	}
+}
+
+// DeleteTxPoolHistory removes the transaction pool lifecycle events of a
+// transaction.
+func DeleteTxPoolHistory(db ethdb.KeyValueWriter, hash common.Hash) {
+	if err := db.Delete(txPoolHistoryKey(hash)); err != nil {
+		log.Crit("Failed to delete txpool history", "err", err)
+	}
+}
+
+// ReadTxPoolHistoryIndex retrieves the hash of the transaction whose history was
+// recorded with the given sequence number.
+func ReadTxPoolHistoryIndex(db ethdb.KeyValueReader, seq uint64) *common.Hash {
+	data, _ := db.Get(txPoolHistoryIndexKey(seq))
+	if len(data) != common.HashLength {
+		return nil
+	}
+	hash := common.BytesToHash(data)
+	return &hash
+}
+
+// WriteTxPoolHistoryIndex stores the hash of the transaction whose history was
+// recorded with the given sequence number.
+func WriteTxPoolHistoryIndex(db ethdb.KeyValueWriter, seq uint64, hash common.Hash) {
+	if err := db.Put(txPoolHistoryIndexKey(seq), hash.Bytes()); err != nil {
+		log.Crit("Failed to store txpool history index", "err", err)
+	}
+}
+
+// DeleteTxPoolHistoryIndex removes the transaction hash recorded with the given
+// sequence number.
+func DeleteTxPoolHistoryIndex(db ethdb.KeyValueWriter, seq uint64) {
+	if err := db.Delete(txPoolHistoryIndexKey(seq)); err != nil {
+		log.Crit("Failed to delete txpool history index", "err", err)
+	}
+}
+
+// ReadTxPoolHistoryBounds retrieves the sequence numbers of the oldest and the
+// next transaction with a recorded history, zeroes if none was recorded.
+func ReadTxPoolHistoryBounds(db ethdb.KeyValueReader) (uint64, uint64) {
+	data, _ := db.Get(txPoolHistoryBoundsKey)
+	if len(data) != 16 {
+		return 0, 0
+	}
+	return binary.BigEndian.Uint64(data[:8]), binary.BigEndian.Uint64(data[8:])
+}
+
+// WriteTxPoolHistoryBounds stores the sequence numbers of the oldest and the
+// next transaction with a recorded history.
+func WriteTxPoolHistoryBounds(db ethdb.KeyValueWriter, first, next uint64) {
+	if err := db.Put(txPoolHistoryBoundsKey, append(encodeBlockNumber(first), encodeBlockNumber(next)...)); err != nil {
+		log.Crit("Failed to store txpool history bounds", "err", err)
+	}
+}
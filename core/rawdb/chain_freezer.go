@@ -39,16 +39,18 @@ const (
 	freezerBatchLimit = 30000
 )
 
-// chainFreezer is a wrapper of freezer with additional chain freezing feature.
-// The background thread will keep moving ancient chain segments from key-value
-// database to flat files for saving space on live database.
+// chainFreezer is a wrapper of ancient store with additional chain freezing
+// feature. The background thread will keep moving ancient chain segments from
+// key-value database to the ancient store, flat files or a remote freezer, for
+// saving space on live database.
 type chainFreezer struct {
 	threshold atomic.Uint64 // Number of recent blocks not to freeze (params.FullImmutabilityThreshold apart from tests)
 
-	*Freezer
-	quit    chan struct{}
-	wg      sync.WaitGroup
-	trigger chan chan struct{} // Manual blocking freeze trigger, test determinism
+	ethdb.AncientStore
+	readonly bool
+	quit     chan struct{}
+	wg       sync.WaitGroup
+	trigger  chan chan struct{} // Manual blocking freeze trigger, test determinism
 }
 
 // newChainFreezer initializes the freezer for ancient chain data.
@@ -57,13 +59,20 @@ func newChainFreezer(datadir string, namespace string, readonly bool) (*chainFre
 	if err != nil {
 		return nil, err
 	}
+	return newChainFreezerWithStore(freezer, readonly), nil
+}
+
+// newChainFreezerWithStore initializes the chain freezing on top of the given
+// ancient store.
+func newChainFreezerWithStore(store ethdb.AncientStore, readonly bool) *chainFreezer {
 	cf := chainFreezer{
-		Freezer: freezer,
-		quit:    make(chan struct{}),
-		trigger: make(chan chan struct{}),
+		AncientStore: store,
+		readonly:     readonly,
+		quit:         make(chan struct{}),
+		trigger:      make(chan chan struct{}),
 	}
 	cf.threshold.Store(vars.FullImmutabilityThreshold)
-	return &cf, nil
+	return &cf
 }
 
 // Close closes the chain freezer instance and terminates the background thread.
@@ -74,7 +83,7 @@ func (f *chainFreezer) Close() error {
 		close(f.quit)
 	}
 	f.wg.Wait()
-	return f.Freezer.Close()
+	return f.AncientStore.Close()
 }
 
 // freeze is a background thread that periodically checks the blockchain for any
@@ -123,8 +132,13 @@ func (f *chainFreezer) freeze(db ethdb.KeyValueStore) {
 		}
 		number := ReadHeaderNumber(nfdb, hash)
 		threshold := f.threshold.Load()
-		frozen := f.frozen.Load()
+		frozen, err := f.Ancients()
 		switch {
+		case err != nil:
+			log.Error("Failed to retrieve frozen block number", "err", err)
+			backoff = true
+			continue
+
 		case number == nil:
 			log.Error("Current full block number unavailable", "hash", hash)
 			backoff = true
@@ -184,7 +198,7 @@ func (f *chainFreezer) freeze(db ethdb.KeyValueStore) {
 
 		// Wipe out side chains also and track dangling side chains
 		var dangling []common.Hash
-		frozen = f.frozen.Load() // Needs reload after during freezeRange
+		frozen, _ = f.Ancients() // Needs reload after during freezeRange
 		for number := first; number < frozen; number++ {
 			// Always keep the genesis block in active database
 			if number != 0 {
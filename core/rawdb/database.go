@@ -83,6 +83,18 @@ func (frdb *freezerdb) Freeze(threshold uint64) error {
 	return nil
 }
 
+// ReadOnlyAncients reports whether the ancient store of the database is only
+// read, being a remote freezer owned by another node. The chain of the node is
+// then kept in the key-value store, and the ancients are never truncated.
+func ReadOnlyAncients(db ethdb.Database) bool {
+	frdb, ok := db.(*freezerdb)
+	if !ok {
+		return false
+	}
+	freezer, ok := frdb.AncientStore.(*chainFreezer)
+	return ok && freezer.readonly
+}
+
 // nofreezedb is a database wrapper that disables freezer data retrievals.
 type nofreezedb struct {
 	ethdb.KeyValueStore
@@ -205,6 +217,27 @@ func NewDatabaseWithFreezer(db ethdb.KeyValueStore, ancient string, namespace st
 		printChainMetadata(db)
 		return nil, err
 	}
+	return newFreezerDatabase(db, frdb, ancient)
+}
+
+// NewDatabaseWithRemoteFreezer creates a high level database on top of a given
+// key-value data store with a remote freezer, served at the given endpoint,
+// moving immutable chain segments into cold storage. The passed ancient indicates
+// the path of root ancient directory where the other freezers, like the state
+// history one, are kept locally. A read-only remote freezer is never written,
+// the chain is then kept in the key-value store and not frozen.
+func NewDatabaseWithRemoteFreezer(db ethdb.KeyValueStore, endpoint string, ancient string, readonly bool) (ethdb.Database, error) {
+	store, err := NewRemoteFreezer(endpoint, readonly)
+	if err != nil {
+		printChainMetadata(db)
+		return nil, err
+	}
+	return newFreezerDatabase(db, newChainFreezerWithStore(store, readonly), ancient)
+}
+
+// newFreezerDatabase checks the given chain freezer is consistent with the key-
+// value data store, and combines the two, starting the freezing of the chain.
+func newFreezerDatabase(db ethdb.KeyValueStore, frdb *chainFreezer, ancient string) (ethdb.Database, error) {
 	// Since the freezer can be stored separately from the user's key-value database,
 	// there's a fairly high probability that the user requests invalid combinations
 	// of the freezer and database. Ensure that we don't shoot ourselves in the foot
@@ -356,13 +389,15 @@ func PreexistingDatabase(path string) string {
 // OpenOptions contains the options to apply when opening a database.
 // OBS: If AncientsDirectory is empty, it indicates that no freezer is to be used.
 type OpenOptions struct {
-	Type              string // "leveldb" | "pebble"
-	Directory         string // the datadir
-	AncientsDirectory string // the ancients-dir
-	Namespace         string // the namespace for database relevant metrics
-	Cache             int    // the capacity(in megabytes) of the data caching
-	Handles           int    // number of files to be open simultaneously
-	ReadOnly          bool
+	Type                   string // "leveldb" | "pebble"
+	Directory              string // the datadir
+	AncientsDirectory      string // the ancients-dir
+	AncientsRemote         string // the endpoint of a remote chain freezer, replacing the local one
+	AncientsRemoteReadOnly bool   // whether the remote chain freezer is owned by another node, and only read
+	Namespace              string // the namespace for database relevant metrics
+	Cache                  int    // the capacity(in megabytes) of the data caching
+	Handles                int    // number of files to be open simultaneously
+	ReadOnly               bool
 	// Ephemeral means that filesystem sync operations should be avoided: data integrity in the face of
 	// a crash is not important. This option should typically be used in tests.
 	Ephemeral bool
@@ -402,7 +437,8 @@ func openKeyValueDatabase(o OpenOptions) (ethdb.Database, error) {
 // integrates it with a freezer database -- if the AncientDir option has been
 // set on the provided OpenOptions.
 // The passed o.AncientDir indicates the path of root ancient directory where
-// the chain freezer can be opened.
+// the chain freezer can be opened, unless o.AncientsRemote is set and the chain
+// freezer is served remotely.
 func Open(o OpenOptions) (ethdb.Database, error) {
 	kvdb, err := openKeyValueDatabase(o)
 	if err != nil {
@@ -411,7 +447,12 @@ func Open(o OpenOptions) (ethdb.Database, error) {
 	if len(o.AncientsDirectory) == 0 {
 		return kvdb, nil
 	}
-	frdb, err := NewDatabaseWithFreezer(kvdb, o.AncientsDirectory, o.Namespace, o.ReadOnly)
+	var frdb ethdb.Database
+	if len(o.AncientsRemote) != 0 {
+		frdb, err = NewDatabaseWithRemoteFreezer(kvdb, o.AncientsRemote, o.AncientsDirectory, o.ReadOnly || o.AncientsRemoteReadOnly)
+	} else {
+		frdb, err = NewDatabaseWithFreezer(kvdb, o.AncientsDirectory, o.Namespace, o.ReadOnly)
+	}
 	if err != nil {
 		kvdb.Close()
 		return nil, err
@@ -479,6 +520,8 @@ func InspectDatabase(db ethdb.Database, keyPrefix, keyStart []byte) error {
 		bloomBits       stat
 		beaconHeaders   stat
 		cliqueSnaps     stat
+		txPoolHistory   stat
+		logIndex        stat
 
 		// Les statistic
 		chtTrieNodes   stat
@@ -541,6 +584,14 @@ func InspectDatabase(db ethdb.Database, keyPrefix, keyStart []byte) error {
 			beaconHeaders.Add(size)
 		case bytes.HasPrefix(key, CliqueSnapshotPrefix) && len(key) == 7+common.HashLength:
 			cliqueSnaps.Add(size)
+		case bytes.HasPrefix(key, txPoolHistoryPrefix) && len(key) == (len(txPoolHistoryPrefix)+common.HashLength):
+			txPoolHistory.Add(size)
+		case bytes.HasPrefix(key, txPoolHistoryIndexPrefix) && len(key) == (len(txPoolHistoryIndexPrefix)+8):
+			txPoolHistory.Add(size)
+		case bytes.HasPrefix(key, logIndexPrefix) && len(key) > (len(logIndexPrefix)+8+common.HashLength+1):
+			logIndex.Add(size)
+		case bytes.HasPrefix(key, LogIndexIndexPrefix):
+			logIndex.Add(size)
 		case bytes.HasPrefix(key, ChtTablePrefix) ||
 			bytes.HasPrefix(key, ChtIndexTablePrefix) ||
 			bytes.HasPrefix(key, ChtPrefix): // Canonical hash trie
@@ -557,6 +608,7 @@ func InspectDatabase(db ethdb.Database, keyPrefix, keyStart []byte) error {
 				snapshotGeneratorKey, snapshotRecoveryKey, txIndexTailKey, fastTxLookupLimitKey,
 				uncleanShutdownKey, badBlockKey, transitionStatusKey, skeletonSyncStatusKey,
 				persistentStateIDKey, trieJournalKey, snapshotSyncStatusKey, snapSyncStatusFlagKey,
+				txPoolHistoryBoundsKey,
 			} {
 				if bytes.Equal(key, meta) {
 					metadata.Add(size)
@@ -594,6 +646,8 @@ func InspectDatabase(db ethdb.Database, keyPrefix, keyStart []byte) error {
 		{"Key-Value store", "Storage snapshot", storageSnaps.Size(), storageSnaps.Count()},
 		{"Key-Value store", "Beacon sync headers", beaconHeaders.Size(), beaconHeaders.Count()},
 		{"Key-Value store", "Clique snapshots", cliqueSnaps.Size(), cliqueSnaps.Count()},
+		{"Key-Value store", "Txpool history", txPoolHistory.Size(), txPoolHistory.Count()},
+		{"Key-Value store", "Log index", logIndex.Size(), logIndex.Count()},
 		{"Key-Value store", "Singleton metadata", metadata.Size(), metadata.Count()},
 		{"Light client", "CHT trie nodes", chtTrieNodes.Size(), chtTrieNodes.Count()},
 		{"Light client", "Bloom trie nodes", bloomTrieNodes.Size(), bloomTrieNodes.Count()},
@@ -0,0 +1,258 @@
+// Copyright 2025 The core-geth Authors
+// This file is part of the core-geth library.
+//
+// The core-geth library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The core-geth library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the core-geth library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+const (
+	// remoteFreezerTimeout is the maximum time a call to the remote freezer may
+	// take, so that an unresponsive store doesn't stall the node forever.
+	remoteFreezerTimeout = time.Minute
+
+	// remoteFreezerChunkSize is the number of item bytes after which a write
+	// operation is sent to the remote freezer in another request. Hex encoded,
+	// a chunk stays below the default 5MB request limit of the RPC server.
+	remoteFreezerChunkSize = 2 * 1024 * 1024
+
+	// remoteFreezerChunkItems is the maximum number of items sent to the remote
+	// freezer in a request.
+	remoteFreezerChunkItems = 4096
+)
+
+// remoteAncientItem is an item appended to the remote freezer.
+type remoteAncientItem struct {
+	Kind   string        `json:"kind"`
+	Number uint64        `json:"number"`
+	Data   hexutil.Bytes `json:"data"`
+}
+
+// RemoteFreezer is an ancient store served by another process over RPC, through
+// the methods of the "freezer" namespace. It allows several nodes to share a
+// single chain history store, written by the node owning it only, and opened
+// read-only by the others.
+type RemoteFreezer struct {
+	client   *rpc.Client
+	readonly bool
+}
+
+// NewRemoteFreezer connects to the ancient store served at the given endpoint,
+// an IPC path or an HTTP or WebSocket URL.
+func NewRemoteFreezer(endpoint string, readonly bool) (*RemoteFreezer, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), remoteFreezerTimeout)
+	defer cancel()
+
+	client, err := rpc.DialContext(ctx, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	return newRemoteFreezer(client, readonly), nil
+}
+
+// newRemoteFreezer creates a remote freezer on top of an RPC client.
+func newRemoteFreezer(client *rpc.Client, readonly bool) *RemoteFreezer {
+	return &RemoteFreezer{client: client, readonly: readonly}
+}
+
+// call performs a call to the remote freezer, failing after remoteFreezerTimeout.
+func (f *RemoteFreezer) call(result interface{}, method string, args ...interface{}) error {
+	ctx, cancel := context.WithTimeout(context.Background(), remoteFreezerTimeout)
+	defer cancel()
+
+	return f.client.CallContext(ctx, result, method, args...)
+}
+
+// HasAncient returns an indicator whether the specified ancient data exists
+// in the remote freezer.
+func (f *RemoteFreezer) HasAncient(kind string, number uint64) (bool, error) {
+	var res bool
+	err := f.call(&res, "freezer_hasAncient", kind, number)
+	return res, err
+}
+
+// Ancient retrieves an ancient binary blob from the remote freezer.
+func (f *RemoteFreezer) Ancient(kind string, number uint64) ([]byte, error) {
+	var res hexutil.Bytes
+	if err := f.call(&res, "freezer_ancient", kind, number); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// AncientRange retrieves multiple items in sequence, starting from the index 'start'.
+func (f *RemoteFreezer) AncientRange(kind string, start, count, maxBytes uint64) ([][]byte, error) {
+	var res []hexutil.Bytes
+	if err := f.call(&res, "freezer_ancientRange", kind, start, count, maxBytes); err != nil {
+		return nil, err
+	}
+	items := make([][]byte, len(res))
+	for i, item := range res {
+		items[i] = item
+	}
+	return items, nil
+}
+
+// Ancients returns the length of the frozen items.
+func (f *RemoteFreezer) Ancients() (uint64, error) {
+	var res uint64
+	err := f.call(&res, "freezer_ancients")
+	return res, err
+}
+
+// Tail returns the number of first stored item in the remote freezer.
+func (f *RemoteFreezer) Tail() (uint64, error) {
+	var res uint64
+	err := f.call(&res, "freezer_tail")
+	return res, err
+}
+
+// AncientSize returns the ancient size of the specified category.
+func (f *RemoteFreezer) AncientSize(kind string) (uint64, error) {
+	var res uint64
+	err := f.call(&res, "freezer_ancientSize", kind)
+	return res, err
+}
+
+// ReadAncients runs the given read operation. The remote freezer can't be locked,
+// so the store must only be written by a single node at a time.
+func (f *RemoteFreezer) ReadAncients(fn func(ethdb.AncientReaderOp) error) (err error) {
+	return fn(f)
+}
+
+// ModifyAncients runs the given write operation, sending its items to the remote
+// freezer in chunks of whole blocks. If a chunk is rejected, the chunks already
+// written are truncated away, so nothing is written if the operation fails.
+func (f *RemoteFreezer) ModifyAncients(fn func(ethdb.AncientWriteOp) error) (int64, error) {
+	if f.readonly {
+		return 0, errReadOnly
+	}
+	batch := new(remoteFreezerBatch)
+	if err := fn(batch); err != nil {
+		return 0, err
+	}
+	if len(batch.items) == 0 {
+		return 0, nil
+	}
+	prevItem, err := f.Ancients()
+	if err != nil {
+		return 0, err
+	}
+	for _, chunk := range batch.chunks(remoteFreezerChunkSize, remoteFreezerChunkItems) {
+		if err := f.call(nil, "freezer_modifyAncients", chunk); err != nil {
+			if _, terr := f.TruncateHead(prevItem); terr != nil {
+				log.Error("Remote freezer rollback failed", "err", terr)
+			}
+			return 0, err
+		}
+	}
+	return batch.size, nil
+}
+
+// TruncateHead discards any recent data above the provided threshold number.
+// It returns the previous head number.
+func (f *RemoteFreezer) TruncateHead(items uint64) (uint64, error) {
+	if f.readonly {
+		return 0, errReadOnly
+	}
+	var res uint64
+	err := f.call(&res, "freezer_truncateHead", items)
+	return res, err
+}
+
+// TruncateTail discards any recent data below the provided threshold number.
+func (f *RemoteFreezer) TruncateTail(tail uint64) (uint64, error) {
+	if f.readonly {
+		return 0, errReadOnly
+	}
+	var res uint64
+	err := f.call(&res, "freezer_truncateTail", tail)
+	return res, err
+}
+
+// Sync flushes the data of the remote freezer to its storage.
+func (f *RemoteFreezer) Sync() error {
+	return f.call(nil, "freezer_sync")
+}
+
+// MigrateTable is not supported by the remote freezer, the tables must be
+// migrated by the server.
+func (f *RemoteFreezer) MigrateTable(kind string, convert convertLegacyFn) error {
+	return errNotSupported
+}
+
+// Close disconnects from the remote freezer, leaving the server running for the
+// other nodes.
+func (f *RemoteFreezer) Close() error {
+	f.client.Close()
+	return nil
+}
+
+// remoteFreezerBatch collects the items of a write operation on the remote
+// freezer.
+type remoteFreezerBatch struct {
+	items []remoteAncientItem
+	size  int64
+}
+
+// Append adds an RLP-encoded item of the given kind.
+func (batch *remoteFreezerBatch) Append(kind string, number uint64, item interface{}) error {
+	data, err := rlp.EncodeToBytes(item)
+	if err != nil {
+		return err
+	}
+	return batch.AppendRaw(kind, number, data)
+}
+
+// AppendRaw adds an item of the given kind.
+func (batch *remoteFreezerBatch) AppendRaw(kind string, number uint64, item []byte) error {
+	batch.items = append(batch.items, remoteAncientItem{Kind: kind, Number: number, Data: common.CopyBytes(item)})
+	batch.size += int64(len(item))
+	return nil
+}
+
+// chunks splits the items of the batch, ordered by number, into chunks of at
+// most the given number of bytes and items. The items of a number are never
+// split, so that every chunk leaves the tables of the remote freezer of equal
+// length, even if it exceeds the limits.
+func (batch *remoteFreezerBatch) chunks(size int, items int) [][]remoteAncientItem {
+	sort.SliceStable(batch.items, func(i, j int) bool {
+		return batch.items[i].Number < batch.items[j].Number
+	})
+	var (
+		chunks    [][]remoteAncientItem
+		start     int
+		chunkSize int
+	)
+	for i, item := range batch.items {
+		if i > start && item.Number != batch.items[i-1].Number && (chunkSize >= size || i-start >= items) {
+			chunks = append(chunks, batch.items[start:i])
+			start, chunkSize = i, 0
+		}
+		chunkSize += len(item.Data)
+	}
+	return append(chunks, batch.items[start:])
+}
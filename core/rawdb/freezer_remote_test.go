@@ -0,0 +1,247 @@
+// Copyright 2025 The core-geth Authors
+// This file is part of the core-geth library.
+//
+// The core-geth library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The core-geth library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the core-geth library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/cmd/ancient-store-mem/lib"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/ethdb/memorydb"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// writeTestChain writes a canonical chain of blocks 0 to head into the given
+// key-value store, returning the hashes of the blocks.
+func writeTestChain(db ethdb.KeyValueWriter, head uint64) []common.Hash {
+	var (
+		hashes []common.Hash
+		parent common.Hash
+	)
+	for number := uint64(0); number <= head; number++ {
+		block := types.NewBlockWithHeader(&types.Header{
+			ParentHash: parent,
+			Number:     new(big.Int).SetUint64(number),
+			Difficulty: big.NewInt(1),
+			Extra:      []byte("remote freezer test"),
+		})
+		WriteBlock(db, block)
+		WriteReceipts(db, block.Hash(), number, nil)
+		WriteTd(db, block.Hash(), number, new(big.Int).SetUint64(number+1))
+		WriteCanonicalHash(db, block.Hash(), number)
+		WriteHeadBlockHash(db, block.Hash())
+		WriteHeadHeaderHash(db, block.Hash())
+
+		hashes = append(hashes, block.Hash())
+		parent = block.Hash()
+	}
+	return hashes
+}
+
+// Tests that the chain freezer moves the ancient blocks to a remote freezer, and
+// that they are read back from it.
+func TestRemoteFreezer(t *testing.T) {
+	server := rpc.NewServer()
+	defer server.Stop()
+	if err := server.RegisterName("freezer", lib.NewMemFreezerRemoteServerAPI()); err != nil {
+		t.Fatal(err)
+	}
+	client := rpc.DialInProc(server)
+
+	kvdb := memorydb.New()
+	hashes := writeTestChain(kvdb, 10)
+
+	db, err := newFreezerDatabase(kvdb, newChainFreezerWithStore(newRemoteFreezer(client, false), false), "")
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	defer db.Close()
+
+	// Freeze all but the two most recent blocks
+	if err := db.(*freezerdb).Freeze(2); err != nil {
+		t.Fatalf("failed to freeze: %v", err)
+	}
+	if frozen, err := db.Ancients(); err != nil || frozen != 9 {
+		t.Fatalf("frozen items mismatch: have %d (%v), want 9", frozen, err)
+	}
+	for number, hash := range hashes {
+		if have := ReadCanonicalHash(db, uint64(number)); have != hash {
+			t.Errorf("block %d: canonical hash mismatch: have %x, want %x", number, have, hash)
+		}
+		if header := ReadHeader(db, hash, uint64(number)); header == nil || header.Hash() != hash {
+			t.Errorf("block %d: header mismatch", number)
+		}
+		if body := ReadBody(db, hash, uint64(number)); body == nil {
+			t.Errorf("block %d: body missing", number)
+		}
+		if td := ReadTd(db, hash, uint64(number)); td == nil || td.Uint64() != uint64(number+1) {
+			t.Errorf("block %d: total difficulty mismatch: have %v, want %d", number, td, number+1)
+		}
+		// The frozen blocks but the genesis are deleted from the key-value store
+		if frozen := number > 0 && number < 9; frozen == HasHeader(NewDatabase(kvdb), hash, uint64(number)) {
+			t.Errorf("block %d: key-value store presence mismatch: have %t, want %t", number, !frozen, frozen)
+		}
+	}
+	items, err := db.AncientRange(ChainFreezerHashTable, 2, 5, 1)
+	if err != nil || len(items) != 1 || common.BytesToHash(items[0]) != hashes[2] {
+		t.Fatalf("range mismatch: have %x (%v), want [%x]", items, err, hashes[2])
+	}
+	// Check the writes are validated by the server and rejected at once
+	_, err = db.ModifyAncients(func(op ethdb.AncientWriteOp) error {
+		if err := op.AppendRaw(ChainFreezerHashTable, 9, hashes[9][:]); err != nil {
+			return err
+		}
+		return op.AppendRaw(ChainFreezerHeaderTable, 9, []byte{0xc0})
+	})
+	if err == nil {
+		t.Fatal("incomplete write accepted")
+	}
+	if has, _ := db.HasAncient(ChainFreezerHashTable, 9); has {
+		t.Fatal("incomplete write partially applied")
+	}
+	// Truncate both ends of the store
+	if old, err := db.TruncateHead(7); err != nil || old != 9 {
+		t.Fatalf("head truncation mismatch: have %d (%v), want 9", old, err)
+	}
+	if old, err := db.TruncateTail(3); err != nil || old != 0 {
+		t.Fatalf("tail truncation mismatch: have %d (%v), want 0", old, err)
+	}
+	if frozen, _ := db.Ancients(); frozen != 7 {
+		t.Errorf("frozen items mismatch: have %d, want 7", frozen)
+	}
+	if tail, _ := db.Tail(); tail != 3 {
+		t.Errorf("tail mismatch: have %d, want 3", tail)
+	}
+	for _, number := range []uint64{2, 7} {
+		if has, _ := db.HasAncient(ChainFreezerHashTable, number); has {
+			t.Errorf("item %d: not truncated", number)
+		}
+	}
+	// Check a read-only store rejects the writes
+	readonly := newRemoteFreezer(rpc.DialInProc(server), true)
+	defer readonly.Close()
+	if _, err := readonly.TruncateHead(0); !errors.Is(err, errReadOnly) {
+		t.Errorf("read-only truncation error mismatch: have %v, want %v", err, errReadOnly)
+	}
+}
+
+// Tests that large writes are sent to the remote freezer in chunks of whole
+// blocks, and that the chunks already written are rolled back if one fails.
+func TestRemoteFreezerChunks(t *testing.T) {
+	server := rpc.NewServer()
+	defer server.Stop()
+	if err := server.RegisterName("freezer", lib.NewMemFreezerRemoteServerAPI()); err != nil {
+		t.Fatal(err)
+	}
+	freezer := newRemoteFreezer(rpc.DialInProc(server), false)
+	defer freezer.Close()
+
+	// Write blocks with headers large enough to need a chunk each, the last one
+	// lacking its difficulty
+	var (
+		header = make([]byte, remoteFreezerChunkSize)
+		tables = []string{ChainFreezerHashTable, ChainFreezerHeaderTable, ChainFreezerBodiesTable, ChainFreezerReceiptTable, ChainFreezerDifficultyTable}
+	)
+	write := func(blocks uint64, complete bool) error {
+		_, err := freezer.ModifyAncients(func(op ethdb.AncientWriteOp) error {
+			for number := uint64(0); number < blocks; number++ {
+				for _, kind := range tables {
+					if !complete && number == blocks-1 && kind == ChainFreezerDifficultyTable {
+						continue
+					}
+					data := []byte{0xc0}
+					if kind == ChainFreezerHeaderTable {
+						data = header
+					}
+					if err := op.AppendRaw(kind, number, data); err != nil {
+						return err
+					}
+				}
+			}
+			return nil
+		})
+		return err
+	}
+	if err := write(3, false); err == nil {
+		t.Fatal("incomplete write accepted")
+	}
+	if frozen, _ := freezer.Ancients(); frozen != 0 {
+		t.Fatalf("failed write not rolled back: have %d items, want 0", frozen)
+	}
+	if has, _ := freezer.HasAncient(ChainFreezerHeaderTable, 0); has {
+		t.Fatal("failed write partially applied")
+	}
+	if err := write(3, true); err != nil {
+		t.Fatalf("failed to write: %v", err)
+	}
+	if frozen, _ := freezer.Ancients(); frozen != 3 {
+		t.Fatalf("frozen items mismatch: have %d, want 3", frozen)
+	}
+	// Check the items of a number are kept together, whatever the limits
+	batch := new(remoteFreezerBatch)
+	for _, kind := range tables[:2] {
+		for number := uint64(0); number < 3; number++ {
+			batch.AppendRaw(kind, number, []byte{0x01})
+		}
+	}
+	chunks := batch.chunks(1, 1)
+	if len(chunks) != 3 {
+		t.Fatalf("chunk count mismatch: have %d, want 3", len(chunks))
+	}
+	for i, chunk := range chunks {
+		if len(chunk) != 2 || chunk[0].Number != uint64(i) || chunk[1].Number != uint64(i) {
+			t.Errorf("chunk %d: items mismatch: %v", i, chunk)
+		}
+	}
+}
+
+// Tests that a database on a remote freezer owned by another node doesn't freeze
+// the chain into it.
+func TestRemoteFreezerReadOnly(t *testing.T) {
+	server := rpc.NewServer()
+	defer server.Stop()
+	if err := server.RegisterName("freezer", lib.NewMemFreezerRemoteServerAPI()); err != nil {
+		t.Fatal(err)
+	}
+	kvdb := memorydb.New()
+	hashes := writeTestChain(kvdb, 10)
+
+	db, err := newFreezerDatabase(kvdb, newChainFreezerWithStore(newRemoteFreezer(rpc.DialInProc(server), true), true), "")
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	defer db.Close()
+
+	if err := db.(*freezerdb).Freeze(2); !errors.Is(err, errReadOnly) {
+		t.Fatalf("freeze error mismatch: have %v, want %v", err, errReadOnly)
+	}
+	if _, err := db.ModifyAncients(func(ethdb.AncientWriteOp) error { return nil }); !errors.Is(err, errReadOnly) {
+		t.Fatalf("write error mismatch: have %v, want %v", err, errReadOnly)
+	}
+	if frozen, _ := db.Ancients(); frozen != 0 {
+		t.Fatalf("frozen items mismatch: have %d, want 0", frozen)
+	}
+	for number, hash := range hashes {
+		if !HasHeader(NewDatabase(kvdb), hash, uint64(number)) {
+			t.Errorf("block %d: missing from the key-value store", number)
+		}
+	}
+}
@@ -93,6 +93,10 @@ var (
 	// transitionStatusKey tracks the eth2 transition status.
 	transitionStatusKey = []byte("eth2-transition")
 
+	// txPoolHistoryBoundsKey tracks the sequence numbers of the oldest and the
+	// next transaction with a recorded transaction pool history.
+	txPoolHistoryBoundsKey = []byte("TxPoolHistoryBounds")
+
 	// snapSyncStatusFlagKey flags that status of snap sync.
 	snapSyncStatusFlagKey = []byte("SnapSyncStatus")
 
@@ -124,6 +128,9 @@ var (
 	// BloomBitsIndexPrefix is the data table of a chain indexer to track its progress
 	BloomBitsIndexPrefix = []byte("iB")
 
+	// LogIndexIndexPrefix is the data table of the log indexer to track its progress
+	LogIndexIndexPrefix = []byte("iL")
+
 	ChtPrefix           = []byte("chtRootV2-") // ChtPrefix + chtNum (uint64 big endian) -> trie root hash
 	ChtTablePrefix      = []byte("cht-")
 	ChtIndexTablePrefix = []byte("chtIndexV2-")
@@ -134,6 +141,11 @@ var (
 
 	CliqueSnapshotPrefix = []byte("clique-")
 
+	txPoolHistoryPrefix      = []byte("txpool-history-") // txPoolHistoryPrefix + hash -> transaction pool lifecycle events
+	txPoolHistoryIndexPrefix = []byte("txpool-index-")   // txPoolHistoryIndexPrefix + seq (uint64 big endian) -> hash
+
+	logIndexPrefix = []byte("log-index-") // logIndexPrefix + section (uint64 big endian) + hash + kind + address or topic -> blocks bitmap
+
 	BestUpdateKey         = []byte("update-")    // bigEndian64(syncPeriod) -> RLP(types.LightClientUpdate)  (nextCommittee only referenced by root hash)
 	FixedCommitteeRootKey = []byte("fixedRoot-") // bigEndian64(syncPeriod) -> committee root hash
 	SyncCommitteeKey      = []byte("committee-") // bigEndian64(syncPeriod) -> serialized committee
@@ -197,6 +209,26 @@ func txLookupKey(hash common.Hash) []byte {
 	return append(txLookupPrefix, hash.Bytes()...)
 }
 
+// txPoolHistoryKey = txPoolHistoryPrefix + hash
+func txPoolHistoryKey(hash common.Hash) []byte {
+	return append(txPoolHistoryPrefix, hash.Bytes()...)
+}
+
+// txPoolHistoryIndexKey = txPoolHistoryIndexPrefix + seq (uint64 big endian)
+func txPoolHistoryIndexKey(seq uint64) []byte {
+	return append(txPoolHistoryIndexPrefix, encodeBlockNumber(seq)...)
+}
+
+// logIndexSectionKey = logIndexPrefix + section (uint64 big endian) + hash
+func logIndexSectionKey(section uint64, hash common.Hash) []byte {
+	return append(append(append([]byte{}, logIndexPrefix...), encodeBlockNumber(section)...), hash.Bytes()...)
+}
+
+// logIndexKey = logIndexPrefix + section (uint64 big endian) + hash + kind + value
+func logIndexKey(section uint64, hash common.Hash, kind byte, value []byte) []byte {
+	return append(append(logIndexSectionKey(section, hash), kind), value...)
+}
+
 // accountSnapshotKey = SnapshotAccountPrefix + hash
 func accountSnapshotKey(hash common.Hash) []byte {
 	return append(SnapshotAccountPrefix, hash.Bytes()...)
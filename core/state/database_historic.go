@@ -0,0 +1,152 @@
+// Copyright 2025 The core-geth Authors
+// This file is part of the core-geth library.
+//
+// The core-geth library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The core-geth library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the core-geth library. If not, see <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/ethereum/go-ethereum/trie/trienode"
+)
+
+// errHistoricState is returned by the operations a historic state can't serve,
+// as it's not backed by trie nodes.
+var errHistoricState = errors.New("not supported by historic state")
+
+// HistoricReader resolves the accounts and storage slots of a historic state,
+// such as the state history reader of the path-based trie database.
+type HistoricReader interface {
+	// Root returns the root of the historic state.
+	Root() common.Hash
+
+	// Account returns the account at the historic state, nil if it doesn't exist.
+	Account(address common.Address) (*types.StateAccount, error)
+
+	// Storage returns the value of the storage slot with the given hash at the
+	// historic state, nil if it doesn't exist.
+	Storage(address common.Address, slot common.Hash) ([]byte, error)
+}
+
+// historicDB is a read-only state database serving a historic state, with the
+// contract codes of the wrapped database.
+type historicDB struct {
+	Database
+	reader HistoricReader
+}
+
+// NewHistoricDatabase creates a state database serving the historic state of
+// the given reader, retrieving the contract codes from the given database.
+//
+// The historic state can be modified, but not committed: the modifications are
+// kept by the state objects only, and the roots of the tries are never updated.
+func NewHistoricDatabase(db Database, reader HistoricReader) Database {
+	return &historicDB{Database: db, reader: reader}
+}
+
+// OpenTrie opens the account trie of the historic state.
+func (db *historicDB) OpenTrie(root common.Hash) (Trie, error) {
+	if types.TrieRootHash(root) != db.reader.Root() {
+		return nil, fmt.Errorf("state %#x is not the historic state %#x", root, db.reader.Root())
+	}
+	return &historicTrie{reader: db.reader, root: db.reader.Root()}, nil
+}
+
+// OpenStorageTrie opens the storage trie of an account of the historic state.
+func (db *historicDB) OpenStorageTrie(stateRoot common.Hash, address common.Address, root common.Hash, self Trie) (Trie, error) {
+	return &historicTrie{reader: db.reader, root: root}, nil
+}
+
+// CopyTrie returns an independent copy of the given trie.
+func (db *historicDB) CopyTrie(t Trie) Trie {
+	if t, ok := t.(*historicTrie); ok {
+		cpy := *t
+		return &cpy
+	}
+	return db.Database.CopyTrie(t)
+}
+
+// historicTrie is an account or storage trie of a historic state, resolving
+// its items with the historic reader.
+type historicTrie struct {
+	reader HistoricReader
+	root   common.Hash
+}
+
+// GetKey returns nil, the preimages are not tracked.
+func (t *historicTrie) GetKey(key []byte) []byte {
+	return nil
+}
+
+// GetAccount returns the account at the historic state.
+func (t *historicTrie) GetAccount(address common.Address) (*types.StateAccount, error) {
+	return t.reader.Account(address)
+}
+
+// GetStorage returns the value of the storage slot at the historic state.
+func (t *historicTrie) GetStorage(addr common.Address, key []byte) ([]byte, error) {
+	return t.reader.Storage(addr, crypto.Keccak256Hash(key))
+}
+
+// UpdateAccount discards the write, the account being kept by its state object.
+func (t *historicTrie) UpdateAccount(address common.Address, account *types.StateAccount) error {
+	return nil
+}
+
+// UpdateStorage discards the write, the slot being kept by its state object.
+func (t *historicTrie) UpdateStorage(addr common.Address, key, value []byte) error {
+	return nil
+}
+
+// DeleteAccount discards the deletion, tracked by the state object.
+func (t *historicTrie) DeleteAccount(address common.Address) error {
+	return nil
+}
+
+// DeleteStorage discards the deletion, tracked by the state object.
+func (t *historicTrie) DeleteStorage(addr common.Address, key []byte) error {
+	return nil
+}
+
+// UpdateContractCode does nothing, the codes are not part of the trie.
+func (t *historicTrie) UpdateContractCode(address common.Address, codeHash common.Hash, code []byte) error {
+	return nil
+}
+
+// Hash returns the root of the trie at the historic state.
+func (t *historicTrie) Hash() common.Hash {
+	return t.root
+}
+
+// Commit returns an error, the historic state being read-only.
+func (t *historicTrie) Commit(collectLeaf bool) (common.Hash, *trienode.NodeSet, error) {
+	return common.Hash{}, nil, errHistoricState
+}
+
+// NodeIterator returns an error, the historic state not having trie nodes.
+func (t *historicTrie) NodeIterator(startKey []byte) (trie.NodeIterator, error) {
+	return nil, errHistoricState
+}
+
+// Prove returns an error, the historic state not having trie nodes.
+func (t *historicTrie) Prove(key []byte, proofDb ethdb.KeyValueWriter) error {
+	return errHistoricState
+}
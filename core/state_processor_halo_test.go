@@ -0,0 +1,226 @@
+// Copyright 2025 The core-geth Authors
+// This file is part of the core-geth library.
+//
+// The core-geth library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The core-geth library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the core-geth library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/consensus/misc/eip1559"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/params/types/genesisT"
+	"github.com/ethereum/go-ethereum/params/vars"
+)
+
+// TestHaloContractFeeSharing tests that contracts which opted in to fee sharing
+// get their share of the base fee paid by successful calls once the fork is
+// activated, and that the payouts are recorded in the receipts.
+func TestHaloContractFeeSharing(t *testing.T) {
+	var (
+		key, _    = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		sender    = crypto.PubkeyToAddress(key.PublicKey)
+		shared    = common.HexToAddress("0xfee1") // Shares 50% of the fund's portion
+		unshared  = common.HexToAddress("0xfee2") // Did not opt in
+		reverting = common.HexToAddress("0xfee3") // Shares 100%, but always reverts
+		recipient = common.HexToAddress("0xbeef")
+		fund      = params.HaloEcosystemFundAddress
+
+		config = *params.HaloChainConfig
+		signer = types.LatestSigner(&config)
+	)
+	config.HaloContractFeeSharingFBlock = big.NewInt(2)
+
+	feeShareStorage := func(percent int64) map[common.Hash]common.Hash {
+		return map[common.Hash]common.Hash{
+			common.HexToHash("0x360894a13ba1a3210667c828492db98dca3e2076cc3735a920a3ca505d382bbc"): common.BigToHash(common.Big1),
+			common.HexToHash("0x360894a13ba1a3210667c828492db98dca3e2076cc3735a920a3ca505d382bbd"): common.BytesToHash(recipient.Bytes()),
+			common.HexToHash("0x360894a13ba1a3210667c828492db98dca3e2076cc3735a920a3ca505d382bbe"): common.BigToHash(big.NewInt(percent)),
+		}
+	}
+	gspec := &genesisT.Genesis{
+		Config:   &config,
+		GasLimit: 8_000_000,
+		Alloc: genesisT.GenesisAlloc{
+			sender:    {Balance: big.NewInt(vars.Ether)},
+			fund:      {Balance: big.NewInt(vars.Ether)},
+			shared:    {Code: []byte{byte(vm.STOP)}, Storage: feeShareStorage(50)},
+			unshared:  {Code: []byte{byte(vm.STOP)}},
+			reverting: {Code: common.FromHex("0x60006000fd"), Storage: feeShareStorage(100)},
+		},
+	}
+	nonce := uint64(0)
+	call := func(b *BlockGen, to common.Address) {
+		tx, _ := types.SignTx(types.NewTx(&types.DynamicFeeTx{
+			ChainID:   config.GetChainID(),
+			Nonce:     nonce,
+			GasTipCap: big.NewInt(vars.GWei),
+			GasFeeCap: big.NewInt(10 * vars.GWei),
+			Gas:       100_000,
+			To:        &to,
+		}), signer, key)
+		b.AddTx(tx)
+		nonce++
+	}
+	engine := ethash.NewFaker()
+	_, blocks, _ := GenerateChainWithGenesis(gspec, engine, 2, func(i int, b *BlockGen) {
+		call(b, shared)
+		if i == 1 {
+			call(b, unshared)
+			call(b, reverting)
+		}
+	})
+
+	blockchain, _ := NewBlockChain(rawdb.NewMemoryDatabase(), nil, gspec, nil, engine, vm.Config{}, nil, nil)
+	defer blockchain.Stop()
+	if _, err := blockchain.InsertChain(blocks); err != nil {
+		t.Fatalf("failed to insert chain: %v", err)
+	}
+
+	// Before the fork, nothing is shared
+	receipts := blockchain.GetReceiptsByHash(blocks[0].Hash())
+	if len(receipts[0].Logs) != 0 {
+		t.Fatalf("block 1: unexpected fee share logs: %v", receipts[0].Logs)
+	}
+	// After the fork, only the successful call to the opted in contract is shared
+	receipts = blockchain.GetReceiptsByHash(blocks[1].Hash())
+	for i, receipt := range receipts[1:] {
+		if len(receipt.Logs) != 0 {
+			t.Errorf("block 2: tx %d: unexpected fee share logs: %v", i+1, receipt.Logs)
+		}
+	}
+	if receipts[2].Status != types.ReceiptStatusFailed {
+		t.Fatalf("block 2: expected tx 2 to revert")
+	}
+	baseFee := blocks[1].BaseFee()
+	want := new(big.Int).Mul(baseFee, new(big.Int).SetUint64(receipts[0].GasUsed))
+	want.Mul(want, big.NewInt(eip1559.HaloEcosystemRatio))
+	want.Div(want, big.NewInt(eip1559.HaloTotalRatio))
+	want.Div(want, big.NewInt(2))
+
+	if len(receipts[0].Logs) != 1 {
+		t.Fatalf("block 2: expected 1 fee share log, have %d", len(receipts[0].Logs))
+	}
+	log := receipts[0].Logs[0]
+	if log.Address != eip1559.HaloFeeShareLogAddress {
+		t.Errorf("log address mismatch: have %v, want %v", log.Address, eip1559.HaloFeeShareLogAddress)
+	}
+	if len(log.Topics) != 3 || log.Topics[0] != eip1559.HaloFeeSharePaidTopic ||
+		log.Topics[1] != common.BytesToHash(shared.Bytes()) || log.Topics[2] != common.BytesToHash(recipient.Bytes()) {
+		t.Errorf("log topics mismatch: %v", log.Topics)
+	}
+	if have := new(big.Int).SetBytes(log.Data); have.Cmp(want) != 0 {
+		t.Errorf("log amount mismatch: have %v, want %v", have, want)
+	}
+	if log.TxHash != blocks[1].Transactions()[0].Hash() || log.BlockHash != blocks[1].Hash() {
+		t.Errorf("log not attributed to the transaction")
+	}
+
+	statedb, _ := blockchain.State()
+	if have := statedb.GetBalance(recipient); have.ToBig().Cmp(want) != 0 {
+		t.Errorf("recipient balance mismatch: have %v, want %v", have, want)
+	}
+	// The fund pays the share out of its portion of the base fees
+	wantFund := big.NewInt(vars.Ether)
+	for _, block := range blocks {
+		portion := new(big.Int).Mul(block.BaseFee(), new(big.Int).SetUint64(block.GasUsed()))
+		portion.Mul(portion, big.NewInt(eip1559.HaloEcosystemRatio))
+		wantFund.Add(wantFund, portion.Div(portion, big.NewInt(eip1559.HaloTotalRatio)))
+	}
+	wantFund.Sub(wantFund, want)
+	if have := statedb.GetBalance(fund); have.ToBig().Cmp(wantFund) != 0 {
+		t.Errorf("fund balance mismatch: have %v, want %v", have, wantFund)
+	}
+}
+
+// TestHaloSponsoredTx tests that the sponsor of a sponsored transaction pays for
+// its gas while the sender pays the value, and that sponsored transactions are
+// rejected before the fork.
+func TestHaloSponsoredTx(t *testing.T) {
+	var (
+		senderKey, _  = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		sponsorKey, _ = crypto.HexToECDSA("8a1f9a8f95be41cd7ccb6168179afb4504aefe388d1e14474d32c45c72ce7b7a")
+		sender        = crypto.PubkeyToAddress(senderKey.PublicKey)
+		sponsor       = crypto.PubkeyToAddress(sponsorKey.PublicKey)
+		recipient     = common.HexToAddress("0xbeef")
+		value         = big.NewInt(1000)
+
+		config = *params.HaloChainConfig
+	)
+	config.HaloSponsoredTxFBlock = big.NewInt(1)
+	signer := types.LatestSigner(&config)
+
+	gspec := &genesisT.Genesis{
+		Config:   &config,
+		GasLimit: 8_000_000,
+		Alloc: genesisT.GenesisAlloc{
+			sender:  {Balance: value},
+			sponsor: {Balance: big.NewInt(vars.Ether)},
+		},
+	}
+	engine := ethash.NewFaker()
+	_, blocks, receipts := GenerateChainWithGenesis(gspec, engine, 1, func(i int, b *BlockGen) {
+		tx, _ := types.SignTx(types.NewTx(&types.SponsoredTx{
+			ChainID:   config.GetChainID(),
+			GasTipCap: big.NewInt(vars.GWei),
+			GasFeeCap: big.NewInt(10 * vars.GWei),
+			Gas:       50_000,
+			To:        &recipient,
+			Value:     value,
+		}), signer, senderKey)
+		tx, _ = types.SignSponsor(tx, sponsorKey)
+		b.AddTx(tx)
+	})
+
+	blockchain, _ := NewBlockChain(rawdb.NewMemoryDatabase(), nil, gspec, nil, engine, vm.Config{}, nil, nil)
+	defer blockchain.Stop()
+	if _, err := blockchain.InsertChain(blocks); err != nil {
+		t.Fatalf("failed to insert chain: %v", err)
+	}
+	statedb, _ := blockchain.State()
+	if have := statedb.GetBalance(sender); !have.IsZero() {
+		t.Errorf("sender balance mismatch: have %v, want 0", have)
+	}
+	if have := statedb.GetBalance(recipient); have.ToBig().Cmp(value) != 0 {
+		t.Errorf("recipient balance mismatch: have %v, want %v", have, value)
+	}
+	fee := new(big.Int).Mul(receipts[0][0].EffectiveGasPrice, new(big.Int).SetUint64(receipts[0][0].GasUsed))
+	want := new(big.Int).Sub(big.NewInt(vars.Ether), fee)
+	if have := statedb.GetBalance(sponsor); have.ToBig().Cmp(want) != 0 {
+		t.Errorf("sponsor balance mismatch: have %v, want %v", have, want)
+	}
+	if have := statedb.GetNonce(sender); have != 1 {
+		t.Errorf("sender nonce mismatch: have %d, want 1", have)
+	}
+	if have := statedb.GetNonce(sponsor); have != 0 {
+		t.Errorf("sponsor nonce mismatch: have %d, want 0", have)
+	}
+
+	// Before the fork, the same block is invalid
+	config.HaloSponsoredTxFBlock = big.NewInt(2)
+	blockchain, _ = NewBlockChain(rawdb.NewMemoryDatabase(), nil, gspec, nil, engine, vm.Config{}, nil, nil)
+	defer blockchain.Stop()
+	if _, err := blockchain.InsertChain(blocks); !errors.Is(err, types.ErrTxTypeNotSupported) {
+		t.Fatalf("sponsored transaction before the fork: have %v, want %v", err, types.ErrTxTypeNotSupported)
+	}
+}
@@ -23,6 +23,7 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	cmath "github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/consensus/misc/eip1559"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/crypto/kzg4844"
@@ -141,6 +142,10 @@ type Message struct {
 	BlobGasFeeCap *big.Int
 	BlobHashes    []common.Hash
 
+	// Sponsor is the account paying for the gas of a sponsored transaction,
+	// nil if the sender pays for its own gas.
+	Sponsor *common.Address
+
 	// When SkipAccountChecks is true, the message nonce is not checked against the
 	// account nonce in state. It also disables checking that the sender is an EOA.
 	// This field will be set to true for operations like RPC eth_call.
@@ -167,6 +172,13 @@ func TransactionToMessage(tx *types.Transaction, s types.Signer, baseFee *big.In
 	if baseFee != nil {
 		msg.GasPrice = cmath.BigMin(msg.GasPrice.Add(msg.GasTipCap, baseFee), msg.GasFeeCap)
 	}
+	if tx.Type() == types.SponsoredTxType {
+		sponsor, err := types.Sponsor(s, tx)
+		if err != nil {
+			return nil, err
+		}
+		msg.Sponsor = &sponsor
+	}
 	var err error
 	msg.From, err = types.Sender(s, tx)
 	return msg, err
@@ -232,6 +244,15 @@ func (st *StateTransition) to() common.Address {
 	return *st.msg.To
 }
 
+// gasPayer returns the account paying for the gas of the message, which is
+// the sponsor of a sponsored transaction and the sender otherwise.
+func (st *StateTransition) gasPayer() common.Address {
+	if st.msg.Sponsor != nil {
+		return *st.msg.Sponsor
+	}
+	return st.msg.From
+}
+
 func (st *StateTransition) buyGas() error {
 	mgval := new(big.Int).SetUint64(st.msg.GasLimit)
 	mgval = mgval.Mul(mgval, st.msg.GasPrice)
@@ -239,7 +260,10 @@ func (st *StateTransition) buyGas() error {
 	if st.msg.GasFeeCap != nil {
 		balanceCheck.SetUint64(st.msg.GasLimit)
 		balanceCheck = balanceCheck.Mul(balanceCheck, st.msg.GasFeeCap)
-		balanceCheck.Add(balanceCheck, st.msg.Value)
+		// The value is paid by the sender, checked before the transfer
+		if st.msg.Sponsor == nil {
+			balanceCheck.Add(balanceCheck, st.msg.Value)
+		}
 	}
 	if st.evm.ChainConfig().IsEnabledByTime(st.evm.ChainConfig().GetEIP4844TransitionTime, &st.evm.Context.Time) || st.evm.ChainConfig().IsEnabled(st.evm.ChainConfig().GetEIP4844Transition, st.evm.Context.BlockNumber) {
 		if blobGas := st.blobGasUsed(); blobGas > 0 {
@@ -253,12 +277,13 @@ func (st *StateTransition) buyGas() error {
 			mgval.Add(mgval, blobFee)
 		}
 	}
+	payer := st.gasPayer()
 	balanceCheckU256, overflow := uint256.FromBig(balanceCheck)
 	if overflow {
-		return fmt.Errorf("%w: address %v required balance exceeds 256 bits", ErrInsufficientFunds, st.msg.From.Hex())
+		return fmt.Errorf("%w: address %v required balance exceeds 256 bits", ErrInsufficientFunds, payer.Hex())
 	}
-	if have, want := st.state.GetBalance(st.msg.From), balanceCheckU256; have.Cmp(want) < 0 {
-		return fmt.Errorf("%w: address %v have %v want %v", ErrInsufficientFunds, st.msg.From.Hex(), have, want)
+	if have, want := st.state.GetBalance(payer), balanceCheckU256; have.Cmp(want) < 0 {
+		return fmt.Errorf("%w: address %v have %v want %v", ErrInsufficientFunds, payer.Hex(), have, want)
 	}
 	if err := st.gp.SubGas(st.msg.GasLimit); err != nil {
 		return err
@@ -267,7 +292,7 @@ func (st *StateTransition) buyGas() error {
 
 	st.initialGas = st.msg.GasLimit
 	mgvalU256, _ := uint256.FromBig(mgval)
-	st.state.SubBalance(st.msg.From, mgvalU256)
+	st.state.SubBalance(payer, mgvalU256)
 	return nil
 }
 
@@ -293,6 +318,14 @@ func (st *StateTransition) preCheck() error {
 			return fmt.Errorf("%w: address %v, codehash: %s", ErrSenderNoEOA,
 				msg.From.Hex(), codeHash)
 		}
+		// Make sure the sponsor is an EOA too
+		if msg.Sponsor != nil {
+			codeHash := st.state.GetCodeHash(*msg.Sponsor)
+			if codeHash != (common.Hash{}) && codeHash != types.EmptyCodeHash {
+				return fmt.Errorf("%w: address %v, codehash: %s", ErrSponsorNoEOA,
+					msg.Sponsor.Hex(), codeHash)
+			}
+		}
 	}
 	// Make sure that transaction gasFeeCap is greater than the baseFee (post london)
 	if st.evm.ChainConfig().IsEnabled(st.evm.ChainConfig().GetEIP1559Transition, st.evm.Context.BlockNumber) {
@@ -420,6 +453,9 @@ func (st *StateTransition) TransitionDb() (*ExecutionResult, error) {
 		// EIP-3651: Warm coinbase
 		eip3651f = st.evm.ChainConfig().IsEnabledByTime(st.evm.ChainConfig().GetEIP3651TransitionTime, &st.evm.Context.Time) ||
 			st.evm.ChainConfig().IsEnabled(st.evm.ChainConfig().GetEIP3651Transition, st.evm.Context.BlockNumber)
+
+		// Halo: Per-contract fee sharing, paid out of the distributed base fees
+		haloFeeSharingf = eip1559.IsHaloContractFeeSharingEnabled(st.evm.ChainConfig(), st.evm.Context.BlockNumber)
 	)
 
 	// Check clauses 4-5, subtract intrinsic gas if everything is correct
@@ -489,6 +525,13 @@ func (st *StateTransition) TransitionDb() (*ExecutionResult, error) {
 		fee := new(uint256.Int).SetUint64(st.gasUsed())
 		fee.Mul(fee, effectiveTipU256)
 		st.state.AddBalance(st.evm.Context.Coinbase, fee)
+
+		// Share part of the base fee paid with the called contract, if it opted in.
+		// The calls simulated without base fee checks, like eth_call, pay no share,
+		// as no block finalization settles it against the fund.
+		if haloFeeSharingf && !st.evm.Config.NoBaseFee && !contractCreation && vmerr == nil {
+			eip1559.ApplyHaloContractFeeSharing(st.evm.ChainConfig(), st.state, st.evm.Context.BlockNumber, *msg.To, st.gasUsed(), st.evm.Context.BaseFee)
+		}
 	}
 
 	return &ExecutionResult{
@@ -510,7 +553,7 @@ func (st *StateTransition) refundGas(refundQuotient uint64) uint64 {
 	// Return ETH for remaining gas, exchanged at the original rate.
 	remaining := uint256.NewInt(st.gasRemaining)
 	remaining = remaining.Mul(remaining, uint256.MustFromBig(st.msg.GasPrice))
-	st.state.AddBalance(st.msg.From, remaining)
+	st.state.AddBalance(st.gasPayer(), remaining)
 
 	// Also return remaining gas to the block gas counter so it is
 	// available for the next transaction.
@@ -314,6 +314,8 @@ type BlobPool struct {
 	discoverFeed event.Feed // Event feed to send out new tx events on pool discovery (reorg excluded)
 	insertFeed   event.Feed // Event feed to send out new tx events on pool inclusion (reorg included)
 
+	history *txpool.History // Lifecycle history of the pooled transactions, nil if disabled
+
 	lock sync.RWMutex // Mutex protecting the pool during reorg handling
 }
 
@@ -334,6 +336,12 @@ func New(config Config, chain BlockChain) *BlobPool {
 	}
 }
 
+// SetHistory sets the store recording the lifecycle events of the pooled
+// transactions. It must be called before the pool is initialized.
+func (p *BlobPool) SetHistory(history *txpool.History) {
+	p.history = history
+}
+
 // Filter returns whether the given transaction can be consumed by the blob pool.
 func (p *BlobPool) Filter(tx *types.Transaction) bool {
 	return tx.Type() == types.BlobTxType
@@ -525,10 +533,12 @@ func (p *BlobPool) recheck(addr common.Address, inclusions map[common.Hash]uint6
 		var (
 			ids    []uint64
 			nonces []uint64
+			hashes []common.Hash
 		)
 		for i := 0; i < len(txs); i++ {
 			ids = append(ids, txs[i].id)
 			nonces = append(nonces, txs[i].nonce)
+			hashes = append(hashes, txs[i].hash)
 
 			p.stored -= uint64(txs[i].size)
 			delete(p.lookup, txs[i].hash)
@@ -548,9 +558,11 @@ func (p *BlobPool) recheck(addr common.Address, inclusions map[common.Hash]uint6
 		if gapped {
 			log.Warn("Dropping dangling blob transactions", "from", addr, "missing", next, "drop", nonces, "ids", ids)
 			dropDanglingMeter.Mark(int64(len(ids)))
+			p.record(txpool.TxEventDropped, "nonce gap", hashes...)
 		} else {
 			log.Trace("Dropping filled blob transactions", "from", addr, "filled", nonces, "ids", ids)
 			dropFilledMeter.Mark(int64(len(ids)))
+			p.recordStale(inclusions, hashes...)
 		}
 		for _, id := range ids {
 			if err := p.store.Delete(id); err != nil {
@@ -565,10 +577,12 @@ func (p *BlobPool) recheck(addr common.Address, inclusions map[common.Hash]uint6
 		var (
 			ids    []uint64
 			nonces []uint64
+			hashes []common.Hash
 		)
 		for txs[0].nonce < next {
 			ids = append(ids, txs[0].id)
 			nonces = append(nonces, txs[0].nonce)
+			hashes = append(hashes, txs[0].hash)
 
 			p.spent[addr] = new(uint256.Int).Sub(p.spent[addr], txs[0].costCap)
 			p.stored -= uint64(txs[0].size)
@@ -582,6 +596,7 @@ func (p *BlobPool) recheck(addr common.Address, inclusions map[common.Hash]uint6
 		}
 		log.Trace("Dropping overlapped blob transactions", "from", addr, "overlapped", nonces, "ids", ids, "left", len(txs))
 		dropOverlappedMeter.Mark(int64(len(ids)))
+		p.recordStale(inclusions, hashes...)
 
 		for _, id := range ids {
 			if err := p.store.Delete(id); err != nil {
@@ -625,6 +640,7 @@ func (p *BlobPool) recheck(addr common.Address, inclusions map[common.Hash]uint6
 
 			log.Error("Dropping repeat nonce blob transaction", "from", addr, "nonce", txs[i].nonce, "id", id)
 			dropRepeatedMeter.Mark(1)
+			p.record(txpool.TxEventDropped, "repeated nonce", txs[i].hash)
 
 			p.spent[addr] = new(uint256.Int).Sub(p.spent[addr], txs[i].costCap)
 			p.stored -= uint64(txs[i].size)
@@ -643,10 +659,12 @@ func (p *BlobPool) recheck(addr common.Address, inclusions map[common.Hash]uint6
 		var (
 			ids    []uint64
 			nonces []uint64
+			hashes []common.Hash
 		)
 		for j := i; j < len(txs); j++ {
 			ids = append(ids, txs[j].id)
 			nonces = append(nonces, txs[j].nonce)
+			hashes = append(hashes, txs[j].hash)
 
 			p.spent[addr] = new(uint256.Int).Sub(p.spent[addr], txs[j].costCap)
 			p.stored -= uint64(txs[j].size)
@@ -656,6 +674,7 @@ func (p *BlobPool) recheck(addr common.Address, inclusions map[common.Hash]uint6
 
 		log.Error("Dropping gapped blob transactions", "from", addr, "missing", txs[i-1].nonce+1, "drop", nonces, "ids", ids)
 		dropGappedMeter.Mark(int64(len(ids)))
+		p.record(txpool.TxEventDropped, "nonce gap", hashes...)
 
 		for _, id := range ids {
 			if err := p.store.Delete(id); err != nil {
@@ -677,6 +696,7 @@ func (p *BlobPool) recheck(addr common.Address, inclusions map[common.Hash]uint6
 		var (
 			ids    []uint64
 			nonces []uint64
+			hashes []common.Hash
 		)
 		for p.spent[addr].Cmp(balance) > 0 {
 			last := txs[len(txs)-1]
@@ -685,6 +705,7 @@ func (p *BlobPool) recheck(addr common.Address, inclusions map[common.Hash]uint6
 
 			ids = append(ids, last.id)
 			nonces = append(nonces, last.nonce)
+			hashes = append(hashes, last.hash)
 
 			p.spent[addr] = new(uint256.Int).Sub(p.spent[addr], last.costCap)
 			p.stored -= uint64(last.size)
@@ -702,6 +723,7 @@ func (p *BlobPool) recheck(addr common.Address, inclusions map[common.Hash]uint6
 		}
 		log.Warn("Dropping overdrafted blob transactions", "from", addr, "balance", balance, "spent", spent, "drop", nonces, "ids", ids)
 		dropOverdraftedMeter.Mark(int64(len(ids)))
+		p.record(txpool.TxEventDropped, "unpayable", hashes...)
 
 		for _, id := range ids {
 			if err := p.store.Delete(id); err != nil {
@@ -717,6 +739,7 @@ func (p *BlobPool) recheck(addr common.Address, inclusions map[common.Hash]uint6
 		var (
 			ids    []uint64
 			nonces []uint64
+			hashes []common.Hash
 		)
 		for len(txs) > maxTxsPerAccount {
 			last := txs[len(txs)-1]
@@ -725,6 +748,7 @@ func (p *BlobPool) recheck(addr common.Address, inclusions map[common.Hash]uint6
 
 			ids = append(ids, last.id)
 			nonces = append(nonces, last.nonce)
+			hashes = append(hashes, last.hash)
 
 			p.spent[addr] = new(uint256.Int).Sub(p.spent[addr], last.costCap)
 			p.stored -= uint64(last.size)
@@ -734,6 +758,7 @@ func (p *BlobPool) recheck(addr common.Address, inclusions map[common.Hash]uint6
 
 		log.Warn("Dropping overcapped blob transactions", "from", addr, "kept", len(txs), "drop", nonces, "ids", ids)
 		dropOvercappedMeter.Mark(int64(len(ids)))
+		p.record(txpool.TxEventDropped, "account limit", hashes...)
 
 		for _, id := range ids {
 			if err := p.store.Delete(id); err != nil {
@@ -1031,6 +1056,7 @@ func (p *BlobPool) SetGasTip(tip *big.Int) {
 					var (
 						ids    = []uint64{tx.id}
 						nonces = []uint64{tx.nonce}
+						hashes = []common.Hash{tx.hash}
 					)
 					p.spent[addr] = new(uint256.Int).Sub(p.spent[addr], txs[i].costCap)
 					p.stored -= uint64(tx.size)
@@ -1041,6 +1067,7 @@ func (p *BlobPool) SetGasTip(tip *big.Int) {
 					for j, tx := range txs[i+1:] {
 						ids = append(ids, tx.id)
 						nonces = append(nonces, tx.nonce)
+						hashes = append(hashes, tx.hash)
 
 						p.spent[addr] = new(uint256.Int).Sub(p.spent[addr], tx.costCap)
 						p.stored -= uint64(tx.size)
@@ -1061,6 +1088,7 @@ func (p *BlobPool) SetGasTip(tip *big.Int) {
 					// Clear out the transactions from the data store
 					log.Warn("Dropping underpriced blob transaction", "from", addr, "rejected", tx.nonce, "tip", tx.execTipCap, "want", tip, "drop", nonces, "ids", ids)
 					dropUnderpricedMeter.Mark(int64(len(ids)))
+					p.record(txpool.TxEventDropped, "underpriced", hashes...)
 
 					for _, id := range ids {
 						if err := p.store.Delete(id); err != nil {
@@ -1319,6 +1347,12 @@ func (p *BlobPool) add(tx *types.Transaction) (err error) {
 		delete(p.lookup, prev.hash)
 		p.lookup[meta.hash] = meta.id
 		p.stored += uint64(meta.size) - uint64(prev.size)
+
+		if p.history != nil {
+			ev := txpool.NewTxEvent(prev.hash, txpool.TxEventReplaced, "")
+			ev.ReplacedBy = meta.hash
+			p.history.Record(ev)
+		}
 	} else {
 		// Transaction extends previously scheduled ones
 		p.index[from] = append(p.index[from], meta)
@@ -1330,6 +1364,8 @@ func (p *BlobPool) add(tx *types.Transaction) (err error) {
 		p.lookup[meta.hash] = meta.id
 		p.stored += uint64(meta.size)
 	}
+	p.record(txpool.TxEventAdded, "", meta.hash)
+
 	// Recompute the rolling eviction fields. In case of a replacement, this will
 	// recompute all subsequent fields. In case of an append, this will only do
 	// the fresh calculation.
@@ -1436,12 +1472,46 @@ func (p *BlobPool) drop() {
 	// Remove the transaction from the data store
 	log.Debug("Evicting overflown blob transaction", "from", from, "evicted", drop.nonce, "id", drop.id)
 	dropOverflownMeter.Mark(1)
+	p.record(txpool.TxEventDropped, "pool full", drop.hash)
 
 	if err := p.store.Delete(drop.id); err != nil {
 		log.Error("Failed to drop evicted transaction", "id", drop.id, "err", err)
 	}
 }
 
+// record writes lifecycle events of the given kind for the given transactions
+// to the history, if one is set.
+func (p *BlobPool) record(kind txpool.TxEventKind, reason string, hashes ...common.Hash) {
+	if p.history == nil || len(hashes) == 0 {
+		return
+	}
+	events := make([]*txpool.TxEvent, 0, len(hashes))
+	for _, hash := range hashes {
+		events = append(events, txpool.NewTxEvent(hash, kind, reason))
+	}
+	p.history.Record(events...)
+}
+
+// recordStale writes the events of transactions removed for their nonce being
+// too low, which are inclusion events if the including block is known.
+func (p *BlobPool) recordStale(inclusions map[common.Hash]uint64, hashes ...common.Hash) {
+	if p.history == nil || len(hashes) == 0 {
+		return
+	}
+	events := make([]*txpool.TxEvent, 0, len(hashes))
+	for _, hash := range hashes {
+		block, ok := inclusions[hash]
+		if !ok {
+			events = append(events, txpool.NewTxEvent(hash, txpool.TxEventDropped, "nonce too low"))
+			continue
+		}
+		ev := txpool.NewTxEvent(hash, txpool.TxEventIncluded, "")
+		ev.BlockNumber = block
+		events = append(events, ev)
+	}
+	p.history.Record(events...)
+}
+
 // Pending retrieves all currently processable transactions, grouped by origin
 // account and sorted by nonce.
 //
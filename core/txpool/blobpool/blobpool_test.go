@@ -1311,6 +1311,75 @@ func TestAdd(t *testing.T) {
 	}
 }
 
+// Tests that the lifecycle events of pooled blob transactions are recorded in
+// the history if one is set.
+func TestHistory(t *testing.T) {
+	storage, _ := os.MkdirTemp("", "blobpool-")
+	defer os.RemoveAll(storage)
+
+	key, _ := crypto.GenerateKey()
+	statedb, _ := state.New(types.EmptyRootHash, state.NewDatabase(rawdb.NewDatabase(memorydb.New())), nil)
+	statedb.AddBalance(crypto.PubkeyToAddress(key.PublicKey), uint256.NewInt(1_000_000_000))
+	statedb.Commit(0, true)
+
+	chain := &testBlockChain{
+		config:  testChainConfig,
+		basefee: uint256.NewInt(1050),
+		blobfee: uint256.NewInt(105),
+		statedb: statedb,
+	}
+	history := txpool.NewHistory(rawdb.NewMemoryDatabase(), 16)
+	defer history.Close()
+
+	pool := New(Config{Datadir: storage}, chain)
+	pool.SetHistory(history)
+	if err := pool.Init(1, chain.CurrentBlock(), makeAddressReserver()); err != nil {
+		t.Fatalf("failed to create blob pool: %v", err)
+	}
+	defer pool.Close()
+
+	// Add a transaction, replace it and drop the replacement by raising the tip
+	var (
+		tx          = makeTx(0, 1, 1000, 100, key)
+		replacement = makeTx(0, 2, 2000, 200, key)
+	)
+	if err := pool.add(tx); err != nil {
+		t.Fatalf("failed to add transaction: %v", err)
+	}
+	if err := pool.add(replacement); err != nil {
+		t.Fatalf("failed to replace transaction: %v", err)
+	}
+	pool.SetGasTip(big.NewInt(3))
+
+	// waitHistory waits until the given events are recorded for a transaction,
+	// as the history is written in the background.
+	waitHistory := func(hash common.Hash, kinds ...txpool.TxEventKind) []*txpool.TxEvent {
+		t.Helper()
+
+		var events []*txpool.TxEvent
+		for start := time.Now(); time.Since(start) < time.Second; time.Sleep(10 * time.Millisecond) {
+			if events = history.Get(hash); len(events) >= len(kinds) {
+				break
+			}
+		}
+		if len(events) != len(kinds) {
+			t.Fatalf("event count mismatch for %x: have %d, want %d", hash, len(events), len(kinds))
+		}
+		for i, kind := range kinds {
+			if events[i].Kind != kind {
+				t.Errorf("event %d kind mismatch for %x: have %s, want %s", i, hash, events[i].Kind, kind)
+			}
+		}
+		return events
+	}
+	if events := waitHistory(tx.Hash(), txpool.TxEventAdded, txpool.TxEventReplaced); events[1].ReplacedBy != replacement.Hash() {
+		t.Errorf("replacement mismatch: have %x, want %x", events[1].ReplacedBy, replacement.Hash())
+	}
+	if events := waitHistory(replacement.Hash(), txpool.TxEventAdded, txpool.TxEventDropped); events[1].Reason != "underpriced" {
+		t.Errorf("drop reason mismatch: have %q, want %q", events[1].Reason, "underpriced")
+	}
+}
+
 // Benchmarks the time it takes to assemble the lazy pending transaction list
 // from the pool contents.
 func BenchmarkPoolPending100Mb(b *testing.B) { benchmarkPoolPending(b, 100_000_000) }
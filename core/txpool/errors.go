@@ -26,6 +26,10 @@ var (
 	// ErrInvalidSender is returned if the transaction contains an invalid signature.
 	ErrInvalidSender = errors.New("invalid sender")
 
+	// ErrInvalidSponsor is returned if a sponsored transaction contains an
+	// invalid sponsor signature.
+	ErrInvalidSponsor = errors.New("invalid sponsor")
+
 	// ErrUnderpriced is returned if a transaction's gas price is below the minimum
 	// configured for the transaction pool.
 	ErrUnderpriced = errors.New("transaction underpriced")
@@ -0,0 +1,253 @@
+// Copyright 2025 The core-geth Authors
+// This file is part of the core-geth library.
+//
+// The core-geth library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The core-geth library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the core-geth library. If not, see <http://www.gnu.org/licenses/>.
+
+package txpool
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// historyQueueSize is the number of event batches that may be waiting to be
+// written before new ones are discarded.
+const historyQueueSize = 1024
+
+// historyNotifyQueueSize is the number of written event batches that may be
+// waiting to be sent to subscribers before new ones are not announced.
+const historyNotifyQueueSize = 1024
+
+// historyTxEventLimit is the maximum number of events retained for a single
+// transaction. Past it, the oldest events but the first one are forgotten.
+const historyTxEventLimit = 64
+
+// TxEventKind is the kind of a transaction lifecycle event.
+type TxEventKind string
+
+const (
+	TxEventAdded    TxEventKind = "added"    // Transaction entered the pool
+	TxEventPromoted TxEventKind = "promoted" // Transaction became executable
+	TxEventDemoted  TxEventKind = "demoted"  // Transaction became non-executable
+	TxEventReplaced TxEventKind = "replaced" // Transaction was replaced by another one with the same nonce
+	TxEventDropped  TxEventKind = "dropped"  // Transaction was removed from the pool
+	TxEventIncluded TxEventKind = "included" // Transaction was included in a block
+)
+
+// TxEvent is a single lifecycle event of a pooled transaction.
+type TxEvent struct {
+	Hash        common.Hash // Hash of the transaction
+	Kind        TxEventKind // Kind of the event
+	Time        uint64      // Time of the event, in milliseconds since the unix epoch
+	Reason      string      // Reason of a drop or a demotion
+	ReplacedBy  common.Hash // Hash of the replacing transaction, zero if not replaced
+	BlockHash   common.Hash // Hash of the including block, zero if not included
+	BlockNumber uint64      // Number of the including block
+}
+
+// NewTxEvent creates a lifecycle event of the given kind, timestamped now.
+func NewTxEvent(hash common.Hash, kind TxEventKind, reason string) *TxEvent {
+	return &TxEvent{
+		Hash:   hash,
+		Kind:   kind,
+		Time:   uint64(time.Now().UnixMilli()),
+		Reason: reason,
+	}
+}
+
+// History is a bounded on-disk store of the lifecycle events of pooled
+// transactions. Events are written by a background goroutine, so recording
+// them never blocks the pool, and announced to subscribers by another, so slow
+// subscribers never block the writes. Once the history of more transactions
+// than the limit is stored, the transactions seen first are forgotten.
+type History struct {
+	db    ethdb.KeyValueStore
+	limit uint64
+
+	first uint64 // Sequence number of the oldest transaction with a history
+	next  uint64 // Sequence number of the next transaction with a history
+
+	feed  event.Feed
+	scope event.SubscriptionScope
+
+	queue  chan []*TxEvent // Event batches waiting to be written
+	notify chan []*TxEvent // Written event batches waiting to be announced
+	quit   chan struct{}
+	wg     sync.WaitGroup
+	lock   sync.Mutex // Serializes writes with reads
+}
+
+// NewHistory creates a transaction history on top of the given database,
+// retaining the events of at most limit transactions.
+func NewHistory(db ethdb.KeyValueStore, limit uint64) *History {
+	h := &History{
+		db:     db,
+		limit:  limit,
+		queue:  make(chan []*TxEvent, historyQueueSize),
+		notify: make(chan []*TxEvent, historyNotifyQueueSize),
+		quit:   make(chan struct{}),
+	}
+	h.first, h.next = rawdb.ReadTxPoolHistoryBounds(db)
+
+	h.wg.Add(2)
+	go h.loop()
+	go h.notifyLoop()
+	return h
+}
+
+// Record schedules the given events to be written to the history. The events
+// are discarded if the writer can't keep up.
+func (h *History) Record(events ...*TxEvent) {
+	if h == nil || len(events) == 0 {
+		return
+	}
+	select {
+	case h.queue <- events:
+	default:
+		log.Warn("Txpool history overloaded, discarding events", "count", len(events))
+	}
+}
+
+// Get retrieves the recorded lifecycle events of a transaction, oldest first.
+func (h *History) Get(hash common.Hash) []*TxEvent {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	return h.read(hash)
+}
+
+// SubscribeEvents registers a subscription for lifecycle events, sent once
+// they are written to the history. Events are not announced if the subscribers
+// fall too far behind.
+func (h *History) SubscribeEvents(ch chan<- []*TxEvent) event.Subscription {
+	return h.scope.Track(h.feed.Subscribe(ch))
+}
+
+// Close writes the pending events and terminates the history writer. The
+// subscriptions are closed first, so a stuck subscriber can't block it.
+func (h *History) Close() {
+	h.scope.Close()
+	close(h.quit)
+	h.wg.Wait()
+}
+
+// loop writes the recorded events until the history is closed.
+func (h *History) loop() {
+	defer h.wg.Done()
+	defer close(h.notify)
+
+	for {
+		select {
+		case events := <-h.queue:
+			h.write(events)
+		case <-h.quit:
+			for {
+				select {
+				case events := <-h.queue:
+					h.write(events)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+// notifyLoop announces the written events to the subscribers until the writer
+// terminates.
+func (h *History) notifyLoop() {
+	defer h.wg.Done()
+
+	for events := range h.notify {
+		h.feed.Send(events)
+	}
+}
+
+// read retrieves the recorded events of a transaction, the lock is assumed held.
+func (h *History) read(hash common.Hash) []*TxEvent {
+	blob := rawdb.ReadTxPoolHistory(h.db, hash)
+	if len(blob) == 0 {
+		return nil
+	}
+	var events []*TxEvent
+	if err := rlp.DecodeBytes(blob, &events); err != nil {
+		log.Error("Invalid txpool history", "hash", hash, "err", err)
+		return nil
+	}
+	return events
+}
+
+// write appends the given events to the histories of their transactions, and
+// forgets the oldest transactions in excess of the limit.
+func (h *History) write(events []*TxEvent) {
+	h.lock.Lock()
+
+	var (
+		batch   = h.db.NewBatch()
+		records = make(map[common.Hash][]*TxEvent)
+		indexed = make(map[uint64]common.Hash) // Transactions first seen in this batch
+	)
+	for _, ev := range events {
+		record, ok := records[ev.Hash]
+		if !ok {
+			record = h.read(ev.Hash)
+			if record == nil {
+				indexed[h.next] = ev.Hash
+				rawdb.WriteTxPoolHistoryIndex(batch, h.next, ev.Hash)
+				h.next++
+			}
+		}
+		record = append(record, ev)
+		if len(record) > historyTxEventLimit {
+			record = append(record[:1], record[len(record)-historyTxEventLimit+1:]...)
+		}
+		records[ev.Hash] = record
+	}
+	for h.next-h.first > h.limit {
+		if hash, ok := indexed[h.first]; ok {
+			delete(records, hash)
+		} else if hash := rawdb.ReadTxPoolHistoryIndex(h.db, h.first); hash != nil {
+			delete(records, *hash)
+			rawdb.DeleteTxPoolHistory(batch, *hash)
+		}
+		rawdb.DeleteTxPoolHistoryIndex(batch, h.first)
+		h.first++
+	}
+	for hash, record := range records {
+		blob, err := rlp.EncodeToBytes(record)
+		if err != nil {
+			log.Error("Failed to encode txpool history", "hash", hash, "err", err)
+			continue
+		}
+		rawdb.WriteTxPoolHistory(batch, hash, blob)
+	}
+	rawdb.WriteTxPoolHistoryBounds(batch, h.first, h.next)
+	if err := batch.Write(); err != nil {
+		log.Error("Failed to write txpool history", "err", err)
+	}
+	h.lock.Unlock()
+
+	select {
+	case h.notify <- events:
+	default:
+		log.Debug("Txpool history subscribers overloaded, not announcing events", "count", len(events))
+	}
+}
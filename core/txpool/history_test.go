@@ -0,0 +1,145 @@
+// Copyright 2025 The core-geth Authors
+// This file is part of the core-geth library.
+//
+// The core-geth library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The core-geth library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the core-geth library. If not, see <http://www.gnu.org/licenses/>.
+
+package txpool
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+)
+
+// Tests that the lifecycle events of transactions are recorded, announced and
+// retained across restarts, and that the oldest transactions are forgotten once
+// the history is full.
+func TestHistory(t *testing.T) {
+	var (
+		db      = rawdb.NewMemoryDatabase()
+		history = NewHistory(db, 2)
+		events  = make(chan []*TxEvent, 16)
+		sub     = history.SubscribeEvents(events)
+	)
+	defer sub.Unsubscribe()
+
+	record := func(evs ...*TxEvent) {
+		t.Helper()
+
+		history.Record(evs...)
+		select {
+		case got := <-events:
+			if len(got) != len(evs) {
+				t.Fatalf("announced event count mismatch: have %d, want %d", len(got), len(evs))
+			}
+		case <-time.After(time.Second):
+			t.Fatal("events not announced")
+		}
+	}
+	var (
+		tx1 = common.Hash{0x01}
+		tx2 = common.Hash{0x02}
+		tx3 = common.Hash{0x03}
+	)
+	replaced := NewTxEvent(tx1, TxEventReplaced, "")
+	replaced.ReplacedBy = tx2
+
+	record(NewTxEvent(tx1, TxEventAdded, ""), NewTxEvent(tx1, TxEventPromoted, ""))
+	record(NewTxEvent(tx2, TxEventAdded, ""), replaced)
+
+	if have := history.Get(tx1); len(have) != 3 {
+		t.Fatalf("event count mismatch: have %d, want 3", len(have))
+	} else {
+		for i, kind := range []TxEventKind{TxEventAdded, TxEventPromoted, TxEventReplaced} {
+			if have[i].Kind != kind {
+				t.Errorf("event %d kind mismatch: have %s, want %s", i, have[i].Kind, kind)
+			}
+		}
+		if have[2].ReplacedBy != tx2 {
+			t.Errorf("replacement mismatch: have %x, want %x", have[2].ReplacedBy, tx2)
+		}
+	}
+	// Reopen the history and exceed its limit
+	sub.Unsubscribe()
+	history.Close()
+
+	history = NewHistory(db, 2)
+	defer history.Close()
+	sub = history.SubscribeEvents(events)
+
+	record(NewTxEvent(tx3, TxEventDropped, "underpriced"))
+
+	if have := history.Get(tx1); have != nil {
+		t.Errorf("oldest transaction not forgotten: %d events", len(have))
+	}
+	if have := history.Get(tx2); len(have) != 1 {
+		t.Errorf("event count mismatch: have %d, want 1", len(have))
+	}
+	if have := history.Get(tx3); len(have) != 1 || have[0].Reason != "underpriced" {
+		t.Errorf("drop event mismatch: have %v", have)
+	}
+	if first, next := rawdb.ReadTxPoolHistoryBounds(db); first != 1 || next != 3 {
+		t.Errorf("bounds mismatch: have [%d, %d), want [1, 3)", first, next)
+	}
+	// Exceed the event limit of a transaction, keeping its first and last events
+	var flapping []*TxEvent
+	for i := 0; i < 2*historyTxEventLimit; i++ {
+		flapping = append(flapping, NewTxEvent(tx3, TxEventPromoted, ""), NewTxEvent(tx3, TxEventDemoted, "gapped"))
+	}
+	record(flapping...)
+
+	have := history.Get(tx3)
+	if len(have) != historyTxEventLimit {
+		t.Fatalf("event count mismatch: have %d, want %d", len(have), historyTxEventLimit)
+	}
+	if have[0].Reason != "underpriced" || have[len(have)-1].Kind != TxEventDemoted {
+		t.Errorf("retained events mismatch: first %v, last %v", have[0], have[len(have)-1])
+	}
+}
+
+// Tests that a subscriber not consuming its events blocks neither the writes
+// nor the shutdown of the history.
+func TestHistoryStuckSubscriber(t *testing.T) {
+	var (
+		history = NewHistory(rawdb.NewMemoryDatabase(), 16)
+		events  = make(chan []*TxEvent) // never read
+	)
+	history.SubscribeEvents(events)
+
+	var hashes []common.Hash
+	for i := 0; i < 8; i++ {
+		hash := common.Hash{byte(i + 1)}
+		hashes = append(hashes, hash)
+		history.Record(NewTxEvent(hash, TxEventAdded, ""))
+	}
+	for _, hash := range hashes {
+		for start := time.Now(); history.Get(hash) == nil; time.Sleep(10 * time.Millisecond) {
+			if time.Since(start) > time.Second {
+				t.Fatalf("events of %x not written", hash)
+			}
+		}
+	}
+	done := make(chan struct{})
+	go func() {
+		history.Close()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("history close blocked by subscriber")
+	}
+}
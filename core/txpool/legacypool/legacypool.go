@@ -135,6 +135,8 @@ type Config struct {
 	GlobalQueue  uint64 // Maximum number of non-executable transaction slots for all accounts
 
 	Lifetime time.Duration // Maximum amount of time non-executable transaction are queued
+
+	History uint64 // Maximum number of transactions with a recorded lifecycle history (0 = disabled)
 }
 
 // DefaultConfig contains the default configurations for the transaction pool.
@@ -231,6 +233,10 @@ type LegacyPool struct {
 	initDoneCh      chan struct{}  // is closed once the pool is initialized (for tests)
 
 	changesSinceReorg int // A counter for how many drops we've performed in-between reorg.
+
+	history    *txpool.History               // Lifecycle history of the pooled transactions, nil if disabled
+	historyBuf []*txpool.TxEvent             // Lifecycle events waiting to be recorded
+	inclusions map[common.Hash]*types.Header // Headers including transactions during a reset
 }
 
 type txpoolResetRequest struct {
@@ -252,7 +258,7 @@ func New(config Config, chain BlockChain) *LegacyPool {
 		pending:         make(map[common.Address]*list),
 		queue:           make(map[common.Address]*list),
 		beats:           make(map[common.Address]time.Time),
-		all:             newLookup(),
+		all:             newLookup(types.LatestSigner(chain.Config())),
 		reqResetCh:      make(chan *txpoolResetRequest),
 		reqPromoteCh:    make(chan *accountSet),
 		queueTxEventCh:  make(chan *types.Transaction),
@@ -273,11 +279,18 @@ func New(config Config, chain BlockChain) *LegacyPool {
 	return pool
 }
 
+// SetHistory sets the store recording the lifecycle events of the pooled
+// transactions. It must be called before the pool is initialized.
+func (pool *LegacyPool) SetHistory(history *txpool.History) {
+	pool.history = history
+}
+
 // Filter returns whether the given transaction can be consumed by the legacy
-// pool, specifically, whether it is a Legacy, AccessList or Dynamic transaction.
+// pool, specifically, whether it is a Legacy, AccessList, Dynamic or Sponsored
+// transaction.
 func (pool *LegacyPool) Filter(tx *types.Transaction) bool {
 	switch tx.Type() {
-	case types.LegacyTxType, types.AccessListTxType, types.DynamicFeeTxType:
+	case types.LegacyTxType, types.AccessListTxType, types.DynamicFeeTxType, types.SponsoredTxType:
 		return true
 	default:
 		return false
@@ -380,9 +393,11 @@ func (pool *LegacyPool) loop() {
 					for _, tx := range list {
 						pool.removeTx(tx.Hash(), true, true)
 					}
+					pool.recordDropped("lifetime exceeded", list...)
 					queuedEvictionMeter.Mark(int64(len(list)))
 				}
 			}
+			pool.flushHistory()
 			pool.mu.Unlock()
 
 		// Handle local transaction journal rotation
@@ -447,6 +462,8 @@ func (pool *LegacyPool) SetGasTip(tip *big.Int) {
 			pool.removeTx(tx.Hash(), false, true)
 		}
 		pool.priced.Removed(len(drop))
+		pool.recordDropped("below gas tip", drop...)
+		pool.flushHistory()
 	}
 	log.Info("Legacy pool tip threshold updated", "tip", newTip)
 }
@@ -609,7 +626,8 @@ func (pool *LegacyPool) validateTxBasics(tx *types.Transaction, local bool) erro
 		Accept: 0 |
 			1<<types.LegacyTxType |
 			1<<types.AccessListTxType |
-			1<<types.DynamicFeeTxType,
+			1<<types.DynamicFeeTxType |
+			1<<types.SponsoredTxType,
 		MaxSize: txMaxSize,
 		MinTip:  pool.gasTip.Load().ToBig(),
 	}
@@ -653,6 +671,18 @@ func (pool *LegacyPool) validateTx(tx *types.Transaction, local bool) error {
 			}
 			return nil
 		},
+		ExistingSponsorship: func(sponsor common.Address, from common.Address, nonce uint64) *big.Int {
+			committed := pool.all.Sponsorship(sponsor)
+			for _, list := range []*list{pool.pending[from], pool.queue[from]} {
+				if list == nil {
+					continue
+				}
+				if tx := list.txs.Get(nonce); tx != nil && pool.all.Sponsor(tx.Hash()) == sponsor {
+					committed.Sub(committed, sponsoredGas(tx))
+				}
+			}
+			return committed
+		},
 	}
 	if err := txpool.ValidateTransactionWithState(tx, pool.signer, opts); err != nil {
 		return err
@@ -770,6 +800,7 @@ func (pool *LegacyPool) add(tx *types.Transaction, local bool) (replaced bool, e
 
 			pool.changesSinceReorg += dropped
 		}
+		pool.recordDropped("underpriced", drop...)
 	}
 
 	// Try to replace an existing transaction in the pending pool
@@ -790,6 +821,10 @@ func (pool *LegacyPool) add(tx *types.Transaction, local bool) (replaced bool, e
 		pool.priced.Put(tx, isLocal)
 		pool.journalTx(from, tx)
 		pool.queueTxEvent(tx)
+		pool.record(txpool.TxEventAdded, "", tx)
+		if old != nil {
+			pool.recordReplaced(old, tx)
+		}
 		log.Trace("Pooled new executable transaction", "hash", hash, "from", from, "to", tx.To())
 
 		// Successful promotion, bump the heartbeat
@@ -811,6 +846,7 @@ func (pool *LegacyPool) add(tx *types.Transaction, local bool) (replaced bool, e
 		localGauge.Inc(1)
 	}
 	pool.journalTx(from, tx)
+	pool.record(txpool.TxEventAdded, "", tx)
 
 	log.Trace("Pooled new future transaction", "hash", hash, "from", from, "to", tx.To())
 	return replaced, nil
@@ -860,6 +896,7 @@ func (pool *LegacyPool) enqueueTx(hash common.Hash, tx *types.Transaction, local
 		pool.all.Remove(old.Hash())
 		pool.priced.Removed(1)
 		queuedReplaceMeter.Mark(1)
+		pool.recordReplaced(old, tx)
 	} else {
 		// Nothing was replaced, bump the queued counter
 		queuedGauge.Inc(1)
@@ -909,6 +946,7 @@ func (pool *LegacyPool) promoteTx(addr common.Address, hash common.Hash, tx *typ
 		pool.all.Remove(hash)
 		pool.priced.Removed(1)
 		pendingDiscardMeter.Mark(1)
+		pool.recordDropped("replacement underpriced", tx)
 		return false
 	}
 	// Otherwise discard any previous transaction and mark this
@@ -916,12 +954,14 @@ func (pool *LegacyPool) promoteTx(addr common.Address, hash common.Hash, tx *typ
 		pool.all.Remove(old.Hash())
 		pool.priced.Removed(1)
 		pendingReplaceMeter.Mark(1)
+		pool.recordReplaced(old, tx)
 	} else {
 		// Nothing was replaced, bump the pending counter
 		pendingGauge.Inc(1)
 	}
 	// Set the potentially new pending nonce and notify any subsystems of the new tx
 	pool.pendingNonces.set(addr, tx.Nonce()+1)
+	pool.record(txpool.TxEventPromoted, "", tx)
 
 	// Successful promotion, bump the heartbeat
 	pool.beats[addr] = time.Now()
@@ -1038,6 +1078,7 @@ func (pool *LegacyPool) addTxsLocked(txs []*types.Transaction, local bool) ([]er
 			dirty.addTx(tx)
 		}
 	}
+	pool.flushHistory()
 	validTxMeter.Mark(int64(len(dirty.accounts)))
 	return errs, dirty
 }
@@ -1133,6 +1174,7 @@ func (pool *LegacyPool) removeTx(hash common.Hash, outofbound bool, unreserve bo
 				// Internal shuffle shouldn't touch the lookup set.
 				pool.enqueueTx(tx.Hash(), tx, false, false)
 			}
+			pool.record(txpool.TxEventDemoted, "nonce gap", invalids...)
 			// Update the account nonce if needed
 			pool.pendingNonces.setIfLower(addr, tx.Nonce())
 			// Reduce the pending counter
@@ -1282,6 +1324,9 @@ func (pool *LegacyPool) runReorg(done chan struct{}, reset *txpoolResetRequest,
 				delete(events, addr)
 			}
 		}
+		// Drop the sponsored transactions whose sponsors can no longer pay for them
+		pool.dropUnfundedSponsorships()
+
 		// Reset needs promote for all addresses
 		promoteAddrs = make([]common.Address, 0, len(pool.queue))
 		for addr := range pool.queue {
@@ -1319,6 +1364,8 @@ func (pool *LegacyPool) runReorg(done chan struct{}, reset *txpoolResetRequest,
 
 	dropBetweenReorgHistogram.Update(int64(pool.changesSinceReorg))
 	pool.changesSinceReorg = 0 // Reset change counter
+	pool.inclusions = nil
+	pool.flushHistory()
 	pool.mu.Unlock()
 
 	// Notify subsystems for newly added transactions
@@ -1431,6 +1478,10 @@ func (pool *LegacyPool) reset(oldHead, newHead *types.Header) {
 	pool.currentState = statedb
 	pool.pendingNonces = newNoncer(statedb)
 
+	// Track the blocks including transactions to record their inclusion
+	if pool.history != nil {
+		pool.inclusions = pool.collectInclusions(oldHead, newHead)
+	}
 	// Inject any transactions discarded due to reorgs
 	log.Debug("Reinjecting stale transactions", "count", len(reinject))
 	core.SenderCacher.Recover(pool.signer, reinject)
@@ -1457,6 +1508,7 @@ func (pool *LegacyPool) promoteExecutables(accounts []common.Address) []*types.T
 			hash := tx.Hash()
 			pool.all.Remove(hash)
 		}
+		pool.recordStale(forwards...)
 		log.Trace("Removed old queued transactions", "count", len(forwards))
 		// Drop all transactions that are too costly (low balance or out of gas)
 		drops, _ := list.Filter(pool.currentState.GetBalance(addr), gasLimit)
@@ -1464,6 +1516,7 @@ func (pool *LegacyPool) promoteExecutables(accounts []common.Address) []*types.T
 			hash := tx.Hash()
 			pool.all.Remove(hash)
 		}
+		pool.recordDropped("unpayable", drops...)
 		log.Trace("Removed unpayable queued transactions", "count", len(drops))
 		queuedNofundsMeter.Mark(int64(len(drops)))
 
@@ -1487,6 +1540,7 @@ func (pool *LegacyPool) promoteExecutables(accounts []common.Address) []*types.T
 				pool.all.Remove(hash)
 				log.Trace("Removed cap-exceeding queued transaction", "hash", hash)
 			}
+			pool.recordDropped("account queue limit", caps...)
 			queuedRateLimitMeter.Mark(int64(len(caps)))
 		}
 		// Mark all the items dropped as removed
@@ -1555,6 +1609,7 @@ func (pool *LegacyPool) truncatePending() {
 						pool.pendingNonces.setIfLower(offenders[i], tx.Nonce())
 						log.Trace("Removed fairness-exceeding pending transaction", "hash", hash)
 					}
+					pool.recordDropped("pending limit", caps...)
 					pool.priced.Removed(len(caps))
 					pendingGauge.Dec(int64(len(caps)))
 					if pool.locals.contains(offenders[i]) {
@@ -1582,6 +1637,7 @@ func (pool *LegacyPool) truncatePending() {
 					pool.pendingNonces.setIfLower(addr, tx.Nonce())
 					log.Trace("Removed fairness-exceeding pending transaction", "hash", hash)
 				}
+				pool.recordDropped("pending limit", caps...)
 				pool.priced.Removed(len(caps))
 				pendingGauge.Dec(int64(len(caps)))
 				if pool.locals.contains(addr) {
@@ -1624,6 +1680,7 @@ func (pool *LegacyPool) truncateQueue() {
 		if size := uint64(list.Len()); size <= drop {
 			for _, tx := range list.Flatten() {
 				pool.removeTx(tx.Hash(), true, true)
+				pool.recordDropped("queue limit", tx)
 			}
 			drop -= size
 			queuedRateLimitMeter.Mark(int64(size))
@@ -1633,12 +1690,49 @@ func (pool *LegacyPool) truncateQueue() {
 		txs := list.Flatten()
 		for i := len(txs) - 1; i >= 0 && drop > 0; i-- {
 			pool.removeTx(txs[i].Hash(), true, true)
+			pool.recordDropped("queue limit", txs[i])
 			drop--
 			queuedRateLimitMeter.Mark(1)
 		}
 	}
 }
 
+// dropUnfundedSponsorships removes sponsored transactions whose sponsors can no
+// longer cover the gas they committed to across the pool, newest first, until the
+// remaining sponsorships are funded. Transactions already made stale by the new
+// head are left to demoteUnexecutables.
+func (pool *LegacyPool) dropUnfundedSponsorships() {
+	for sponsor, txs := range pool.all.Sponsored() {
+		var (
+			balance   = pool.currentState.GetBalance(sponsor).ToBig()
+			committed = new(big.Int)
+			live      = txs[:0]
+		)
+		for _, tx := range txs {
+			from, _ := types.Sender(pool.signer, tx) // already validated during insertion
+			if tx.Nonce() < pool.currentState.GetNonce(from) {
+				continue
+			}
+			committed.Add(committed, sponsoredGas(tx))
+			live = append(live, tx)
+		}
+		if committed.Cmp(balance) <= 0 {
+			continue
+		}
+		sort.Slice(live, func(i, j int) bool { return live[i].Time().After(live[j].Time()) })
+		for _, tx := range live {
+			if committed.Cmp(balance) <= 0 {
+				break
+			}
+			log.Trace("Removed unfunded sponsored transaction", "hash", tx.Hash(), "sponsor", sponsor)
+			pool.removeTx(tx.Hash(), true, true)
+			pool.recordDropped("unfunded sponsorship", tx)
+			committed.Sub(committed, sponsoredGas(tx))
+			pendingNofundsMeter.Mark(1)
+		}
+	}
+}
+
 // demoteUnexecutables removes invalid and processed transactions from the pools
 // executable/pending queue and any subsequent transactions that become unexecutable
 // are moved back into the future queue.
@@ -1659,6 +1753,7 @@ func (pool *LegacyPool) demoteUnexecutables() {
 			pool.all.Remove(hash)
 			log.Trace("Removed old pending transaction", "hash", hash)
 		}
+		pool.recordStale(olds...)
 		// Drop all transactions that are too costly (low balance or out of gas), and queue any invalids back for later
 		drops, invalids := list.Filter(pool.currentState.GetBalance(addr), gasLimit)
 		for _, tx := range drops {
@@ -1666,6 +1761,7 @@ func (pool *LegacyPool) demoteUnexecutables() {
 			log.Trace("Removed unpayable pending transaction", "hash", hash)
 			pool.all.Remove(hash)
 		}
+		pool.recordDropped("unpayable", drops...)
 		pendingNofundsMeter.Mark(int64(len(drops)))
 
 		for _, tx := range invalids {
@@ -1675,6 +1771,7 @@ func (pool *LegacyPool) demoteUnexecutables() {
 			// Internal shuffle shouldn't touch the lookup set.
 			pool.enqueueTx(hash, tx, false, false)
 		}
+		pool.record(txpool.TxEventDemoted, "unexecutable", invalids...)
 		pendingGauge.Dec(int64(len(olds) + len(drops) + len(invalids)))
 		if pool.locals.contains(addr) {
 			localGauge.Dec(int64(len(olds) + len(drops) + len(invalids)))
@@ -1689,6 +1786,7 @@ func (pool *LegacyPool) demoteUnexecutables() {
 				// Internal shuffle shouldn't touch the lookup set.
 				pool.enqueueTx(hash, tx, false, false)
 			}
+			pool.record(txpool.TxEventDemoted, "nonce gap", gapped...)
 			pendingGauge.Dec(int64(len(gapped)))
 		}
 		// Delete the entire pending entry if it became empty.
@@ -1701,6 +1799,95 @@ func (pool *LegacyPool) demoteUnexecutables() {
 	}
 }
 
+// collectInclusions maps the transactions of the blocks of the new chain, from
+// its head down to the common ancestor with the old chain and up to a shallow
+// reorg depth, to the headers of their blocks.
+func (pool *LegacyPool) collectInclusions(oldHead, newHead *types.Header) map[common.Hash]*types.Header {
+	var (
+		inclusions = make(map[common.Hash]*types.Header)
+		rem        *types.Block
+		add        = pool.chain.GetBlock(newHead.Hash(), newHead.Number.Uint64())
+	)
+	if oldHead != nil {
+		rem = pool.chain.GetBlock(oldHead.Hash(), oldHead.Number.Uint64())
+	}
+	for depth := 0; add != nil && depth < 64; depth++ {
+		// Rewind the old chain to the height of the new one, stopping at the
+		// common ancestor
+		for rem != nil && rem.NumberU64() > add.NumberU64() {
+			rem = pool.chain.GetBlock(rem.ParentHash(), rem.NumberU64()-1)
+		}
+		if rem != nil && rem.Hash() == add.Hash() {
+			break
+		}
+		header := add.Header()
+		for _, tx := range add.Transactions() {
+			inclusions[tx.Hash()] = header
+		}
+		if add.NumberU64() == 0 {
+			break
+		}
+		add = pool.chain.GetBlock(add.ParentHash(), add.NumberU64()-1)
+	}
+	return inclusions
+}
+
+// record queues lifecycle events of the given kind for the given transactions,
+// to be written to the history on the next flush.
+//
+// Note, this method assumes the pool lock is held!
+func (pool *LegacyPool) record(kind txpool.TxEventKind, reason string, txs ...*types.Transaction) {
+	if pool.history == nil {
+		return
+	}
+	for _, tx := range txs {
+		pool.historyBuf = append(pool.historyBuf, txpool.NewTxEvent(tx.Hash(), kind, reason))
+	}
+}
+
+// recordDropped queues drop events for the given transactions.
+func (pool *LegacyPool) recordDropped(reason string, txs ...*types.Transaction) {
+	pool.record(txpool.TxEventDropped, reason, txs...)
+}
+
+// recordReplaced queues the replacement event of a transaction.
+func (pool *LegacyPool) recordReplaced(old, tx *types.Transaction) {
+	if pool.history == nil {
+		return
+	}
+	ev := txpool.NewTxEvent(old.Hash(), txpool.TxEventReplaced, "")
+	ev.ReplacedBy = tx.Hash()
+	pool.historyBuf = append(pool.historyBuf, ev)
+}
+
+// recordStale queues the events of transactions removed for their nonce being
+// too low, which are inclusion events if the including block is known.
+func (pool *LegacyPool) recordStale(txs ...*types.Transaction) {
+	if pool.history == nil {
+		return
+	}
+	for _, tx := range txs {
+		header, ok := pool.inclusions[tx.Hash()]
+		if !ok {
+			pool.historyBuf = append(pool.historyBuf, txpool.NewTxEvent(tx.Hash(), txpool.TxEventDropped, "nonce too low"))
+			continue
+		}
+		ev := txpool.NewTxEvent(tx.Hash(), txpool.TxEventIncluded, "")
+		ev.BlockHash, ev.BlockNumber = header.Hash(), header.Number.Uint64()
+		pool.historyBuf = append(pool.historyBuf, ev)
+	}
+}
+
+// flushHistory writes the queued lifecycle events to the history.
+//
+// Note, this method assumes the pool lock is held!
+func (pool *LegacyPool) flushHistory() {
+	if len(pool.historyBuf) > 0 {
+		pool.history.Record(pool.historyBuf...)
+		pool.historyBuf = nil
+	}
+}
+
 // addressByHeartbeat is an account address tagged with its last activity timestamp.
 type addressByHeartbeat struct {
 	address   common.Address
@@ -1800,13 +1987,20 @@ type lookup struct {
 	lock    sync.RWMutex
 	locals  map[common.Hash]*types.Transaction
 	remotes map[common.Hash]*types.Transaction
+
+	signer    types.Signer                                          // Signer to recover the sponsors with
+	sponsors  map[common.Hash]common.Address                        // Sponsor of each pooled sponsored transaction
+	sponsored map[common.Address]map[common.Hash]*types.Transaction // Pooled sponsored transactions by sponsor
 }
 
 // newLookup returns a new lookup structure.
-func newLookup() *lookup {
+func newLookup(signer types.Signer) *lookup {
 	return &lookup{
-		locals:  make(map[common.Hash]*types.Transaction),
-		remotes: make(map[common.Hash]*types.Transaction),
+		locals:    make(map[common.Hash]*types.Transaction),
+		remotes:   make(map[common.Hash]*types.Transaction),
+		signer:    signer,
+		sponsors:  make(map[common.Hash]common.Address),
+		sponsored: make(map[common.Address]map[common.Hash]*types.Transaction),
 	}
 }
 
@@ -1905,6 +2099,17 @@ func (t *lookup) Add(tx *types.Transaction, local bool) {
 	} else {
 		t.remotes[tx.Hash()] = tx
 	}
+	if tx.Type() == types.SponsoredTxType {
+		sponsor, err := types.Sponsor(t.signer, tx) // already validated
+		if err != nil {
+			return
+		}
+		t.sponsors[tx.Hash()] = sponsor
+		if t.sponsored[sponsor] == nil {
+			t.sponsored[sponsor] = make(map[common.Hash]*types.Transaction)
+		}
+		t.sponsored[sponsor][tx.Hash()] = tx
+	}
 }
 
 // Remove removes a transaction from the lookup.
@@ -1925,6 +2130,55 @@ func (t *lookup) Remove(hash common.Hash) {
 
 	delete(t.locals, hash)
 	delete(t.remotes, hash)
+
+	if sponsor, ok := t.sponsors[hash]; ok {
+		delete(t.sponsors, hash)
+		delete(t.sponsored[sponsor], hash)
+		if len(t.sponsored[sponsor]) == 0 {
+			delete(t.sponsored, sponsor)
+		}
+	}
+}
+
+// Sponsor returns the sponsor of a pooled sponsored transaction, or the zero
+// address if the transaction is not sponsored or not found.
+func (t *lookup) Sponsor(hash common.Hash) common.Address {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	return t.sponsors[hash]
+}
+
+// Sponsorship returns the cumulative gas cost of the pooled transactions
+// sponsored by the given account.
+func (t *lookup) Sponsorship(sponsor common.Address) *big.Int {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	committed := new(big.Int)
+	for _, tx := range t.sponsored[sponsor] {
+		committed.Add(committed, sponsoredGas(tx))
+	}
+	return committed
+}
+
+// Sponsored returns the pooled transactions sponsored by each sponsor.
+func (t *lookup) Sponsored() map[common.Address][]*types.Transaction {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	sponsored := make(map[common.Address][]*types.Transaction, len(t.sponsored))
+	for sponsor, txs := range t.sponsored {
+		for _, tx := range txs {
+			sponsored[sponsor] = append(sponsored[sponsor], tx)
+		}
+	}
+	return sponsored
+}
+
+// sponsoredGas returns the maximum gas cost a sponsor pays for a transaction.
+func sponsoredGas(tx *types.Transaction) *big.Int {
+	return new(big.Int).Mul(tx.GasFeeCap(), new(big.Int).SetUint64(tx.Gas()))
 }
 
 // RemoteToLocals migrates the transactions belongs to the given locals to locals
@@ -2673,3 +2673,252 @@ func BenchmarkMultiAccountBatchInsert(b *testing.B) {
 		pool.addRemotesSync([]*types.Transaction{tx})
 	}
 }
+
+// Tests that sponsored transactions are only accepted once enabled, and that
+// the sponsor needs to be able to pay for their gas, not the sender.
+func TestSponsoredTransactions(t *testing.T) {
+	t.Parallel()
+
+	config := *params.HaloChainConfig
+	enabled := config
+	enabled.HaloSponsoredTxFBlock = big.NewInt(0)
+	signer := types.LatestSigner(&enabled)
+
+	sponsoredTx := func(key, sponsorKey *ecdsa.PrivateKey) *types.Transaction {
+		tx, _ := types.SignNewTx(key, signer, &types.SponsoredTx{
+			ChainID:   config.GetChainID(),
+			GasTipCap: big.NewInt(1),
+			GasFeeCap: big.NewInt(vars.InitialBaseFee),
+			Gas:       21000,
+			To:        &common.Address{},
+			Value:     big.NewInt(100),
+		})
+		tx, _ = types.SignSponsor(tx, sponsorKey)
+		return tx
+	}
+	sponsorKey, _ := crypto.GenerateKey()
+	sponsor := crypto.PubkeyToAddress(sponsorKey.PublicKey)
+
+	// Before the fork, sponsored transactions are rejected
+	pool, key := setupPoolWithConfig(&config)
+	defer pool.Close()
+
+	if err := pool.addRemote(sponsoredTx(key, sponsorKey)); !errors.Is(err, core.ErrTxTypeNotSupported) {
+		t.Fatalf("pre-fork error mismatch: have %v, want %v", err, core.ErrTxTypeNotSupported)
+	}
+
+	config.HaloSponsoredTxFBlock = big.NewInt(0)
+	pool, key = setupPoolWithConfig(&config)
+	defer pool.Close()
+
+	// The sender only needs to pay the value, the sponsor the gas
+	from := crypto.PubkeyToAddress(key.PublicKey)
+	testAddBalance(pool, from, big.NewInt(100))
+	if err := pool.addRemote(sponsoredTx(key, sponsorKey)); !errors.Is(err, core.ErrInsufficientFunds) {
+		t.Fatalf("unfunded sponsor error mismatch: have %v, want %v", err, core.ErrInsufficientFunds)
+	}
+	testAddBalance(pool, sponsor, big.NewInt(21000*vars.InitialBaseFee))
+	if err := pool.addRemoteSync(sponsoredTx(key, sponsorKey)); err != nil {
+		t.Fatalf("failed to add sponsored transaction: %v", err)
+	}
+	if pending, _ := pool.Stats(); pending != 1 {
+		t.Fatalf("pending transactions mismatch: have %d, want 1", pending)
+	}
+	// Transactions without a valid sponsor signature are rejected
+	tx, _ := types.SignNewTx(key, signer, &types.SponsoredTx{
+		ChainID:   config.GetChainID(),
+		Nonce:     1,
+		GasTipCap: big.NewInt(1),
+		GasFeeCap: big.NewInt(vars.InitialBaseFee),
+		Gas:       21000,
+		To:        &common.Address{},
+	})
+	if err := pool.addRemote(tx); !errors.Is(err, txpool.ErrInvalidSponsor) {
+		t.Fatalf("missing sponsor error mismatch: have %v, want %v", err, txpool.ErrInvalidSponsor)
+	}
+}
+
+// Tests that the gas sponsored across the pool is checked against the balance of
+// the sponsor, both when adding transactions and when the sponsor's balance drops.
+func TestSponsoredTransactionsCommittedGas(t *testing.T) {
+	t.Parallel()
+
+	config := *params.HaloChainConfig
+	config.HaloSponsoredTxFBlock = big.NewInt(0)
+
+	sponsorKey, _ := crypto.GenerateKey()
+	sponsor := crypto.PubkeyToAddress(sponsorKey.PublicKey)
+
+	keys := make([]*ecdsa.PrivateKey, 3)
+	for i := range keys {
+		keys[i], _ = crypto.GenerateKey()
+	}
+	txs := make([]*types.Transaction, len(keys))
+	for i, key := range keys {
+		tx, _ := types.SignNewTx(key, types.LatestSigner(&config), &types.SponsoredTx{
+			ChainID:   config.GetChainID(),
+			GasTipCap: big.NewInt(1),
+			GasFeeCap: big.NewInt(vars.InitialBaseFee),
+			Gas:       21000,
+			To:        &common.Address{},
+			Value:     big.NewInt(100),
+		})
+		txs[i], _ = types.SignSponsor(tx, sponsorKey)
+	}
+	pool, _ := setupPoolWithConfig(&config)
+	defer pool.Close()
+
+	resetState := func(sponsored int64) {
+		statedb, _ := state.New(types.EmptyRootHash, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+		for _, key := range keys {
+			statedb.AddBalance(crypto.PubkeyToAddress(key.PublicKey), uint256.NewInt(100))
+		}
+		statedb.AddBalance(sponsor, uint256.NewInt(uint64(sponsored*21000*vars.InitialBaseFee)))
+
+		pool.chain = newTestBlockChain(pool.chainconfig, 10000000, statedb, new(event.Feed))
+		<-pool.requestReset(nil, nil)
+	}
+	// The sponsor can pay for two transactions, the third one is rejected
+	resetState(2)
+	if errs := pool.addRemotesSync(txs[:2]); errs[0] != nil || errs[1] != nil {
+		t.Fatalf("failed to add sponsored transactions: %v", errs)
+	}
+	if err := pool.addRemote(txs[2]); !errors.Is(err, core.ErrInsufficientFunds) {
+		t.Fatalf("overcommitted sponsor error mismatch: have %v, want %v", err, core.ErrInsufficientFunds)
+	}
+	if have := pool.all.Sponsorship(sponsor); have.Cmp(big.NewInt(2*21000*vars.InitialBaseFee)) != 0 {
+		t.Fatalf("sponsorship mismatch: have %v, want %v", have, 2*21000*vars.InitialBaseFee)
+	}
+	// The sponsor's balance drops, one of the transactions is evicted
+	resetState(1)
+	if pending, queued := pool.Stats(); pending+queued != 1 {
+		t.Fatalf("pooled transactions mismatch: have %d, want 1", pending+queued)
+	}
+	if have := pool.all.Sponsorship(sponsor); have.Cmp(big.NewInt(21000*vars.InitialBaseFee)) != 0 {
+		t.Fatalf("sponsorship mismatch: have %v, want %v", have, 21000*vars.InitialBaseFee)
+	}
+	if err := validatePoolInternals(pool); err != nil {
+		t.Fatalf("pool internal state corrupted: %v", err)
+	}
+}
+
+// historyTestChain is a test blockchain serving the blocks added to it.
+type historyTestChain struct {
+	*testBlockChain
+	blocks map[common.Hash]*types.Block
+}
+
+func (bc *historyTestChain) GetBlock(hash common.Hash, number uint64) *types.Block {
+	if block, ok := bc.blocks[hash]; ok {
+		return block
+	}
+	return bc.testBlockChain.GetBlock(hash, number)
+}
+
+// Tests that the lifecycle events of pooled transactions are recorded in the
+// history if one is set.
+func TestTransactionHistory(t *testing.T) {
+	t.Parallel()
+
+	statedb, _ := state.New(types.EmptyRootHash, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	chain := &historyTestChain{
+		testBlockChain: newTestBlockChain(params.TestChainConfig, 10000000, statedb, new(event.Feed)),
+		blocks:         make(map[common.Hash]*types.Block),
+	}
+	history := txpool.NewHistory(rawdb.NewMemoryDatabase(), 16)
+	defer history.Close()
+
+	pool := New(testTxPoolConfig, chain)
+	pool.SetHistory(history)
+	if err := pool.Init(testTxPoolConfig.PriceLimit, chain.CurrentBlock(), makeAddressReserver()); err != nil {
+		t.Fatalf("failed to init pool: %v", err)
+	}
+	defer pool.Close()
+
+	key, _ := crypto.GenerateKey()
+	testAddBalance(pool, crypto.PubkeyToAddress(key.PublicKey), big.NewInt(1000000000))
+
+	// waitHistory waits until the given number of events is recorded for a
+	// transaction, as the history is written in the background.
+	waitHistory := func(hash common.Hash, kinds ...txpool.TxEventKind) []*txpool.TxEvent {
+		t.Helper()
+
+		var events []*txpool.TxEvent
+		for start := time.Now(); time.Since(start) < time.Second; time.Sleep(10 * time.Millisecond) {
+			if events = history.Get(hash); len(events) >= len(kinds) {
+				break
+			}
+		}
+		if len(events) != len(kinds) {
+			t.Fatalf("event count mismatch for %x: have %d, want %d", hash, len(events), len(kinds))
+		}
+		for i, kind := range kinds {
+			if events[i].Kind != kind {
+				t.Errorf("event %d kind mismatch for %x: have %s, want %s", i, hash, events[i].Kind, kind)
+			}
+		}
+		return events
+	}
+	// Add a transaction and replace it
+	tx := pricedTransaction(0, 100000, big.NewInt(1), key)
+	if err := pool.addRemoteSync(tx); err != nil {
+		t.Fatalf("failed to add transaction: %v", err)
+	}
+	replacement := pricedTransaction(0, 100000, big.NewInt(2), key)
+	if err := pool.addRemoteSync(replacement); err != nil {
+		t.Fatalf("failed to add replacement: %v", err)
+	}
+	events := waitHistory(tx.Hash(), txpool.TxEventAdded, txpool.TxEventPromoted, txpool.TxEventReplaced)
+	if events[2].ReplacedBy != replacement.Hash() {
+		t.Errorf("replacement mismatch: have %x, want %x", events[2].ReplacedBy, replacement.Hash())
+	}
+	// Include the replacement in a block
+	var (
+		parent = chain.CurrentBlock()
+		block  = types.NewBlock(&types.Header{
+			ParentHash: parent.Hash(),
+			Number:     big.NewInt(1),
+			GasLimit:   parent.GasLimit,
+			BaseFee:    big.NewInt(1),
+		}, []*types.Transaction{replacement}, nil, nil, trie.NewStackTrie(nil))
+	)
+	chain.blocks[block.Hash()] = block
+	testSetNonce(pool, crypto.PubkeyToAddress(key.PublicKey), 1)
+	<-pool.requestReset(parent, block.Header())
+
+	events = waitHistory(replacement.Hash(), txpool.TxEventAdded, txpool.TxEventIncluded)
+	if events[1].BlockHash != block.Hash() || events[1].BlockNumber != 1 {
+		t.Errorf("inclusion mismatch: have %x #%d, want %x #1", events[1].BlockHash, events[1].BlockNumber, block.Hash())
+	}
+	// Reorg to a block of the same height including another transaction
+	reorged := pricedTransaction(1, 100000, big.NewInt(1), key)
+	if err := pool.addRemoteSync(reorged); err != nil {
+		t.Fatalf("failed to add transaction: %v", err)
+	}
+	sibling := types.NewBlock(&types.Header{
+		ParentHash: parent.Hash(),
+		Number:     big.NewInt(1),
+		GasLimit:   parent.GasLimit,
+		BaseFee:    big.NewInt(1),
+		Extra:      []byte("sibling"),
+	}, []*types.Transaction{replacement, reorged}, nil, nil, trie.NewStackTrie(nil))
+	chain.blocks[sibling.Hash()] = sibling
+	testSetNonce(pool, crypto.PubkeyToAddress(key.PublicKey), 2)
+	<-pool.requestReset(block.Header(), sibling.Header())
+
+	events = waitHistory(reorged.Hash(), txpool.TxEventAdded, txpool.TxEventPromoted, txpool.TxEventIncluded)
+	if events[2].BlockHash != sibling.Hash() || events[2].BlockNumber != 1 {
+		t.Errorf("reorg inclusion mismatch: have %x #%d, want %x #1", events[2].BlockHash, events[2].BlockNumber, sibling.Hash())
+	}
+	// Drop a transaction by raising the gas tip
+	dropped := pricedTransaction(2, 100000, big.NewInt(1), key)
+	if err := pool.addRemoteSync(dropped); err != nil {
+		t.Fatalf("failed to add transaction: %v", err)
+	}
+	pool.SetGasTip(big.NewInt(2))
+
+	events = waitHistory(dropped.Hash(), txpool.TxEventAdded, txpool.TxEventPromoted, txpool.TxEventDropped)
+	if events[2].Reason != "below gas tip" {
+		t.Errorf("drop reason mismatch: have %q, want %q", events[2].Reason, "below gas tip")
+	}
+}
@@ -75,6 +75,9 @@ func ValidateTransaction(tx *types.Transaction, head *types.Header, signer types
 	if !eip4844Enabled && tx.Type() == types.BlobTxType {
 		return fmt.Errorf("%w: type %d rejected, pool not yet in Cancun", core.ErrTxTypeNotSupported, tx.Type())
 	}
+	if !opts.Config.IsEnabled(opts.Config.GetHaloSponsoredTxTransition, head.Number) && tx.Type() == types.SponsoredTxType {
+		return fmt.Errorf("%w: type %d rejected, sponsored transactions not yet enabled", core.ErrTxTypeNotSupported, tx.Type())
+	}
 	// Check whether the init code size has been exceeded
 	if opts.Config.IsEnabledByTime(opts.Config.GetEIP3860TransitionTime, &head.Time) && tx.To() == nil && uint64(len(tx.Data())) > vars.MaxInitCodeSize {
 		return fmt.Errorf("%w: code size %v, limit %v", core.ErrMaxInitCodeSizeExceeded, len(tx.Data()), vars.MaxInitCodeSize)
@@ -103,6 +106,11 @@ func ValidateTransaction(tx *types.Transaction, head *types.Header, signer types
 	if _, err := types.Sender(signer, tx); err != nil {
 		return ErrInvalidSender
 	}
+	if tx.Type() == types.SponsoredTxType {
+		if _, err := types.Sponsor(signer, tx); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidSponsor, err)
+		}
+	}
 	// Ensure the transaction has more gas than the bare minimum needed to cover
 	// the transaction metadata
 	intrGas, err := core.IntrinsicGas(tx.Data(), tx.AccessList(), tx.To() == nil, true, opts.Config.IsEnabled(opts.Config.GetEIP2028Transition, head.Number), opts.Config.IsEnabledByTime(opts.Config.GetEIP3860TransitionTime, &head.Time))
@@ -194,6 +202,13 @@ type ValidationOptionsWithState struct {
 	// ExistingCost is a mandatory callback to retrieve an already pooled
 	// transaction's cost with the given nonce to check for overdrafts.
 	ExistingCost func(addr common.Address, nonce uint64) *big.Int
+
+	// ExistingSponsorship is an optional callback to retrieve the cumulative gas
+	// cost sponsored by an account in the already pooled transactions, excluding
+	// the transaction with the given sender and nonce that would be replaced. If
+	// this method is not set, only the gas of the new transaction is checked
+	// against the sponsor's balance.
+	ExistingSponsorship func(sponsor common.Address, from common.Address, nonce uint64) *big.Int
 }
 
 // ValidateTransactionWithState is a helper method to check whether a transaction
@@ -227,6 +242,26 @@ func ValidateTransactionWithState(tx *types.Transaction, signer types.Signer, op
 	if balance.Cmp(cost) < 0 {
 		return fmt.Errorf("%w: balance %v, tx cost %v, overshot %v", core.ErrInsufficientFunds, balance, cost, new(big.Int).Sub(cost, balance))
 	}
+	// Ensure the sponsor has enough funds to cover the gas of the transaction on
+	// top of the gas it already sponsors in other pooled transactions.
+	if tx.Type() == types.SponsoredTxType {
+		sponsor, err := types.Sponsor(signer, tx) // already validated
+		if err != nil {
+			return err
+		}
+		var (
+			balance   = opts.State.GetBalance(sponsor).ToBig()
+			gas       = new(big.Int).Mul(tx.GasFeeCap(), new(big.Int).SetUint64(tx.Gas()))
+			committed = new(big.Int)
+		)
+		if opts.ExistingSponsorship != nil {
+			committed.Set(opts.ExistingSponsorship(sponsor, from, tx.Nonce()))
+		}
+		need := new(big.Int).Add(committed, gas)
+		if balance.Cmp(need) < 0 {
+			return fmt.Errorf("%w: sponsor balance %v, sponsored cost %v, tx gas cost %v, overshot %v", core.ErrInsufficientFunds, balance, committed, gas, new(big.Int).Sub(need, balance))
+		}
+	}
 	// Ensure the transactor has enough funds to cover for replacements or nonce
 	// expansions without overdrafts
 	spent := opts.ExistingExpenditure(from)
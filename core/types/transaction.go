@@ -49,6 +49,7 @@ const (
 	AccessListTxType = 0x01
 	DynamicFeeTxType = 0x02
 	BlobTxType       = 0x03
+	SponsoredTxType  = 0x07
 )
 
 // Transaction is an Ethereum transaction.
@@ -206,6 +207,8 @@ func (tx *Transaction) decodeTyped(b []byte) (TxData, error) {
 		inner = new(DynamicFeeTx)
 	case BlobTxType:
 		inner = new(BlobTx)
+	case SponsoredTxType:
+		inner = new(SponsoredTx)
 	default:
 		return nil, ErrTxTypeNotSupported
 	}
@@ -309,8 +312,13 @@ func (tx *Transaction) To() *common.Address {
 	return copyAddressPtr(tx.inner.to())
 }
 
-// Cost returns (gas * gasPrice) + (blobGas * blobGasPrice) + value.
+// Cost returns (gas * gasPrice) + (blobGas * blobGasPrice) + value, the amount
+// paid by the sender. The gas of sponsored transactions is paid by the sponsor,
+// their cost is the value only.
 func (tx *Transaction) Cost() *big.Int {
+	if tx.Type() == SponsoredTxType {
+		return new(big.Int).Set(tx.Value())
+	}
 	total := new(big.Int).Mul(tx.GasPrice(), new(big.Int).SetUint64(tx.Gas()))
 	if tx.Type() == BlobTxType {
 		total.Add(total, new(big.Int).Mul(tx.BlobGasFeeCap(), new(big.Int).SetUint64(tx.BlobGas())))
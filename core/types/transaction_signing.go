@@ -53,6 +53,9 @@ func MakeSigner(config ctypes.ChainConfigurator, blockNumber *big.Int, blockTime
 	default:
 		signer = FrontierSigner{}
 	}
+	if config.IsEnabled(config.GetHaloSponsoredTxTransition, blockNumber) {
+		signer = NewSponsorSigner(signer)
+	}
 	return signer
 }
 
@@ -65,17 +68,22 @@ func MakeSigner(config ctypes.ChainConfigurator, blockNumber *big.Int, blockTime
 // have the current block number available, use MakeSigner instead.
 func LatestSigner(config ctypes.ChainConfigurator) Signer {
 	if chainID := config.GetChainID(); chainID != nil {
-		if config.GetEIP4844TransitionTime() != nil || config.GetEIP4844Transition() != nil {
-			return NewCancunSigner(chainID)
-		}
-		if config.GetEIP1559Transition() != nil {
-			return NewEIP1559Signer(chainID)
-		}
-		if config.GetEIP2930Transition() != nil {
-			return NewEIP2930Signer(chainID)
+		var signer Signer
+		switch {
+		case config.GetEIP4844TransitionTime() != nil || config.GetEIP4844Transition() != nil:
+			signer = NewCancunSigner(chainID)
+		case config.GetEIP1559Transition() != nil:
+			signer = NewEIP1559Signer(chainID)
+		case config.GetEIP2930Transition() != nil:
+			signer = NewEIP2930Signer(chainID)
+		case config.GetEIP155Transition() != nil:
+			signer = NewEIP155Signer(chainID)
 		}
-		if config.GetEIP155Transition() != nil {
-			return NewEIP155Signer(chainID)
+		if signer != nil {
+			if config.GetHaloSponsoredTxTransition() != nil {
+				signer = NewSponsorSigner(signer)
+			}
+			return signer
 		}
 	}
 	return HomesteadSigner{}
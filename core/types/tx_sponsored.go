@@ -0,0 +1,266 @@
+// Copyright 2025 The core-geth Authors
+// This file is part of the core-geth library.
+//
+// The core-geth library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The core-geth library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the core-geth library. If not, see <http://www.gnu.org/licenses/>.
+
+package types
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// ErrMissingSponsor is returned if a sponsored transaction is not countersigned
+// by its sponsor.
+var ErrMissingSponsor = errors.New("missing sponsor signature")
+
+// SponsoredTx is the envelope of a dynamic fee transaction whose gas is paid by
+// a sponsor. The sender signs the inner transaction, as it would sign an EIP-1559
+// one, and the sponsor countersigns the sender-signed transaction. The sender
+// pays the value and the sponsor pays for the gas, receiving the refund.
+type SponsoredTx struct {
+	ChainID    *big.Int
+	Nonce      uint64
+	GasTipCap  *big.Int // a.k.a. maxPriorityFeePerGas
+	GasFeeCap  *big.Int // a.k.a. maxFeePerGas
+	Gas        uint64
+	To         *common.Address `rlp:"nil"` // nil means contract creation
+	Value      *big.Int
+	Data       []byte
+	AccessList AccessList
+
+	// Signature values of the sender
+	V *big.Int `json:"v" gencodec:"required"`
+	R *big.Int `json:"r" gencodec:"required"`
+	S *big.Int `json:"s" gencodec:"required"`
+
+	// Signature values of the sponsor
+	SponsorV *big.Int `json:"sponsorV" gencodec:"required"`
+	SponsorR *big.Int `json:"sponsorR" gencodec:"required"`
+	SponsorS *big.Int `json:"sponsorS" gencodec:"required"`
+}
+
+// copy creates a deep copy of the transaction data and initializes all fields.
+func (tx *SponsoredTx) copy() TxData {
+	cpy := &SponsoredTx{
+		Nonce: tx.Nonce,
+		To:    copyAddressPtr(tx.To),
+		Data:  common.CopyBytes(tx.Data),
+		Gas:   tx.Gas,
+		// These are copied below.
+		AccessList: make(AccessList, len(tx.AccessList)),
+		Value:      new(big.Int),
+		ChainID:    new(big.Int),
+		GasTipCap:  new(big.Int),
+		GasFeeCap:  new(big.Int),
+		V:          new(big.Int),
+		R:          new(big.Int),
+		S:          new(big.Int),
+		SponsorV:   new(big.Int),
+		SponsorR:   new(big.Int),
+		SponsorS:   new(big.Int),
+	}
+	copy(cpy.AccessList, tx.AccessList)
+	for _, v := range []struct{ dst, src *big.Int }{
+		{cpy.Value, tx.Value}, {cpy.ChainID, tx.ChainID},
+		{cpy.GasTipCap, tx.GasTipCap}, {cpy.GasFeeCap, tx.GasFeeCap},
+		{cpy.V, tx.V}, {cpy.R, tx.R}, {cpy.S, tx.S},
+		{cpy.SponsorV, tx.SponsorV}, {cpy.SponsorR, tx.SponsorR}, {cpy.SponsorS, tx.SponsorS},
+	} {
+		if v.src != nil {
+			v.dst.Set(v.src)
+		}
+	}
+	return cpy
+}
+
+// accessors for innerTx.
+func (tx *SponsoredTx) txType() byte           { return SponsoredTxType }
+func (tx *SponsoredTx) chainID() *big.Int      { return tx.ChainID }
+func (tx *SponsoredTx) accessList() AccessList { return tx.AccessList }
+func (tx *SponsoredTx) data() []byte           { return tx.Data }
+func (tx *SponsoredTx) gas() uint64            { return tx.Gas }
+func (tx *SponsoredTx) gasFeeCap() *big.Int    { return tx.GasFeeCap }
+func (tx *SponsoredTx) gasTipCap() *big.Int    { return tx.GasTipCap }
+func (tx *SponsoredTx) gasPrice() *big.Int     { return tx.GasFeeCap }
+func (tx *SponsoredTx) value() *big.Int        { return tx.Value }
+func (tx *SponsoredTx) nonce() uint64          { return tx.Nonce }
+func (tx *SponsoredTx) to() *common.Address    { return tx.To }
+
+func (tx *SponsoredTx) effectiveGasPrice(dst *big.Int, baseFee *big.Int) *big.Int {
+	if baseFee == nil {
+		return dst.Set(tx.GasFeeCap)
+	}
+	tip := dst.Sub(tx.GasFeeCap, baseFee)
+	if tip.Cmp(tx.GasTipCap) > 0 {
+		tip.Set(tx.GasTipCap)
+	}
+	return tip.Add(tip, baseFee)
+}
+
+func (tx *SponsoredTx) rawSignatureValues() (v, r, s *big.Int) {
+	return tx.V, tx.R, tx.S
+}
+
+func (tx *SponsoredTx) setSignatureValues(chainID, v, r, s *big.Int) {
+	tx.ChainID, tx.V, tx.R, tx.S = chainID, v, r, s
+}
+
+func (tx *SponsoredTx) encode(b *bytes.Buffer) error {
+	return rlp.Encode(b, tx)
+}
+
+func (tx *SponsoredTx) decode(input []byte) error {
+	return rlp.DecodeBytes(input, tx)
+}
+
+// RawSponsorSignatureValues returns the V, R, S signature values of the sponsor
+// of a sponsored transaction, or nils for other transaction types.
+// The return values should not be modified by the caller.
+func (tx *Transaction) RawSponsorSignatureValues() (v, r, s *big.Int) {
+	if inner, ok := tx.inner.(*SponsoredTx); ok {
+		return inner.SponsorV, inner.SponsorR, inner.SponsorS
+	}
+	return nil, nil, nil
+}
+
+// SponsorHash returns the hash to be signed by the sponsor of a sponsored
+// transaction. It commits to the transaction signed by the sender.
+func SponsorHash(tx *Transaction) common.Hash {
+	v, r, s := tx.RawSignatureValues()
+	return prefixedRlpHash(
+		tx.Type(),
+		[]interface{}{
+			tx.ChainId(),
+			tx.Nonce(),
+			tx.GasTipCap(),
+			tx.GasFeeCap(),
+			tx.Gas(),
+			tx.To(),
+			tx.Value(),
+			tx.Data(),
+			tx.AccessList(),
+			v, r, s,
+		})
+}
+
+// Sponsor returns the address paying for the gas of a sponsored transaction,
+// derived from the signature of the sponsor.
+func Sponsor(signer Signer, tx *Transaction) (common.Address, error) {
+	inner, ok := tx.inner.(*SponsoredTx)
+	if !ok {
+		return common.Address{}, ErrTxTypeNotSupported
+	}
+	// Copies and decoded transactions carry zero values instead of nils
+	if inner.SponsorV == nil || inner.SponsorR == nil || inner.SponsorS == nil ||
+		inner.SponsorR.Sign() == 0 || inner.SponsorS.Sign() == 0 {
+		return common.Address{}, ErrMissingSponsor
+	}
+	if tx.ChainId().Cmp(signer.ChainID()) != 0 {
+		return common.Address{}, fmt.Errorf("%w: have %d want %d", ErrInvalidChainId, tx.ChainId(), signer.ChainID())
+	}
+	// Sponsors use 0 and 1 as their recovery id, add 27 to become equivalent
+	// to unprotected Homestead signatures.
+	V := new(big.Int).Add(inner.SponsorV, big.NewInt(27))
+	return recoverPlain(SponsorHash(tx), inner.SponsorR, inner.SponsorS, V, true)
+}
+
+// SignSponsor countersigns a sender-signed sponsored transaction, making the
+// owner of the key pay for its gas.
+func SignSponsor(tx *Transaction, prv *ecdsa.PrivateKey) (*Transaction, error) {
+	if tx.Type() != SponsoredTxType {
+		return nil, ErrTxTypeNotSupported
+	}
+	h := SponsorHash(tx)
+	sig, err := crypto.Sign(h[:], prv)
+	if err != nil {
+		return nil, err
+	}
+	cpy := tx.inner.copy().(*SponsoredTx)
+	cpy.SponsorR, cpy.SponsorS, _ = decodeSignature(sig)
+	cpy.SponsorV = big.NewInt(int64(sig[64]))
+	return &Transaction{inner: cpy, time: tx.time}, nil
+}
+
+// sponsorSigner wraps a signer, accepting sponsored transactions in addition to
+// the transactions accepted by the wrapped signer.
+type sponsorSigner struct{ Signer }
+
+// NewSponsorSigner returns a signer accepting sponsored transactions, and the
+// transactions accepted by the given replay protected signer.
+func NewSponsorSigner(signer Signer) Signer {
+	return sponsorSigner{signer}
+}
+
+func (s sponsorSigner) Sender(tx *Transaction) (common.Address, error) {
+	if tx.Type() != SponsoredTxType {
+		return s.Signer.Sender(tx)
+	}
+	V, R, S := tx.RawSignatureValues()
+	// Sponsored txs are defined to use 0 and 1 as their recovery
+	// id, add 27 to become equivalent to unprotected Homestead signatures.
+	V = new(big.Int).Add(V, big.NewInt(27))
+	if tx.ChainId().Cmp(s.ChainID()) != 0 {
+		return common.Address{}, fmt.Errorf("%w: have %d want %d", ErrInvalidChainId, tx.ChainId(), s.ChainID())
+	}
+	return recoverPlain(s.Hash(tx), R, S, V, true)
+}
+
+func (s sponsorSigner) Equal(s2 Signer) bool {
+	x, ok := s2.(sponsorSigner)
+	return ok && s.Signer.Equal(x.Signer)
+}
+
+func (s sponsorSigner) SignatureValues(tx *Transaction, sig []byte) (R, S, V *big.Int, err error) {
+	txdata, ok := tx.inner.(*SponsoredTx)
+	if !ok {
+		return s.Signer.SignatureValues(tx, sig)
+	}
+	// Check that chain ID of tx matches the signer. We also accept ID zero here,
+	// because it indicates that the chain ID was not specified in the tx.
+	if txdata.ChainID.Sign() != 0 && txdata.ChainID.Cmp(s.ChainID()) != 0 {
+		return nil, nil, nil, fmt.Errorf("%w: have %d want %d", ErrInvalidChainId, txdata.ChainID, s.ChainID())
+	}
+	R, S, _ = decodeSignature(sig)
+	V = big.NewInt(int64(sig[64]))
+	return R, S, V, nil
+}
+
+// Hash returns the hash to be signed by the sender.
+// It does not uniquely identify the transaction.
+func (s sponsorSigner) Hash(tx *Transaction) common.Hash {
+	if tx.Type() != SponsoredTxType {
+		return s.Signer.Hash(tx)
+	}
+	return prefixedRlpHash(
+		tx.Type(),
+		[]interface{}{
+			s.ChainID(),
+			tx.Nonce(),
+			tx.GasTipCap(),
+			tx.GasFeeCap(),
+			tx.Gas(),
+			tx.To(),
+			tx.Value(),
+			tx.Data(),
+			tx.AccessList(),
+		})
+}
@@ -0,0 +1,106 @@
+// Copyright 2025 The core-geth Authors
+// This file is part of the core-geth library.
+//
+// The core-geth library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The core-geth library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the core-geth library. If not, see <http://www.gnu.org/licenses/>.
+
+package types
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Tests that sponsored transactions survive the binary and JSON encodings, and
+// that both the sender and the sponsor are recovered from them.
+func TestSponsoredTxEncoding(t *testing.T) {
+	var (
+		senderKey, _  = crypto.GenerateKey()
+		sponsorKey, _ = crypto.GenerateKey()
+		sender        = crypto.PubkeyToAddress(senderKey.PublicKey)
+		sponsor       = crypto.PubkeyToAddress(sponsorKey.PublicKey)
+		to            = common.HexToAddress("0xcccc")
+		signer        = NewSponsorSigner(NewEIP155Signer(big.NewInt(7)))
+	)
+	tx, err := SignNewTx(senderKey, signer, &SponsoredTx{
+		ChainID:    big.NewInt(7),
+		Nonce:      3,
+		GasTipCap:  big.NewInt(1),
+		GasFeeCap:  big.NewInt(10),
+		Gas:        25000,
+		To:         &to,
+		Value:      big.NewInt(5),
+		Data:       []byte{0x01},
+		AccessList: AccessList{{Address: to, StorageKeys: []common.Hash{{0x01}}}},
+	})
+	if err != nil {
+		t.Fatalf("failed to sign transaction: %v", err)
+	}
+	if _, err := Sponsor(signer, tx); !errors.Is(err, ErrMissingSponsor) {
+		t.Fatalf("sponsor of uncountersigned transaction: have %v, want %v", err, ErrMissingSponsor)
+	}
+	if _, err := tx.MarshalBinary(); err != nil {
+		t.Fatalf("failed to encode uncountersigned transaction: %v", err)
+	}
+	tx, err = SignSponsor(tx, sponsorKey)
+	if err != nil {
+		t.Fatalf("failed to countersign transaction: %v", err)
+	}
+	if tx.Cost().Cmp(tx.Value()) != 0 {
+		t.Errorf("sender cost mismatch: have %v, want %v", tx.Cost(), tx.Value())
+	}
+
+	bin, err := tx.MarshalBinary()
+	if err != nil {
+		t.Fatalf("failed to encode transaction: %v", err)
+	}
+	js, err := tx.MarshalJSON()
+	if err != nil {
+		t.Fatalf("failed to encode transaction to json: %v", err)
+	}
+	binTx, jsTx := new(Transaction), new(Transaction)
+	if err := binTx.UnmarshalBinary(bin); err != nil {
+		t.Fatalf("failed to decode transaction: %v", err)
+	}
+	if err := jsTx.UnmarshalJSON(js); err != nil {
+		t.Fatalf("failed to decode transaction from json: %v", err)
+	}
+	for name, dec := range map[string]*Transaction{"binary": binTx, "json": jsTx} {
+		if dec.Hash() != tx.Hash() {
+			t.Errorf("%s: hash mismatch: have %x, want %x", name, dec.Hash(), tx.Hash())
+		}
+		if from, err := Sender(signer, dec); err != nil || from != sender {
+			t.Errorf("%s: sender mismatch: have %x (%v), want %x", name, from, err, sender)
+		}
+		if payer, err := Sponsor(signer, dec); err != nil || payer != sponsor {
+			t.Errorf("%s: sponsor mismatch: have %x (%v), want %x", name, payer, err, sponsor)
+		}
+	}
+	// The sponsor signature commits to the sender signed transaction
+	tampered := tx.inner.copy().(*SponsoredTx)
+	tampered.Value = big.NewInt(6)
+	if payer, _ := Sponsor(signer, NewTx(tampered)); payer == sponsor {
+		t.Error("sponsor recovered from tampered transaction")
+	}
+	// Sponsored transactions are only accepted by sponsor signers
+	if _, err := Sender(NewCancunSigner(big.NewInt(7)), binTx); !errors.Is(err, ErrTxTypeNotSupported) {
+		t.Errorf("plain signer error mismatch: have %v, want %v", err, ErrTxTypeNotSupported)
+	}
+	if _, err := Sponsor(NewSponsorSigner(NewEIP155Signer(big.NewInt(8))), binTx); !errors.Is(err, ErrInvalidChainId) {
+		t.Errorf("chain id error mismatch: have %v, want %v", err, ErrInvalidChainId)
+	}
+}
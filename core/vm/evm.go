@@ -21,7 +21,6 @@ import (
 	"math/big"
 	"sync/atomic"
 
-	"github.com/ethereum/evmc/v7/bindings/go/evmc"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
@@ -171,11 +170,11 @@ func NewEVM(blockCtx BlockContext, txCtx TxContext, statedb StateDB, chainConfig
 	// In some implementations, EWASM may be configured with a block number.
 	// In this implementation, the interpreter is configured globally instead.
 	if config.EWASMInterpreter != "" {
-		evm.interpreters = append(evm.interpreters, &EVMC{ewasmModule, evm, evmc.CapabilityEWASM, false})
+		evm.interpreters = append(evm.interpreters, newEVMCEwasm(evm))
 	}
 
 	if config.EVMInterpreter != "" {
-		evm.interpreters = append(evm.interpreters, &EVMC{evmModule, evm, evmc.CapabilityEVM1, false})
+		evm.interpreters = append(evm.interpreters, newEVMCEVM(evm))
 	} else {
 		evm.interpreters = append(evm.interpreters, NewEVMInterpreter(evm))
 	}
@@ -17,10 +17,13 @@
 // Implements interaction with EVMC-based VMs.
 // https://github.com/ethereum/evmc
 
+//go:build cgo
+
 package vm
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"math/big"
 	"strings"
@@ -50,37 +53,60 @@ var (
 	evmcMux     sync.Mutex
 )
 
-func InitEVMCEVM(config string) {
+// InitEVMCEVM loads the EVMC VM interpreting EVM1 code, configured as the path
+// of the VM followed by its comma separated options.
+func InitEVMCEVM(config string) error {
 	evmcMux.Lock()
 	defer evmcMux.Unlock()
 	if evmModule != nil {
-		return
+		return nil
 	}
-
-	evmModule = initEVMC(evmc.CapabilityEVM1, config)
+	instance, err := initEVMC(evmc.CapabilityEVM1, config)
+	if err != nil {
+		return err
+	}
+	evmModule = instance
 	log.Info("initialized EVMC interpreter", "path", config)
+	return nil
 }
 
-func InitEVMCEwasm(config string) {
+// InitEVMCEwasm loads the EVMC VM interpreting Ewasm code, configured as the
+// path of the VM followed by its comma separated options.
+func InitEVMCEwasm(config string) error {
 	evmcMux.Lock()
 	defer evmcMux.Unlock()
 	if ewasmModule != nil {
-		return
+		return nil
+	}
+	instance, err := initEVMC(evmc.CapabilityEWASM, config)
+	if err != nil {
+		return err
 	}
-	ewasmModule = initEVMC(evmc.CapabilityEWASM, config)
+	ewasmModule = instance
+	return nil
+}
+
+// newEVMCEVM returns the EVMC interpreter of EVM1 code, set by InitEVMCEVM.
+func newEVMCEVM(env *EVM) Interpreter {
+	return &EVMC{evmModule, env, evmc.CapabilityEVM1, false}
+}
+
+// newEVMCEwasm returns the EVMC interpreter of Ewasm code, set by InitEVMCEwasm.
+func newEVMCEwasm(env *EVM) Interpreter {
+	return &EVMC{ewasmModule, env, evmc.CapabilityEWASM, false}
 }
 
-func initEVMC(cap evmc.Capability, config string) *evmc.VM {
+func initEVMC(cap evmc.Capability, config string) (*evmc.VM, error) {
 	options := strings.Split(config, ",")
 	path := options[0]
 
 	if path == "" {
-		panic("EVMC VM path not provided, set --vm.(evm|ewasm)=/path/to/vm")
+		return nil, errors.New("EVMC VM path not provided, set --vm.(evm|ewasm)=/path/to/vm")
 	}
 
 	instance, err := evmc.Load(path)
 	if err != nil {
-		panic(err.Error())
+		return nil, err
 	}
 	log.Info("EVMC VM loaded", "name", instance.Name(), "version", instance.Version(), "path", path)
 
@@ -99,9 +125,10 @@ func initEVMC(cap evmc.Capability, config string) *evmc.VM {
 	}
 
 	if !instance.HasCapability(cap) {
-		panic(fmt.Errorf("the EVMC module %s does not have requested capability %d", path, cap))
+		instance.Destroy()
+		return nil, fmt.Errorf("the EVMC module %s does not have requested capability %d", path, cap)
 	}
-	return instance
+	return instance, nil
 }
 
 // hostContext implements evmc.HostContext interface.
@@ -0,0 +1,45 @@
+// Copyright 2025 The core-geth Authors
+// This file is part of the core-geth library.
+//
+// The core-geth library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The core-geth library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the core-geth library. If not, see <http://www.gnu.org/licenses/>.
+
+//go:build !cgo
+
+package vm
+
+import "errors"
+
+// errEVMCUnsupported is returned when an EVMC VM is configured in a build without
+// cgo, which is required to load the VM.
+var errEVMCUnsupported = errors.New("EVMC VMs are not supported by builds without cgo")
+
+// InitEVMCEVM is not supported without cgo.
+func InitEVMCEVM(config string) error {
+	return errEVMCUnsupported
+}
+
+// InitEVMCEwasm is not supported without cgo.
+func InitEVMCEwasm(config string) error {
+	return errEVMCUnsupported
+}
+
+// newEVMCEVM is unreachable without cgo, the EVMC VMs failing to initialize.
+func newEVMCEVM(env *EVM) Interpreter {
+	panic(errEVMCUnsupported)
+}
+
+// newEVMCEwasm is unreachable without cgo, the EVMC VMs failing to initialize.
+func newEVMCEwasm(env *EVM) Interpreter {
+	panic(errEVMCUnsupported)
+}
@@ -0,0 +1,359 @@
+// Copyright 2025 The core-geth Authors
+// This file is part of the core-geth library.
+//
+// The core-geth library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The core-geth library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the core-geth library. If not, see <http://www.gnu.org/licenses/>.
+
+package eth
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/consensus/misc/eip1559"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params/mutations"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/holiman/uint256"
+)
+
+// HaloMaxRangeBlocks is the maximum number of blocks aggregated by halo_getSupplyDelta.
+const HaloMaxRangeBlocks = 100000
+
+var errHaloUnsupportedEngine = errors.New("block rewards are only available for proof-of-work chains")
+
+// HaloAPI provides the tokenomics of blocks: the rewards issued and the
+// distribution of the base fees. Amounts are computed by the same functions
+// used by block finalization, so they match the state transitions.
+type HaloAPI struct {
+	eth *Ethereum
+}
+
+// NewHaloAPI creates a new HaloAPI instance.
+func NewHaloAPI(eth *Ethereum) *HaloAPI {
+	return &HaloAPI{eth: eth}
+}
+
+// HaloUncleReward is the reward of an uncle included by a block.
+type HaloUncleReward struct {
+	Hash   common.Hash    `json:"hash"`
+	Number hexutil.Uint64 `json:"number"`
+	Miner  common.Address `json:"miner"`
+	Reward *hexutil.Big   `json:"reward"`
+}
+
+// HaloFeeCredit is an amount of the base fees credited to a recipient.
+type HaloFeeCredit struct {
+	Recipient common.Address `json:"recipient"`
+	Amount    *hexutil.Big   `json:"amount"`
+}
+
+// HaloContractFeeShare is a per-contract fee sharing payout of a transaction.
+type HaloContractFeeShare struct {
+	TxHash    common.Hash    `json:"transactionHash"`
+	Contract  common.Address `json:"contract"`
+	Recipient common.Address `json:"recipient"`
+	Amount    *hexutil.Big   `json:"amount"`
+}
+
+// HaloBlockRewards is the result of halo_getBlockRewards.
+type HaloBlockRewards struct {
+	Number       hexutil.Uint64    `json:"number"`
+	Hash         common.Hash       `json:"hash"`
+	Miner        common.Address    `json:"miner"`
+	BlockReward  *hexutil.Big      `json:"blockReward"`  // Static block reward of the miner
+	NephewReward *hexutil.Big      `json:"nephewReward"` // Reward of the miner for including uncles
+	Uncles       []HaloUncleReward `json:"uncles"`
+	Issued       *hexutil.Big      `json:"issued"` // Sum of all rewards
+
+	BaseFees     *hexutil.Big    `json:"baseFees"`     // Base fees paid by the transactions
+	Burned       *hexutil.Big    `json:"burned"`       // Part of the base fees burned
+	MinerFees    *hexutil.Big    `json:"minerFees"`    // Part of the base fees credited to the miner
+	FeeShares    []HaloFeeCredit `json:"feeShares"`    // Parts of the base fees credited to the fee distribution recipients
+	PriorityFees *hexutil.Big    `json:"priorityFees"` // Tips paid to the miner
+
+	ContractFeeShares []HaloContractFeeShare `json:"contractFeeShares"` // Paid out of the contract fee share fund
+}
+
+// HaloSupplyDelta is the result of halo_getSupplyDelta.
+type HaloSupplyDelta struct {
+	FromBlock hexutil.Uint64 `json:"fromBlock"`
+	ToBlock   hexutil.Uint64 `json:"toBlock"`
+
+	BlockRewards  *hexutil.Big    `json:"blockRewards"`
+	NephewRewards *hexutil.Big    `json:"nephewRewards"`
+	UncleRewards  *hexutil.Big    `json:"uncleRewards"`
+	Issued        *hexutil.Big    `json:"issued"`
+	BaseFees      *hexutil.Big    `json:"baseFees"`
+	Burned        *hexutil.Big    `json:"burned"`
+	MinerFees     *hexutil.Big    `json:"minerFees"`
+	FeeShares     []HaloFeeCredit `json:"feeShares"`
+	Delta         *hexutil.Big    `json:"delta"` // Issued minus burned, may be negative
+}
+
+// haloBlockSupply is the change of supply of a single block.
+type haloBlockSupply struct {
+	blockReward  *uint256.Int
+	nephewReward *uint256.Int
+	uncleRewards []*uint256.Int
+	baseFees     *eip1559.HaloBaseFeeCredits
+}
+
+// issued returns the sum of all rewards of the block.
+func (s *haloBlockSupply) issued() *uint256.Int {
+	issued := new(uint256.Int).Add(s.blockReward, s.nephewReward)
+	for _, reward := range s.uncleRewards {
+		issued.Add(issued, reward)
+	}
+	return issued
+}
+
+// blockSupply computes the rewards and the base fee distribution of a block,
+// as applied by the ethash and lyra2 block finalization.
+func (api *HaloAPI) blockSupply(header *types.Header, uncles []*types.Header) (*haloBlockSupply, error) {
+	config := api.eth.blockchain.Config()
+	if engine := config.GetConsensusEngineType(); !engine.IsEthash() && !engine.IsLyra2() {
+		return nil, errHaloUnsupportedEngine
+	}
+	supply := &haloBlockSupply{
+		blockReward:  new(uint256.Int),
+		nephewReward: new(uint256.Int),
+		uncleRewards: make([]*uint256.Int, len(uncles)),
+	}
+	for i := range uncles {
+		supply.uncleRewards[i] = new(uint256.Int)
+	}
+	// Proof-of-stake blocks are not finalized by ethash
+	pos := header.Difficulty.Sign() == 0
+	if !pos && header.Number.Sign() > 0 {
+		// The nephew reward is the miner reward in excess of the static block reward
+		supply.blockReward, _ = mutations.GetRewards(config, header, nil)
+		minerReward, uncleRewards := mutations.GetRewards(config, header, uncles)
+		supply.nephewReward.Sub(minerReward, supply.blockReward)
+		supply.uncleRewards = uncleRewards
+	}
+	if header.BaseFee == nil {
+		return supply, nil
+	}
+	// The contract fee shares paid during the block are settled from the receipts
+	var receipts types.Receipts
+	if eip1559.IsHaloContractFeeSharingEnabled(config, header.Number) && header.GasUsed > 0 {
+		receipts = api.eth.blockchain.GetReceiptsByHash(header.Hash())
+		if receipts == nil {
+			return nil, fmt.Errorf("receipts of block #%d not found", header.Number)
+		}
+	}
+	credits, err := eip1559.HaloBlockBaseFeeCredits(config, header, receipts)
+	if err != nil {
+		return nil, err
+	}
+	supply.baseFees = credits
+	return supply, nil
+}
+
+// blockByNumberOrHash returns the requested block of the canonical chain.
+func (api *HaloAPI) blockByNumberOrHash(blockNrOrHash rpc.BlockNumberOrHash) (*types.Block, error) {
+	chain := api.eth.blockchain
+	if hash, ok := blockNrOrHash.Hash(); ok {
+		block := chain.GetBlockByHash(hash)
+		if block == nil {
+			return nil, fmt.Errorf("block %s not found", hash.Hex())
+		}
+		if blockNrOrHash.RequireCanonical && chain.GetCanonicalHash(block.NumberU64()) != hash {
+			return nil, fmt.Errorf("block %s is not canonical", hash.Hex())
+		}
+		return block, nil
+	}
+	number, _ := blockNrOrHash.Number()
+	var header *types.Header
+	switch number {
+	case rpc.LatestBlockNumber, rpc.PendingBlockNumber:
+		header = chain.CurrentBlock()
+	case rpc.FinalizedBlockNumber:
+		header = chain.CurrentFinalBlock()
+	case rpc.SafeBlockNumber:
+		header = chain.CurrentSafeBlock()
+	default:
+		header = chain.GetHeaderByNumber(uint64(number))
+	}
+	if header == nil {
+		return nil, fmt.Errorf("block #%d not found", number)
+	}
+	block := chain.GetBlock(header.Hash(), header.Number.Uint64())
+	if block == nil {
+		return nil, fmt.Errorf("block #%d not found", number)
+	}
+	return block, nil
+}
+
+// GetBlockRewards returns the rewards issued by the given block, and the
+// distribution of its base fees and fee sharing payouts.
+func (api *HaloAPI) GetBlockRewards(blockNrOrHash rpc.BlockNumberOrHash) (*HaloBlockRewards, error) {
+	block, err := api.blockByNumberOrHash(blockNrOrHash)
+	if err != nil {
+		return nil, err
+	}
+	header := block.Header()
+	supply, err := api.blockSupply(header, block.Uncles())
+	if err != nil {
+		return nil, err
+	}
+	result := &HaloBlockRewards{
+		Number:            hexutil.Uint64(block.NumberU64()),
+		Hash:              block.Hash(),
+		Miner:             block.Coinbase(),
+		BlockReward:       (*hexutil.Big)(supply.blockReward.ToBig()),
+		NephewReward:      (*hexutil.Big)(supply.nephewReward.ToBig()),
+		Uncles:            make([]HaloUncleReward, len(block.Uncles())),
+		Issued:            (*hexutil.Big)(supply.issued().ToBig()),
+		BaseFees:          new(hexutil.Big),
+		Burned:            new(hexutil.Big),
+		MinerFees:         new(hexutil.Big),
+		FeeShares:         []HaloFeeCredit{},
+		PriorityFees:      new(hexutil.Big),
+		ContractFeeShares: []HaloContractFeeShare{},
+	}
+	for i, uncle := range block.Uncles() {
+		result.Uncles[i] = HaloUncleReward{
+			Hash:   uncle.Hash(),
+			Number: hexutil.Uint64(uncle.Number.Uint64()),
+			Miner:  uncle.Coinbase,
+			Reward: (*hexutil.Big)(supply.uncleRewards[i].ToBig()),
+		}
+	}
+	if credits := supply.baseFees; credits != nil {
+		result.BaseFees = (*hexutil.Big)(credits.Total.ToBig())
+		result.Burned = (*hexutil.Big)(credits.Burned.ToBig())
+		result.MinerFees = (*hexutil.Big)(credits.Miner.ToBig())
+		for _, share := range credits.Shares {
+			result.FeeShares = append(result.FeeShares, HaloFeeCredit{Recipient: share.Recipient, Amount: (*hexutil.Big)(share.Amount.ToBig())})
+		}
+	}
+	if len(block.Transactions()) == 0 {
+		return result, nil
+	}
+	receipts := api.eth.blockchain.GetReceiptsByHash(block.Hash())
+	if len(receipts) != len(block.Transactions()) {
+		return nil, fmt.Errorf("receipts of block #%d not found", block.NumberU64())
+	}
+	tips := new(big.Int)
+	for _, receipt := range receipts {
+		if header.BaseFee != nil && receipt.EffectiveGasPrice != nil {
+			tip := new(big.Int).Sub(receipt.EffectiveGasPrice, header.BaseFee)
+			tips.Add(tips, tip.Mul(tip, new(big.Int).SetUint64(receipt.GasUsed)))
+		}
+		for _, log := range receipt.Logs {
+			if log.Address != eip1559.HaloFeeShareLogAddress || len(log.Topics) != 3 || log.Topics[0] != eip1559.HaloFeeSharePaidTopic {
+				continue
+			}
+			result.ContractFeeShares = append(result.ContractFeeShares, HaloContractFeeShare{
+				TxHash:    receipt.TxHash,
+				Contract:  common.BytesToAddress(log.Topics[1].Bytes()),
+				Recipient: common.BytesToAddress(log.Topics[2].Bytes()),
+				Amount:    (*hexutil.Big)(new(big.Int).SetBytes(log.Data)),
+			})
+		}
+	}
+	if header.BaseFee != nil {
+		result.PriorityFees = (*hexutil.Big)(tips)
+	}
+	return result, nil
+}
+
+// GetSupplyDelta returns the rewards issued and the base fees burned by the
+// canonical blocks from fromBlock to toBlock, inclusive.
+func (api *HaloAPI) GetSupplyDelta(fromBlock, toBlock hexutil.Uint64) (*HaloSupplyDelta, error) {
+	if fromBlock > toBlock {
+		return nil, fmt.Errorf("invalid range: from block %d is after to block %d", fromBlock, toBlock)
+	}
+	if toBlock-fromBlock >= HaloMaxRangeBlocks {
+		return nil, fmt.Errorf("range of %d blocks exceeds the limit of %d", toBlock-fromBlock+1, HaloMaxRangeBlocks)
+	}
+	var (
+		blockRewards  = new(uint256.Int)
+		nephewRewards = new(uint256.Int)
+		uncleRewards  = new(uint256.Int)
+		issued        = new(uint256.Int)
+		baseFees      = new(uint256.Int)
+		burned        = new(uint256.Int)
+		minerFees     = new(uint256.Int)
+		feeShares     = make(map[common.Address]*uint256.Int)
+		recipients    []common.Address
+	)
+	for number := uint64(fromBlock); number <= uint64(toBlock); number++ {
+		block := api.eth.blockchain.GetBlockByNumber(number)
+		if block == nil {
+			return nil, fmt.Errorf("block #%d not found", number)
+		}
+		supply, err := api.blockSupply(block.Header(), block.Uncles())
+		if err != nil {
+			return nil, err
+		}
+		blockRewards.Add(blockRewards, supply.blockReward)
+		nephewRewards.Add(nephewRewards, supply.nephewReward)
+		for _, reward := range supply.uncleRewards {
+			uncleRewards.Add(uncleRewards, reward)
+		}
+		issued.Add(issued, supply.issued())
+		if credits := supply.baseFees; credits != nil {
+			baseFees.Add(baseFees, credits.Total)
+			burned.Add(burned, credits.Burned)
+			minerFees.Add(minerFees, credits.Miner)
+			for _, share := range credits.Shares {
+				if feeShares[share.Recipient] == nil {
+					feeShares[share.Recipient] = new(uint256.Int)
+					recipients = append(recipients, share.Recipient)
+				}
+				feeShares[share.Recipient].Add(feeShares[share.Recipient], share.Amount)
+			}
+		}
+	}
+	result := &HaloSupplyDelta{
+		FromBlock:     fromBlock,
+		ToBlock:       toBlock,
+		BlockRewards:  (*hexutil.Big)(blockRewards.ToBig()),
+		NephewRewards: (*hexutil.Big)(nephewRewards.ToBig()),
+		UncleRewards:  (*hexutil.Big)(uncleRewards.ToBig()),
+		Issued:        (*hexutil.Big)(issued.ToBig()),
+		BaseFees:      (*hexutil.Big)(baseFees.ToBig()),
+		Burned:        (*hexutil.Big)(burned.ToBig()),
+		MinerFees:     (*hexutil.Big)(minerFees.ToBig()),
+		FeeShares:     make([]HaloFeeCredit, len(recipients)),
+		Delta:         (*hexutil.Big)(new(big.Int).Sub(issued.ToBig(), burned.ToBig())),
+	}
+	for i, recipient := range recipients {
+		result.FeeShares[i] = HaloFeeCredit{Recipient: recipient, Amount: (*hexutil.Big)(feeShares[recipient].ToBig())}
+	}
+	return result, nil
+}
+
+// GetFundBalances returns the balances of the fee distribution recipients in
+// effect at the given block.
+func (api *HaloAPI) GetFundBalances(blockNrOrHash rpc.BlockNumberOrHash) (map[common.Address]*hexutil.Big, error) {
+	block, err := api.blockByNumberOrHash(blockNrOrHash)
+	if err != nil {
+		return nil, err
+	}
+	statedb, err := api.eth.blockchain.StateAt(block.Root())
+	if err != nil {
+		return nil, err
+	}
+	balances := make(map[common.Address]*hexutil.Big)
+	for _, share := range eip1559.HaloFeeDistribution(api.eth.blockchain.Config(), block.Number()).Shares {
+		balances[share.Recipient] = (*hexutil.Big)(statedb.GetBalance(share.Recipient).ToBig())
+	}
+	return balances, nil
+}
@@ -0,0 +1,233 @@
+// Copyright 2025 The core-geth Authors
+// This file is part of the core-geth library.
+//
+// The core-geth library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The core-geth library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the core-geth library. If not, see <http://www.gnu.org/licenses/>.
+
+package eth
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/params/types/genesisT"
+	"github.com/ethereum/go-ethereum/params/vars"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// TestHaloBlockRewards checks that the rewards and base fee distribution
+// reported by the halo API account for the change of supply of the chain.
+func TestHaloBlockRewards(t *testing.T) {
+	var (
+		key, _     = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		sender     = crypto.PubkeyToAddress(key.PublicKey)
+		miner      = common.HexToAddress("0xaaaa")
+		uncleMiner = common.HexToAddress("0xbbbb")
+		to         = common.HexToAddress("0xcccc")
+		config     = params.HaloChainConfig
+		signer     = types.LatestSigner(config)
+		gspec      = &genesisT.Genesis{
+			Config:   config,
+			GasLimit: 8_000_000,
+			Alloc:    genesisT.GenesisAlloc{sender: {Balance: big.NewInt(vars.Ether)}},
+		}
+	)
+	engine := ethash.NewFaker()
+	_, blocks, _ := core.GenerateChainWithGenesis(gspec, engine, 4, func(i int, b *core.BlockGen) {
+		b.SetCoinbase(miner)
+		tx, _ := types.SignTx(types.NewTx(&types.DynamicFeeTx{
+			ChainID:   config.GetChainID(),
+			Nonce:     uint64(i),
+			GasTipCap: big.NewInt(vars.GWei),
+			GasFeeCap: big.NewInt(10 * vars.GWei),
+			Gas:       21_000,
+			To:        &to,
+			Value:     big.NewInt(1),
+		}), signer, key)
+		b.AddTx(tx)
+		if i == 2 {
+			b.AddUncle(&types.Header{
+				ParentHash: b.PrevBlock(0).Hash(),
+				Number:     big.NewInt(2),
+				Coinbase:   uncleMiner,
+			})
+		}
+	})
+	chain, _ := core.NewBlockChain(rawdb.NewMemoryDatabase(), nil, gspec, nil, engine, vm.Config{}, nil, nil)
+	defer chain.Stop()
+	if _, err := chain.InsertChain(blocks); err != nil {
+		t.Fatalf("failed to insert chain: %v", err)
+	}
+	api := NewHaloAPI(&Ethereum{blockchain: chain})
+
+	rewards, err := api.GetBlockRewards(rpc.BlockNumberOrHashWithNumber(3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rewards.Hash != blocks[2].Hash() || rewards.Miner != miner {
+		t.Fatalf("wrong block: have %v, want %v", rewards.Hash, blocks[2].Hash())
+	}
+	if len(rewards.Uncles) != 1 || rewards.Uncles[0].Miner != uncleMiner || rewards.Uncles[0].Reward.ToInt().Sign() <= 0 {
+		t.Fatalf("wrong uncle rewards: %+v", rewards.Uncles)
+	}
+	if rewards.NephewReward.ToInt().Sign() <= 0 {
+		t.Errorf("missing nephew reward")
+	}
+	issued := new(big.Int).Add(rewards.BlockReward.ToInt(), rewards.NephewReward.ToInt())
+	issued.Add(issued, rewards.Uncles[0].Reward.ToInt())
+	if issued.Cmp(rewards.Issued.ToInt()) != 0 {
+		t.Errorf("issued mismatch: have %v, want %v", rewards.Issued, issued)
+	}
+	baseFees := new(big.Int).Mul(blocks[2].BaseFee(), new(big.Int).SetUint64(blocks[2].GasUsed()))
+	if rewards.BaseFees.ToInt().Cmp(baseFees) != 0 {
+		t.Errorf("base fees mismatch: have %v, want %v", rewards.BaseFees, baseFees)
+	}
+	distributed := new(big.Int).Add(rewards.Burned.ToInt(), rewards.MinerFees.ToInt())
+	for _, share := range rewards.FeeShares {
+		distributed.Add(distributed, share.Amount.ToInt())
+	}
+	if distributed.Cmp(baseFees) != 0 {
+		t.Errorf("distributed base fees mismatch: have %v, want %v", distributed, baseFees)
+	}
+	if want := new(big.Int).Mul(big.NewInt(vars.GWei), big.NewInt(21_000)); rewards.PriorityFees.ToInt().Cmp(want) != 0 {
+		t.Errorf("priority fees mismatch: have %v, want %v", rewards.PriorityFees, want)
+	}
+
+	// The supply delta of the whole chain must match the change of the balances
+	delta, err := api.GetSupplyDelta(1, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	statedb, _ := chain.State()
+	supply := new(big.Int)
+	for _, addr := range []common.Address{sender, miner, uncleMiner, to, params.HaloEcosystemFundAddress, params.HaloReserveFundAddress} {
+		supply.Add(supply, statedb.GetBalance(addr).ToBig())
+	}
+	if want := new(big.Int).Sub(supply, big.NewInt(vars.Ether)); delta.Delta.ToInt().Cmp(want) != 0 {
+		t.Errorf("supply delta mismatch: have %v, want %v", delta.Delta, want)
+	}
+	balances, err := api.GetFundBalances(rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, share := range delta.FeeShares {
+		if balances[share.Recipient].ToInt().Cmp(share.Amount.ToInt()) != 0 {
+			t.Errorf("fund %v balance mismatch: have %v, want %v", share.Recipient, balances[share.Recipient], share.Amount)
+		}
+	}
+	if _, err := api.GetSupplyDelta(4, 1); err == nil {
+		t.Error("expected error for inverted range")
+	}
+	if _, err := api.GetSupplyDelta(1, hexutil.Uint64(HaloMaxRangeBlocks+1)); err == nil {
+		t.Error("expected error for oversized range")
+	}
+}
+
+// TestHaloBlockRewardsContractFeeShare checks that the contract fee shares paid
+// during a block are deducted from the fund credit reported by the halo API.
+func TestHaloBlockRewardsContractFeeShare(t *testing.T) {
+	var (
+		key, _    = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		sender    = crypto.PubkeyToAddress(key.PublicKey)
+		miner     = common.HexToAddress("0xaaaa")
+		contract  = common.HexToAddress("0xc0de")
+		recipient = common.HexToAddress("0xbeef")
+		config    = *params.HaloChainConfig
+		signer    = types.LatestSigner(&config)
+	)
+	config.HaloContractFeeSharingFBlock = big.NewInt(0)
+	gspec := &genesisT.Genesis{
+		Config:   &config,
+		GasLimit: 8_000_000,
+		Alloc: genesisT.GenesisAlloc{
+			sender: {Balance: big.NewInt(vars.Ether)},
+			// Contract opted in to receive 50% of the fund share of its base fees
+			contract: {
+				Code: []byte{0x00},
+				Storage: map[common.Hash]common.Hash{
+					common.HexToHash("0x360894a13ba1a3210667c828492db98dca3e2076cc3735a920a3ca505d382bbc"): common.BigToHash(common.Big1),
+					common.HexToHash("0x360894a13ba1a3210667c828492db98dca3e2076cc3735a920a3ca505d382bbd"): common.BytesToHash(recipient.Bytes()),
+					common.HexToHash("0x360894a13ba1a3210667c828492db98dca3e2076cc3735a920a3ca505d382bbe"): common.BigToHash(big.NewInt(50)),
+				},
+			},
+		},
+	}
+	engine := ethash.NewFaker()
+	_, blocks, _ := core.GenerateChainWithGenesis(gspec, engine, 1, func(i int, b *core.BlockGen) {
+		b.SetCoinbase(miner)
+		tx, _ := types.SignTx(types.NewTx(&types.DynamicFeeTx{
+			ChainID:   config.GetChainID(),
+			GasTipCap: big.NewInt(vars.GWei),
+			GasFeeCap: big.NewInt(10 * vars.GWei),
+			Gas:       50_000,
+			To:        &contract,
+		}), signer, key)
+		b.AddTx(tx)
+	})
+	chain, _ := core.NewBlockChain(rawdb.NewMemoryDatabase(), nil, gspec, nil, engine, vm.Config{}, nil, nil)
+	defer chain.Stop()
+	if _, err := chain.InsertChain(blocks); err != nil {
+		t.Fatalf("failed to insert chain: %v", err)
+	}
+	api := NewHaloAPI(&Ethereum{blockchain: chain})
+
+	rewards, err := api.GetBlockRewards(rpc.BlockNumberOrHashWithNumber(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rewards.ContractFeeShares) != 1 || rewards.ContractFeeShares[0].Recipient != recipient {
+		t.Fatalf("wrong contract fee shares: %+v", rewards.ContractFeeShares)
+	}
+	paid := rewards.ContractFeeShares[0].Amount.ToInt()
+	if paid.Sign() <= 0 {
+		t.Fatalf("no contract fee share paid")
+	}
+	// The fund is credited its share of the base fees less the contract fee share
+	baseFees := new(big.Int).Mul(blocks[0].BaseFee(), new(big.Int).SetUint64(blocks[0].GasUsed()))
+	want := new(big.Int).Div(new(big.Int).Mul(baseFees, big.NewInt(200)), big.NewInt(1000))
+	want.Sub(want, paid)
+	var found bool
+	for _, share := range rewards.FeeShares {
+		if share.Recipient == params.HaloEcosystemFundAddress {
+			found = true
+			if share.Amount.ToInt().Cmp(want) != 0 {
+				t.Errorf("fund credit mismatch: have %v, want %v", share.Amount, want)
+			}
+		}
+	}
+	if !found {
+		t.Fatalf("missing fund credit: %+v", rewards.FeeShares)
+	}
+	// The supply delta must match the change of the balances
+	delta, err := api.GetSupplyDelta(1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	statedb, _ := chain.State()
+	supply := new(big.Int)
+	for _, addr := range []common.Address{sender, miner, recipient, params.HaloEcosystemFundAddress, params.HaloReserveFundAddress} {
+		supply.Add(supply, statedb.GetBalance(addr).ToBig())
+	}
+	if want := new(big.Int).Sub(supply, big.NewInt(vars.Ether)); delta.Delta.ToInt().Cmp(want) != 0 {
+		t.Errorf("supply delta mismatch: have %v, want %v", delta.Delta, want)
+	}
+}
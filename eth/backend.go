@@ -31,6 +31,7 @@ import (
 	"github.com/ethereum/go-ethereum/consensus/beacon"
 	"github.com/ethereum/go-ethereum/consensus/clique"
 	"github.com/ethereum/go-ethereum/consensus/lyra2"
+	"github.com/ethereum/go-ethereum/consensus/stratum"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/bloombits"
 	"github.com/ethereum/go-ethereum/core/rawdb"
@@ -73,7 +74,8 @@ type Ethereum struct {
 	config *ethconfig.Config
 
 	// Handlers
-	txPool *txpool.TxPool
+	txPool    *txpool.TxPool
+	txHistory *txpool.History // Lifecycle history of pooled transactions, nil if disabled
 
 	blockchain         *core.BlockChain
 	handler            *handler
@@ -90,6 +92,7 @@ type Ethereum struct {
 
 	bloomRequests     chan chan *bloombits.Retrieval // Channel receiving bloom data retrieval requests
 	bloomIndexer      *core.ChainIndexer             // Bloom indexer operating during block imports
+	logIndexer        *core.ChainIndexer             // Log indexer operating during block imports, nil if disabled
 	closeBloomHandler chan struct{}
 
 	APIBackend *EthAPIBackend
@@ -118,6 +121,9 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 	if !config.SyncMode.IsValid() {
 		return nil, fmt.Errorf("invalid sync mode %d", config.SyncMode)
 	}
+	if config.DatabaseFreezerRemoteReadOnly && config.SyncMode != downloader.FullSync {
+		return nil, errors.New("a read-only remote ancient store requires full sync, snap sync writes the ancients")
+	}
 	if config.Miner.GasPrice == nil || config.Miner.GasPrice.Cmp(common.Big0) <= 0 {
 		log.Warn("Sanitizing invalid miner gas price", "provided", config.Miner.GasPrice, "updated", ethconfig.Defaults.Miner.GasPrice)
 		config.Miner.GasPrice = new(big.Int).Set(ethconfig.Defaults.Miner.GasPrice)
@@ -134,7 +140,16 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 	log.Info("Allocated trie memory caches", "clean", common.StorageSize(config.TrieCleanCache)*1024*1024, "dirty", common.StorageSize(config.TrieDirtyCache)*1024*1024)
 
 	// Assemble the Ethereum object
-	chainDb, err := stack.OpenDatabaseWithFreezer("chaindata", config.DatabaseCache, config.DatabaseHandles, config.DatabaseFreezer, "eth/db/chaindata/", false)
+	var (
+		chainDb ethdb.Database
+		err     error
+	)
+	if config.DatabaseFreezerRemote != "" {
+		log.Info("Using remote ancient store", "endpoint", config.DatabaseFreezerRemote)
+		chainDb, err = stack.OpenDatabaseWithRemoteFreezer("chaindata", config.DatabaseCache, config.DatabaseHandles, config.DatabaseFreezer, config.DatabaseFreezerRemote, config.DatabaseFreezerRemoteReadOnly, "eth/db/chaindata/", false)
+	} else {
+		chainDb, err = stack.OpenDatabaseWithFreezer("chaindata", config.DatabaseCache, config.DatabaseHandles, config.DatabaseFreezer, "eth/db/chaindata/", false)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -151,6 +166,12 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 	// Transfer mining-related config to the ethash config.
 	ethashConfig := config.Ethash
 	ethashConfig.NotifyFull = config.Miner.NotifyFull
+	ethashConfig.Stratum = stratum.Config{
+		Addr:          config.Miner.Stratum,
+		Difficulty:    config.Miner.StratumDifficulty,
+		MinDifficulty: config.Miner.StratumMinDifficulty,
+		Password:      config.Miner.StratumPassword,
+	}
 
 	if config.Genesis != nil && config.Genesis.Config != nil {
 		ethashConfig.ECIP1099Block = config.Genesis.GetEthashECIP1099Transition()
@@ -164,7 +185,7 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 	var lyra2Config *lyra2.Config
 	if config.Genesis != nil && config.Genesis.Config != nil {
 		if config.Genesis.Config.GetConsensusEngineType() == ctypes.ConsensusEngineT_Lyra2 {
-			lyra2Config = &lyra2.Config{}
+			lyra2Config = &lyra2.Config{Stratum: ethashConfig.Stratum}
 		}
 	}
 
@@ -242,6 +263,10 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 		return nil, err
 	}
 	eth.bloomIndexer.Start(eth.blockchain)
+	if config.LogIndex {
+		eth.logIndexer = core.NewLogIndexer(chainDb, vars.LogIndexBlocks, vars.LogIndexConfirms)
+		eth.logIndexer.Start(eth.blockchain)
+	}
 	// Handle artificial finality config override cases.
 	if n := config.OverrideECBP1100; n != nil {
 		if err := eth.blockchain.Config().SetECBP1100Transition(n); err != nil {
@@ -269,6 +294,11 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 		config.TxPool.Journal = stack.ResolvePath(config.TxPool.Journal)
 	}
 	legacyPool := legacypool.New(config.TxPool, eth.blockchain)
+	if config.TxPool.History > 0 {
+		eth.txHistory = txpool.NewHistory(chainDb, config.TxPool.History)
+		legacyPool.SetHistory(eth.txHistory)
+		blobPool.SetHistory(eth.txHistory)
+	}
 
 	eth.txPool, err = txpool.New(config.TxPool.PriceLimit, eth.blockchain, []txpool.SubPool{legacyPool, blobPool})
 	if err != nil {
@@ -379,6 +409,9 @@ func (s *Ethereum) APIs() []rpc.API {
 		}, {
 			Namespace: "debug",
 			Service:   NewDebugAPI(s),
+		}, {
+			Namespace: "halo",
+			Service:   NewHaloAPI(s),
 		}, {
 			Namespace: "net",
 			Service:   s.netRPCService,
@@ -551,6 +584,7 @@ func (s *Ethereum) Synced() bool                       { return s.handler.synced
 func (s *Ethereum) SetSynced()                         { s.handler.enableSyncedFeatures() }
 func (s *Ethereum) ArchiveMode() bool                  { return s.config.NoPruning }
 func (s *Ethereum) BloomIndexer() *core.ChainIndexer   { return s.bloomIndexer }
+func (s *Ethereum) LogIndexer() *core.ChainIndexer     { return s.logIndexer }
 func (s *Ethereum) Merger() *consensus.Merger          { return s.merger }
 func (s *Ethereum) SyncMode() downloader.SyncMode {
 	mode, _ := s.handler.chainSync.modeAndLocalHead()
@@ -601,8 +635,14 @@ func (s *Ethereum) Stop() error {
 
 	// Then stop everything else.
 	s.bloomIndexer.Close()
+	if s.logIndexer != nil {
+		s.logIndexer.Close()
+	}
 	close(s.closeBloomHandler)
 	s.txPool.Close()
+	if s.txHistory != nil {
+		s.txHistory.Close()
+	}
 	s.miner.Close()
 	s.blockchain.Stop()
 	s.engine.Close()
@@ -18,6 +18,7 @@
 package downloader
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"math/big"
@@ -33,9 +34,12 @@ import (
 	"github.com/ethereum/go-ethereum/eth/protocols/snap"
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/internal/telemetry"
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/p2p"
 	"github.com/ethereum/go-ethereum/params/vars"
 	"github.com/ethereum/go-ethereum/triedb"
+	"go.opentelemetry.io/otel/trace"
 )
 
 var (
@@ -89,6 +93,10 @@ var (
 // peerDropFn is a callback type for dropping a peer detected as malicious.
 type peerDropFn func(id string)
 
+// peerPenalizeFn is a callback type for lowering the reputation of a peer for
+// a misbehaviour.
+type peerPenalizeFn func(id string, event p2p.ScoreEvent)
+
 // badBlockFn is a callback for the async beacon sync to notify the caller that
 // the origin header requested to sync to, produced a chain with a bad block.
 type badBlockFn func(invalid *types.Header, origin *types.Header)
@@ -120,8 +128,9 @@ type Downloader struct {
 	blockchain BlockChain
 
 	// Callbacks
-	dropPeer peerDropFn // Drops a peer for misbehaving
-	badBlock badBlockFn // Reports a block as rejected by the chain
+	dropPeer     peerDropFn     // Drops a peer for misbehaving
+	penalizePeer peerPenalizeFn // Lowers the reputation of a misbehaving peer
+	badBlock     badBlockFn     // Reports a block as rejected by the chain
 
 	// Status
 	synchroniseMock func(id string, hash common.Hash) error // Replacement for synchronise during testing
@@ -226,7 +235,7 @@ type BlockChain interface {
 }
 
 // New creates a new downloader to fetch hashes and blocks from remote peers.
-func New(checkpoint uint64, stateDb ethdb.Database, mux *event.TypeMux, chain BlockChain, lightchain LightChain, dropPeer peerDropFn, success func()) *Downloader {
+func New(checkpoint uint64, stateDb ethdb.Database, mux *event.TypeMux, chain BlockChain, lightchain LightChain, dropPeer peerDropFn, penalizePeer peerPenalizeFn, success func()) *Downloader {
 	if lightchain == nil {
 		lightchain = chain
 	}
@@ -239,6 +248,7 @@ func New(checkpoint uint64, stateDb ethdb.Database, mux *event.TypeMux, chain Bl
 		blockchain:     chain,
 		lightchain:     lightchain,
 		dropPeer:       dropPeer,
+		penalizePeer:   penalizePeer,
 		headerProcCh:   make(chan *headerTask, 1),
 		totalDiffCh:    make(chan struct{}),
 		quitCh:         make(chan struct{}),
@@ -338,6 +348,14 @@ func (d *Downloader) UnregisterPeer(id string) error {
 	return nil
 }
 
+// penalize lowers the reputation of a misbehaving peer, if reputations are
+// tracked.
+func (d *Downloader) penalize(id string, event p2p.ScoreEvent) {
+	if d.penalizePeer != nil {
+		d.penalizePeer(id, event)
+	}
+}
+
 // LegacySync tries to sync up our local block chain with a remote peer, both
 // adding various sanity checks as well as wrapping it with various log entries.
 func (d *Downloader) LegacySync(id string, head common.Hash, td, ttd *big.Int, mode SyncMode) error {
@@ -351,6 +369,14 @@ func (d *Downloader) LegacySync(id string, head common.Hash, td, ttd *big.Int, m
 		errors.Is(err, errStallingPeer) || errors.Is(err, errUnsyncedPeer) || errors.Is(err, errEmptyHeaderSet) ||
 		errors.Is(err, errPeersUnavailable) || errors.Is(err, errTooOld) || errors.Is(err, errInvalidAncestor) {
 		log.Warn("Synchronisation failed, dropping peer", "peer", id, "err", err)
+		switch {
+		case errors.Is(err, errTimeout) || errors.Is(err, errStallingPeer):
+			d.penalize(id, p2p.ScoreTimeout)
+		case errors.Is(err, errInvalidChain) || errors.Is(err, errInvalidAncestor):
+			d.penalize(id, p2p.ScoreInvalidBlock)
+		case errors.Is(err, errBadPeer) || errors.Is(err, errEmptyHeaderSet):
+			d.penalize(id, p2p.ScoreInvalidMessage)
+		}
 		if d.dropPeer == nil {
 			// The dropPeer method is nil when `--copydb` is used for a local copy.
 			// Timeouts can occur if e.g. compaction hits at the wrong time, and can be ignored
@@ -483,8 +509,12 @@ func (d *Downloader) syncWithPeer(p *peerConnection, hash common.Hash, td, ttd *
 	}()
 	mode := d.getMode()
 
+	ctx, endSync := telemetry.StartSpan(context.Background(), "downloader.sync", telemetry.SyncMode(mode.String()))
+	defer func() { endSync(traceError(&err)) }()
+
 	if !beaconMode {
 		log.Debug("Synchronising with the network", "peer", p.id, "eth", p.version, "head", hash, "td", td, "mode", mode)
+		trace.SpanFromContext(ctx).SetAttributes(telemetry.PeerID(p.id))
 	} else {
 		log.Debug("Backfilling with the network", "mode", mode)
 	}
@@ -496,7 +526,9 @@ func (d *Downloader) syncWithPeer(p *peerConnection, hash common.Hash, td, ttd *
 	var latest, pivot, final *types.Header
 	if !beaconMode {
 		// In legacy mode, use the master peer to retrieve the headers from
+		_, endFetch := telemetry.StartSpan(ctx, "downloader.fetchHead")
 		latest, pivot, err = d.fetchHead(p)
+		endFetch(traceError(&err))
 		if err != nil {
 			return err
 		}
@@ -543,7 +575,9 @@ func (d *Downloader) syncWithPeer(p *peerConnection, hash common.Hash, td, ttd *
 	var origin uint64
 	if !beaconMode {
 		// In legacy mode, reach out to the network and find the ancestor
+		_, endFind := telemetry.StartSpan(ctx, "downloader.findAncestor")
 		origin, err = d.findAncestor(p, latest)
+		endFind(traceError(&err))
 		if err != nil {
 			return err
 		}
@@ -642,31 +676,51 @@ func (d *Downloader) syncWithPeer(p *peerConnection, hash common.Hash, td, ttd *
 	var headerFetcher func() error
 	if !beaconMode {
 		// In legacy mode, headers are retrieved from the network
-		headerFetcher = func() error { return d.fetchHeaders(p, origin+1, latest.Number.Uint64()) }
+		headerFetcher = traceFetcher(ctx, "downloader.fetchHeaders", func() error { return d.fetchHeaders(p, origin+1, latest.Number.Uint64()) })
 	} else {
 		// In beacon mode, headers are served by the skeleton syncer
-		headerFetcher = func() error { return d.fetchBeaconHeaders(origin + 1) }
+		headerFetcher = traceFetcher(ctx, "downloader.fetchBeaconHeaders", func() error { return d.fetchBeaconHeaders(origin + 1) })
 	}
 	fetchers := []func() error{
 		headerFetcher, // Headers are always retrieved
-		func() error { return d.fetchBodies(origin+1, beaconMode) },   // Bodies are retrieved during normal and snap sync
-		func() error { return d.fetchReceipts(origin+1, beaconMode) }, // Receipts are retrieved during snap sync
-		func() error { return d.processHeaders(origin+1, td, ttd, beaconMode) },
+		traceFetcher(ctx, "downloader.fetchBodies", func() error { return d.fetchBodies(origin+1, beaconMode) }),     // Bodies are retrieved during normal and snap sync
+		traceFetcher(ctx, "downloader.fetchReceipts", func() error { return d.fetchReceipts(origin+1, beaconMode) }), // Receipts are retrieved during snap sync
+		traceFetcher(ctx, "downloader.processHeaders", func() error { return d.processHeaders(origin+1, td, ttd, beaconMode) }),
 	}
 	if mode == SnapSync {
 		d.pivotLock.Lock()
 		d.pivotHeader = pivot
 		d.pivotLock.Unlock()
 
-		fetchers = append(fetchers, func() error { return d.processSnapSyncContent() })
+		fetchers = append(fetchers, traceFetcher(ctx, "downloader.processSnapSyncContent", func() error { return d.processSnapSyncContent() }))
 	} else if mode == FullSync {
-		fetchers = append(fetchers, func() error { return d.processFullSyncContent(ttd, beaconMode) })
+		fetchers = append(fetchers, traceFetcher(ctx, "downloader.processFullSyncContent", func() error { return d.processFullSyncContent(ttd, beaconMode) }))
 	}
-	fetchers = append(fetchers, func() error { return d.fetchTotalDifficulty(p, latest) })
+	fetchers = append(fetchers, traceFetcher(ctx, "downloader.fetchTotalDifficulty", func() error { return d.fetchTotalDifficulty(p, latest) }))
 
 	return d.spawnSync(fetchers)
 }
 
+// traceFetcher wraps the fetcher function into a telemetry span, child of the
+// span of the sync.
+func traceFetcher(ctx context.Context, name string, fetcher func() error) func() error {
+	return func() error {
+		_, end := telemetry.StartSpan(ctx, name)
+		err := fetcher()
+		end(traceError(&err))
+		return err
+	}
+}
+
+// traceError returns the error to record on a span, omitting the cancellation
+// of the sync as it's not a failure.
+func traceError(err *error) *error {
+	if errors.Is(*err, errCanceled) {
+		return nil
+	}
+	return err
+}
+
 // spawnSync runs d.process and all given fetcher functions to completion in
 // separate goroutines, returning the first error that appears.
 func (d *Downloader) spawnSync(fetchers []func() error) error {
@@ -25,6 +25,7 @@ import (
 	"github.com/ethereum/go-ethereum/common/prque"
 	"github.com/ethereum/go-ethereum/eth/protocols/eth"
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/p2p"
 )
 
 // timeoutGracePeriod is the amount of time to allow for a peer to deliver a
@@ -115,6 +116,10 @@ func (d *Downloader) concurrentFetch(queue typedQueue, beaconMode bool) error {
 			req.Close()
 		}
 	}()
+	// Track the peers penalized for stalling, lowering their reputation only once
+	// even if they aren't disconnected right away.
+	stalled := make(map[string]struct{})
+
 	// Subscribe to peer lifecycle events to schedule tasks to new joiners and
 	// reschedule tasks upon disconnections. We don't care which event happened
 	// for simplicity, so just use a single channel.
@@ -152,6 +157,10 @@ func (d *Downloader) concurrentFetch(queue typedQueue, beaconMode bool) error {
 						// permitted it, consider the peer malicious attempting to
 						// stall the sync.
 						peer.log.Warn("Peer stalling, dropping", "waited", common.PrettyDuration(waited))
+						if _, ok := stalled[peer.id]; !ok {
+							stalled[peer.id] = struct{}{}
+							d.penalize(peer.id, p2p.ScoreTimeout)
+						}
 						d.dropPeer(peer.id)
 					}
 				}
@@ -316,7 +325,8 @@ func (d *Downloader) concurrentFetch(queue typedQueue, beaconMode bool) error {
 			} else {
 				d.dropPeer(peer.id)
 
-				// If this peer was the master peer, abort sync immediately
+				// If this peer was the master peer, abort sync immediately,
+				// the sync failure penalizing it
 				d.cancelLock.RLock()
 				master := peer.id == d.cancelPeer
 				d.cancelLock.RUnlock()
@@ -325,6 +335,10 @@ func (d *Downloader) concurrentFetch(queue typedQueue, beaconMode bool) error {
 					d.cancel()
 					return errTimeout
 				}
+				if _, ok := stalled[peer.id]; !ok {
+					stalled[peer.id] = struct{}{}
+					d.penalize(peer.id, p2p.ScoreTimeout)
+				}
 			}
 
 		case res := <-responses:
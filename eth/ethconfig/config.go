@@ -159,11 +159,12 @@ type Config struct {
 	UltraLightOnlyAnnounce bool     `toml:",omitempty"` // Whether to only announce headers, or also serve them
 
 	// Database options
-	SkipBcVersionCheck    bool `toml:"-"`
-	DatabaseHandles       int  `toml:"-"`
-	DatabaseCache         int
-	DatabaseFreezer       string
-	DatabaseFreezerRemote string
+	SkipBcVersionCheck            bool `toml:"-"`
+	DatabaseHandles               int  `toml:"-"`
+	DatabaseCache                 int
+	DatabaseFreezer               string
+	DatabaseFreezerRemote         string
+	DatabaseFreezerRemoteReadOnly bool
 
 	TrieCleanCache int
 	TrieDirtyCache int
@@ -174,6 +175,10 @@ type Config struct {
 	// This is the number of blocks for which logs will be cached in the filter system.
 	FilterLogCacheSize int
 
+	// Whether to maintain the secondary log index, answering wide range log
+	// queries without scanning the bloom bits.
+	LogIndex bool
+
 	// Mining options
 	Miner miner.Config
 
@@ -268,6 +273,7 @@ func CreateConsensusEngine(stack *node.Node, ethashConfig *ethash.Config, clique
 				DatasetsOnDisk:   ethashConfig.DatasetsOnDisk,
 				DatasetsLockMmap: ethashConfig.DatasetsLockMmap,
 				NotifyFull:       ethashConfig.NotifyFull,
+				Stratum:          ethashConfig.Stratum,
 				ECIP1099Block:    ethashConfig.ECIP1099Block,
 			}, notify, noverify)
 			engine.(*ethash.Ethash).SetThreads(-1) // Disable CPU mining
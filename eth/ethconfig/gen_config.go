@@ -19,60 +19,62 @@ import (
 // MarshalTOML marshals as TOML.
 func (c Config) MarshalTOML() (interface{}, error) {
 	type Config struct {
-		Genesis                    *genesisT.Genesis `toml:",omitempty"`
-		NetworkId                  uint64
-		ProtocolVersions           []uint
-		SyncMode                   downloader.SyncMode
-		EthDiscoveryURLs           []string
-		SnapDiscoveryURLs          []string
-		NoPruning                  bool
-		NoPrefetch                 bool
-		TxLookupLimit              uint64                 `toml:",omitempty"`
-		TransactionHistory         uint64                 `toml:",omitempty"`
-		StateHistory               uint64                 `toml:",omitempty"`
-		StateScheme                string                 `toml:",omitempty"`
-		RequiredBlocks             map[uint64]common.Hash `toml:"-"`
-		LightServ                  int                    `toml:",omitempty"`
-		LightIngress               int                    `toml:",omitempty"`
-		LightEgress                int                    `toml:",omitempty"`
-		LightPeers                 int                    `toml:",omitempty"`
-		LightNoPrune               bool                   `toml:",omitempty"`
-		LightNoSyncServe           bool                   `toml:",omitempty"`
-		SyncFromCheckpoint         bool                   `toml:",omitempty"`
-		UltraLightServers          []string               `toml:",omitempty"`
-		UltraLightFraction         int                    `toml:",omitempty"`
-		UltraLightOnlyAnnounce     bool                   `toml:",omitempty"`
-		SkipBcVersionCheck         bool                   `toml:"-"`
-		DatabaseHandles            int                    `toml:"-"`
-		DatabaseCache              int
-		DatabaseFreezer            string
-		DatabaseFreezerRemote      string
-		TrieCleanCache             int
-		TrieDirtyCache             int
-		TrieTimeout                time.Duration
-		SnapshotCache              int
-		Preimages                  bool
-		FilterLogCacheSize         int
-		Miner                      miner.Config
-		Ethash                     ethash.Config
-		TxPool                     legacypool.Config
-		BlobPool                   blobpool.Config
-		GPO                        gasprice.Config
-		EnablePreimageRecording    bool
-		DocRoot                    string `toml:"-"`
-		EWASMInterpreter           string
-		EVMInterpreter             string
-		RPCGasCap                  uint64
-		RPCEVMTimeout              time.Duration
-		RPCTxFeeCap                float64
-		Checkpoint                 *ctypes.TrustedCheckpoint      `toml:",omitempty"`
-		CheckpointOracle           *ctypes.CheckpointOracleConfig `toml:",omitempty"`
-		OverrideECBP1100           *uint64                        `toml:",omitempty"`
-		OverrideECBP1100Deactivate *uint64                        `toml:",omitempty"`
-		ECBP1100NoDisable          *bool                          `toml:",omitempty"`
-		OverrideShanghai           *uint64                        `toml:",omitempty"`
-		OverrideCancun             *uint64                        `toml:",omitempty"`
-		OverrideVerkle             *uint64                        `toml:",omitempty"`
+		Genesis                       *genesisT.Genesis `toml:",omitempty"`
+		NetworkId                     uint64
+		ProtocolVersions              []uint
+		SyncMode                      downloader.SyncMode
+		EthDiscoveryURLs              []string
+		SnapDiscoveryURLs             []string
+		NoPruning                     bool
+		NoPrefetch                    bool
+		TxLookupLimit                 uint64                 `toml:",omitempty"`
+		TransactionHistory            uint64                 `toml:",omitempty"`
+		StateHistory                  uint64                 `toml:",omitempty"`
+		StateScheme                   string                 `toml:",omitempty"`
+		RequiredBlocks                map[uint64]common.Hash `toml:"-"`
+		LightServ                     int                    `toml:",omitempty"`
+		LightIngress                  int                    `toml:",omitempty"`
+		LightEgress                   int                    `toml:",omitempty"`
+		LightPeers                    int                    `toml:",omitempty"`
+		LightNoPrune                  bool                   `toml:",omitempty"`
+		LightNoSyncServe              bool                   `toml:",omitempty"`
+		SyncFromCheckpoint            bool                   `toml:",omitempty"`
+		UltraLightServers             []string               `toml:",omitempty"`
+		UltraLightFraction            int                    `toml:",omitempty"`
+		UltraLightOnlyAnnounce        bool                   `toml:",omitempty"`
+		SkipBcVersionCheck            bool                   `toml:"-"`
+		DatabaseHandles               int                    `toml:"-"`
+		DatabaseCache                 int
+		DatabaseFreezer               string
+		DatabaseFreezerRemote         string
+		DatabaseFreezerRemoteReadOnly bool
+		TrieCleanCache                int
+		TrieDirtyCache                int
+		TrieTimeout                   time.Duration
+		SnapshotCache                 int
+		Preimages                     bool
+		FilterLogCacheSize            int
+		LogIndex                      bool
+		Miner                         miner.Config
+		Ethash                        ethash.Config
+		TxPool                        legacypool.Config
+		BlobPool                      blobpool.Config
+		GPO                           gasprice.Config
+		EnablePreimageRecording       bool
+		DocRoot                       string `toml:"-"`
+		EWASMInterpreter              string
+		EVMInterpreter                string
+		RPCGasCap                     uint64
+		RPCEVMTimeout                 time.Duration
+		RPCTxFeeCap                   float64
+		Checkpoint                    *ctypes.TrustedCheckpoint      `toml:",omitempty"`
+		CheckpointOracle              *ctypes.CheckpointOracleConfig `toml:",omitempty"`
+		OverrideECBP1100              *uint64                        `toml:",omitempty"`
+		OverrideECBP1100Deactivate    *uint64                        `toml:",omitempty"`
+		ECBP1100NoDisable             *bool                          `toml:",omitempty"`
+		OverrideShanghai              *uint64                        `toml:",omitempty"`
+		OverrideCancun                *uint64                        `toml:",omitempty"`
+		OverrideVerkle                *uint64                        `toml:",omitempty"`
 	}
 	var enc Config
 	enc.Genesis = c.Genesis
@@ -103,12 +105,14 @@ func (c Config) MarshalTOML() (interface{}, error) {
 	enc.DatabaseCache = c.DatabaseCache
 	enc.DatabaseFreezer = c.DatabaseFreezer
 	enc.DatabaseFreezerRemote = c.DatabaseFreezerRemote
+	enc.DatabaseFreezerRemoteReadOnly = c.DatabaseFreezerRemoteReadOnly
 	enc.TrieCleanCache = c.TrieCleanCache
 	enc.TrieDirtyCache = c.TrieDirtyCache
 	enc.TrieTimeout = c.TrieTimeout
 	enc.SnapshotCache = c.SnapshotCache
 	enc.Preimages = c.Preimages
 	enc.FilterLogCacheSize = c.FilterLogCacheSize
+	enc.LogIndex = c.LogIndex
 	enc.Miner = c.Miner
 	enc.Ethash = c.Ethash
 	enc.TxPool = c.TxPool
@@ -135,60 +139,62 @@ func (c Config) MarshalTOML() (interface{}, error) {
 // UnmarshalTOML unmarshals from TOML.
 func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	type Config struct {
-		Genesis                    *genesisT.Genesis `toml:",omitempty"`
-		NetworkId                  *uint64
-		ProtocolVersions           []uint
-		SyncMode                   *downloader.SyncMode
-		EthDiscoveryURLs           []string
-		SnapDiscoveryURLs          []string
-		NoPruning                  *bool
-		NoPrefetch                 *bool
-		TxLookupLimit              *uint64                `toml:",omitempty"`
-		TransactionHistory         *uint64                `toml:",omitempty"`
-		StateHistory               *uint64                `toml:",omitempty"`
-		StateScheme                *string                `toml:",omitempty"`
-		RequiredBlocks             map[uint64]common.Hash `toml:"-"`
-		LightServ                  *int                   `toml:",omitempty"`
-		LightIngress               *int                   `toml:",omitempty"`
-		LightEgress                *int                   `toml:",omitempty"`
-		LightPeers                 *int                   `toml:",omitempty"`
-		LightNoPrune               *bool                  `toml:",omitempty"`
-		LightNoSyncServe           *bool                  `toml:",omitempty"`
-		SyncFromCheckpoint         *bool                  `toml:",omitempty"`
-		UltraLightServers          []string               `toml:",omitempty"`
-		UltraLightFraction         *int                   `toml:",omitempty"`
-		UltraLightOnlyAnnounce     *bool                  `toml:",omitempty"`
-		SkipBcVersionCheck         *bool                  `toml:"-"`
-		DatabaseHandles            *int                   `toml:"-"`
-		DatabaseCache              *int
-		DatabaseFreezer            *string
-		DatabaseFreezerRemote      *string
-		TrieCleanCache             *int
-		TrieDirtyCache             *int
-		TrieTimeout                *time.Duration
-		SnapshotCache              *int
-		Preimages                  *bool
-		FilterLogCacheSize         *int
-		Miner                      *miner.Config
-		Ethash                     *ethash.Config
-		TxPool                     *legacypool.Config
-		BlobPool                   *blobpool.Config
-		GPO                        *gasprice.Config
-		EnablePreimageRecording    *bool
-		DocRoot                    *string `toml:"-"`
-		EWASMInterpreter           *string
-		EVMInterpreter             *string
-		RPCGasCap                  *uint64
-		RPCEVMTimeout              *time.Duration
-		RPCTxFeeCap                *float64
-		Checkpoint                 *ctypes.TrustedCheckpoint      `toml:",omitempty"`
-		CheckpointOracle           *ctypes.CheckpointOracleConfig `toml:",omitempty"`
-		OverrideECBP1100           *uint64                        `toml:",omitempty"`
-		OverrideECBP1100Deactivate *uint64                        `toml:",omitempty"`
-		ECBP1100NoDisable          *bool                          `toml:",omitempty"`
-		OverrideShanghai           *uint64                        `toml:",omitempty"`
-		OverrideCancun             *uint64                        `toml:",omitempty"`
-		OverrideVerkle             *uint64                        `toml:",omitempty"`
+		Genesis                       *genesisT.Genesis `toml:",omitempty"`
+		NetworkId                     *uint64
+		ProtocolVersions              []uint
+		SyncMode                      *downloader.SyncMode
+		EthDiscoveryURLs              []string
+		SnapDiscoveryURLs             []string
+		NoPruning                     *bool
+		NoPrefetch                    *bool
+		TxLookupLimit                 *uint64                `toml:",omitempty"`
+		TransactionHistory            *uint64                `toml:",omitempty"`
+		StateHistory                  *uint64                `toml:",omitempty"`
+		StateScheme                   *string                `toml:",omitempty"`
+		RequiredBlocks                map[uint64]common.Hash `toml:"-"`
+		LightServ                     *int                   `toml:",omitempty"`
+		LightIngress                  *int                   `toml:",omitempty"`
+		LightEgress                   *int                   `toml:",omitempty"`
+		LightPeers                    *int                   `toml:",omitempty"`
+		LightNoPrune                  *bool                  `toml:",omitempty"`
+		LightNoSyncServe              *bool                  `toml:",omitempty"`
+		SyncFromCheckpoint            *bool                  `toml:",omitempty"`
+		UltraLightServers             []string               `toml:",omitempty"`
+		UltraLightFraction            *int                   `toml:",omitempty"`
+		UltraLightOnlyAnnounce        *bool                  `toml:",omitempty"`
+		SkipBcVersionCheck            *bool                  `toml:"-"`
+		DatabaseHandles               *int                   `toml:"-"`
+		DatabaseCache                 *int
+		DatabaseFreezer               *string
+		DatabaseFreezerRemote         *string
+		DatabaseFreezerRemoteReadOnly *bool
+		TrieCleanCache                *int
+		TrieDirtyCache                *int
+		TrieTimeout                   *time.Duration
+		SnapshotCache                 *int
+		Preimages                     *bool
+		FilterLogCacheSize            *int
+		LogIndex                      *bool
+		Miner                         *miner.Config
+		Ethash                        *ethash.Config
+		TxPool                        *legacypool.Config
+		BlobPool                      *blobpool.Config
+		GPO                           *gasprice.Config
+		EnablePreimageRecording       *bool
+		DocRoot                       *string `toml:"-"`
+		EWASMInterpreter              *string
+		EVMInterpreter                *string
+		RPCGasCap                     *uint64
+		RPCEVMTimeout                 *time.Duration
+		RPCTxFeeCap                   *float64
+		Checkpoint                    *ctypes.TrustedCheckpoint      `toml:",omitempty"`
+		CheckpointOracle              *ctypes.CheckpointOracleConfig `toml:",omitempty"`
+		OverrideECBP1100              *uint64                        `toml:",omitempty"`
+		OverrideECBP1100Deactivate    *uint64                        `toml:",omitempty"`
+		ECBP1100NoDisable             *bool                          `toml:",omitempty"`
+		OverrideShanghai              *uint64                        `toml:",omitempty"`
+		OverrideCancun                *uint64                        `toml:",omitempty"`
+		OverrideVerkle                *uint64                        `toml:",omitempty"`
 	}
 	var dec Config
 	if err := unmarshal(&dec); err != nil {
@@ -278,6 +284,9 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	if dec.DatabaseFreezerRemote != nil {
 		c.DatabaseFreezerRemote = *dec.DatabaseFreezerRemote
 	}
+	if dec.DatabaseFreezerRemoteReadOnly != nil {
+		c.DatabaseFreezerRemoteReadOnly = *dec.DatabaseFreezerRemoteReadOnly
+	}
 	if dec.TrieCleanCache != nil {
 		c.TrieCleanCache = *dec.TrieCleanCache
 	}
@@ -296,6 +305,9 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	if dec.FilterLogCacheSize != nil {
 		c.FilterLogCacheSize = *dec.FilterLogCacheSize
 	}
+	if dec.LogIndex != nil {
+		c.LogIndex = *dec.LogIndex
+	}
 	if dec.Miner != nil {
 		c.Miner = *dec.Miner
 	}
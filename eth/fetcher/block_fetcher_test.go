@@ -31,6 +31,7 @@ import (
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/eth/protocols/eth"
+	"github.com/ethereum/go-ethereum/p2p"
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/params/types/genesisT"
 	"github.com/ethereum/go-ethereum/params/vars"
@@ -92,6 +93,7 @@ type fetcherTester struct {
 	headers map[common.Hash]*types.Header // Headers belonging to the tester
 	blocks  map[common.Hash]*types.Block  // Blocks belonging to the tester
 	drops   map[string]bool               // Map of peers dropped by the fetcher
+	scores  map[string][]p2p.ScoreEvent   // Misbehaviours reported by the fetcher
 
 	lock sync.RWMutex
 }
@@ -103,8 +105,9 @@ func newTester(light bool) *fetcherTester {
 		headers: map[common.Hash]*types.Header{genesis.Hash(): genesis.Header()},
 		blocks:  map[common.Hash]*types.Block{genesis.Hash(): genesis},
 		drops:   make(map[string]bool),
+		scores:  make(map[string][]p2p.ScoreEvent),
 	}
-	tester.fetcher = NewBlockFetcher(light, tester.getHeader, tester.getBlock, tester.verifyHeader, tester.broadcastBlock, tester.chainHeight, tester.insertHeaders, tester.insertChain, tester.dropPeer)
+	tester.fetcher = NewBlockFetcher(light, tester.getHeader, tester.getBlock, tester.verifyHeader, tester.broadcastBlock, tester.chainHeight, tester.insertHeaders, tester.insertChain, tester.dropPeer, tester.penalizePeer)
 	tester.fetcher.Start()
 
 	return tester
@@ -197,6 +200,15 @@ func (f *fetcherTester) dropPeer(peer string) {
 	f.drops[peer] = true
 }
 
+// penalizePeer is an emulator for the peer reputation, simply accumulating the
+// misbehaviours reported by the fetcher.
+func (f *fetcherTester) penalizePeer(peer string, event p2p.ScoreEvent) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	f.scores[peer] = append(f.scores[peer], event)
+}
+
 // makeHeaderFetcher retrieves a block header fetcher associated with a simulated peer.
 func (f *fetcherTester) makeHeaderFetcher(peer string, blocks map[common.Hash]*types.Block, drift time.Duration) headerRequesterFn {
 	closure := make(map[common.Hash]*types.Block)
@@ -786,11 +798,15 @@ func testInvalidNumberAnnouncement(t *testing.T, light bool) {
 	verifyImportEvent(t, imported, false)
 	tester.lock.RLock()
 	dropped := tester.drops["bad"]
+	scores := tester.scores["bad"]
 	tester.lock.RUnlock()
 
 	if !dropped {
 		t.Fatalf("peer with invalid numbered announcement not dropped")
 	}
+	if len(scores) != 1 || scores[0] != p2p.ScoreInvalidMessage {
+		t.Fatalf("peer with invalid numbered announcement penalized wrongly: %v", scores)
+	}
 	goodHeaderFetcher := tester.makeHeaderFetcher("good", blocks, -gatherSlack)
 	goodBodyFetcher := tester.makeBodyFetcher("good", blocks, 0)
 	// Make sure a good announcement passes without a drop
@@ -800,11 +816,15 @@ func testInvalidNumberAnnouncement(t *testing.T, light bool) {
 
 	tester.lock.RLock()
 	dropped = tester.drops["good"]
+	scores = tester.scores["good"]
 	tester.lock.RUnlock()
 
 	if dropped {
 		t.Fatalf("peer with valid numbered announcement dropped")
 	}
+	if len(scores) != 0 {
+		t.Fatalf("peer with valid numbered announcement penalized: %v", scores)
+	}
 	verifyImportDone(t, imported)
 }
 
@@ -22,8 +22,11 @@ import (
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/bitutil"
 	"github.com/ethereum/go-ethereum/core/bloombits"
+	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/rpc"
 )
 
@@ -198,6 +201,17 @@ func (f *Filter) rangeLogsAsync(ctx context.Context) (chan *types.Log, chan erro
 			size, sections = f.sys.backend.BloomStatus()
 			err            error
 		)
+		// Use the log index first if available, bloom bits are only used for
+		// the remaining sections
+		if f.logIndexable() {
+			if size, sections := f.sys.backend.LogIndexStatus(); sections*size > uint64(f.begin) {
+				indexed := min(sections*size, end+1)
+				if err = f.logIndexedLogs(ctx, size, indexed-1, logChan); err != nil {
+					errChan <- err
+					return
+				}
+			}
+		}
 		if indexed := sections * size; indexed > uint64(f.begin) {
 			if indexed > end {
 				indexed = end + 1
@@ -265,6 +279,124 @@ func (f *Filter) indexedLogs(ctx context.Context, end uint64, logChan chan *type
 	}
 }
 
+// logIndexable reports whether the filter criteria restrict the addresses or
+// the topics, so the matching blocks can be found by the log index.
+func (f *Filter) logIndexable() bool {
+	if len(f.addresses) > 0 {
+		return true
+	}
+	for _, sub := range f.topics {
+		if len(sub) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// logIndexedLogs returns the logs matching the filter criteria based on the
+// secondary log index, which is checked section by section. The blocks of the
+// sections missing from the index are scanned instead.
+func (f *Filter) logIndexedLogs(ctx context.Context, size uint64, end uint64, logChan chan *types.Log) error {
+	db := f.sys.backend.ChainDb()
+	for section := uint64(f.begin) / size; section*size <= end; section++ {
+		head := rawdb.ReadCanonicalHash(db, (section+1)*size-1)
+		matches, err := f.logIndexMatches(db, section, head, size)
+		if err != nil {
+			return err
+		}
+		last := min((section+1)*size-1, end)
+		for number := uint64(f.begin); number <= last; number++ {
+			offset := number - section*size
+			if matches != nil && matches[offset/8]&(1<<(7-offset%8)) == 0 {
+				continue
+			}
+			header, err := f.sys.backend.HeaderByNumber(ctx, rpc.BlockNumber(number))
+			if header == nil || err != nil {
+				return err
+			}
+			var found []*types.Log
+			if matches != nil {
+				found, err = f.checkMatches(ctx, header)
+			} else {
+				found, err = f.blockLogs(ctx, header)
+			}
+			if err != nil {
+				return err
+			}
+			for _, log := range found {
+				select {
+				case logChan <- log:
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			}
+		}
+		f.begin = int64(last) + 1
+	}
+	return nil
+}
+
+// logIndexMatches returns the bitmap of the blocks of a section possibly matching
+// the filter criteria: the union of the blocks of the addresses, intersected with
+// the union of the blocks of each topic position. Since the topics are indexed
+// regardless of their position, the logs of the matching blocks still need to be
+// checked. Nil is returned if the section is not indexed.
+func (f *Filter) logIndexMatches(db ethdb.KeyValueReader, section uint64, head common.Hash, size uint64) ([]byte, error) {
+	if !rawdb.HasLogIndexSection(db, section, head) {
+		return nil, nil
+	}
+	var (
+		matches []byte
+		union   = func(bitmaps [][]byte) ([]byte, error) {
+			result := make([]byte, size/8)
+			for _, data := range bitmaps {
+				if data == nil {
+					continue
+				}
+				bits, err := bitutil.DecompressBytes(data, int(size/8))
+				if err != nil {
+					return nil, err
+				}
+				bitutil.ORBytes(result, result, bits)
+			}
+			return result, nil
+		}
+		intersect = func(bits []byte) {
+			if matches == nil {
+				matches = bits
+			} else {
+				bitutil.ANDBytes(matches, matches, bits)
+			}
+		}
+	)
+	if len(f.addresses) > 0 {
+		bitmaps := make([][]byte, len(f.addresses))
+		for i, address := range f.addresses {
+			bitmaps[i] = rawdb.ReadLogIndexAddress(db, section, head, address)
+		}
+		bits, err := union(bitmaps)
+		if err != nil {
+			return nil, err
+		}
+		intersect(bits)
+	}
+	for _, sub := range f.topics {
+		if len(sub) == 0 {
+			continue // empty rule set == wildcard
+		}
+		bitmaps := make([][]byte, len(sub))
+		for i, topic := range sub {
+			bitmaps[i] = rawdb.ReadLogIndexTopic(db, section, head, topic)
+		}
+		bits, err := union(bitmaps)
+		if err != nil {
+			return nil, err
+		}
+		intersect(bits)
+	}
+	return matches, nil
+}
+
 // unindexedLogs returns the logs matching the filter criteria based on raw block
 // iteration and bloom matching.
 func (f *Filter) unindexedLogs(ctx context.Context, end uint64, logChan chan *types.Log) error {
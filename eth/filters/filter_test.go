@@ -17,6 +17,7 @@
 package filters
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"math/big"
@@ -390,3 +391,115 @@ func TestFilters(t *testing.T) {
 		}
 	})
 }
+
+// Tests that the logs found with the help of the log index are the same as the
+// ones found by scanning the blocks, including the ranges partially indexed.
+func TestLogIndexedFilters(t *testing.T) {
+	var (
+		db           = rawdb.NewMemoryDatabase()
+		backend, sys = newTestFilterSystem(t, db, Config{})
+		key, _       = crypto.GenerateKey()
+		addr         = crypto.PubkeyToAddress(key.PublicKey)
+		signer       = types.NewEIP1559Signer(big.NewInt(1))
+
+		// Contracts emitting LOG2(0x2a, number) and LOG1(number)
+		contract1 = common.Address{0xfe}
+		contract2 = common.Address{0xff}
+
+		gspec = &genesisT.Genesis{
+			Config: params.TestChainConfig,
+			Alloc: genesisT.GenesisAlloc{
+				addr:      {Balance: big.NewInt(0).Mul(big.NewInt(100), big.NewInt(vars.Ether))},
+				contract1: {Balance: big.NewInt(0), Code: common.FromHex("43602a60006000a200")},
+				contract2: {Balance: big.NewInt(0), Code: common.FromHex("4360006000a100")},
+			},
+			BaseFee: big.NewInt(vars.InitialBaseFee),
+		}
+	)
+	g, err := core.CommitGenesis(gspec, db, triedb.NewDatabase(db, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Call the first contract in every block and the second one in every 3rd
+	chain, _ := core.GenerateChain(gspec.Config, g, ethash.NewFaker(), db, 100, func(i int, gen *core.BlockGen) {
+		for _, contract := range []common.Address{contract1, contract2} {
+			if contract == contract2 && i%3 != 0 {
+				continue
+			}
+			tx, _ := types.SignTx(types.NewTx(&types.LegacyTx{
+				Nonce:    gen.TxNonce(addr),
+				GasPrice: gen.BaseFee(),
+				Gas:      30000,
+				To:       &contract,
+			}), signer, key)
+			gen.AddTx(tx)
+		}
+	})
+	bc, err := core.NewBlockChain(db, nil, gspec, nil, ethash.NewFaker(), vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer bc.Stop()
+	if _, err := bc.InsertChain(chain); err != nil {
+		t.Fatal(err)
+	}
+	// Index the sections of 16 blocks, leaving the last ones to the block scan
+	indexer := core.NewLogIndexer(db, 16, 0)
+	defer indexer.Close()
+	if err := indexer.IndexSections(bc.CurrentBlock().Number.Uint64(), nil); err != nil {
+		t.Fatal(err)
+	}
+	sections, _, _ := indexer.Sections()
+	if sections != 6 {
+		t.Fatalf("indexed sections mismatch: have %d, want 6", sections)
+	}
+	number := func(n int64) common.Hash { return common.BigToHash(big.NewInt(n)) }
+
+	for i, tc := range []struct {
+		begin, end int64
+		addresses  []common.Address
+		topics     [][]common.Hash
+		want       int
+	}{
+		{0, -1, []common.Address{contract2}, nil, 34},
+		{20, 70, []common.Address{contract1, contract2}, nil, 68},
+		{0, -1, nil, [][]common.Hash{{number(0x2a)}, {number(7), number(90), number(99)}}, 3},
+		{0, -1, nil, [][]common.Hash{{number(7), number(8), number(97)}}, 2},
+		{0, -1, []common.Address{contract1}, [][]common.Hash{nil, {number(40)}}, 1},
+		{10, 95, nil, [][]common.Hash{{number(0x2c)}}, 0},
+		{0, -1, []common.Address{{0x01}}, nil, 0},
+	} {
+		backend.logSize, backend.logSections = 0, 0
+		scanned, err := sys.NewRangeFilter(tc.begin, tc.end, tc.addresses, tc.topics).Logs(context.Background())
+		if err != nil {
+			t.Fatalf("test %d: failed to scan logs: %v", i, err)
+		}
+		backend.logSize, backend.logSections = 16, sections
+		indexed, err := sys.NewRangeFilter(tc.begin, tc.end, tc.addresses, tc.topics).Logs(context.Background())
+		if err != nil {
+			t.Fatalf("test %d: failed to find indexed logs: %v", i, err)
+		}
+		if len(scanned) != tc.want {
+			t.Errorf("test %d: scanned log count mismatch: have %d, want %d", i, len(scanned), tc.want)
+		}
+		have, _ := json.Marshal(indexed)
+		want, _ := json.Marshal(scanned)
+		if !bytes.Equal(have, want) {
+			t.Errorf("test %d: indexed logs mismatch:\nhave %s\nwant %s", i, have, want)
+		}
+	}
+	// Drop the index of a section, its blocks are scanned instead of skipped
+	batch := db.NewBatch()
+	rawdb.DeleteLogIndexSection(db, batch, 2)
+	if err := batch.Write(); err != nil {
+		t.Fatal(err)
+	}
+	backend.logSize, backend.logSections = 16, sections
+	logs, err := sys.NewRangeFilter(0, -1, []common.Address{contract2}, nil).Logs(context.Background())
+	if err != nil {
+		t.Fatalf("failed to find logs of unindexed section: %v", err)
+	}
+	if len(logs) != 34 {
+		t.Errorf("unindexed section log count mismatch: have %d, want 34", len(logs))
+	}
+}
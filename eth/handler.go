@@ -194,7 +194,7 @@ func newHandler(config *handlerConfig) (*handler, error) {
 		return nil, errors.New("snap sync not supported with snapshots disabled")
 	}
 	// Construct the downloader (long sync)
-	h.downloader = downloader.New(h.checkpointNumber, config.Database, h.eventMux, h.chain, nil, h.removePeer, h.enableSyncedFeatures)
+	h.downloader = downloader.New(h.checkpointNumber, config.Database, h.eventMux, h.chain, nil, h.removePeer, h.penalizePeer, h.enableSyncedFeatures)
 	if ttd := h.chain.Config().GetEthashTerminalTotalDifficulty(); ttd != nil {
 		if h.chain.Config().GetEthashTerminalTotalDifficultyPassed() {
 			log.Info("Chain post-merge, sync via beacon client")
@@ -295,7 +295,7 @@ func newHandler(config *handlerConfig) (*handler, error) {
 		}
 		return n, err
 	}
-	h.blockFetcher = fetcher.NewBlockFetcher(false, nil, h.chain.GetBlockByHash, validator, h.BroadcastBlock, heighter, nil, inserter, h.removePeer)
+	h.blockFetcher = fetcher.NewBlockFetcher(false, nil, h.chain.GetBlockByHash, validator, h.BroadcastBlock, heighter, nil, inserter, h.removePeer, h.penalizePeer)
 
 	fetchTx := func(peer string, hashes []common.Hash) error {
 		p := h.peers.peer(peer)
@@ -307,7 +307,13 @@ func newHandler(config *handlerConfig) (*handler, error) {
 	addTxs := func(txs []*types.Transaction) []error {
 		return h.txpool.Add(txs, false, false)
 	}
-	h.txFetcher = fetcher.NewTxFetcher(h.txpool.Has, addTxs, fetchTx, h.removePeer)
+	// The transaction fetcher only drops the peers announcing transactions
+	// inconsistently with their delivery
+	dropTxPeer := func(peer string) {
+		h.penalizePeer(peer, p2p.ScoreInvalidMessage)
+		h.removePeer(peer)
+	}
+	h.txFetcher = fetcher.NewTxFetcher(h.txpool.Has, addTxs, fetchTx, dropTxPeer)
 	h.chainSync = newChainSyncer(h)
 	return h, nil
 }
@@ -473,6 +479,7 @@ func (h *handler) runEthPeer(peer *eth.Peer, handler eth.Handler) error {
 
 			case <-timeout.C:
 				peer.Log().Warn("Checkpoint challenge timed out, dropping", "addr", peer.RemoteAddr(), "type", peer.Name())
+				peer.Penalize(p2p.ScoreTimeout)
 				h.removePeer(peer.ID())
 
 			case <-dead:
@@ -511,6 +518,7 @@ func (h *handler) runEthPeer(peer *eth.Peer, handler eth.Handler) error {
 				}
 				if headers[0].Number.Uint64() != number || headers[0].Hash() != hash {
 					peer.Log().Info("Required block mismatch, dropping peer", "number", number, "hash", headers[0].Hash(), "want", hash)
+					peer.Penalize(p2p.ScoreInvalidBlock)
 					res.Done <- errors.New("required block mismatch")
 					return
 				}
@@ -518,6 +526,7 @@ func (h *handler) runEthPeer(peer *eth.Peer, handler eth.Handler) error {
 				res.Done <- nil
 			case <-timeout.C:
 				peer.Log().Warn("Required block challenge timed out, dropping", "addr", peer.RemoteAddr(), "type", peer.Name())
+				peer.Penalize(p2p.ScoreTimeout)
 				h.removePeer(peer.ID())
 			}
 		}(number, hash, req)
@@ -558,6 +567,15 @@ func (h *handler) removePeer(id string) {
 	}
 }
 
+// penalizePeer lowers the reputation of a misbehaving peer, which gets banned
+// by the p2p server once its reputation is too low.
+func (h *handler) penalizePeer(id string, event p2p.ScoreEvent) {
+	peer := h.peers.peer(id)
+	if peer != nil {
+		peer.Peer.Penalize(event)
+	}
+}
+
 // unregisterPeer removes a peer from the downloader, fetchers and main peer set.
 func (h *handler) unregisterPeer(id string) {
 	// Create a custom logger to avoid printing the entire id
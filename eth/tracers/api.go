@@ -200,6 +200,8 @@ type blockTraceResult struct {
 	Block  hexutil.Uint64   `json:"block"`  // Block number corresponding to this trace
 	Hash   common.Hash      `json:"hash"`   // Block hash corresponding to this trace
 	Traces []*txTraceResult `json:"traces"` // Trace results produced by the task
+
+	formatted []interface{} // Notifications replacing the result, if formatted
 }
 
 // txTraceTask represents a single transaction trace task when an entire block
@@ -225,7 +227,15 @@ func getTraceConfigFromTraceCallConfig(config *TraceCallConfig) *TraceConfig {
 
 // TraceChain returns the structured logs created during the execution of EVM
 // between two blocks (excluding start) and returns them as a JSON object.
-func (api *API) TraceChain(ctx context.Context, start, end rpc.BlockNumber, config *TraceConfig) (*rpc.Subscription, error) { // Fetch the block interval that we want to trace
+func (api *API) TraceChain(ctx context.Context, start, end rpc.BlockNumber, config *TraceConfig) (*rpc.Subscription, error) {
+	return api.subscribeChain(ctx, start, end, config, nil)
+}
+
+// subscribeChain traces the blocks between start (excluded) and end, and streams
+// the results to a new subscription. If set, format converts the results of every
+// block into the notifications streamed one by one in their place.
+func (api *API) subscribeChain(ctx context.Context, start, end rpc.BlockNumber, config *TraceConfig, format func(*types.Block, *blockTraceResult) []interface{}) (*rpc.Subscription, error) {
+	// Fetch the block interval that we want to trace
 	from, err := api.blockByNumber(ctx, start)
 	if err != nil {
 		return nil, err
@@ -244,9 +254,15 @@ func (api *API) TraceChain(ctx context.Context, start, end rpc.BlockNumber, conf
 	}
 	sub := notifier.CreateSubscription()
 
-	resCh := api.traceChain(from, to, config, notifier.Closed())
+	resCh := api.traceChain(from, to, config, format, notifier.Closed())
 	go func() {
 		for result := range resCh {
+			if result.formatted != nil {
+				for _, item := range result.formatted {
+					notifier.Notify(sub.ID, item)
+				}
+				continue
+			}
 			notifier.Notify(sub.ID, result)
 		}
 	}()
@@ -258,7 +274,9 @@ func (api *API) TraceChain(ctx context.Context, start, end rpc.BlockNumber, conf
 // the end block but excludes the start one. The return value will be one item per
 // transaction, dependent on the requested tracer.
 // The tracing procedure should be aborted in case the closed signal is received.
-func (api *API) traceChain(start, end *types.Block, config *TraceConfig, closed <-chan interface{}) chan *blockTraceResult {
+// If set, format is called on the results of every block, which are then streamed
+// even if the block has no transactions.
+func (api *API) traceChain(start, end *types.Block, config *TraceConfig, format func(*types.Block, *blockTraceResult) []interface{}, closed <-chan interface{}) chan *blockTraceResult {
 	reexec := defaultTraceReexec
 	if config != nil && config.Reexec != nil {
 		reexec = *config.Reexec
@@ -428,11 +446,14 @@ func (api *API) traceChain(start, end *types.Block, config *TraceConfig, closed
 				Hash:   res.block.Hash(),
 				Traces: res.results,
 			}
+			if format != nil {
+				result.formatted = format(res.block, result)
+			}
 			done[uint64(result.Block)] = result
 
 			// Stream completed traces to the result channel
 			for result, ok := done[next]; ok; result, ok = done[next] {
-				if len(result.Traces) > 0 || next == end.NumberU64() {
+				if len(result.Traces) > 0 || result.formatted != nil || next == end.NumberU64() {
 					// It will be blocked in case the channel consumer doesn't take the
 					// tracing result in time(e.g. the websocket connect is not stable)
 					// which will eventually block the entire chain tracer. It's the
@@ -1017,10 +1038,8 @@ func (api *API) TraceCallMany(ctx context.Context, txs []ethapi.TransactionArgs,
 // be tracer dependent.
 func (api *API) traceTx(ctx context.Context, message *core.Message, txctx *Context, vmctx vm.BlockContext, statedb *state.StateDB, config *TraceConfig) (interface{}, error) {
 	var (
-		tracer    Tracer
-		err       error
-		timeout   = defaultTraceTimeout
-		txContext = core.NewEVMTxContext(message)
+		tracer Tracer
+		err    error
 	)
 	if config == nil {
 		config = &TraceConfig{}
@@ -1033,11 +1052,25 @@ func (api *API) traceTx(ctx context.Context, message *core.Message, txctx *Conte
 			return nil, err
 		}
 	}
+	if _, err := api.runTracer(ctx, tracer, message, txctx, vmctx, statedb, config.Timeout); err != nil {
+		return nil, err
+	}
+	return tracer.GetResult()
+}
+
+// runTracer executes the given message with the tracer in the provided
+// environment, stopping it after the given timeout, or the default one.
+func (api *API) runTracer(ctx context.Context, tracer Tracer, message *core.Message, txctx *Context, vmctx vm.BlockContext, statedb *state.StateDB, timeoutStr *string) (*core.ExecutionResult, error) {
+	var (
+		err       error
+		timeout   = defaultTraceTimeout
+		txContext = core.NewEVMTxContext(message)
+	)
 	vmenv := vm.NewEVM(vmctx, txContext, statedb, api.backend.ChainConfig(), vm.Config{Tracer: tracer, NoBaseFee: true})
 
 	// Define a meaningful timeout of a single transaction trace
-	if config.Timeout != nil {
-		if timeout, err = time.ParseDuration(*config.Timeout); err != nil {
+	if timeoutStr != nil {
+		if timeout, err = time.ParseDuration(*timeoutStr); err != nil {
 			return nil, err
 		}
 	}
@@ -1059,10 +1092,11 @@ func (api *API) traceTx(ctx context.Context, message *core.Message, txctx *Conte
 	if traceStateCapturer, ok := tracer.(vm.EVMLogger_StateCapturer); ok {
 		traceStateCapturer.CapturePreEVM(vmenv)
 	}
-	if _, err = core.ApplyMessage(vmenv, message, new(core.GasPool).AddGas(message.GasLimit)); err != nil {
+	result, err := core.ApplyMessage(vmenv, message, new(core.GasPool).AddGas(message.GasLimit))
+	if err != nil {
 		return nil, fmt.Errorf("tracing failed: %w", err)
 	}
-	return tracer.GetResult()
+	return result, nil
 }
 
 // APIs return the collection of RPC services the tracer package offers.
@@ -20,13 +20,21 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/consensus/misc/eip1559"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/internal/ethapi"
 	"github.com/ethereum/go-ethereum/params/mutations"
 	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/holiman/uint256"
 )
 
 // TraceFilterArgs represents the arguments for a call.
@@ -184,6 +192,70 @@ func (api *TraceAPI) traceBlockUncleRewards(ctx context.Context, block *types.Bl
 	return results, nil
 }
 
+// traceBlockFeeDistribution retrieve the base fee credits of the Halo fee distribution
+func (api *TraceAPI) traceBlockFeeDistribution(ctx context.Context, block *types.Block, config *TraceConfig) ([]*ParityTrace, error) {
+	chainConfig := api.debugAPI.backend.ChainConfig()
+	header := block.Header()
+
+	// The contract fee shares paid during the block are settled from the receipts
+	var receipts types.Receipts
+	if eip1559.IsHaloContractFeeSharingEnabled(chainConfig, header.Number) && header.GasUsed > 0 {
+		receipts = rawdb.ReadRawReceipts(api.debugAPI.backend.ChainDb(), block.Hash(), block.NumberU64())
+		if receipts == nil {
+			return nil, fmt.Errorf("receipts of block #%d not found", block.NumberU64())
+		}
+	}
+	credits, err := eip1559.HaloBlockBaseFeeCredits(chainConfig, header, receipts)
+	if credits == nil || err != nil {
+		return nil, err
+	}
+	results := []*ParityTrace{}
+	appendCredit := func(author common.Address, value *uint256.Int) {
+		if value.IsZero() {
+			return
+		}
+		results = append(results, &ParityTrace{
+			Type: "reward",
+			Action: TraceRewardAction{
+				Value:      (*hexutil.Big)(value.ToBig()),
+				Author:     &author,
+				RewardType: "baseFeeShare",
+			},
+			TraceAddress: []int{},
+			BlockNumber:  block.NumberU64(),
+			BlockHash:    block.Hash(),
+		})
+	}
+	appendCredit(block.Coinbase(), credits.Miner)
+	for _, share := range credits.Shares {
+		appendCredit(share.Recipient, share.Amount)
+	}
+
+	return results, nil
+}
+
+// traceBlockRewards retrieve the traces of all the balance changes made by the
+// block finalization: the block and uncle rewards, and the base fee credits.
+func (api *TraceAPI) traceBlockRewards(ctx context.Context, block *types.Block, config *TraceConfig) ([]*ParityTrace, error) {
+	traceReward, err := api.traceBlockReward(ctx, block, config)
+	if err != nil {
+		return nil, err
+	}
+
+	traceUncleRewards, err := api.traceBlockUncleRewards(ctx, block, config)
+	if err != nil {
+		return nil, err
+	}
+
+	traceFeeDistribution, err := api.traceBlockFeeDistribution(ctx, block, config)
+	if err != nil {
+		return nil, err
+	}
+
+	results := append([]*ParityTrace{traceReward}, traceUncleRewards...)
+	return append(results, traceFeeDistribution...), nil
+}
+
 // Block returns the structured logs created during the execution of
 // EVM and returns them as a JSON object.
 // The correct name will be TraceBlockByNumber, though we want to be compatible with Parity trace module.
@@ -200,16 +272,17 @@ func (api *TraceAPI) Block(ctx context.Context, number rpc.BlockNumber, config *
 		return nil, err
 	}
 
-	traceReward, err := api.traceBlockReward(ctx, block, config)
+	traceRewards, err := api.traceBlockRewards(ctx, block, config)
 	if err != nil {
 		return nil, err
 	}
 
-	traceUncleRewards, err := api.traceBlockUncleRewards(ctx, block, config)
-	if err != nil {
-		return nil, err
-	}
+	return flattenTraceResults(traceResults, traceRewards, config)
+}
 
+// flattenTraceResults merges the traces of the transactions of a block and its
+// reward traces into a single list, the way Parity does.
+func flattenTraceResults(traceResults []*txTraceResult, traceRewards []*ParityTrace, config *TraceConfig) ([]interface{}, error) {
 	results := []interface{}{}
 
 	for _, result := range traceResults {
@@ -227,10 +300,8 @@ func (api *TraceAPI) Block(ctx context.Context, number rpc.BlockNumber, config *
 		}
 	}
 
-	results = append(results, traceReward)
-
-	for _, uncleReward := range traceUncleRewards {
-		results = append(results, uncleReward)
+	for _, reward := range traceRewards {
+		results = append(results, reward)
 	}
 
 	return results, nil
@@ -245,7 +316,9 @@ func (api *TraceAPI) Transaction(ctx context.Context, hash common.Hash, config *
 
 // Filter configures a new tracer according to the provided configuration, and
 // executes all the transactions contained within. The return value will be one item
-// per transaction, dependent on the requested tracer.
+// per trace: the traces of the transactions of every block followed by its reward
+// traces, as returned by trace_block. The failure to trace a block is streamed in
+// place of its traces.
 func (api *TraceAPI) Filter(ctx context.Context, args TraceFilterArgs, config *TraceConfig) (*rpc.Subscription, error) {
 	config = setTraceConfigDefaultTracer(config)
 
@@ -253,7 +326,23 @@ func (api *TraceAPI) Filter(ctx context.Context, args TraceFilterArgs, config *T
 	start := rpc.BlockNumber(args.FromBlock)
 	end := rpc.BlockNumber(args.ToBlock)
 
-	return api.debugAPI.TraceChain(ctx, start, end, config)
+	return api.debugAPI.subscribeChain(ctx, start, end, config, func(block *types.Block, result *blockTraceResult) []interface{} {
+		traces, err := api.filterBlockTraces(block, result, config)
+		if err != nil {
+			return []interface{}{&txTraceResult{Error: err.Error()}}
+		}
+		return traces
+	})
+}
+
+// filterBlockTraces flattens the traces of a block and its reward traces into
+// the list of traces streamed by trace_filter.
+func (api *TraceAPI) filterBlockTraces(block *types.Block, result *blockTraceResult, config *TraceConfig) ([]interface{}, error) {
+	traceRewards, err := api.traceBlockRewards(context.Background(), block, config)
+	if err != nil {
+		return nil, err
+	}
+	return flattenTraceResults(result.Traces, traceRewards, config)
 }
 
 // Call lets you trace a given eth_call. It collects the structured logs created during the execution of EVM
@@ -276,3 +365,188 @@ func (api *TraceAPI) CallMany(ctx context.Context, txs []ethapi.TransactionArgs,
 	config = setTraceCallConfigDefaultTracer(config)
 	return api.debugAPI.TraceCallMany(ctx, txs, blockNrOrHash, config)
 }
+
+// replayTx executes the given message with the tracers of the trace types, and
+// assembles their outputs.
+func (api *TraceAPI) replayTx(ctx context.Context, message *core.Message, txctx *Context, vmctx vm.BlockContext, statedb *state.StateDB, traceTypes []string) (*ParityReplayResult, error) {
+	table, err := vm.LookupInstructionSet(api.debugAPI.backend.ChainConfig(), vmctx.BlockNumber, &vmctx.Time)
+	if err != nil {
+		return nil, err
+	}
+	tracer, err := newReplayTracer(traceTypes, txctx, table)
+	if err != nil {
+		return nil, err
+	}
+	result, err := api.debugAPI.runTracer(ctx, tracer, message, txctx, vmctx, statedb, nil)
+	if err != nil {
+		return nil, err
+	}
+	return tracer.result(result.Return())
+}
+
+// ReplayTransaction replays a transaction, returning the traces of the given
+// types: "trace", "vmTrace" and "stateDiff".
+func (api *TraceAPI) ReplayTransaction(ctx context.Context, hash common.Hash, traceTypes []string) (*ParityReplayResult, error) {
+	found, _, blockHash, blockNumber, index, err := api.debugAPI.backend.GetTransaction(ctx, hash)
+	if err != nil {
+		return nil, ethapi.NewTxIndexingError()
+	}
+	// Only mined txes are supported
+	if !found {
+		return nil, errTxNotFound
+	}
+	// It shouldn't happen in practice.
+	if blockNumber == 0 {
+		return nil, errors.New("genesis is not traceable")
+	}
+	block, err := api.debugAPI.blockByNumberAndHash(ctx, rpc.BlockNumber(blockNumber), blockHash)
+	if err != nil {
+		return nil, err
+	}
+	msg, vmctx, statedb, release, err := api.debugAPI.backend.StateAtTransaction(ctx, block, int(index), defaultTraceReexec)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	txctx := &Context{
+		BlockHash:   blockHash,
+		BlockNumber: block.Number(),
+		TxIndex:     int(index),
+		TxHash:      hash,
+	}
+	return api.replayTx(ctx, msg, txctx, vmctx, statedb, traceTypes)
+}
+
+// ReplayBlockTransactions replays all the transactions of a block, returning
+// the traces of the given types for each of them.
+func (api *TraceAPI) ReplayBlockTransactions(ctx context.Context, number rpc.BlockNumber, traceTypes []string) ([]*ParityReplayResult, error) {
+	block, err := api.debugAPI.blockByNumber(ctx, number)
+	if err != nil {
+		return nil, err
+	}
+	if block.NumberU64() == 0 {
+		return nil, errors.New("genesis is not traceable")
+	}
+	parent, err := api.debugAPI.blockByNumberAndHash(ctx, rpc.BlockNumber(block.NumberU64()-1), block.ParentHash())
+	if err != nil {
+		return nil, err
+	}
+	statedb, release, err := api.debugAPI.backend.StateAtBlock(ctx, parent, defaultTraceReexec, nil, true, false)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	var (
+		chainConfig = api.debugAPI.backend.ChainConfig()
+		txs         = block.Transactions()
+		blockHash   = block.Hash()
+		isEIP161D   = chainConfig.IsEnabled(chainConfig.GetEIP161dTransition, block.Number())
+		blockCtx    = core.NewEVMBlockContext(block.Header(), api.debugAPI.chainContext(ctx), nil)
+		signer      = types.MakeSigner(chainConfig, block.Number(), block.Time())
+		results     = make([]*ParityReplayResult, len(txs))
+	)
+	for i, tx := range txs {
+		msg, err := core.TransactionToMessage(tx, signer, block.BaseFee())
+		if err != nil {
+			return nil, err
+		}
+		txctx := &Context{
+			BlockHash:   blockHash,
+			BlockNumber: block.Number(),
+			TxIndex:     i,
+			TxHash:      tx.Hash(),
+		}
+		res, err := api.replayTx(ctx, msg, txctx, blockCtx, statedb, traceTypes)
+		if err != nil {
+			return nil, err
+		}
+		txHash := tx.Hash()
+		res.TransactionHash = &txHash
+		results[i] = res
+
+		// Finalize the state so any modifications are written to the trie
+		statedb.Finalise(isEIP161D)
+	}
+	return results, nil
+}
+
+// RawTransaction traces a signed transaction on top of the latest block,
+// returning the traces of the given types, without broadcasting it.
+func (api *TraceAPI) RawTransaction(ctx context.Context, input hexutil.Bytes, traceTypes []string) (*ParityReplayResult, error) {
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(input); err != nil {
+		return nil, err
+	}
+	block, err := api.debugAPI.blockByNumber(ctx, rpc.LatestBlockNumber)
+	if err != nil {
+		return nil, err
+	}
+	statedb, release, err := api.debugAPI.backend.StateAtBlock(ctx, block, defaultTraceReexec, nil, true, false)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	// The transaction is executed in a pending block on top of the latest one,
+	// whose context is used by both the signer and the EVM
+	var (
+		chainConfig = api.debugAPI.backend.ChainConfig()
+		parent      = block.Header()
+		header      = &types.Header{
+			ParentHash: parent.Hash(),
+			Number:     new(big.Int).Add(parent.Number, common.Big1),
+			Time:       parent.Time + 1,
+			GasLimit:   parent.GasLimit,
+			Coinbase:   parent.Coinbase,
+			Difficulty: parent.Difficulty,
+		}
+	)
+	if chainConfig.IsEnabled(chainConfig.GetEIP1559Transition, header.Number) {
+		header.BaseFee = eip1559.CalcBaseFee(chainConfig, parent)
+	}
+	signer := types.MakeSigner(chainConfig, header.Number, header.Time)
+	msg, err := core.TransactionToMessage(tx, signer, header.BaseFee)
+	if err != nil {
+		return nil, err
+	}
+	vmctx := core.NewEVMBlockContext(header, api.debugAPI.chainContext(ctx), nil)
+	return api.replayTx(ctx, msg, &Context{TxHash: tx.Hash()}, vmctx, statedb, traceTypes)
+}
+
+// Get returns the trace of a transaction at the given trace address, or null
+// if there is none.
+func (api *TraceAPI) Get(ctx context.Context, hash common.Hash, indices []hexutil.Uint64) (json.RawMessage, error) {
+	tracer := "callTracerParity"
+	res, err := api.debugAPI.TraceTransaction(ctx, hash, &TraceConfig{Tracer: &tracer})
+	if err != nil {
+		return nil, err
+	}
+	var traces []json.RawMessage
+	if err := json.Unmarshal(res.(json.RawMessage), &traces); err != nil {
+		return nil, err
+	}
+	for _, trace := range traces {
+		var frame struct {
+			TraceAddress []int `json:"traceAddress"`
+		}
+		if err := json.Unmarshal(trace, &frame); err != nil {
+			return nil, err
+		}
+		if len(frame.TraceAddress) != len(indices) {
+			continue
+		}
+		match := true
+		for i, index := range indices {
+			if uint64(frame.TraceAddress[i]) != uint64(index) {
+				match = false
+				break
+			}
+		}
+		if match {
+			return trace, nil
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,385 @@
+// Copyright 2025 The core-geth Authors
+// This file is part of the core-geth library.
+//
+// The core-geth library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The core-geth library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the core-geth library. If not, see <http://www.gnu.org/licenses/>.
+
+package tracers
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/params/vars"
+	"github.com/holiman/uint256"
+)
+
+// Trace types selectable by the Parity replay methods.
+const (
+	parityTraceType     = "trace"
+	parityVMTraceType   = "vmTrace"
+	parityStateDiffType = "stateDiff"
+)
+
+// ParityReplayResult is the result of replaying a transaction, with the outputs
+// of the requested trace types, the others being null.
+type ParityReplayResult struct {
+	Output          hexutil.Bytes   `json:"output"`
+	StateDiff       json.RawMessage `json:"stateDiff"`
+	Trace           json.RawMessage `json:"trace"`
+	VMTrace         *ParityVMTrace  `json:"vmTrace"`
+	TransactionHash *common.Hash    `json:"transactionHash,omitempty"`
+}
+
+// ParityVMTrace is a Parity formatted vmTrace: the instructions executed by a
+// call frame, along with the frames of the calls they made.
+type ParityVMTrace struct {
+	Code hexutil.Bytes      `json:"code"`
+	Ops  []*ParityVMTraceOp `json:"ops"`
+}
+
+// ParityVMTraceOp is an instruction of a Parity formatted vmTrace.
+type ParityVMTraceOp struct {
+	Cost uint64           `json:"cost"`
+	Ex   *ParityVMTraceEx `json:"ex"`
+	Pc   uint64           `json:"pc"`
+	Sub  *ParityVMTrace   `json:"sub"`
+}
+
+// ParityVMTraceEx is the outcome of an instruction of a Parity formatted vmTrace.
+type ParityVMTraceEx struct {
+	Mem   *ParityVMTraceMem   `json:"mem"`
+	Push  []*hexutil.Big      `json:"push"`
+	Store *ParityVMTraceStore `json:"store"`
+	Used  uint64              `json:"used"` // Gas left after the instruction
+}
+
+// ParityVMTraceMem is a memory area written by an instruction.
+type ParityVMTraceMem struct {
+	Data hexutil.Bytes `json:"data"`
+	Off  uint64        `json:"off"`
+}
+
+// ParityVMTraceStore is a storage slot written by an instruction.
+type ParityVMTraceStore struct {
+	Key *hexutil.Big `json:"key"`
+	Val *hexutil.Big `json:"val"`
+}
+
+// replayTracer runs the tracers of the trace types selected for a replay in a
+// single execution.
+type replayTracer struct {
+	trace     Tracer
+	stateDiff Tracer
+	vmTrace   *vmTracer
+	tracers   []Tracer
+	reason    error // Reason of the interruption, if any
+}
+
+// newReplayTracer creates the tracers of the given trace types. The vmTrace
+// reports the pushed items of the instructions of the given jump table.
+func newReplayTracer(traceTypes []string, txctx *Context, table vm.JumpTable) (*replayTracer, error) {
+	t := new(replayTracer)
+	for _, typ := range traceTypes {
+		var err error
+		switch typ {
+		case parityTraceType:
+			if t.trace == nil {
+				t.trace, err = DefaultDirectory.New("callTracerParity", txctx, nil)
+				t.tracers = append(t.tracers, t.trace)
+			}
+		case parityStateDiffType:
+			if t.stateDiff == nil {
+				t.stateDiff, err = DefaultDirectory.New("stateDiffTracer", txctx, nil)
+				t.tracers = append(t.tracers, t.stateDiff)
+			}
+		case parityVMTraceType:
+			if t.vmTrace == nil {
+				t.vmTrace = &vmTracer{table: table}
+				t.tracers = append(t.tracers, t.vmTrace)
+			}
+		default:
+			return nil, fmt.Errorf("unknown trace type %q", typ)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return t, nil
+}
+
+// result assembles the outputs of the tracers.
+func (t *replayTracer) result(output []byte) (*ParityReplayResult, error) {
+	res := &ParityReplayResult{Output: output}
+	if output == nil {
+		res.Output = []byte{}
+	}
+	var err error
+	if t.trace != nil {
+		if res.Trace, err = t.trace.GetResult(); err != nil {
+			return nil, err
+		}
+	}
+	if t.stateDiff != nil {
+		if res.StateDiff, err = t.stateDiff.GetResult(); err != nil {
+			return nil, err
+		}
+	}
+	if t.vmTrace != nil {
+		if t.reason != nil {
+			return nil, t.reason
+		}
+		res.VMTrace = t.vmTrace.root
+	}
+	return res, nil
+}
+
+func (t *replayTracer) CapturePreEVM(env *vm.EVM) {
+	for _, tracer := range t.tracers {
+		if capturer, ok := tracer.(vm.EVMLogger_StateCapturer); ok {
+			capturer.CapturePreEVM(env)
+		}
+	}
+}
+
+func (t *replayTracer) CaptureTxStart(gasLimit uint64) {
+	for _, tracer := range t.tracers {
+		tracer.CaptureTxStart(gasLimit)
+	}
+}
+
+func (t *replayTracer) CaptureTxEnd(restGas uint64) {
+	for _, tracer := range t.tracers {
+		tracer.CaptureTxEnd(restGas)
+	}
+}
+
+func (t *replayTracer) CaptureStart(env *vm.EVM, from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) {
+	for _, tracer := range t.tracers {
+		tracer.CaptureStart(env, from, to, create, input, gas, value)
+	}
+}
+
+func (t *replayTracer) CaptureEnd(output []byte, gasUsed uint64, err error) {
+	for _, tracer := range t.tracers {
+		tracer.CaptureEnd(output, gasUsed, err)
+	}
+}
+
+func (t *replayTracer) CaptureEnter(typ vm.OpCode, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) {
+	for _, tracer := range t.tracers {
+		tracer.CaptureEnter(typ, from, to, input, gas, value)
+	}
+}
+
+func (t *replayTracer) CaptureExit(output []byte, gasUsed uint64, err error) {
+	for _, tracer := range t.tracers {
+		tracer.CaptureExit(output, gasUsed, err)
+	}
+}
+
+func (t *replayTracer) CaptureState(pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, rData []byte, depth int, err error) {
+	for _, tracer := range t.tracers {
+		tracer.CaptureState(pc, op, gas, cost, scope, rData, depth, err)
+	}
+}
+
+func (t *replayTracer) CaptureFault(pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, depth int, err error) {
+	for _, tracer := range t.tracers {
+		tracer.CaptureFault(pc, op, gas, cost, scope, depth, err)
+	}
+}
+
+// GetResult is unused, the results are assembled by result.
+func (t *replayTracer) GetResult() (json.RawMessage, error) {
+	return nil, nil
+}
+
+func (t *replayTracer) Stop(err error) {
+	t.reason = err
+	for _, tracer := range t.tracers {
+		tracer.Stop(err)
+	}
+}
+
+// vmTracer builds a vmTrace during the execution. Only the outcome of each
+// instruction is recorded: the items it pushed, the storage slot and the memory
+// area it wrote, rather than the full state of the frame at every step.
+type vmTracer struct {
+	env    *vm.EVM
+	table  vm.JumpTable
+	root   *ParityVMTrace
+	frames []*vmFrame // Call frames being executed, innermost last
+}
+
+// vmFrame is a call frame being traced.
+type vmFrame struct {
+	trace *ParityVMTrace
+
+	// Last instruction executed, its outcome being known at the next step of the
+	// frame, or at its exit
+	last    *ParityVMTraceOp
+	gas     uint64 // Gas left before the last instruction
+	pushed  int    // Number of items pushed by the last instruction
+	memOff  uint64 // Memory area written by the last instruction
+	memSize uint64
+}
+
+// enter starts tracing a call frame running the given code.
+func (t *vmTracer) enter(code []byte) {
+	frame := &vmFrame{trace: &ParityVMTrace{Code: common.CopyBytes(code), Ops: []*ParityVMTraceOp{}}}
+	if t.root == nil {
+		t.root = frame.trace
+	}
+	t.frames = append(t.frames, frame)
+}
+
+// exit completes the trace of the innermost call frame. The calls to accounts
+// without code have no instructions to report, and no trace.
+func (t *vmTracer) exit() {
+	if len(t.frames) == 0 {
+		return
+	}
+	frame := t.frames[len(t.frames)-1]
+	t.frames = t.frames[:len(t.frames)-1]
+	if frame.last != nil && frame.gas > frame.last.Cost {
+		frame.last.Ex.Used = frame.gas - frame.last.Cost
+	}
+	if len(t.frames) > 0 && len(frame.trace.Ops) > 0 {
+		if parent := t.frames[len(t.frames)-1]; parent.last != nil {
+			parent.last.Sub = frame.trace
+		}
+	}
+}
+
+// calledCode returns the code run by a call frame: the init code of creations,
+// or the code of the called account before its execution.
+func (t *vmTracer) calledCode(create bool, to common.Address, input []byte) []byte {
+	if create {
+		return input
+	}
+	return t.env.StateDB.GetCode(to)
+}
+
+func (t *vmTracer) CaptureTxStart(gasLimit uint64) {}
+
+func (t *vmTracer) CaptureTxEnd(restGas uint64) {}
+
+func (t *vmTracer) CaptureStart(env *vm.EVM, from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) {
+	t.env = env
+	t.enter(t.calledCode(create, to, input))
+}
+
+func (t *vmTracer) CaptureEnd(output []byte, gasUsed uint64, err error) {
+	t.exit()
+}
+
+func (t *vmTracer) CaptureEnter(typ vm.OpCode, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) {
+	t.enter(t.calledCode(typ == vm.CREATE || typ == vm.CREATE2, to, input))
+}
+
+func (t *vmTracer) CaptureExit(output []byte, gasUsed uint64, err error) {
+	t.exit()
+}
+
+// CaptureState completes the outcome of the previous instruction of the frame
+// from its current state, and records the instruction about to be executed.
+func (t *vmTracer) CaptureState(pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, rData []byte, depth int, err error) {
+	if len(t.frames) == 0 {
+		return
+	}
+	var (
+		frame  = t.frames[len(t.frames)-1]
+		stack  = scope.Stack.Data()
+		memory = scope.Memory.Data()
+	)
+	if last := frame.last; last != nil {
+		last.Ex.Used = gas
+		if frame.pushed > 0 && frame.pushed <= len(stack) {
+			for _, item := range stack[len(stack)-frame.pushed:] {
+				last.Ex.Push = append(last.Ex.Push, (*hexutil.Big)(item.ToBig()))
+			}
+		}
+		if end := frame.memOff + frame.memSize; frame.memSize > 0 && end >= frame.memOff && end <= uint64(len(memory)) {
+			last.Ex.Mem = &ParityVMTraceMem{Data: common.CopyBytes(memory[frame.memOff:end]), Off: frame.memOff}
+		}
+	}
+	next := &ParityVMTraceOp{Cost: cost, Pc: pc, Ex: &ParityVMTraceEx{Push: []*hexutil.Big{}}}
+	if op == vm.SSTORE && len(stack) >= 2 {
+		next.Ex.Store = &ParityVMTraceStore{
+			Key: (*hexutil.Big)(stack[len(stack)-1].ToBig()),
+			Val: (*hexutil.Big)(stack[len(stack)-2].ToBig()),
+		}
+	}
+	frame.trace.Ops = append(frame.trace.Ops, next)
+	frame.last, frame.gas = next, gas
+
+	// Items pushed by the instruction, as defined by its stack requirements
+	frame.pushed = 0
+	if instr := t.table[op]; instr != nil {
+		pops, limit := instr.Stack()
+		frame.pushed = int(vars.StackLimit) + pops - limit
+	}
+	frame.memOff, frame.memSize, _ = memoryWritten(op, stack)
+}
+
+func (t *vmTracer) CaptureFault(pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, depth int, err error) {
+}
+
+// GetResult is unused, the vmTrace is assembled by the replay tracer.
+func (t *vmTracer) GetResult() (json.RawMessage, error) {
+	return nil, nil
+}
+
+func (t *vmTracer) Stop(err error) {}
+
+// peekStack returns the n-th item from the top of the stack, if it exists and
+// fits in 64 bits.
+func peekStack(stack []uint256.Int, n int) (uint64, bool) {
+	if len(stack) <= n {
+		return 0, false
+	}
+	item := stack[len(stack)-1-n]
+	return item.Uint64(), item.IsUint64()
+}
+
+// memoryWritten returns the memory area written by an instruction, given the
+// stack before its execution.
+func memoryWritten(op vm.OpCode, stack []uint256.Int) (uint64, uint64, bool) {
+	var offArg, sizeArg int
+	switch op {
+	case vm.MSTORE:
+		off, ok := peekStack(stack, 0)
+		return off, 32, ok
+	case vm.MSTORE8:
+		off, ok := peekStack(stack, 0)
+		return off, 1, ok
+	case vm.CALLDATACOPY, vm.CODECOPY, vm.RETURNDATACOPY, vm.MCOPY:
+		offArg, sizeArg = 0, 2
+	case vm.EXTCODECOPY:
+		offArg, sizeArg = 1, 3
+	case vm.CALL, vm.CALLCODE:
+		offArg, sizeArg = 5, 6
+	case vm.DELEGATECALL, vm.STATICCALL:
+		offArg, sizeArg = 4, 5
+	default:
+		return 0, 0, false
+	}
+	off, okOff := peekStack(stack, offArg)
+	size, okSize := peekStack(stack, sizeArg)
+	return off, size, okOff && okSize
+}
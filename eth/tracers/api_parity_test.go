@@ -1,7 +1,19 @@
 package tracers
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"math/big"
 	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/params/types/genesisT"
+	"github.com/ethereum/go-ethereum/params/vars"
 )
 
 // BenchmarkTraceResultsAppend1 compares performance against BenchmarkTraceResultsAppend2,
@@ -39,3 +51,271 @@ func BenchmarkTraceResultsAppend2(b *testing.B) {
 		results = append(results, traceResults...) // nolint:ineffassign,staticcheck
 	}
 }
+
+// TestTraceBlockHaloRewards tests that the reward traces of a block account for
+// every balance change made by the block finalization.
+func TestTraceBlockHaloRewards(t *testing.T) {
+	accounts := newAccounts(2)
+	miner := common.HexToAddress("0xaaaa")
+	config := params.HaloChainConfig
+	genesis := &genesisT.Genesis{
+		Config:   config,
+		GasLimit: 8_000_000,
+		Alloc: genesisT.GenesisAlloc{
+			accounts[0].addr: {Balance: big.NewInt(vars.Ether)},
+		},
+	}
+	signer := types.LatestSigner(config)
+	backend := newTestBackend(t, 2, genesis, func(i int, b *core.BlockGen) {
+		b.SetCoinbase(miner)
+		// No tip, so that the balance of the miner only changes by the rewards
+		tx, _ := types.SignTx(types.NewTx(&types.DynamicFeeTx{
+			ChainID:   config.GetChainID(),
+			Nonce:     uint64(i),
+			GasFeeCap: b.BaseFee(),
+			Gas:       vars.TxGas,
+			To:        &accounts[1].addr,
+			Value:     big.NewInt(1000),
+		}), signer, accounts[0].key)
+		b.AddTx(tx)
+	})
+	defer backend.teardown()
+	api := NewTraceAPI(NewAPI(backend))
+
+	block := backend.chain.GetBlockByNumber(2)
+	traces, err := api.traceBlockRewards(context.Background(), block, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rewards := make(map[common.Address]*big.Int)
+	rewardTypes := make(map[string]int)
+	for _, trace := range traces {
+		if trace.Type != "reward" || trace.BlockNumber != 2 {
+			t.Fatalf("unexpected trace: %+v", trace)
+		}
+		if rewards[*trace.Action.Author] == nil {
+			rewards[*trace.Action.Author] = new(big.Int)
+		}
+		rewards[*trace.Action.Author].Add(rewards[*trace.Action.Author], trace.Action.Value.ToInt())
+		rewardTypes[trace.Action.RewardType]++
+	}
+	if rewardTypes["block"] != 1 || rewardTypes["baseFeeShare"] != 3 {
+		t.Fatalf("unexpected reward types: %v", rewardTypes)
+	}
+	parent, _ := backend.chain.StateAt(backend.chain.GetHeaderByNumber(1).Root)
+	statedb, _ := backend.chain.StateAt(backend.chain.GetHeaderByNumber(2).Root)
+	for _, addr := range []common.Address{miner, params.HaloEcosystemFundAddress, params.HaloReserveFundAddress} {
+		delta := new(big.Int).Sub(statedb.GetBalance(addr).ToBig(), parent.GetBalance(addr).ToBig())
+		if rewards[addr] == nil || rewards[addr].Cmp(delta) != 0 {
+			t.Errorf("reward mismatch for %v: have %v, want %v", addr, rewards[addr], delta)
+		}
+	}
+	// The base fees of proof-of-stake blocks are burned, not distributed
+	header := block.Header()
+	header.Difficulty = new(big.Int)
+	credits, err := api.traceBlockFeeDistribution(context.Background(), block.WithSeal(header), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(credits) != 0 {
+		t.Errorf("unexpected base fee credits of proof-of-stake block: %+v", credits)
+	}
+}
+
+// TestFilterBlockTraces tests that trace_filter flattens the reward traces of a
+// block into its list of traces, next to the transaction traces.
+func TestFilterBlockTraces(t *testing.T) {
+	genesis := &genesisT.Genesis{Config: params.HaloChainConfig, GasLimit: 8_000_000}
+	backend := newTestBackend(t, 1, genesis, func(i int, b *core.BlockGen) {
+		b.SetCoinbase(common.HexToAddress("0xaaaa"))
+	})
+	defer backend.teardown()
+	api := NewTraceAPI(NewAPI(backend))
+	config := setTraceConfigDefaultTracer(nil)
+
+	block := backend.chain.GetBlockByNumber(1)
+	result := &blockTraceResult{
+		Block: hexutil.Uint64(block.NumberU64()),
+		Hash:  block.Hash(),
+		Traces: []*txTraceResult{
+			{TxHash: common.Hash{1}, Result: json.RawMessage(`[{"type":"call"},{"type":"call"}]`)},
+		},
+	}
+	traces, err := api.filterBlockTraces(block, result, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The two call traces, followed by the block reward
+	if len(traces) != 3 {
+		t.Fatalf("trace count mismatch: have %d, want 3", len(traces))
+	}
+	if reward, ok := traces[2].(*ParityTrace); !ok || reward.Type != "reward" || reward.Action.RewardType != "block" {
+		t.Errorf("unexpected reward trace: %+v", traces[2])
+	}
+
+	// A failed transaction trace fails the block
+	result.Traces = append(result.Traces, &txTraceResult{Error: "failed"})
+	if traces, err := api.filterBlockTraces(block, result, config); err == nil || err.Error() != "failed" || traces != nil {
+		t.Errorf("unexpected result of failed block: %v, %v", traces, err)
+	}
+}
+
+// TestReplayVMTrace tests that the vmTrace of a replayed transaction reports
+// the instructions of every call frame, with their effects.
+func TestReplayVMTrace(t *testing.T) {
+	t.Parallel()
+
+	var (
+		accounts = newAccounts(1)
+		callee   = common.HexToAddress("0xcccc")
+		caller   = common.HexToAddress("0xdddd")
+		// Returns the word 1
+		calleeCode = common.FromHex("600160005260206000f3")
+		// Stores 0x2a in slot 0, writes 0x2b to memory and calls the callee,
+		// copying its output over it
+		callerCode = common.FromHex("602a600055602b6000526020600060006000600073" + common.Bytes2Hex(callee[:]) + "61fffff100")
+	)
+	genesis := &genesisT.Genesis{
+		Config: params.TestChainConfig,
+		Alloc: genesisT.GenesisAlloc{
+			accounts[0].addr: {Balance: big.NewInt(vars.Ether)},
+			callee:           {Code: calleeCode},
+			caller:           {Code: callerCode},
+		},
+	}
+	var target common.Hash
+	backend := newTestBackend(t, 1, genesis, func(i int, b *core.BlockGen) {
+		tx, _ := types.SignTx(types.NewTx(&types.LegacyTx{
+			Nonce:    uint64(i),
+			To:       &caller,
+			Gas:      200000,
+			GasPrice: b.BaseFee(),
+		}), types.HomesteadSigner{}, accounts[0].key)
+		b.AddTx(tx)
+		target = tx.Hash()
+	})
+	defer backend.chain.Stop()
+	api := NewTraceAPI(NewAPI(backend))
+
+	res, err := api.ReplayTransaction(context.Background(), target, []string{"vmTrace"})
+	if err != nil {
+		t.Fatalf("failed to replay transaction: %v", err)
+	}
+	if res.Trace != nil || res.StateDiff != nil || res.VMTrace == nil {
+		t.Fatalf("unexpected trace types: %+v", res)
+	}
+	trace := res.VMTrace
+	if !bytes.Equal(trace.Code, callerCode) || len(trace.Ops) != 15 {
+		t.Fatalf("caller trace mismatch: code %x, %d ops", trace.Code, len(trace.Ops))
+	}
+	word := func(n int64) []byte { return common.BigToHash(big.NewInt(n)).Bytes() }
+
+	if push := trace.Ops[0].Ex.Push; len(push) != 1 || push[0].ToInt().Int64() != 0x2a {
+		t.Errorf("push mismatch: have %v, want [0x2a]", push)
+	}
+	if store := trace.Ops[2].Ex.Store; store == nil || store.Key.ToInt().Sign() != 0 || store.Val.ToInt().Int64() != 0x2a {
+		t.Errorf("store mismatch: have %+v", store)
+	}
+	if mem := trace.Ops[5].Ex.Mem; mem == nil || mem.Off != 0 || !bytes.Equal(mem.Data, word(0x2b)) {
+		t.Errorf("memory mismatch: have %+v", mem)
+	}
+	call := trace.Ops[13]
+	if call.Sub == nil || !bytes.Equal(call.Sub.Code, calleeCode) || len(call.Sub.Ops) != 6 {
+		t.Fatalf("callee trace mismatch: %+v", call.Sub)
+	}
+	if push := call.Ex.Push; len(push) != 1 || push[0].ToInt().Int64() != 1 {
+		t.Errorf("call result mismatch: have %v, want [1]", push)
+	}
+	if mem := call.Ex.Mem; mem == nil || mem.Off != 0 || !bytes.Equal(mem.Data, word(1)) {
+		t.Errorf("call output mismatch: have %+v", mem)
+	}
+	if call.Ex.Used >= trace.Ops[12].Ex.Used || call.Ex.Used != trace.Ops[14].Ex.Used {
+		t.Errorf("call gas mismatch: used %d", call.Ex.Used)
+	}
+	for i, op := range trace.Ops {
+		if i != 13 && op.Sub != nil {
+			t.Errorf("op %d: unexpected sub trace", i)
+		}
+	}
+	// The transactions of a block are replayed in order
+	results, err := api.ReplayBlockTransactions(context.Background(), 1, []string{"vmTrace"})
+	if err != nil {
+		t.Fatalf("failed to replay block: %v", err)
+	}
+	if len(results) != 1 || *results[0].TransactionHash != target || len(results[0].VMTrace.Ops) != 15 {
+		t.Fatalf("block replay mismatch: %+v", results)
+	}
+	if _, err := api.ReplayTransaction(context.Background(), target, []string{"unknown"}); err == nil {
+		t.Fatal("unknown trace type accepted")
+	}
+	// Raw transactions are traced in a pending block on top of the head
+	tx, _ := types.SignTx(types.NewTx(&types.DynamicFeeTx{
+		ChainID:   params.TestChainConfig.GetChainID(),
+		Nonce:     1,
+		To:        &caller,
+		Gas:       200000,
+		GasFeeCap: big.NewInt(vars.InitialBaseFee),
+	}), types.LatestSigner(params.TestChainConfig), accounts[0].key)
+	raw, _ := tx.MarshalBinary()
+	res, err = api.RawTransaction(context.Background(), raw, []string{"vmTrace"})
+	if err != nil {
+		t.Fatalf("failed to trace raw transaction: %v", err)
+	}
+	if !bytes.Equal(res.VMTrace.Code, callerCode) || len(res.VMTrace.Ops) != 15 || res.VMTrace.Ops[13].Sub == nil {
+		t.Fatalf("raw transaction trace mismatch: %+v", res.VMTrace)
+	}
+}
+
+func TestReplayVMTraceCreate(t *testing.T) {
+	t.Parallel()
+
+	var (
+		accounts = newAccounts(1)
+		creator  = common.HexToAddress("0xdddd")
+		// Deploys the code 0x00
+		initCode = common.FromHex("600060005360016000f3")
+		// Creates a contract with the init code
+		creatorCode = common.FromHex("69" + common.Bytes2Hex(initCode) + "600052600a60166000f000")
+	)
+	genesis := &genesisT.Genesis{
+		Config: params.TestChainConfig,
+		Alloc: genesisT.GenesisAlloc{
+			accounts[0].addr: {Balance: big.NewInt(vars.Ether)},
+			creator:          {Code: creatorCode},
+		},
+	}
+	var target common.Hash
+	backend := newTestBackend(t, 1, genesis, func(i int, b *core.BlockGen) {
+		tx, _ := types.SignTx(types.NewTx(&types.LegacyTx{
+			Nonce:    uint64(i),
+			To:       &creator,
+			Gas:      200000,
+			GasPrice: b.BaseFee(),
+		}), types.HomesteadSigner{}, accounts[0].key)
+		b.AddTx(tx)
+		target = tx.Hash()
+	})
+	defer backend.chain.Stop()
+	api := NewTraceAPI(NewAPI(backend))
+
+	res, err := api.ReplayTransaction(context.Background(), target, []string{"vmTrace"})
+	if err != nil {
+		t.Fatalf("failed to replay transaction: %v", err)
+	}
+	trace := res.VMTrace
+	if trace == nil || !bytes.Equal(trace.Code, creatorCode) || len(trace.Ops) != 8 {
+		t.Fatalf("creator trace mismatch: %+v", trace)
+	}
+	// The creation frame runs the init code, not the code it deployed
+	create := trace.Ops[6]
+	if create.Sub == nil || !bytes.Equal(create.Sub.Code, initCode) || len(create.Sub.Ops) != 6 {
+		t.Fatalf("creation trace mismatch: %+v", create.Sub)
+	}
+	if push := create.Ex.Push; len(push) != 1 || push[0].ToInt().Sign() == 0 {
+		t.Errorf("created address mismatch: have %v", push)
+	}
+	// Only the byte written by MSTORE8 is reported
+	if mem := create.Sub.Ops[2].Ex.Mem; mem == nil || mem.Off != 0 || !bytes.Equal(mem.Data, []byte{0}) {
+		t.Errorf("memory mismatch: have %+v", mem)
+	}
+}
@@ -1145,10 +1145,12 @@ trace_traceTransaction
 var allRPCMethods = []string{
 	"admin_addPeer",
 	"admin_addTrustedPeer",
+	"admin_banPeer",
 	"admin_datadir",
 	"admin_ecbp1100",
 	"admin_exportChain",
 	"admin_importChain",
+	"admin_listBans",
 	"admin_maxPeers",
 	"admin_nodeInfo",
 	"admin_peers",
@@ -1161,6 +1163,7 @@ var allRPCMethods = []string{
 	"admin_stopHTTP",
 	"admin_stopRPC",
 	"admin_stopWS",
+	"admin_unbanPeer",
 	"debug_accountRange",
 	"debug_blockProfile",
 	"debug_chaindbCompact",
@@ -1247,6 +1250,7 @@ var allRPCMethods = []string{
 	"eth_getRawTransactionByBlockNumberAndIndex",
 	"eth_getRawTransactionByHash",
 	"eth_getStorageAt",
+	"eth_getStratumWorkers",
 	"eth_getTransactionByBlockHashAndIndex",
 	"eth_getTransactionByBlockNumberAndIndex",
 	"eth_getTransactionByHash",
@@ -1274,6 +1278,7 @@ var allRPCMethods = []string{
 	"eth_sendTransaction",
 	"eth_sign",
 	"eth_signTransaction",
+	"eth_simulateV1",
 	"eth_submitHashrate",
 	"eth_submitWork",
 	"eth_subscribe",
@@ -1281,9 +1286,13 @@ var allRPCMethods = []string{
 	"eth_uninstallFilter",
 	"eth_unsubscribe",
 	"ethash_getHashrate",
+	"ethash_getStratumWorkers",
 	"ethash_getWork",
 	"ethash_submitHashrate",
 	"ethash_submitWork",
+	"halo_getBlockRewards",
+	"halo_getFundBalances",
+	"halo_getSupplyDelta",
 	"miner_setEtherbase",
 	"miner_setExtra",
 	"miner_setGasLimit",
@@ -1312,11 +1321,17 @@ var allRPCMethods = []string{
 	"trace_call",
 	"trace_callMany",
 	"trace_filter",
+	"trace_get",
+	"trace_rawTransaction",
+	"trace_replayBlockTransactions",
+	"trace_replayTransaction",
 	"trace_subscribe",
 	"trace_transaction",
 	"trace_unsubscribe",
 	"txpool_content",
+	"txpool_events",
 	"txpool_contentFrom",
+	"txpool_history",
 	"txpool_inspect",
 	"txpool_status",
 	"web3_clientVersion",
@@ -42,6 +42,7 @@ import (
 	"github.com/ethereum/go-ethereum/miner"
 	"github.com/ethereum/go-ethereum/node"
 	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/ethereum/go-ethereum/params/types/ctypes"
 	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/gorilla/websocket"
 )
@@ -77,7 +78,9 @@ type fullNodeBackend interface {
 	backend
 	BlockByNumber(ctx context.Context, number rpc.BlockNumber) (*types.Block, error)
 	CurrentBlock() *types.Header
+	GetReceipts(ctx context.Context, hash common.Hash) (types.Receipts, error)
 	SuggestGasTipCap(ctx context.Context) (*big.Int, error)
+	ChainConfig() ctypes.ChainConfigurator
 }
 
 // miningNodeBackend encompasses the functionality necessary for a mining node
@@ -103,6 +106,8 @@ type Service struct {
 
 	headSub event.Subscription
 	txSub   event.Subscription
+
+	window blockWindow // Rolling window of the recent blocks for the extended reports
 }
 
 // connWrapper is a wrapper to prevent concurrent-write or concurrent-read on the
@@ -121,7 +126,8 @@ type Service struct {
 //
 // The Close and WriteControl methods can be called concurrently with all other methods.
 type connWrapper struct {
-	conn *websocket.Conn
+	conn     *websocket.Conn
+	extended bool // Whether the server accepts the extended reports
 
 	rlock sync.Mutex
 	wlock sync.Mutex
@@ -345,6 +351,9 @@ func (s *Service) loop(chainHeadCh chan core.ChainHeadEvent, txEventCh chan core
 					if err = s.reportPending(conn); err != nil {
 						log.Warn("Post-block transaction stats report failed", "err", err)
 					}
+					if err = s.reportExtended(conn, head); err != nil {
+						log.Warn("Extended stats report failed", "err", err)
+					}
 				case <-txCh:
 					if err = s.reportPending(conn); err != nil {
 						log.Warn("Transaction stats report failed", "err", err)
@@ -463,6 +472,7 @@ type nodeInfo struct {
 	OsVer    string `json:"os_v"`
 	Client   string `json:"client"`
 	History  bool   `json:"canUpdateHistory"`
+	Extended bool   `json:"canReportExtended"`
 }
 
 // authMsg is the authentication infos needed to login to a monitoring server.
@@ -500,6 +510,7 @@ func (s *Service) login(conn *connWrapper) error {
 			OsVer:    runtime.GOARCH,
 			Client:   "0.1.1",
 			History:  true,
+			Extended: true,
 		},
 		Secret: s.pass,
 	}
@@ -509,11 +520,13 @@ func (s *Service) login(conn *connWrapper) error {
 	if err := conn.WriteJSON(login); err != nil {
 		return err
 	}
-	// Retrieve the remote ack or connection termination
+	// Retrieve the remote ack or connection termination. Servers accepting the
+	// extended reports say so in the ack.
 	var ack map[string][]string
-	if err := conn.ReadJSON(&ack); err != nil || len(ack["emit"]) != 1 || ack["emit"][0] != "ready" {
+	if err := conn.ReadJSON(&ack); err != nil || len(ack["emit"]) < 1 || len(ack["emit"]) > 2 || ack["emit"][0] != "ready" {
 		return errors.New("unauthorized")
 	}
+	conn.extended = len(ack["emit"]) == 2 && ack["emit"][1] == extendedAck
 	return nil
 }
 
@@ -533,6 +546,9 @@ func (s *Service) report(conn *connWrapper) error {
 	if err := s.reportStats(conn); err != nil {
 		return err
 	}
+	if err := s.reportExtended(conn, nil); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -0,0 +1,261 @@
+// Copyright 2025 The core-geth Authors
+// This file is part of the core-geth library.
+//
+// The core-geth library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The core-geth library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the core-geth library. If not, see <http://www.gnu.org/licenses/>.
+
+package ethstats
+
+import (
+	"bytes"
+	"context"
+	"math/big"
+	"sort"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/misc/eip1559"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// extendedAck is the optional argument of the login acknowledgement, sent by the
+// servers accepting the extended reports. Standard servers only acknowledge with
+// "ready" and are never sent the extended reports.
+const extendedAck = "extended"
+
+// windowStats is the average block time over a window of recent blocks.
+type windowStats struct {
+	Blocks    int     `json:"blocks"`
+	BlockTime float64 `json:"blockTime"` // Seconds
+}
+
+// minerStats is the number of blocks mined by a miner over a window of recent
+// blocks.
+type minerStats struct {
+	Miner  common.Address `json:"miner"`
+	Blocks int            `json:"blocks"`
+}
+
+// extendedStats is the information to report about the health of the network
+// at a block, computed over the Halo difficulty windows ending at it.
+type extendedStats struct {
+	Number     *big.Int      `json:"number"`
+	Hash       common.Hash   `json:"hash"`
+	BaseFee    string        `json:"baseFee,omitempty"` // Empty before London
+	Burned     string        `json:"burned,omitempty"`  // Base fees burned by the block
+	UncleRate  float64       `json:"uncleRate"`         // Uncles per block over the longest window
+	BlockTimes []windowStats `json:"blockTimes"`        // Average block times of the complete windows
+	Miners     []minerStats  `json:"miners"`            // Blocks per miner over the longest window, most first
+	Emergency  bool          `json:"emergencyMode"`     // Whether the next difficulty is in emergency mode
+}
+
+// reportExtended reports the extended network metrics at the given block, the
+// current head if nil, to the servers accepting them.
+func (s *Service) reportExtended(conn *connWrapper, block *types.Block) error {
+	if !conn.extended {
+		return nil
+	}
+	details := s.assembleExtendedStats(block)
+	if details == nil {
+		return nil
+	}
+	log.Trace("Sending extended stats to ethstats", "number", details.Number, "hash", details.Hash)
+
+	stats := map[string]interface{}{
+		"id":    s.node,
+		"stats": details,
+	}
+	report := map[string][]interface{}{
+		"emit": {"extended-stats", stats},
+	}
+	return conn.WriteJSON(report)
+}
+
+// haloWindows are the lengths, in blocks, of the windows averaged by the Halo
+// difficulty algorithm, shortest first.
+var haloWindows = []int{15, 75, 150}
+
+// Emergency mode parameters of the Halo difficulty algorithm.
+const (
+	haloEmergencyBlocks    = 10 // Number of blocks inspected for emergency mode
+	haloEmergencyBlockTime = 60 // Average block time, in seconds, above which the mode is on
+)
+
+// windowBlock is a block tracked by the rolling window of recent blocks.
+type windowBlock struct {
+	header *types.Header
+	uncles int
+}
+
+// blockWindow is a rolling window of the most recent blocks, oldest first. It is
+// updated incrementally, only the blocks it doesn't track yet are retrieved.
+type blockWindow struct {
+	blocks []windowBlock
+	lock   sync.Mutex
+}
+
+// update moves the window to end at the given block, keeping at most size blocks,
+// and returns the blocks of the window. Blocks not yet tracked are retrieved
+// from the backend, until an ancestor is found in the window or is unknown.
+func (w *blockWindow) update(backend fullNodeBackend, block *types.Block, size int) []windowBlock {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	tracked := make(map[common.Hash]int, len(w.blocks))
+	for i, b := range w.blocks {
+		tracked[b.header.Hash()] = i
+	}
+	// Gather the untracked blocks, newest first
+	var (
+		fresh = []windowBlock{{header: block.Header(), uncles: len(block.Uncles())}}
+		kept  []windowBlock
+	)
+	if i, ok := tracked[block.Hash()]; ok {
+		fresh, kept = nil, w.blocks[:i+1]
+	}
+	for fresh != nil && len(fresh) < size {
+		child := fresh[len(fresh)-1].header
+		if i, ok := tracked[child.ParentHash]; ok {
+			kept = w.blocks[:i+1]
+			break
+		}
+		if child.Number.Sign() == 0 {
+			break
+		}
+		parent, _ := backend.HeaderByNumber(context.Background(), rpc.BlockNumber(child.Number.Uint64()-1))
+		if parent == nil || parent.Hash() != child.ParentHash {
+			break // Unknown or reorged ancestor
+		}
+		var uncles int
+		if parent.UncleHash != types.EmptyUncleHash {
+			if ancestor, _ := backend.BlockByNumber(context.Background(), rpc.BlockNumber(parent.Number.Uint64())); ancestor != nil && ancestor.Hash() == parent.Hash() {
+				uncles = len(ancestor.Uncles())
+			}
+		}
+		fresh = append(fresh, windowBlock{header: parent, uncles: uncles})
+	}
+	blocks := make([]windowBlock, 0, len(kept)+len(fresh))
+	blocks = append(blocks, kept...)
+	for i := len(fresh) - 1; i >= 0; i-- {
+		blocks = append(blocks, fresh[i])
+	}
+	if len(blocks) > size {
+		blocks = blocks[len(blocks)-size:]
+	}
+	w.blocks = blocks
+	return blocks
+}
+
+// haloEmergencyMode reports whether the difficulty of the child of the first of
+// the given headers, followed by its ancestors, is computed in emergency mode,
+// that is whether the average block time over the most recent blocks exceeds 60
+// seconds.
+func haloEmergencyMode(headers []*types.Header) bool {
+	if len(headers) < haloEmergencyBlocks || headers[0].Number.Uint64() < haloEmergencyBlocks {
+		return false
+	}
+	newest, oldest := headers[0], headers[haloEmergencyBlocks-1]
+	return (newest.Time-oldest.Time)/(haloEmergencyBlocks-1) > haloEmergencyBlockTime
+}
+
+// assembleExtendedStats updates the window of recent blocks to end at the given
+// one and assembles the extended stats. If block is nil, the current head is
+// processed.
+func (s *Service) assembleExtendedStats(block *types.Block) *extendedStats {
+	// Light nodes would need on-demand lookups for uncles, skip
+	fullBackend, ok := s.backend.(fullNodeBackend)
+	if !ok {
+		return nil
+	}
+	if block == nil {
+		head := fullBackend.CurrentBlock()
+		block, _ = fullBackend.BlockByNumber(context.Background(), rpc.BlockNumber(head.Number.Uint64()))
+	}
+	if block == nil {
+		return nil
+	}
+	// Track one more block than the longest window to measure its block times
+	longest := haloWindows[len(haloWindows)-1]
+	window := s.window.update(fullBackend, block, longest+1)
+
+	// Order the headers newest first
+	var (
+		headers = make([]*types.Header, len(window))
+		uncles  int
+	)
+	for i, b := range window {
+		headers[len(window)-1-i] = b.header
+		if len(window)-1-i < longest {
+			uncles += b.uncles
+		}
+	}
+	header := headers[0]
+	stats := &extendedStats{
+		Number:     header.Number,
+		Hash:       header.Hash(),
+		UncleRate:  float64(uncles) / float64(min(len(headers), longest)),
+		BlockTimes: []windowStats{},
+	}
+	// Average block times of the windows with all their blocks known
+	for _, window := range haloWindows {
+		if len(headers) <= window {
+			break
+		}
+		stats.BlockTimes = append(stats.BlockTimes, windowStats{
+			Blocks:    window,
+			BlockTime: float64(header.Time-headers[window].Time) / float64(window),
+		})
+	}
+	// Share of the miners over the longest window
+	mined := make(map[common.Address]int)
+	for _, h := range headers[:min(len(headers), longest)] {
+		author, _ := s.engine.Author(h)
+		mined[author]++
+	}
+	stats.Miners = make([]minerStats, 0, len(mined))
+	for miner, blocks := range mined {
+		stats.Miners = append(stats.Miners, minerStats{Miner: miner, Blocks: blocks})
+	}
+	sort.Slice(stats.Miners, func(i, j int) bool {
+		if stats.Miners[i].Blocks != stats.Miners[j].Blocks {
+			return stats.Miners[i].Blocks > stats.Miners[j].Blocks
+		}
+		return bytes.Compare(stats.Miners[i].Miner[:], stats.Miners[j].Miner[:]) < 0
+	})
+	// Base fees burned, unless distributed by the proof-of-work finalization
+	config := fullBackend.ChainConfig()
+	if header.BaseFee != nil {
+		var receipts types.Receipts
+		if eip1559.IsHaloContractFeeSharingEnabled(config, header.Number) && header.GasUsed > 0 {
+			var err error
+			if receipts, err = fullBackend.GetReceipts(context.Background(), header.Hash()); err != nil {
+				log.Warn("Failed to retrieve block receipts", "number", header.Number, "err", err)
+				return nil
+			}
+		}
+		credits, err := eip1559.HaloBlockBaseFeeCredits(config, header, receipts)
+		if err != nil {
+			log.Warn("Failed to distribute base fees", "number", header.Number, "err", err)
+			return nil
+		}
+		stats.BaseFee = header.BaseFee.String()
+		stats.Burned = credits.Burned.Dec()
+	}
+	next := new(big.Int).Add(header.Number, common.Big1)
+	if config.IsEnabled(config.GetHaloDifficultyTransition, next) || config.IsEnabled(config.GetHaloDifficultyV2Transition, next) {
+		stats.Emergency = haloEmergencyMode(headers)
+	}
+	return stats
+}
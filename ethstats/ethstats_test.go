@@ -17,8 +17,32 @@
 package ethstats
 
 import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
 	"strconv"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/consensus/misc/eip1559"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	ethproto "github.com/ethereum/go-ethereum/eth/protocols/eth"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/params/types/ctypes"
+	"github.com/ethereum/go-ethereum/params/types/genesisT"
+	"github.com/ethereum/go-ethereum/params/vars"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/gorilla/websocket"
 )
 
 func TestParseEthstatsURL(t *testing.T) {
@@ -80,3 +104,316 @@ func TestParseEthstatsURL(t *testing.T) {
 		}
 	}
 }
+
+// testBackend is a full node backend serving a pregenerated chain.
+type testBackend struct {
+	config   ctypes.ChainConfigurator
+	blocks   []*types.Block // Blocks by number, genesis included
+	headFeed event.Feed
+	txFeed   event.Feed
+	lookups  int // Number of blocks and headers retrieved by number
+}
+
+func (b *testBackend) SubscribeChainHeadEvent(ch chan<- core.ChainHeadEvent) event.Subscription {
+	return b.headFeed.Subscribe(ch)
+}
+
+func (b *testBackend) SubscribeNewTxsEvent(ch chan<- core.NewTxsEvent) event.Subscription {
+	return b.txFeed.Subscribe(ch)
+}
+
+func (b *testBackend) CurrentHeader() *types.Header {
+	return b.blocks[len(b.blocks)-1].Header()
+}
+
+func (b *testBackend) CurrentBlock() *types.Header {
+	return b.CurrentHeader()
+}
+
+func (b *testBackend) HeaderByNumber(ctx context.Context, number rpc.BlockNumber) (*types.Header, error) {
+	if block, _ := b.BlockByNumber(ctx, number); block != nil {
+		return block.Header(), nil
+	}
+	return nil, nil
+}
+
+func (b *testBackend) BlockByNumber(ctx context.Context, number rpc.BlockNumber) (*types.Block, error) {
+	b.lookups++
+	if number < 0 || int(number) >= len(b.blocks) {
+		return nil, nil
+	}
+	return b.blocks[number], nil
+}
+
+func (b *testBackend) GetReceipts(ctx context.Context, hash common.Hash) (types.Receipts, error) {
+	return nil, nil
+}
+
+func (b *testBackend) GetTd(ctx context.Context, hash common.Hash) *big.Int { return common.Big1 }
+func (b *testBackend) Stats() (int, int)                                    { return 0, 0 }
+func (b *testBackend) SyncProgress() ethereum.SyncProgress                  { return ethereum.SyncProgress{} }
+func (b *testBackend) ChainConfig() ctypes.ChainConfigurator                { return b.config }
+
+func (b *testBackend) SuggestGasTipCap(ctx context.Context) (*big.Int, error) {
+	return common.Big1, nil
+}
+
+// fakeServer is a local stats server recording the reports of a node.
+type fakeServer struct {
+	t        *testing.T
+	extended bool            // Whether the extended reports are accepted
+	reports  chan fakeReport // Reports received after the login
+	upgrader websocket.Upgrader
+}
+
+// fakeReport is a message emitted by a node.
+type fakeReport struct {
+	kind    string
+	payload json.RawMessage
+}
+
+func (s *fakeServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	conn, err := s.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	read := func() (string, json.RawMessage, bool) {
+		var msg map[string][]json.RawMessage
+		if err := conn.ReadJSON(&msg); err != nil || len(msg["emit"]) != 2 {
+			return "", nil, false
+		}
+		var kind string
+		json.Unmarshal(msg["emit"][0], &kind)
+		return kind, msg["emit"][1], true
+	}
+	// Authenticate the node, acknowledging the extended reports if accepted
+	kind, payload, ok := read()
+	var auth authMsg
+	if !ok || kind != "hello" || json.Unmarshal(payload, &auth) != nil || auth.Secret != "secret" || !auth.Info.Extended {
+		s.t.Errorf("invalid login: %s %s", kind, payload)
+		return
+	}
+	ack := []string{"ready"}
+	if s.extended {
+		ack = append(ack, extendedAck)
+	}
+	conn.WriteJSON(map[string][]string{"emit": ack})
+
+	for {
+		kind, payload, ok := read()
+		if !ok {
+			return
+		}
+		if kind == "node-ping" {
+			conn.WriteJSON(map[string][]interface{}{"emit": {"node-pong", map[string]string{}}})
+		}
+		s.reports <- fakeReport{kind: kind, payload: payload}
+	}
+}
+
+// Tests that the extended stats are only reported to the servers accepting them,
+// along with the standard reports.
+func TestExtendedStats(t *testing.T) {
+	var (
+		key, _ = crypto.GenerateKey()
+		sender = crypto.PubkeyToAddress(key.PublicKey)
+		miner1 = common.HexToAddress("0xaaaa")
+		miner2 = common.HexToAddress("0xbbbb")
+		to     = common.HexToAddress("0xcccc")
+		config = params.HaloChainConfig
+		signer = types.LatestSigner(config)
+		gspec  = &genesisT.Genesis{
+			Config:   config,
+			GasLimit: 8_000_000,
+			Alloc:    genesisT.GenesisAlloc{sender: {Balance: big.NewInt(vars.Ether)}},
+		}
+	)
+	// Every third block is mined by the first miner, an uncle is included near the
+	// head and the last blocks are slow enough for the emergency mode
+	_, blocks, _ := core.GenerateChainWithGenesis(gspec, ethash.NewFaker(), 160, func(i int, b *core.BlockGen) {
+		if i%3 == 0 {
+			b.SetCoinbase(miner1)
+		} else {
+			b.SetCoinbase(miner2)
+		}
+		if i >= 150 {
+			b.OffsetTime(100)
+		}
+		if i == 155 {
+			b.AddUncle(&types.Header{
+				ParentHash: b.PrevBlock(i - 2).Hash(),
+				Number:     big.NewInt(int64(i)),
+				Coinbase:   miner1,
+			})
+		}
+		if i == 159 {
+			tx, _ := types.SignTx(types.NewTx(&types.DynamicFeeTx{
+				ChainID:   config.GetChainID(),
+				GasTipCap: big.NewInt(vars.GWei),
+				GasFeeCap: big.NewInt(10 * vars.GWei),
+				Gas:       21_000,
+				To:        &to,
+			}), signer, key)
+			b.AddTx(tx)
+		}
+	})
+	backend := &testBackend{
+		config: config,
+		blocks: append([]*types.Block{core.GenesisToBlock(gspec, nil)}, blocks...),
+	}
+	head, parent := blocks[len(blocks)-1], blocks[len(blocks)-2]
+
+	srv := &p2p.Server{Config: p2p.Config{
+		PrivateKey:  key,
+		MaxPeers:    1,
+		NoDiscovery: true,
+		Protocols: []p2p.Protocol{{
+			Name:     "eth",
+			Version:  ethproto.ETH68,
+			NodeInfo: func() interface{} { return &ethproto.NodeInfo{Network: 1} },
+		}},
+	}}
+	if err := srv.Start(); err != nil {
+		t.Fatalf("failed to start p2p server: %v", err)
+	}
+	defer srv.Stop()
+
+	for _, extended := range []bool{false, true} {
+		server := &fakeServer{
+			t:        t,
+			extended: extended,
+			reports:  make(chan fakeReport, 64),
+			upgrader: websocket.Upgrader{CheckOrigin: func(*http.Request) bool { return true }},
+		}
+		httpsrv := httptest.NewServer(server)
+
+		service := &Service{
+			server:  srv,
+			backend: backend,
+			engine:  ethash.NewFaker(),
+			node:    "test",
+			pass:    "secret",
+			host:    strings.Replace(httpsrv.URL, "http://", "ws://", 1),
+			pongCh:  make(chan struct{}),
+			histCh:  make(chan []uint64, 1),
+		}
+		service.Start()
+
+		// Collect the reports until the block of the second head event, the first
+		// one being fully reported by then
+		var (
+			kinds  []string
+			stats  []*extendedStats
+			blocks int
+		)
+		for blocks < 3 {
+			select {
+			case report := <-server.reports:
+				kinds = append(kinds, report.kind)
+				switch report.kind {
+				case "block":
+					blocks++
+				case "stats":
+					backend.headFeed.Send(core.ChainHeadEvent{Block: parent})
+				case "pending":
+					if blocks == 2 {
+						backend.headFeed.Send(core.ChainHeadEvent{Block: head})
+					}
+				case "extended-stats":
+					var msg struct {
+						Stats *extendedStats `json:"stats"`
+					}
+					if err := json.Unmarshal(report.payload, &msg); err != nil {
+						t.Fatalf("invalid extended stats: %v", err)
+					}
+					stats = append(stats, msg.Stats)
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatalf("extended %v: reports timed out, received %v", extended, kinds)
+			}
+		}
+		service.Stop()
+		httpsrv.Close()
+
+		want := []string{"node-ping", "latency", "block", "pending", "stats", "block", "pending", "block"}
+		if extended {
+			want = []string{"node-ping", "latency", "block", "pending", "stats", "extended-stats", "block", "pending", "extended-stats", "block"}
+		}
+		if strings.Join(kinds, " ") != strings.Join(want, " ") {
+			t.Fatalf("extended %v: reports mismatch: have %v, want %v", extended, kinds, want)
+		}
+		if !extended {
+			continue
+		}
+		// Check the report of the head, and of its parent notified by the event
+		if stats[0].Hash != head.Hash() || stats[1].Hash != parent.Hash() {
+			t.Fatalf("reported blocks mismatch: have %x, %x", stats[0].Hash, stats[1].Hash)
+		}
+		have := stats[0]
+		if want := 1.0 / 150; have.UncleRate != want {
+			t.Errorf("uncle rate mismatch: have %v, want %v", have.UncleRate, want)
+		}
+		if len(have.BlockTimes) != 3 {
+			t.Fatalf("block time windows mismatch: have %d, want 3", len(have.BlockTimes))
+		}
+		for i, blocks := range []int{15, 75, 150} {
+			first := backend.blocks[160-blocks]
+			want := float64(head.Time()-first.Time()) / float64(blocks)
+			if window := have.BlockTimes[i]; window.Blocks != blocks || window.BlockTime != want {
+				t.Errorf("window %d mismatch: have %+v, want %d blocks of %vs", i, window, blocks, want)
+			}
+		}
+		if len(have.Miners) != 2 || have.Miners[0] != (minerStats{miner2, 100}) || have.Miners[1] != (minerStats{miner1, 50}) {
+			t.Errorf("miners mismatch: have %+v", have.Miners)
+		}
+		credits, err := eip1559.CalcHaloBaseFeeCredits(config, head.Number(), head.BaseFee(), head.GasUsed())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if have.BaseFee != head.BaseFee().String() || have.Burned != credits.Burned.Dec() || head.GasUsed() == 0 {
+			t.Errorf("base fees mismatch: have %s burned of %s, want %s of %s", have.Burned, have.BaseFee, credits.Burned.Dec(), head.BaseFee())
+		}
+		if !have.Emergency {
+			t.Errorf("emergency mode not reported")
+		}
+	}
+}
+
+// Tests that the window of recent blocks only retrieves the blocks it doesn't
+// track yet.
+func TestBlockWindow(t *testing.T) {
+	gspec := &genesisT.Genesis{Config: params.TestChainConfig}
+	_, blocks, _ := core.GenerateChainWithGenesis(gspec, ethash.NewFaker(), 20, nil)
+	backend := &testBackend{
+		config: gspec.Config,
+		blocks: append([]*types.Block{core.GenesisToBlock(gspec, nil)}, blocks...),
+	}
+	var window blockWindow
+	for i, tt := range []struct {
+		number  int
+		lookups int
+		first   uint64
+	}{
+		{10, 4, 6},  // Empty window, retrieve the ancestors
+		{11, 0, 7},  // Child of the newest block
+		{9, 0, 7},   // Tracked block, the window is rewound
+		{15, 4, 11}, // Distant block, the gap is retrieved
+		{3, 3, 0},   // Untracked block close to genesis
+	} {
+		backend.lookups = 0
+		have := window.update(backend, backend.blocks[tt.number], 5)
+		if backend.lookups != tt.lookups {
+			t.Errorf("test %d: lookups mismatch: have %d, want %d", i, backend.lookups, tt.lookups)
+		}
+		if first, last := have[0].header.Number.Uint64(), have[len(have)-1].header.Number.Uint64(); first != tt.first || last != uint64(tt.number) {
+			t.Errorf("test %d: window mismatch: have [%d, %d], want [%d, %d]", i, first, last, tt.first, tt.number)
+		}
+		for j := 1; j < len(have); j++ {
+			if have[j].header.ParentHash != have[j-1].header.Hash() {
+				t.Fatalf("test %d: window not contiguous at %d", i, j)
+			}
+		}
+	}
+}
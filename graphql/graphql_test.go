@@ -38,10 +38,13 @@ import (
 	"github.com/ethereum/go-ethereum/eth"
 	"github.com/ethereum/go-ethereum/eth/ethconfig"
 	"github.com/ethereum/go-ethereum/eth/filters"
+	"github.com/ethereum/go-ethereum/event"
 	"github.com/ethereum/go-ethereum/node"
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/params/types/genesisT"
 	"github.com/ethereum/go-ethereum/params/vars"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/gorilla/websocket"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -432,6 +435,96 @@ func TestWithdrawals(t *testing.T) {
 	}
 }
 
+// Tests that the call traces and internal transfers of transactions, and the
+// rewards and base fee distribution of blocks are resolved.
+func TestGraphQLTracesAndRewards(t *testing.T) {
+	var (
+		key, _    = crypto.GenerateKey()
+		addr      = crypto.PubkeyToAddress(key.PublicKey)
+		miner     = common.HexToAddress("0xaaaa")
+		uncle     = common.HexToAddress("0xbbbb")
+		forwarder = common.HexToAddress("0xf0")
+		sink      = common.HexToAddress("0xbeef")
+		config    = params.HaloChainConfig
+		genesis   = &genesisT.Genesis{
+			Config:     config,
+			GasLimit:   11500000,
+			Difficulty: big.NewInt(1048576),
+			Alloc: genesisT.GenesisAlloc{
+				addr: {Balance: big.NewInt(vars.Ether)},
+				// CALL(GAS, 0xbeef, CALLVALUE, 0, 0, 0, 0)
+				forwarder: {Code: common.Hex2Bytes("60006000600060003461beef5af100")},
+			},
+		}
+		signer = types.LatestSigner(genesis.Config)
+		stack  = createNode(t)
+	)
+	defer stack.Close()
+
+	handler, _ := newGQLService(t, stack, false, genesis, 3, func(i int, gen *core.BlockGen) {
+		gen.SetCoinbase(miner)
+		switch i {
+		case 0:
+			tx, _ := types.SignNewTx(key, signer, &types.DynamicFeeTx{
+				ChainID:   config.GetChainID(),
+				To:        &forwarder,
+				Value:     big.NewInt(100),
+				Gas:       100000,
+				GasTipCap: big.NewInt(vars.GWei),
+				GasFeeCap: big.NewInt(10 * vars.GWei),
+			})
+			gen.AddTx(tx)
+		case 2:
+			gen.AddUncle(&types.Header{
+				ParentHash: gen.PrevBlock(0).Hash(),
+				Number:     big.NewInt(2),
+				Coinbase:   uncle,
+			})
+		}
+	})
+	// start node
+	if err := stack.Start(); err != nil {
+		t.Fatalf("could not start node: %v", err)
+	}
+	for i, tt := range []struct {
+		body string
+		want string
+	}{
+		{
+			body: "{block(number: 1) { transactions { trace { type from to value calls { type from to value error calls { type } } } internalTransfers { type from to value traceAddress } } } }",
+			want: fmt.Sprintf(`{"block":{"transactions":[{"trace":{"type":"CALL","from":"%s","to":"%s","value":"0x64","calls":[{"type":"CALL","from":"%s","to":"%s","value":"0x64","error":null,"calls":[]}]},"internalTransfers":[{"type":"CALL","from":"%s","to":"%s","value":"0x64","traceAddress":[0]}]}]}}`,
+				strings.ToLower(addr.Hex()), strings.ToLower(forwarder.Hex()), strings.ToLower(forwarder.Hex()), strings.ToLower(sink.Hex()), strings.ToLower(forwarder.Hex()), strings.ToLower(sink.Hex())),
+		},
+		{
+			body: "{block(number: 3) { rewards { blockReward nephewReward total ommers { miner amount ommer { number } } } } }",
+			want: `{"block":{"rewards":{"blockReward":"0x22b1c8c1227a00000","nephewReward":"0x853a0d2313c0000","total":"0x348fe72ed6cac0000","ommers":[{"miner":"0x000000000000000000000000000000000000bbbb","amount":"0x1158e460913d00000","ommer":{"number":"0x2"}}]}}}`,
+		},
+		{
+			// 30% to the miner, 20% to the ecosystem fund, 10% to the reserve fund, 40% burned
+			body: "{block(number: 1) { baseFeeDistribution { total miner shares { recipient amount } burned } } }",
+			want: `{"block":{"baseFeeDistribution":{"total":"0x2c05f6ad2140","miner":"0xd34fd33f060","shares":[{"recipient":"0xa7548df196e2c1476bdc41602e288c0a8f478c4f","amount":"0x8cdfe22a040"},{"recipient":"0xb95ae9b737e104c666d369cfb16d6de88208bd80","amount":"0x466ff115020"}],"burned":"0x119bfc454080"}}}`,
+		},
+	} {
+		res := handler.Schema.Exec(context.Background(), tt.body, "", map[string]interface{}{})
+		if res.Errors != nil {
+			t.Fatalf("failed to execute query for testcase #%d: %v", i, res.Errors)
+		}
+		have, err := json.Marshal(res.Data)
+		if err != nil {
+			t.Fatalf("failed to encode graphql response for testcase #%d: %s", i, err)
+		}
+		if string(have) != tt.want {
+			t.Errorf("response unmatch for testcase #%d.\nhave:\n%s\nwant:\n%s", i, have, tt.want)
+		}
+	}
+	// Queries tracing more blocks than their budget are rejected
+	ctx := withTraceBudget(context.Background(), 1)
+	res := handler.Schema.Exec(ctx, "{a: block(number: 1) { transactions { trace { type } } } b: block(number: 1) { transactions { trace { type } } } }", "", map[string]interface{}{})
+	if len(res.Errors) == 0 || !strings.Contains(res.Errors[0].Message, errTraceBudgetExceeded.Error()) {
+		t.Errorf("trace budget not enforced: %v", res.Errors)
+	}
+}
+
 func createNode(t *testing.T) *node.Node {
 	stack, err := node.New(&node.Config{
 		HTTPHost:     "127.0.0.1",
@@ -478,7 +571,7 @@ func newGQLService(t *testing.T, stack *node.Node, shanghai bool, gspec *genesis
 		t.Fatalf("could not create eth backend: %v", err)
 	}
 	// Create some blocks and import them
-	chain, _ := core.GenerateChain(params.AllEthashProtocolChanges, ethBackend.BlockChain().Genesis(),
+	chain, _ := core.GenerateChain(gspec.Config, ethBackend.BlockChain().Genesis(),
 		engine, ethBackend.ChainDb(), genBlocks, genfunc)
 	_, err = ethBackend.BlockChain().InsertChain(chain)
 	if err != nil {
@@ -492,3 +585,295 @@ func newGQLService(t *testing.T, stack *node.Node, shanghai bool, gspec *genesis
 	}
 	return handler, chain
 }
+
+// Tests that new blocks, logs and pending transactions are pushed to the GraphQL
+// subscriptions over websocket, and that queries are answered over websocket.
+func TestGraphQLSubscriptions(t *testing.T) {
+	var (
+		key, _  = crypto.GenerateKey()
+		addr    = crypto.PubkeyToAddress(key.PublicKey)
+		dadStr  = "0x0000000000000000000000000000000000000dad"
+		dad     = common.HexToAddress(dadStr)
+		genesis = &genesisT.Genesis{
+			Config:     params.TestChainConfig,
+			GasLimit:   11500000,
+			Difficulty: big.NewInt(1048576),
+			Alloc: genesisT.GenesisAlloc{
+				addr: {Balance: big.NewInt(vars.Ether)},
+				dad: {
+					// LOG0(0, 0), LOG0(0, 0), RETURN(0, 0)
+					Code:    common.Hex2Bytes("60006000a060006000a060006000f3"),
+					Nonce:   0,
+					Balance: big.NewInt(0),
+				},
+			},
+		}
+		signer = types.LatestSigner(genesis.Config)
+		stack  = createNode(t)
+	)
+	defer stack.Close()
+
+	ethBackend, err := eth.New(stack, &ethconfig.Config{
+		Genesis:        genesis,
+		Ethash:         ethash.Config{PowMode: ethash.ModeFake},
+		NetworkId:      1337,
+		TrieCleanCache: 5,
+		TrieDirtyCache: 5,
+		TrieTimeout:    60 * time.Minute,
+		SnapshotCache:  5,
+		TxPool:         ethconfig.Defaults.TxPool,
+	})
+	if err != nil {
+		t.Fatalf("could not create eth backend: %v", err)
+	}
+	filterSystem := filters.NewFilterSystem(ethBackend.APIBackend, filters.Config{})
+	if _, err := newHandler(stack, ethBackend.APIBackend, filterSystem, []string{}, []string{}); err != nil {
+		t.Fatalf("could not create graphql service: %v", err)
+	}
+	if err := stack.Start(); err != nil {
+		t.Fatalf("could not start node: %v", err)
+	}
+	// Connect to the GraphQL endpoint over websocket
+	endpoint := "ws" + strings.TrimPrefix(stack.HTTPEndpoint(), "http") + "/graphql"
+	dialer := websocket.Dialer{Subprotocols: []string{wsProtocol}}
+	if _, resp, err := dialer.Dial(endpoint, nil); err == nil || resp == nil || resp.StatusCode != http.StatusForbidden {
+		t.Fatalf("connection without origin not rejected: %v", err)
+	}
+	conn, _, err := dialer.Dial(endpoint, http.Header{"Origin": {stack.HTTPEndpoint()}})
+	if err != nil {
+		t.Fatalf("could not dial graphql websocket: %v", err)
+	}
+	defer conn.Close()
+
+	send := func(msg wsMessage) {
+		t.Helper()
+		if err := conn.WriteJSON(msg); err != nil {
+			t.Fatalf("could not send %s message: %v", msg.Type, err)
+		}
+	}
+	read := func() wsMessage {
+		t.Helper()
+		for {
+			var msg wsMessage
+			conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+			if err := conn.ReadJSON(&msg); err != nil {
+				t.Fatalf("could not read message: %v", err)
+			}
+			if msg.Type != wsConnectionKeepAlive {
+				return msg
+			}
+		}
+	}
+	start := func(id string, query string) {
+		t.Helper()
+		payload, _ := json.Marshal(map[string]string{"query": query})
+		send(wsMessage{ID: id, Type: wsStart, Payload: payload})
+	}
+	send(wsMessage{Type: wsConnectionInit})
+	if msg := read(); msg.Type != wsConnectionAck {
+		t.Fatalf("connection not acknowledged: %s", msg.Type)
+	}
+	start("blocks", `subscription { newBlocks { number } }`)
+	start("logs", fmt.Sprintf(`subscription { logs(filter: {addresses: ["%s"]}) { index account { address } } }`, dadStr))
+	start("txs", `subscription { pendingTransactions { hash } }`)
+
+	// Operations are started in order, so the subscriptions are installed once
+	// the query is answered.
+	start("query", `{ block { number } }`)
+	want := map[string][]string{
+		"query": {`{"data":{"block":{"number":"0x0"}}}`, wsComplete},
+	}
+	expect := func() {
+		t.Helper()
+		for len(want) > 0 {
+			msg := read()
+			switch msg.Type {
+			case wsData, wsComplete:
+			default:
+				t.Fatalf("unexpected %s message for %q: %s", msg.Type, msg.ID, msg.Payload)
+			}
+			payload := string(msg.Payload)
+			if msg.Type == wsComplete {
+				payload = wsComplete
+			}
+			if len(want[msg.ID]) == 0 || want[msg.ID][0] != payload {
+				t.Fatalf("unexpected message for %q: have %s, want %v", msg.ID, payload, want[msg.ID])
+			}
+			if want[msg.ID] = want[msg.ID][1:]; len(want[msg.ID]) == 0 {
+				delete(want, msg.ID)
+			}
+		}
+	}
+	expect()
+
+	// Pool a transaction and include it in a block
+	tx, _ := types.SignNewTx(key, signer, &types.LegacyTx{To: &dad, Gas: 100000, GasPrice: big.NewInt(vars.InitialBaseFee)})
+	if errs := ethBackend.TxPool().Add([]*types.Transaction{tx}, true, true); errs[0] != nil {
+		t.Fatalf("could not pool transaction: %v", errs[0])
+	}
+	want = map[string][]string{
+		"txs": {fmt.Sprintf(`{"data":{"pendingTransactions":{"hash":"%s"}}}`, tx.Hash())},
+	}
+	expect()
+
+	chain, _ := core.GenerateChain(params.TestChainConfig, ethBackend.BlockChain().Genesis(),
+		ethash.NewFaker(), ethBackend.ChainDb(), 1, func(i int, gen *core.BlockGen) { gen.AddTx(tx) })
+	if _, err := ethBackend.BlockChain().InsertChain(chain); err != nil {
+		t.Fatalf("could not import blocks: %v", err)
+	}
+	want = map[string][]string{
+		"blocks": {`{"data":{"newBlocks":{"number":"0x1"}}}`},
+		"logs": {
+			fmt.Sprintf(`{"data":{"logs":{"index":"0x0","account":{"address":"%s"}}}}`, dadStr),
+			fmt.Sprintf(`{"data":{"logs":{"index":"0x1","account":{"address":"%s"}}}}`, dadStr),
+		},
+	}
+	expect()
+
+	// Stopped subscriptions are not notified anymore
+	send(wsMessage{ID: "blocks", Type: wsStop})
+	start("query", `{ block { number } }`)
+	want = map[string][]string{
+		"query": {`{"data":{"block":{"number":"0x1"}}}`, wsComplete},
+	}
+	expect()
+
+	// Operations beyond the per connection limit are rejected
+	for i := 2; i < wsMaxOperations; i++ {
+		start(fmt.Sprintf("sub%d", i), `subscription { newBlocks { number } }`)
+	}
+	start("overflow", `subscription { newBlocks { number } }`)
+	if msg := read(); msg.Type != wsError || msg.ID != "overflow" {
+		t.Fatalf("operation beyond the limit not rejected: %s message for %q: %s", msg.Type, msg.ID, msg.Payload)
+	}
+}
+
+// feedBackend is a filter backend delivering the events sent to its feeds.
+type feedBackend struct {
+	filters.Backend
+	txsFeed, logsFeed, rmLogsFeed, chainFeed, sideFeed, pendingLogsFeed event.Feed
+}
+
+func (b *feedBackend) SubscribeNewTxsEvent(ch chan<- core.NewTxsEvent) event.Subscription {
+	return b.txsFeed.Subscribe(ch)
+}
+
+func (b *feedBackend) SubscribeLogsEvent(ch chan<- []*types.Log) event.Subscription {
+	return b.logsFeed.Subscribe(ch)
+}
+
+func (b *feedBackend) SubscribeRemovedLogsEvent(ch chan<- core.RemovedLogsEvent) event.Subscription {
+	return b.rmLogsFeed.Subscribe(ch)
+}
+
+func (b *feedBackend) SubscribeChainEvent(ch chan<- core.ChainEvent) event.Subscription {
+	return b.chainFeed.Subscribe(ch)
+}
+
+func (b *feedBackend) SubscribeChainSideEvent(ch chan<- core.ChainSideEvent) event.Subscription {
+	return b.sideFeed.Subscribe(ch)
+}
+
+func (b *feedBackend) SubscribePendingLogsEvent(ch chan<- []*types.Log) event.Subscription {
+	return b.pendingLogsFeed.Subscribe(ch)
+}
+
+// Tests that a subscriber never reading its events is unsubscribed without
+// stalling the other subscribers.
+func TestGraphQLSlowSubscriber(t *testing.T) {
+	backend := new(feedBackend)
+	r := &SubscriptionResolver{Resolver: &Resolver{filterSystem: filters.NewFilterSystem(backend, filters.Config{})}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	slow, _ := r.NewBlocks(ctx)
+	fast, _ := r.NewBlocks(ctx)
+
+	blocks := 2 * subscriptionBuffer
+	go func() {
+		for i := 1; i <= blocks; i++ {
+			block := types.NewBlockWithHeader(&types.Header{Number: big.NewInt(int64(i))})
+			backend.chainFeed.Send(core.ChainEvent{Block: block, Hash: block.Hash()})
+		}
+	}()
+	for i := 1; i <= blocks; i++ {
+		select {
+		case block := <-fast:
+			if number, _ := block.Number(ctx); uint64(number) != uint64(i) {
+				t.Fatalf("block %d: have number %d", i, number)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("block %d not notified, subscriptions stalled", i)
+		}
+	}
+	// The slow subscriber got its buffer filled, then was unsubscribed
+	var notified int
+	for range slow {
+		notified++
+	}
+	if notified != subscriptionBuffer {
+		t.Fatalf("slow subscriber notified %d blocks, want %d", notified, subscriptionBuffer)
+	}
+}
+
+// Tests that the GraphQL queries and websocket connections are subject to the
+// API keys, namespace allowlists and rate limits of the RPC endpoints.
+func TestGraphQLAccess(t *testing.T) {
+	stack, err := node.New(&node.Config{
+		HTTPHost:     "127.0.0.1",
+		HTTPTimeouts: node.DefaultConfig.HTTPTimeouts,
+		RPCAccess: &rpc.AccessConfig{
+			APIKeys: []rpc.APIKeyConfig{
+				{Key: "secret"},
+				{Key: "ethonly", Namespaces: []string{"eth"}},
+			},
+			IPRate:  0.001,
+			IPBurst: 2,
+		},
+	})
+	if err != nil {
+		t.Fatalf("could not create node: %v", err)
+	}
+	defer stack.Close()
+	if _, err := newHandler(stack, nil, nil, []string{}, []string{}); err != nil {
+		t.Fatalf("could not create graphql service: %v", err)
+	}
+	if err := stack.Start(); err != nil {
+		t.Fatalf("could not start node: %v", err)
+	}
+	url := fmt.Sprintf("%s/graphql", stack.HTTPEndpoint())
+
+	for i, tt := range []struct {
+		url  string
+		key  string
+		code int
+	}{
+		{url: url, code: http.StatusOK},
+		{url: url, code: http.StatusOK},
+		{url: url, code: http.StatusTooManyRequests}, // IP burst exhausted
+		{url: url, key: "secret", code: http.StatusOK},
+		{url: url + "/secret", code: http.StatusOK},
+		{url: url, key: "ethonly", code: http.StatusForbidden},
+		{url: url, key: "bogus", code: http.StatusUnauthorized},
+	} {
+		req, _ := http.NewRequest(http.MethodPost, tt.url, strings.NewReader(`{"query": "{__typename}"}`))
+		req.Header.Set("Content-Type", "application/json")
+		if tt.key != "" {
+			req.Header.Set(rpc.APIKeyHeader, tt.key)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("could not post: %v", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != tt.code {
+			t.Errorf("testcase %d: status code mismatch: have %d, want %d", i, resp.StatusCode, tt.code)
+		}
+	}
+	// Websocket upgrades are checked before the connection is accepted
+	wsURL := "ws" + strings.TrimPrefix(url, "http")
+	_, resp, err := websocket.DefaultDialer.Dial(wsURL, http.Header{rpc.APIKeyHeader: {"ethonly"}})
+	if err == nil || resp == nil || resp.StatusCode != http.StatusForbidden {
+		t.Fatalf("websocket connection with a disallowed API key not rejected: %v", err)
+	}
+}
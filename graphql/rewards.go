@@ -0,0 +1,180 @@
+// Copyright 2025 The core-geth Authors
+// This file is part of the core-geth library.
+//
+// The core-geth library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The core-geth library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the core-geth library. If not, see <http://www.gnu.org/licenses/>.
+
+package graphql
+
+import (
+	"context"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/consensus/misc/eip1559"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params/mutations"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/holiman/uint256"
+)
+
+// Rewards returns the rewards issued by the block, computed by the same code as
+// the ethash and lyra2 block finalization.
+func (b *Block) Rewards(ctx context.Context) (*BlockRewards, error) {
+	config := b.r.backend.ChainConfig()
+	if engine := config.GetConsensusEngineType(); !engine.IsEthash() && !engine.IsLyra2() {
+		return nil, nil
+	}
+	block, err := b.resolve(ctx)
+	if err != nil || block == nil {
+		return nil, err
+	}
+	header := block.Header()
+	rewards := &BlockRewards{
+		blockReward:  new(uint256.Int),
+		nephewReward: new(uint256.Int),
+	}
+	var uncleRewards []*uint256.Int
+	// Neither the genesis nor proof-of-stake blocks are rewarded
+	if header.Number.Sign() > 0 && header.Difficulty.Sign() > 0 {
+		// The nephew reward is the miner reward in excess of the static block reward
+		rewards.blockReward, _ = mutations.GetRewards(config, header, nil)
+		minerReward, ommerRewards := mutations.GetRewards(config, header, block.Uncles())
+		rewards.nephewReward.Sub(minerReward, rewards.blockReward)
+		uncleRewards = ommerRewards
+	}
+	for i, uncle := range block.Uncles() {
+		amount := new(uint256.Int)
+		if uncleRewards != nil {
+			amount = uncleRewards[i]
+		}
+		blockNumberOrHash := rpc.BlockNumberOrHashWithHash(uncle.Hash(), false)
+		rewards.ommers = append(rewards.ommers, &UncleReward{
+			ommer: &Block{
+				r:            b.r,
+				numberOrHash: &blockNumberOrHash,
+				header:       uncle,
+				hash:         uncle.Hash(),
+			},
+			miner:  uncle.Coinbase,
+			amount: amount,
+		})
+	}
+	return rewards, nil
+}
+
+// BaseFeeDistribution returns the split of the base fees paid in the block. The
+// base fees are burned, unless distributed by the Halo fee distribution.
+func (b *Block) BaseFeeDistribution(ctx context.Context) (*BaseFeeDistribution, error) {
+	header, err := b.resolveHeader(ctx)
+	if err != nil || header == nil || header.BaseFee == nil {
+		return nil, err
+	}
+	config := b.r.backend.ChainConfig()
+	var receipts types.Receipts
+	if eip1559.IsHaloContractFeeSharingEnabled(config, header.Number) && header.GasUsed > 0 {
+		if receipts, err = b.resolveReceipts(ctx); err != nil {
+			return nil, err
+		}
+	}
+	credits, err := eip1559.HaloBlockBaseFeeCredits(config, header, receipts)
+	if err != nil {
+		return nil, err
+	}
+	return &BaseFeeDistribution{credits: credits}, nil
+}
+
+// BlockRewards represents the rewards issued by a block.
+type BlockRewards struct {
+	blockReward  *uint256.Int
+	nephewReward *uint256.Int
+	ommers       []*UncleReward
+}
+
+func (r *BlockRewards) BlockReward(ctx context.Context) hexutil.Big {
+	return hexutil.Big(*r.blockReward.ToBig())
+}
+
+func (r *BlockRewards) NephewReward(ctx context.Context) hexutil.Big {
+	return hexutil.Big(*r.nephewReward.ToBig())
+}
+
+func (r *BlockRewards) Ommers(ctx context.Context) []*UncleReward {
+	return r.ommers
+}
+
+func (r *BlockRewards) Total(ctx context.Context) hexutil.Big {
+	total := new(uint256.Int).Add(r.blockReward, r.nephewReward)
+	for _, ommer := range r.ommers {
+		total.Add(total, ommer.amount)
+	}
+	return hexutil.Big(*total.ToBig())
+}
+
+// UncleReward represents the reward of an ommer included by a block.
+type UncleReward struct {
+	ommer  *Block
+	miner  common.Address
+	amount *uint256.Int
+}
+
+func (u *UncleReward) Ommer(ctx context.Context) *Block {
+	return u.ommer
+}
+
+func (u *UncleReward) Miner(ctx context.Context) common.Address {
+	return u.miner
+}
+
+func (u *UncleReward) Amount(ctx context.Context) hexutil.Big {
+	return hexutil.Big(*u.amount.ToBig())
+}
+
+// BaseFeeDistribution represents the split of the base fees of a block.
+type BaseFeeDistribution struct {
+	credits *eip1559.HaloBaseFeeCredits
+}
+
+func (d *BaseFeeDistribution) Total(ctx context.Context) hexutil.Big {
+	return hexutil.Big(*d.credits.Total.ToBig())
+}
+
+func (d *BaseFeeDistribution) Miner(ctx context.Context) hexutil.Big {
+	return hexutil.Big(*d.credits.Miner.ToBig())
+}
+
+func (d *BaseFeeDistribution) Shares(ctx context.Context) []*FeeCredit {
+	ret := make([]*FeeCredit, 0, len(d.credits.Shares))
+	for _, share := range d.credits.Shares {
+		ret = append(ret, &FeeCredit{recipient: share.Recipient, amount: share.Amount})
+	}
+	return ret
+}
+
+func (d *BaseFeeDistribution) Burned(ctx context.Context) hexutil.Big {
+	return hexutil.Big(*d.credits.Burned.ToBig())
+}
+
+// FeeCredit represents a part of the base fees credited to a recipient.
+type FeeCredit struct {
+	recipient common.Address
+	amount    *uint256.Int
+}
+
+func (c *FeeCredit) Recipient(ctx context.Context) common.Address {
+	return c.recipient
+}
+
+func (c *FeeCredit) Amount(ctx context.Context) hexutil.Big {
+	return hexutil.Big(*c.amount.ToBig())
+}
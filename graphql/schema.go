@@ -16,7 +16,44 @@
 
 package graphql
 
+// schema is the GraphQL schema served over HTTP, answering queries and mutations.
 const schema string = `
+    schema {
+        query: Query
+        mutation: Mutation
+    }
+` + schemaTypes
+
+// subscriptionSchema is the GraphQL schema serving subscriptions over websocket.
+// All root fields of a schema are resolved by the same object, so the query root
+// of this schema is kept apart from the one of the main schema, whose fields are
+// clashing with the subscriptions.
+const subscriptionSchema string = `
+    schema {
+        query: SubscriptionQuery
+        subscription: Subscription
+    }
+
+    # SubscriptionQuery is the query root of the subscription schema. Queries and
+    # mutations sent over websocket are answered by the main schema instead.
+    type SubscriptionQuery {
+        # ChainID returns the current chain ID for transaction replay protection.
+        chainID: BigInt!
+    }
+
+    type Subscription {
+        # NewBlocks notifies the blocks becoming the head of the chain.
+        newBlocks: Block!
+        # Logs notifies the log entries matching the provided filter, as they are
+        # included in new blocks. The block range of the filter is ignored.
+        logs(filter: FilterCriteria!): Log!
+        # PendingTransactions notifies the transactions entering the pool.
+        pendingTransactions: Transaction!
+    }
+` + schemaTypes
+
+// schemaTypes contains the type definitions shared by the GraphQL schemas.
+const schemaTypes string = `
     # Bytes32 is a 32 byte binary string, represented as 0x-prefixed hexadecimal.
     scalar Bytes32
     # Address is a 20 byte Ethereum address, represented as 0x-prefixed hexadecimal.
@@ -33,11 +70,6 @@ const schema string = `
     # 0x-prefixed hexadecimal.
     scalar Long
 
-    schema {
-        query: Query
-        mutation: Mutation
-    }
-
     # Account is an Ethereum account at a particular block.
     type Account {
         # Address is the address owning the account.
@@ -89,6 +121,96 @@ const schema string = `
         amount: Long!
     }
 
+    # CallFrame is a call made while executing a transaction, as reported by the
+    # callTracer.
+    type CallFrame {
+        # Type is the kind of call: CALL, STATICCALL, DELEGATECALL, CALLCODE,
+        # CREATE, CREATE2 or SELFDESTRUCT.
+        type: String!
+        # From is the address making the call.
+        from: Address!
+        # To is the address called, or the created contract. It is null if the
+        # creation failed.
+        to: Address
+        # Value is the amount of wei sent with the call.
+        value: BigInt
+        # Gas is the amount of gas available to the call.
+        gas: Long!
+        # GasUsed is the amount of gas used by the call.
+        gasUsed: Long!
+        # Input is the data sent with the call.
+        input: Bytes!
+        # Output is the data returned by the call.
+        output: Bytes
+        # Error is the error the call failed with, null if it succeeded.
+        error: String
+        # RevertReason is the decoded reason of a reverted call.
+        revertReason: String
+        # Calls is the list of calls made by this call.
+        calls: [CallFrame!]!
+    }
+
+    # InternalTransfer is a value transfer made by a call nested in a
+    # transaction. Transfers of reverted calls are not reported.
+    type InternalTransfer {
+        # Type is the kind of call making the transfer: CALL, CREATE, CREATE2
+        # or SELFDESTRUCT.
+        type: String!
+        # From is the address sending the value.
+        from: Address!
+        # To is the address receiving the value.
+        to: Address!
+        # Value is the amount of wei transferred.
+        value: BigInt!
+        # TraceAddress is the position of the call in the call tree of the
+        # transaction, as the indices of its ancestors' calls.
+        traceAddress: [Long!]!
+    }
+
+    # UncleReward is the reward of an ommer (AKA uncle) included by a block.
+    type UncleReward {
+        # Ommer is the rewarded ommer block.
+        ommer: Block!
+        # Miner is the account credited with the reward.
+        miner: Address!
+        # Amount is the reward in wei.
+        amount: BigInt!
+    }
+
+    # BlockRewards is the breakdown of the rewards issued by a block.
+    type BlockRewards {
+        # BlockReward is the static reward of the miner.
+        blockReward: BigInt!
+        # NephewReward is the reward of the miner for including ommers.
+        nephewReward: BigInt!
+        # Ommers is the list of rewards of the included ommers.
+        ommers: [UncleReward!]!
+        # Total is the sum of all rewards issued by the block.
+        total: BigInt!
+    }
+
+    # FeeCredit is a part of the base fees credited to a recipient.
+    type FeeCredit {
+        # Recipient is the account credited.
+        recipient: Address!
+        # Amount is the credited amount in wei.
+        amount: BigInt!
+    }
+
+    # BaseFeeDistribution is the split of the base fees paid by the
+    # transactions of a block between the miner, the fee distribution
+    # recipients (like the ecosystem fund) and the burn.
+    type BaseFeeDistribution {
+        # Total is the amount of base fees paid by the transactions.
+        total: BigInt!
+        # Miner is the part credited to the miner of the block.
+        miner: BigInt!
+        # Shares is the list of parts credited to the fee distribution recipients.
+        shares: [FeeCredit!]!
+        # Burned is the part not credited to any account.
+        burned: BigInt!
+    }
+
     # Transaction is an Ethereum transaction.
     type Transaction {
         # Hash is the hash of this transaction.
@@ -170,6 +292,14 @@ const schema string = `
         rawReceipt: Bytes!
         # BlobVersionedHashes is a set of hash outputs from the blobs in the transaction.
         blobVersionedHashes: [Bytes32!]
+        # Trace is the call trace of the transaction, produced by re-executing
+        # its block. If the transaction is pending, this field will be null.
+        # A query may re-execute at most 16 blocks to resolve traces.
+        trace: CallFrame
+        # InternalTransfers is the list of value transfers made by the calls
+        # nested in the transaction. If the transaction is pending, this field
+        # will be null.
+        internalTransfers: [InternalTransfer!]
     }
 
     # BlockFilterCriteria encapsulates log filter criteria for a filter applied
@@ -279,6 +409,12 @@ const schema string = `
         blobGasUsed: Long
         # ExcessBlobGas is a running total of blob gas consumed in excess of the target, prior to the block.
         excessBlobGas: Long
+        # Rewards is the breakdown of the rewards issued by this block. If the
+        # chain is not a proof-of-work one, this field will be null.
+        rewards: BlockRewards
+        # BaseFeeDistribution is the split of the base fees paid in this block.
+        # If the block has no base fee, this field will be null.
+        baseFeeDistribution: BaseFeeDistribution
     }
 
     # CallData represents the data associated with a local contract call.
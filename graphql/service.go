@@ -28,10 +28,20 @@ import (
 	"github.com/ethereum/go-ethereum/internal/ethapi"
 	"github.com/ethereum/go-ethereum/node"
 	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/gorilla/websocket"
 	"github.com/graph-gophers/graphql-go"
 	gqlErrors "github.com/graph-gophers/graphql-go/errors"
 )
 
+const (
+	// queryMethod is the method the queries, and the websocket connections and
+	// operations, are charged as by the access control of the node.
+	queryMethod = "graphql_query"
+
+	// traceMethod is the method each block traced by a query is charged as.
+	traceMethod = "graphql_trace"
+)
+
 type handler struct {
 	Schema *graphql.Schema
 }
@@ -87,7 +97,7 @@ func (h handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		})
 	}
 
-	response := h.Schema.Exec(ctx, params.Query, params.OperationName, params.Variables)
+	response := h.Schema.Exec(withTraceBudget(ctx, maxTracedBlocks), params.Query, params.OperationName, params.Variables)
 	if timer != nil {
 		timer.Stop()
 	}
@@ -111,8 +121,9 @@ func New(stack *node.Node, backend ethapi.Backend, filterSystem *filters.FilterS
 	return err
 }
 
-// newHandler returns a new `http.Handler` that will answer GraphQL queries.
-// It additionally exports an interactive query browser on the / endpoint.
+// newHandler returns a new `http.Handler` that will answer GraphQL queries, and
+// subscriptions over websocket. It additionally exports an interactive query
+// browser on the / endpoint.
 func newHandler(stack *node.Node, backend ethapi.Backend, filterSystem *filters.FilterSystem, cors, vhosts []string) (*handler, error) {
 	q := Resolver{backend, filterSystem}
 
@@ -120,8 +131,25 @@ func newHandler(stack *node.Node, backend ethapi.Backend, filterSystem *filters.
 	if err != nil {
 		return nil, err
 	}
+	sub, err := graphql.ParseSchema(subscriptionSchema, &SubscriptionResolver{Resolver: &q})
+	if err != nil {
+		return nil, err
+	}
 	h := handler{Schema: s}
-	handler := node.NewHTTPHandlerStack(h, cors, vhosts, nil)
+	wsHandler := newWSHandler(s, sub, stack.Config().WSOrigins)
+
+	// Websocket upgrades go through the same host and access checks as the queries
+	handler, err := node.NewAccessHandlerStack(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if websocket.IsWebSocketUpgrade(r) {
+			wsHandler.ServeHTTP(w, r)
+			return
+		}
+		h.ServeHTTP(w, r)
+	}), "/graphql", stack.Config().RPCAccess, queryMethod)
+	if err != nil {
+		return nil, err
+	}
+	handler = node.NewHTTPHandlerStack(handler, cors, vhosts, nil)
 
 	stack.RegisterHandler("GraphQL UI", "/graphql/ui", GraphiQL{})
 	stack.RegisterHandler("GraphQL UI", "/graphql/ui/", GraphiQL{})
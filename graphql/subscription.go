@@ -0,0 +1,143 @@
+// Copyright 2025 The core-geth Authors
+// This file is part of the core-geth library.
+//
+// The core-geth library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The core-geth library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the core-geth library. If not, see <http://www.gnu.org/licenses/>.
+
+package graphql
+
+import (
+	"context"
+	"sync"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/eth/filters"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// subscriptionBuffer is the number of items buffered for a subscriber not keeping
+// up with its events, before it is unsubscribed.
+const subscriptionBuffer = 256
+
+// SubscriptionResolver is the root resolver of the subscription schema. The
+// subscriptions are backed by the event system of the filter API.
+type SubscriptionResolver struct {
+	*Resolver
+
+	once   sync.Once
+	events *filters.EventSystem // Created on the first subscription
+}
+
+// eventSystem returns the event system feeding the subscriptions.
+func (r *SubscriptionResolver) eventSystem() *filters.EventSystem {
+	r.once.Do(func() {
+		r.events = filters.NewEventSystem(r.filterSystem, false)
+	})
+	return r.events
+}
+
+// forward relays the events of a subscription to the channel of a resolver,
+// converting them, until the subscription fails or the context is cancelled.
+// The events are always consumed without blocking, as they are delivered by the
+// loop of the event system: a subscriber not keeping up with them, for example
+// because the writes to its connection stall, is unsubscribed once its buffer
+// is full.
+func forward[E any, T any](ctx context.Context, sub *filters.Subscription, events chan E, convert func(E) []T) <-chan T {
+	ch := make(chan T, subscriptionBuffer)
+	go func() {
+		defer close(ch)
+		defer sub.Unsubscribe()
+
+		for {
+			select {
+			case ev := <-events:
+				for _, item := range convert(ev) {
+					select {
+					case ch <- item:
+					default:
+						log.Debug("Unsubscribing slow GraphQL subscriber", "id", sub.ID)
+						return
+					}
+				}
+			case <-sub.Err():
+				return
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch
+}
+
+// NewBlocks notifies the blocks becoming the head of the chain.
+func (r *SubscriptionResolver) NewBlocks(ctx context.Context) (<-chan *Block, error) {
+	headers := make(chan *types.Header)
+	sub := r.eventSystem().SubscribeNewHeads(headers)
+
+	return forward(ctx, sub, headers, func(header *types.Header) []*Block {
+		numberOrHash := rpc.BlockNumberOrHashWithHash(header.Hash(), false)
+		return []*Block{{
+			r:            r.Resolver,
+			numberOrHash: &numberOrHash,
+			hash:         header.Hash(),
+			header:       header,
+		}}
+	}), nil
+}
+
+// Logs notifies the log entries matching the filter as they are included in
+// new blocks. Logs removed by reorgs are not notified.
+func (r *SubscriptionResolver) Logs(ctx context.Context, args struct{ Filter FilterCriteria }) (<-chan *Log, error) {
+	var crit ethereum.FilterQuery
+	if args.Filter.Addresses != nil {
+		crit.Addresses = *args.Filter.Addresses
+	}
+	if args.Filter.Topics != nil {
+		crit.Topics = *args.Filter.Topics
+	}
+	logs := make(chan []*types.Log)
+	sub, err := r.eventSystem().SubscribeLogs(crit, logs)
+	if err != nil {
+		return nil, err
+	}
+	return forward(ctx, sub, logs, func(logs []*types.Log) []*Log {
+		ret := make([]*Log, 0, len(logs))
+		for _, log := range logs {
+			if log.Removed {
+				continue
+			}
+			ret = append(ret, &Log{
+				r:           r.Resolver,
+				transaction: &Transaction{r: r.Resolver, hash: log.TxHash},
+				log:         log,
+			})
+		}
+		return ret
+	}), nil
+}
+
+// PendingTransactions notifies the transactions entering the pool.
+func (r *SubscriptionResolver) PendingTransactions(ctx context.Context) (<-chan *Transaction, error) {
+	txs := make(chan []*types.Transaction)
+	sub := r.eventSystem().SubscribePendingTxs(txs)
+
+	return forward(ctx, sub, txs, func(txs []*types.Transaction) []*Transaction {
+		ret := make([]*Transaction, 0, len(txs))
+		for _, tx := range txs {
+			ret = append(ret, &Transaction{r: r.Resolver, hash: tx.Hash(), tx: tx})
+		}
+		return ret
+	}), nil
+}
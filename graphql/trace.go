@@ -0,0 +1,277 @@
+// Copyright 2025 The core-geth Authors
+// This file is part of the core-geth library.
+//
+// The core-geth library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The core-geth library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the core-geth library. If not, see <http://www.gnu.org/licenses/>.
+
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/eth/tracers"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	// Register the native tracers, the call traces are produced by the callTracer
+	_ "github.com/ethereum/go-ethereum/eth/tracers/native"
+)
+
+// maxTracedBlocks is the maximum number of blocks a single query may re-execute
+// to resolve call traces.
+const maxTracedBlocks = 16
+
+var (
+	errTracingUnsupported  = errors.New("tracing is not supported by the backend")
+	errTraceBudgetExceeded = errors.New("query traces too many blocks")
+)
+
+// traceBudgetKey is the context key of the tracing budget of a query.
+type traceBudgetKey struct{}
+
+// traceBudget is the number of blocks a query may still re-execute for traces.
+type traceBudget struct {
+	left int
+	lock sync.Mutex
+}
+
+// withTraceBudget returns a context allowing the query executed with it to trace
+// at most the given number of blocks.
+func withTraceBudget(ctx context.Context, blocks int) context.Context {
+	return context.WithValue(ctx, traceBudgetKey{}, &traceBudget{left: blocks})
+}
+
+// spendTraceBudget consumes a block from the tracing budget of the query, if it
+// has one.
+func spendTraceBudget(ctx context.Context) error {
+	budget, ok := ctx.Value(traceBudgetKey{}).(*traceBudget)
+	if !ok {
+		return nil
+	}
+	budget.lock.Lock()
+	defer budget.lock.Unlock()
+
+	if budget.left <= 0 {
+		return errTraceBudgetExceeded
+	}
+	budget.left--
+	return nil
+}
+
+// callTrace is the result of the callTracer for a single call.
+type callTrace struct {
+	Type         string          `json:"type"`
+	From         common.Address  `json:"from"`
+	To           *common.Address `json:"to"`
+	Value        *hexutil.Big    `json:"value"`
+	Gas          hexutil.Uint64  `json:"gas"`
+	GasUsed      hexutil.Uint64  `json:"gasUsed"`
+	Input        hexutil.Bytes   `json:"input"`
+	Output       *hexutil.Bytes  `json:"output"`
+	Error        string          `json:"error"`
+	RevertReason string          `json:"revertReason"`
+	Calls        []*callTrace    `json:"calls"`
+}
+
+// resolveTraces returns the call traces of the transactions of this block,
+// re-executing it if necessary. Each transaction is traced within the EVM
+// timeout of the node, and the block is charged to the tracing budget of the
+// query.
+func (b *Block) resolveTraces(ctx context.Context) ([]*callTrace, error) {
+	if _, err := b.resolveHeader(ctx); err != nil {
+		return nil, err
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.traces != nil {
+		return b.traces, nil
+	}
+	backend, ok := b.r.backend.(tracers.Backend)
+	if !ok {
+		return nil, errTracingUnsupported
+	}
+	if err := spendTraceBudget(ctx); err != nil {
+		return nil, err
+	}
+	if err := rpc.CheckAccess(ctx, traceMethod); err != nil {
+		return nil, err
+	}
+	tracer := "callTracer"
+	config := &tracers.TraceConfig{Tracer: &tracer}
+	if timeout := b.r.backend.RPCEVMTimeout(); timeout > 0 {
+		t := timeout.String()
+		config.Timeout = &t
+	}
+	results, err := tracers.NewAPI(backend).TraceBlockByHash(ctx, b.hash, config)
+	if err != nil {
+		return nil, err
+	}
+	traces := make([]*callTrace, len(results))
+	for i, result := range results {
+		if result.Error != "" {
+			return nil, fmt.Errorf("failed to trace transaction %s: %s", result.TxHash.Hex(), result.Error)
+		}
+		blob, err := json.Marshal(result.Result)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(blob, &traces[i]); err != nil {
+			return nil, err
+		}
+	}
+	b.traces = traces
+	return traces, nil
+}
+
+// resolveTrace returns the call trace of the transaction, or nil if it is
+// pending.
+func (t *Transaction) resolveTrace(ctx context.Context) (*callTrace, error) {
+	_, block := t.resolve(ctx)
+	if block == nil {
+		return nil, nil
+	}
+	traces, err := block.resolveTraces(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if t.index >= uint64(len(traces)) {
+		return nil, fmt.Errorf("trace of transaction %s not found", t.hash.Hex())
+	}
+	return traces[t.index], nil
+}
+
+func (t *Transaction) Trace(ctx context.Context) (*CallFrame, error) {
+	trace, err := t.resolveTrace(ctx)
+	if err != nil || trace == nil {
+		return nil, err
+	}
+	return &CallFrame{trace: trace}, nil
+}
+
+func (t *Transaction) InternalTransfers(ctx context.Context) (*[]*InternalTransfer, error) {
+	trace, err := t.resolveTrace(ctx)
+	if err != nil || trace == nil {
+		return nil, err
+	}
+	transfers := collectTransfers(trace, nil, []*InternalTransfer{})
+	return &transfers, nil
+}
+
+// collectTransfers appends the value transfers of the nested calls of a call to
+// the given list. The calls which failed are skipped along with their nested
+// calls, as their transfers are reverted.
+func collectTransfers(call *callTrace, address []Long, transfers []*InternalTransfer) []*InternalTransfer {
+	if call.Error != "" {
+		return transfers
+	}
+	if len(address) > 0 && call.To != nil && call.Value != nil && call.Value.ToInt().Sign() > 0 {
+		switch call.Type {
+		case "CALL", "CREATE", "CREATE2", "SELFDESTRUCT":
+			transfers = append(transfers, &InternalTransfer{call: call, address: address})
+		}
+	}
+	for i, child := range call.Calls {
+		transfers = collectTransfers(child, append(address[:len(address):len(address)], Long(i)), transfers)
+	}
+	return transfers
+}
+
+// CallFrame represents a call made while executing a transaction.
+type CallFrame struct {
+	trace *callTrace
+}
+
+func (c *CallFrame) Type(ctx context.Context) string {
+	return c.trace.Type
+}
+
+func (c *CallFrame) From(ctx context.Context) common.Address {
+	return c.trace.From
+}
+
+func (c *CallFrame) To(ctx context.Context) *common.Address {
+	return c.trace.To
+}
+
+func (c *CallFrame) Value(ctx context.Context) *hexutil.Big {
+	return c.trace.Value
+}
+
+func (c *CallFrame) Gas(ctx context.Context) hexutil.Uint64 {
+	return c.trace.Gas
+}
+
+func (c *CallFrame) GasUsed(ctx context.Context) hexutil.Uint64 {
+	return c.trace.GasUsed
+}
+
+func (c *CallFrame) Input(ctx context.Context) hexutil.Bytes {
+	return c.trace.Input
+}
+
+func (c *CallFrame) Output(ctx context.Context) *hexutil.Bytes {
+	return c.trace.Output
+}
+
+func (c *CallFrame) Error(ctx context.Context) *string {
+	if c.trace.Error == "" {
+		return nil
+	}
+	return &c.trace.Error
+}
+
+func (c *CallFrame) RevertReason(ctx context.Context) *string {
+	if c.trace.RevertReason == "" {
+		return nil
+	}
+	return &c.trace.RevertReason
+}
+
+func (c *CallFrame) Calls(ctx context.Context) []*CallFrame {
+	ret := make([]*CallFrame, 0, len(c.trace.Calls))
+	for _, call := range c.trace.Calls {
+		ret = append(ret, &CallFrame{trace: call})
+	}
+	return ret
+}
+
+// InternalTransfer represents a value transfer made by a nested call.
+type InternalTransfer struct {
+	call    *callTrace
+	address []Long
+}
+
+func (t *InternalTransfer) Type(ctx context.Context) string {
+	return t.call.Type
+}
+
+func (t *InternalTransfer) From(ctx context.Context) common.Address {
+	return t.call.From
+}
+
+func (t *InternalTransfer) To(ctx context.Context) common.Address {
+	return *t.call.To
+}
+
+func (t *InternalTransfer) Value(ctx context.Context) hexutil.Big {
+	return *t.call.Value
+}
+
+func (t *InternalTransfer) TraceAddress(ctx context.Context) []Long {
+	return t.address
+}
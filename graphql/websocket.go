@@ -0,0 +1,320 @@
+// Copyright 2025 The core-geth Authors
+// This file is part of the core-geth library.
+//
+// The core-geth library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The core-geth library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the core-geth library. If not, see <http://www.gnu.org/licenses/>.
+
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/gorilla/websocket"
+	"github.com/graph-gophers/graphql-go"
+	gqlErrors "github.com/graph-gophers/graphql-go/errors"
+)
+
+// wsProtocol is the websocket subprotocol of the GraphQL subscriptions, see
+// https://github.com/apollographql/subscriptions-transport-ws/blob/master/PROTOCOL.md
+const wsProtocol = "graphql-ws"
+
+// Message types of the graphql-ws protocol.
+const (
+	wsConnectionInit      = "connection_init"      // Client requests to start the connection
+	wsConnectionAck       = "connection_ack"       // Server accepts the connection
+	wsConnectionKeepAlive = "ka"                   // Server keeps the connection alive
+	wsConnectionTerminate = "connection_terminate" // Client terminates the connection
+	wsStart               = "start"                // Client starts an operation
+	wsStop                = "stop"                 // Client stops an operation
+	wsData                = "data"                 // Server sends a result of an operation
+	wsError               = "error"                // Server reports an operation that failed to start
+	wsComplete            = "complete"             // Server reports an operation as finished
+)
+
+const (
+	wsReadLimit         = 1024 * 1024      // Maximum size of the client messages
+	wsWriteTimeout      = 10 * time.Second // Maximum time to write a message to the client
+	wsKeepAliveInterval = 30 * time.Second // Interval between keep alive messages
+	wsMaxOperations     = 100              // Maximum number of running operations, subscriptions included, per connection
+)
+
+// wsMessage is a message of the graphql-ws protocol.
+type wsMessage struct {
+	ID      string          `json:"id,omitempty"`
+	Type    string          `json:"type"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+// wsHandler serves GraphQL operations over websocket, using the graphql-ws
+// protocol. Queries and mutations are answered by the main schema, and
+// subscriptions by the subscription schema.
+type wsHandler struct {
+	schema       *graphql.Schema
+	subscription *graphql.Schema
+	upgrader     websocket.Upgrader
+}
+
+// newWSHandler creates a websocket handler accepting connections from the same
+// origin, or from the allowed websocket origins.
+func newWSHandler(schema, subscription *graphql.Schema, origins []string) *wsHandler {
+	return &wsHandler{
+		schema:       schema,
+		subscription: subscription,
+		upgrader: websocket.Upgrader{
+			Subprotocols: []string{wsProtocol},
+			CheckOrigin:  wsOriginChecker(origins),
+		},
+	}
+}
+
+// wsOriginChecker returns a function checking whether a websocket handshake
+// request comes from the same origin, or from one of the allowed ones. Requests
+// without an origin are rejected.
+func wsOriginChecker(origins []string) func(*http.Request) bool {
+	return func(r *http.Request) bool {
+		origin := r.Header.Get("Origin")
+		if origin == "" {
+			log.Warn("Rejected GraphQL websocket connection without origin")
+			return false
+		}
+		if u, err := url.Parse(origin); err == nil && strings.EqualFold(u.Host, r.Host) {
+			return true
+		}
+		for _, allowed := range origins {
+			if allowed == "*" || strings.EqualFold(allowed, origin) {
+				return true
+			}
+		}
+		log.Warn("Rejected GraphQL websocket connection", "origin", origin)
+		return false
+	}
+}
+
+func (h *wsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	conn, err := h.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return // The upgrader already responded
+	}
+	if conn.Subprotocol() != wsProtocol {
+		msg := websocket.FormatCloseMessage(websocket.CloseProtocolError, "unsupported subprotocol")
+		conn.WriteControl(websocket.CloseMessage, msg, time.Now().Add(wsWriteTimeout))
+		conn.Close()
+		return
+	}
+	conn.SetReadLimit(wsReadLimit)
+
+	c := &wsConn{
+		handler: h,
+		conn:    conn,
+		ops:     make(map[string]context.CancelFunc),
+	}
+	c.serve(r.Context())
+}
+
+// wsConn is a websocket connection serving GraphQL operations.
+type wsConn struct {
+	handler *wsHandler
+	conn    *websocket.Conn
+
+	ops   map[string]context.CancelFunc // Running operations by client assigned id
+	opsMu sync.Mutex
+	wg    sync.WaitGroup
+
+	writeMu sync.Mutex
+}
+
+// serve handles the client messages until the connection is terminated. The
+// operations are run with the values of the given upgrade request context, like
+// the access of the client.
+func (c *wsConn) serve(reqCtx context.Context) {
+	ctx, cancel := context.WithCancel(context.WithoutCancel(reqCtx))
+	defer func() {
+		cancel()
+		c.wg.Wait()
+		c.conn.Close()
+	}()
+
+	var initialized bool
+	for {
+		var msg wsMessage
+		if err := c.conn.ReadJSON(&msg); err != nil {
+			return
+		}
+		switch msg.Type {
+		case wsConnectionInit:
+			if initialized {
+				continue
+			}
+			initialized = true
+			c.write(&wsMessage{Type: wsConnectionAck})
+
+			c.wg.Add(1)
+			go c.keepAlive(ctx)
+
+		case wsStart:
+			if !initialized {
+				c.writeError(msg.ID, "connection not initialized")
+				continue
+			}
+			c.start(ctx, msg.ID, msg.Payload)
+
+		case wsStop:
+			c.stop(msg.ID)
+
+		case wsConnectionTerminate:
+			return
+
+		default:
+			c.writeError(msg.ID, "unknown message type "+msg.Type)
+		}
+	}
+}
+
+// keepAlive periodically notifies the client that the connection is alive.
+func (c *wsConn) keepAlive(ctx context.Context) {
+	defer c.wg.Done()
+
+	ticker := time.NewTicker(wsKeepAliveInterval)
+	defer ticker.Stop()
+
+	c.write(&wsMessage{Type: wsConnectionKeepAlive})
+	for {
+		select {
+		case <-ticker.C:
+			c.write(&wsMessage{Type: wsConnectionKeepAlive})
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// start runs an operation, sending its results until it finishes or is stopped.
+func (c *wsConn) start(ctx context.Context, id string, payload json.RawMessage) {
+	var params struct {
+		Query         string                 `json:"query"`
+		OperationName string                 `json:"operationName"`
+		Variables     map[string]interface{} `json:"variables"`
+	}
+	if err := json.Unmarshal(payload, &params); err != nil {
+		c.writeError(id, err.Error())
+		return
+	}
+	c.opsMu.Lock()
+	if _, ok := c.ops[id]; ok || id == "" {
+		c.opsMu.Unlock()
+		c.writeError(id, "invalid operation id")
+		return
+	}
+	if len(c.ops) >= wsMaxOperations {
+		c.opsMu.Unlock()
+		c.writeError(id, "too many running operations")
+		return
+	}
+	if err := rpc.CheckAccess(ctx, queryMethod); err != nil {
+		c.opsMu.Unlock()
+		c.writeError(id, err.Error())
+		return
+	}
+	opCtx, cancel := context.WithCancel(ctx)
+	c.ops[id] = cancel
+	c.opsMu.Unlock()
+
+	// Operations only valid for the main schema are queries or mutations served
+	// by it, anything else is attempted as a subscription.
+	var responses <-chan interface{}
+	if !c.handler.isQuery(params.Query, params.Variables) {
+		var err error
+		responses, err = c.handler.subscription.Subscribe(opCtx, params.Query, params.OperationName, params.Variables)
+		if err != nil {
+			c.stop(id)
+			c.writeError(id, err.Error())
+			return
+		}
+	}
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+
+		if responses == nil {
+			ch := make(chan interface{}, 1)
+			ch <- c.handler.schema.Exec(withTraceBudget(opCtx, maxTracedBlocks), params.Query, params.OperationName, params.Variables)
+			close(ch)
+			responses = ch
+		}
+		for response := range responses {
+			data, err := json.Marshal(response)
+			if err != nil {
+				log.Warn("Failed to encode GraphQL response", "err", err)
+				continue
+			}
+			c.write(&wsMessage{ID: id, Type: wsData, Payload: data})
+		}
+		// Report the completion unless stopped by the client or the connection
+		// is closing
+		c.opsMu.Lock()
+		_, running := c.ops[id]
+		delete(c.ops, id)
+		c.opsMu.Unlock()
+
+		cancel()
+		if running && ctx.Err() == nil {
+			c.write(&wsMessage{ID: id, Type: wsComplete})
+		}
+	}()
+}
+
+// isQuery reports whether an operation is to be executed by the main schema.
+// The validation doesn't reject subscriptions against a schema without any, so
+// both schemas are checked.
+func (h *wsHandler) isQuery(query string, variables map[string]interface{}) bool {
+	if errs := h.schema.ValidateWithVariables(query, variables); len(errs) != 0 {
+		return false
+	}
+	return len(h.subscription.ValidateWithVariables(query, variables)) != 0
+}
+
+// stop cancels a running operation.
+func (c *wsConn) stop(id string) {
+	c.opsMu.Lock()
+	defer c.opsMu.Unlock()
+
+	if cancel, ok := c.ops[id]; ok {
+		cancel()
+		delete(c.ops, id)
+	}
+}
+
+// write sends a message to the client.
+func (c *wsConn) write(msg *wsMessage) {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	c.conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+	if err := c.conn.WriteJSON(msg); err != nil {
+		log.Debug("Failed to write GraphQL websocket message", "err", err)
+	}
+}
+
+// writeError reports an operation which failed to start.
+func (c *wsConn) writeError(id string, message string) {
+	payload, _ := json.Marshal(&gqlErrors.QueryError{Message: message})
+	c.write(&wsMessage{ID: id, Type: wsError, Payload: payload})
+}
@@ -38,6 +38,7 @@ import (
 	"github.com/ethereum/go-ethereum/consensus/misc/eip1559"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/txpool"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/crypto"
@@ -266,6 +267,89 @@ func (s *TxPoolAPI) Inspect() map[string]map[string]map[string]string {
 	return content
 }
 
+// errTxPoolHistoryDisabled is returned if the lifecycle history of pooled
+// transactions is requested from a node not recording it.
+var errTxPoolHistoryDisabled = errors.New("txpool history disabled, enable it with --txpool.history")
+
+// RPCTxPoolEvent represents a lifecycle event of a pooled transaction.
+type RPCTxPoolEvent struct {
+	Hash        common.Hash     `json:"hash"`
+	Kind        string          `json:"kind"`
+	Time        hexutil.Uint64  `json:"time"`
+	Reason      string          `json:"reason,omitempty"`
+	ReplacedBy  *common.Hash    `json:"replacedBy,omitempty"`
+	BlockHash   *common.Hash    `json:"blockHash,omitempty"`
+	BlockNumber *hexutil.Uint64 `json:"blockNumber,omitempty"`
+}
+
+// newRPCTxPoolEvent returns a lifecycle event that will serialize to the RPC
+// representation.
+func newRPCTxPoolEvent(ev *txpool.TxEvent) *RPCTxPoolEvent {
+	result := &RPCTxPoolEvent{
+		Hash:   ev.Hash,
+		Kind:   string(ev.Kind),
+		Time:   hexutil.Uint64(ev.Time),
+		Reason: ev.Reason,
+	}
+	if ev.ReplacedBy != (common.Hash{}) {
+		result.ReplacedBy = &ev.ReplacedBy
+	}
+	if ev.BlockHash != (common.Hash{}) {
+		result.BlockHash = &ev.BlockHash
+		result.BlockNumber = (*hexutil.Uint64)(&ev.BlockNumber)
+	}
+	return result
+}
+
+// History returns the recorded lifecycle events of a transaction, oldest first.
+func (s *TxPoolAPI) History(hash common.Hash) ([]*RPCTxPoolEvent, error) {
+	history := s.b.TxPoolHistory()
+	if history == nil {
+		return nil, errTxPoolHistoryDisabled
+	}
+	events := history.Get(hash)
+	result := make([]*RPCTxPoolEvent, len(events))
+	for i, ev := range events {
+		result[i] = newRPCTxPoolEvent(ev)
+	}
+	return result, nil
+}
+
+// Events creates a subscription that is triggered each time a lifecycle event
+// of a pooled transaction is recorded.
+func (s *TxPoolAPI) Events(ctx context.Context) (*rpc.Subscription, error) {
+	history := s.b.TxPoolHistory()
+	if history == nil {
+		return &rpc.Subscription{}, errTxPoolHistoryDisabled
+	}
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+	rpcSub := notifier.CreateSubscription()
+
+	go func() {
+		events := make(chan []*txpool.TxEvent, 128)
+		sub := history.SubscribeEvents(events)
+		defer sub.Unsubscribe()
+
+		for {
+			select {
+			case evs := <-events:
+				for _, ev := range evs {
+					notifier.Notify(rpcSub.ID, newRPCTxPoolEvent(ev))
+				}
+			case <-rpcSub.Err():
+				return
+			case <-notifier.Closed():
+				return
+			}
+		}
+	}()
+
+	return rpcSub, nil
+}
+
 // EthereumAccountAPI provides an API to access accounts managed by this node.
 // It offers only methods that can retrieve accounts.
 type EthereumAccountAPI struct {
@@ -1045,6 +1129,37 @@ func (diff *BlockOverrides) Apply(blockCtx *vm.BlockContext) {
 	}
 }
 
+// MakeHeader returns a copy of the header with the overrides applied. The
+// blob base fee can't be set on headers and is not overridden.
+func (diff *BlockOverrides) MakeHeader(header *types.Header) *types.Header {
+	if diff == nil {
+		return header
+	}
+	h := types.CopyHeader(header)
+	if diff.Number != nil {
+		h.Number = diff.Number.ToInt()
+	}
+	if diff.Difficulty != nil {
+		h.Difficulty = diff.Difficulty.ToInt()
+	}
+	if diff.Time != nil {
+		h.Time = uint64(*diff.Time)
+	}
+	if diff.GasLimit != nil {
+		h.GasLimit = uint64(*diff.GasLimit)
+	}
+	if diff.Coinbase != nil {
+		h.Coinbase = *diff.Coinbase
+	}
+	if diff.Random != nil {
+		h.MixDigest = *diff.Random
+	}
+	if diff.BaseFee != nil {
+		h.BaseFee = diff.BaseFee.ToInt()
+	}
+	return h
+}
+
 // ChainContextBackend provides methods required to implement ChainContext.
 type ChainContextBackend interface {
 	Engine() consensus.Engine
@@ -1104,6 +1219,13 @@ func doCall(ctx context.Context, b Backend, args TransactionArgs, state *state.S
 	}
 	evm := b.GetEVM(ctx, msg, state, header, &vm.Config{NoBaseFee: true}, &blockCtx)
 
+	gp := new(core.GasPool).AddGas(math.MaxUint64)
+	return applyMessageWithEVM(ctx, evm, msg, state, timeout, gp)
+}
+
+// applyMessageWithEVM executes the message with the given EVM, aborting it when
+// the context is done.
+func applyMessageWithEVM(ctx context.Context, evm *vm.EVM, msg *core.Message, state *state.StateDB, timeout time.Duration, gp *core.GasPool) (*core.ExecutionResult, error) {
 	// Wait for the context to be done and cancel the evm. Even if the
 	// EVM has finished, cancelling may be done (repeatedly)
 	go func() {
@@ -1112,7 +1234,6 @@ func doCall(ctx context.Context, b Backend, args TransactionArgs, state *state.S
 	}()
 
 	// Execute the message.
-	gp := new(core.GasPool).AddGas(math.MaxUint64)
 	result, err := core.ApplyMessage(evm, msg, gp)
 	if err := state.Error(); err != nil {
 		return nil, err
@@ -1476,6 +1597,10 @@ type RPCTransaction struct {
 	R                   *hexutil.Big      `json:"r"`
 	S                   *hexutil.Big      `json:"s"`
 	YParity             *hexutil.Uint64   `json:"yParity,omitempty"`
+	Sponsor             *common.Address   `json:"sponsor,omitempty"`
+	SponsorV            *hexutil.Big      `json:"sponsorV,omitempty"`
+	SponsorR            *hexutil.Big      `json:"sponsorR,omitempty"`
+	SponsorS            *hexutil.Big      `json:"sponsorS,omitempty"`
 }
 
 // newRPCTransaction returns a transaction that will serialize to the RPC
@@ -1550,6 +1675,28 @@ func newRPCTransaction(tx *types.Transaction, blockHash common.Hash, blockNumber
 		}
 		result.MaxFeePerBlobGas = (*hexutil.Big)(tx.BlobGasFeeCap())
 		result.BlobVersionedHashes = tx.BlobHashes()
+
+	case types.SponsoredTxType:
+		al := tx.AccessList()
+		yparity := hexutil.Uint64(v.Sign())
+		result.Accesses = &al
+		result.ChainID = (*hexutil.Big)(tx.ChainId())
+		result.YParity = &yparity
+		result.GasFeeCap = (*hexutil.Big)(tx.GasFeeCap())
+		result.GasTipCap = (*hexutil.Big)(tx.GasTipCap())
+		// if the transaction has been mined, compute the effective gas price
+		if baseFee != nil && blockHash != (common.Hash{}) {
+			result.GasPrice = (*hexutil.Big)(effectiveGasPrice(tx, baseFee))
+		} else {
+			result.GasPrice = (*hexutil.Big)(tx.GasFeeCap())
+		}
+		if sponsor, err := types.Sponsor(signer, tx); err == nil {
+			result.Sponsor = &sponsor
+		}
+		sv, sr, ss := tx.RawSponsorSignatureValues()
+		result.SponsorV = (*hexutil.Big)(sv)
+		result.SponsorR = (*hexutil.Big)(sr)
+		result.SponsorS = (*hexutil.Big)(ss)
 	}
 	return result
 }
@@ -43,6 +43,7 @@ import (
 	"github.com/ethereum/go-ethereum/core/bloombits"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/txpool"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/crypto"
@@ -605,6 +606,7 @@ func (b testBackend) TxPoolContent() (map[common.Address][]*types.Transaction, m
 func (b testBackend) TxPoolContentFrom(addr common.Address) ([]*types.Transaction, []*types.Transaction) {
 	panic("implement me")
 }
+func (b testBackend) TxPoolHistory() *txpool.History { return nil }
 func (b testBackend) SubscribeNewTxsEvent(events chan<- core.NewTxsEvent) event.Subscription {
 	panic("implement me")
 }
@@ -622,7 +624,8 @@ func (b testBackend) SubscribeLogsEvent(ch chan<- []*types.Log) event.Subscripti
 func (b testBackend) SubscribePendingLogsEvent(ch chan<- []*types.Log) event.Subscription {
 	panic("implement me")
 }
-func (b testBackend) BloomStatus() (uint64, uint64) { panic("implement me") }
+func (b testBackend) BloomStatus() (uint64, uint64)    { panic("implement me") }
+func (b testBackend) LogIndexStatus() (uint64, uint64) { panic("implement me") }
 func (b testBackend) ServiceFilter(ctx context.Context, session *bloombits.MatcherSession) {
 	panic("implement me")
 }
@@ -984,6 +987,193 @@ func TestCall(t *testing.T) {
 	}
 }
 
+func TestSimulateV1(t *testing.T) {
+	t.Parallel()
+	// Initialize test accounts
+	var (
+		accounts = newAccounts(2)
+		genesis  = &genesisT.Genesis{
+			Config: params.MergedTestChainConfig,
+			Alloc: genesisT.GenesisAlloc{
+				accounts[0].addr: {Balance: big.NewInt(vars.Ether)},
+			},
+		}
+		genBlocks = 4
+		signer    = types.HomesteadSigner{}
+	)
+	api := NewBlockChainAPI(newTestBackend(t, genBlocks, genesis, beacon.New(ethash.NewFaker()), func(i int, b *core.BlockGen) {
+		tx, _ := types.SignTx(types.NewTx(&types.LegacyTx{Nonce: uint64(i), To: &accounts[1].addr, Value: big.NewInt(1000), Gas: vars.TxGas, GasPrice: b.BaseFee(), Data: nil}), signer, accounts[0].key)
+		b.AddTx(tx)
+		b.SetPoS()
+	}))
+	var (
+		latest    = rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber)
+		random    = newAccounts(3)
+		forwarder = random[0].addr // Emits LOG1(0x2a) and forwards 1 wei to the sink
+		sink      = random[1].addr
+		hasher    = random[2].addr // Returns the hash of the parent block
+		coinbase  = common.Address{0xc0}
+		timestamp = hexutil.Uint64(1 << 40)
+		value     = big.NewInt(1000)
+	)
+	results, err := api.SimulateV1(context.Background(), simOpts{
+		TraceTransfers: true,
+		BlockStateCalls: []simBlock{{
+			StateOverrides: &StateOverride{
+				forwarder: {Code: hex2Bytes("602a60006000a16000600060006000600173" + common.Bytes2Hex(sink[:]) + "5af100")},
+				hasher:    {Code: hex2Bytes("43600190034060005260206000f3")},
+			},
+			Calls: []TransactionArgs{{
+				From:  &accounts[0].addr,
+				To:    &forwarder,
+				Value: (*hexutil.Big)(value),
+			}},
+		}, {
+			BlockOverrides: &BlockOverrides{Time: &timestamp, Coinbase: &coinbase},
+			Calls: []TransactionArgs{{
+				From: &accounts[0].addr,
+				To:   &hasher,
+			}},
+		}, {
+			BlockOverrides: &BlockOverrides{Number: (*hexutil.Big)(big.NewInt(int64(genBlocks + 5)))},
+		}},
+	}, &latest)
+	if err != nil {
+		t.Fatalf("failed to simulate: %v", err)
+	}
+	// The gap before the last block is filled with empty blocks
+	if len(results) != 5 {
+		t.Fatalf("block count mismatch: have %d, want 5", len(results))
+	}
+	for i, res := range results {
+		if have, want := res.Block.NumberU64(), uint64(genBlocks+1+i); have != want {
+			t.Errorf("block %d: number mismatch: have %d, want %d", i, have, want)
+		}
+		if i > 0 && res.Block.ParentHash() != results[i-1].Block.Hash() {
+			t.Errorf("block %d: parent hash mismatch", i)
+		}
+		if res.Block.BaseFee().Sign() != 0 {
+			t.Errorf("block %d: base fee mismatch: have %v, want 0", i, res.Block.BaseFee())
+		}
+	}
+	// The ether transfers are reported as logs, along the contract ones in order
+	first := results[0]
+	if len(first.Calls) != 1 || first.Calls[0].Status != hexutil.Uint64(types.ReceiptStatusSuccessful) {
+		t.Fatalf("first block: unexpected calls %+v", first.Calls)
+	}
+	transferLog := func(from, to common.Address, value *big.Int) *types.Log {
+		return &types.Log{
+			Address: transferAddress,
+			Topics:  []common.Hash{transferTopic, common.BytesToHash(from[:]), common.BytesToHash(to[:])},
+			Data:    common.BigToHash(value).Bytes(),
+		}
+	}
+	want := []*types.Log{
+		transferLog(accounts[0].addr, forwarder, value),
+		{Address: forwarder, Topics: []common.Hash{common.BigToHash(big.NewInt(0x2a))}, Data: []byte{}},
+		transferLog(forwarder, sink, big.NewInt(1)),
+	}
+	logs := first.Calls[0].Logs
+	if len(logs) != len(want) {
+		t.Fatalf("log count mismatch: have %d, want %d", len(logs), len(want))
+	}
+	for i, log := range logs {
+		if log.Address != want[i].Address || !reflect.DeepEqual(log.Topics, want[i].Topics) || !bytes.Equal(log.Data, want[i].Data) {
+			t.Errorf("log %d: mismatch: have %+v, want %+v", i, log, want[i])
+		}
+		if log.Index != uint(i) || log.BlockHash != first.Block.Hash() || log.TxHash != first.Block.Transactions()[0].Hash() {
+			t.Errorf("log %d: position mismatch: index %d, block %x, tx %x", i, log.Index, log.BlockHash, log.TxHash)
+		}
+	}
+	// The block overrides apply and the simulated parent is visible to BLOCKHASH
+	second := results[1]
+	if second.Block.Time() != uint64(timestamp) || second.Block.Coinbase() != coinbase {
+		t.Errorf("second block: overrides mismatch: time %d, coinbase %x", second.Block.Time(), second.Block.Coinbase())
+	}
+	if have := common.BytesToHash(second.Calls[0].ReturnValue); have != first.Block.Hash() {
+		t.Errorf("second block: parent hash mismatch: have %x, want %x", have, first.Block.Hash())
+	}
+	if results[2].Block.Time() != uint64(timestamp)+timestampIncrement {
+		t.Errorf("gap block: time mismatch: have %d, want %d", results[2].Block.Time(), uint64(timestamp)+timestampIncrement)
+	}
+	// The results are returned as blocks with their calls, and the senders of
+	// the unsigned transactions
+	var dec struct {
+		Hash         common.Hash `json:"hash"`
+		Transactions []struct {
+			From common.Address `json:"from"`
+		} `json:"transactions"`
+		Calls []json.RawMessage `json:"calls"`
+	}
+	first.fullTx = true
+	enc, err := json.Marshal(first)
+	if err != nil {
+		t.Fatalf("failed to encode result: %v", err)
+	}
+	if err := json.Unmarshal(enc, &dec); err != nil {
+		t.Fatalf("failed to decode result: %v", err)
+	}
+	if dec.Hash != first.Block.Hash() || len(dec.Calls) != 1 || len(dec.Transactions) != 1 || dec.Transactions[0].From != accounts[0].addr {
+		t.Errorf("encoded result mismatch: %s", enc)
+	}
+	// Invalid requests are rejected
+	for i, tc := range []struct {
+		validate bool
+		blocks   []simBlock
+		code     int
+	}{
+		// Block numbers going backwards
+		{
+			blocks: []simBlock{
+				{BlockOverrides: &BlockOverrides{Number: (*hexutil.Big)(big.NewInt(int64(genBlocks + 2)))}},
+				{BlockOverrides: &BlockOverrides{Number: (*hexutil.Big)(big.NewInt(int64(genBlocks + 1)))}},
+			},
+			code: errCodeBlockNumberInvalid,
+		},
+		// Timestamps not increasing
+		{
+			blocks: []simBlock{
+				{BlockOverrides: &BlockOverrides{Time: &timestamp}},
+				{BlockOverrides: &BlockOverrides{Time: &timestamp}},
+			},
+			code: errCodeBlockTimestampInvalid,
+		},
+		// Too many blocks
+		{
+			blocks: []simBlock{
+				{BlockOverrides: &BlockOverrides{Number: (*hexutil.Big)(big.NewInt(int64(genBlocks + maxSimulateBlocks + 1)))}},
+			},
+			code: errCodeClientLimitExceeded,
+		},
+		// Nonce checked with validation
+		{
+			validate: true,
+			blocks: []simBlock{{Calls: []TransactionArgs{{
+				From:         &accounts[0].addr,
+				To:           &accounts[1].addr,
+				Nonce:        new(hexutil.Uint64),
+				MaxFeePerGas: (*hexutil.Big)(big.NewInt(vars.GWei)),
+			}}}},
+			code: errCodeNonceTooLow,
+		},
+		// Fee cap below the base fee with validation
+		{
+			validate: true,
+			blocks: []simBlock{{Calls: []TransactionArgs{{
+				From: &accounts[0].addr,
+				To:   &accounts[1].addr,
+			}}}},
+			code: errCodeInvalidParams,
+		},
+	} {
+		_, err := api.SimulateV1(context.Background(), simOpts{Validation: tc.validate, BlockStateCalls: tc.blocks}, &latest)
+		var rpcErr rpc.Error
+		if !errors.As(err, &rpcErr) || rpcErr.ErrorCode() != tc.code {
+			t.Errorf("test %d: error mismatch: have %v, want code %d", i, err, tc.code)
+		}
+	}
+}
+
 func TestSignTransaction(t *testing.T) {
 	t.Parallel()
 	// Initialize test accounts
@@ -0,0 +1,150 @@
+// Copyright 2025 The core-geth Authors
+// This file is part of the core-geth library.
+//
+// The core-geth library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The core-geth library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the core-geth library. If not, see <http://www.gnu.org/licenses/>.
+
+package ethapi
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+)
+
+var (
+	// keccak256("Transfer(address,address,uint256)")
+	transferTopic = common.HexToHash("ddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef")
+	// ERC-7528
+	transferAddress = common.HexToAddress("0xEeeeeEeeeEeEeeEeEeEeeEEEeeeeEeeeeeeeEEeE")
+)
+
+// transfer is an ether transfer turned into a log, with the number of logs of
+// the transaction emitted before it.
+type transfer struct {
+	position int
+	log      *types.Log
+}
+
+// transferTracer is an EVM logger turning the ether transfers of transactions
+// into ERC-20 like Transfer logs, emitted by the ERC-7528 address.
+//
+// The logs of the contracts are not seen by the logger, so the transfers are
+// positioned among them by the number of logs of the transaction in the state
+// at the time of the transfer. As the logs of the reverted frames are removed
+// from the state, this is the number of logs preceding the transfer in the end.
+type transferTracer struct {
+	logCount func() int // Number of logs of the running transaction in the state
+
+	frames    [][]transfer // Transfers of the open call frames
+	transfers []transfer   // Transfers of the last transaction
+}
+
+// newTransferTracer creates a transfer tracer, counting the logs of the running
+// transaction with the given function.
+func newTransferTracer(logCount func() int) *transferTracer {
+	return &transferTracer{logCount: logCount}
+}
+
+// reset clears the transfers, before running a new transaction.
+func (t *transferTracer) reset() {
+	t.frames = t.frames[:0]
+	t.transfers = nil
+}
+
+// logs merges the transfers of the last transaction into its logs, in order.
+func (t *transferTracer) logs(logs []*types.Log) []*types.Log {
+	if len(t.transfers) == 0 {
+		return logs
+	}
+	merged := make([]*types.Log, 0, len(logs)+len(t.transfers))
+	next := 0
+	for i, log := range logs {
+		for ; next < len(t.transfers) && t.transfers[next].position <= i; next++ {
+			merged = append(merged, t.transfers[next].log)
+		}
+		merged = append(merged, log)
+	}
+	for ; next < len(t.transfers); next++ {
+		merged = append(merged, t.transfers[next].log)
+	}
+	return merged
+}
+
+// enter opens a call frame, recording its transfer if any.
+func (t *transferTracer) enter(from common.Address, to common.Address, value *big.Int) {
+	var frame []transfer
+	if value != nil && value.Sign() > 0 {
+		frame = append(frame, transfer{
+			position: t.logCount(),
+			log: &types.Log{
+				Address: transferAddress,
+				Topics:  []common.Hash{transferTopic, common.BytesToHash(from.Bytes()), common.BytesToHash(to.Bytes())},
+				Data:    common.BigToHash(value).Bytes(),
+			},
+		})
+	}
+	t.frames = append(t.frames, frame)
+}
+
+// exit closes a call frame, passing its transfers to the parent frame unless
+// it failed and its transfers were reverted.
+func (t *transferTracer) exit(err error) {
+	if len(t.frames) == 0 {
+		return
+	}
+	frame := t.frames[len(t.frames)-1]
+	t.frames = t.frames[:len(t.frames)-1]
+	if err != nil {
+		return
+	}
+	if len(t.frames) == 0 {
+		t.transfers = frame
+		return
+	}
+	parent := len(t.frames) - 1
+	t.frames[parent] = append(t.frames[parent], frame...)
+}
+
+func (t *transferTracer) CaptureTxStart(gasLimit uint64) {}
+
+func (t *transferTracer) CaptureTxEnd(restGas uint64) {}
+
+func (t *transferTracer) CaptureStart(env *vm.EVM, from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) {
+	t.enter(from, to, value)
+}
+
+func (t *transferTracer) CaptureEnd(output []byte, gasUsed uint64, err error) {
+	t.exit(err)
+}
+
+func (t *transferTracer) CaptureEnter(typ vm.OpCode, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) {
+	// The value of delegate calls is the one of the parent frame, and call codes
+	// send it to the caller itself
+	if typ == vm.DELEGATECALL || typ == vm.CALLCODE {
+		value = nil
+	}
+	t.enter(from, to, value)
+}
+
+func (t *transferTracer) CaptureExit(output []byte, gasUsed uint64, err error) {
+	t.exit(err)
+}
+
+func (t *transferTracer) CaptureState(pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, rData []byte, depth int, err error) {
+}
+
+func (t *transferTracer) CaptureFault(pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, depth int, err error) {
+}
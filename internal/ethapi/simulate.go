@@ -0,0 +1,452 @@
+// Copyright 2025 The core-geth Authors
+// This file is part of the core-geth library.
+//
+// The core-geth library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The core-geth library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the core-geth library. If not, see <http://www.gnu.org/licenses/>.
+
+package ethapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/consensus/misc/eip1559"
+	"github.com/ethereum/go-ethereum/consensus/misc/eip4844"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/params/types/ctypes"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+const (
+	// maxSimulateBlocks is the maximum number of blocks that can be simulated
+	// in a single request.
+	maxSimulateBlocks = 256
+
+	// timestampIncrement is the default increment between block timestamps.
+	timestampIncrement = 1
+)
+
+// simBlock is a batch of calls to be simulated sequentially.
+type simBlock struct {
+	BlockOverrides *BlockOverrides
+	StateOverrides *StateOverride
+	Calls          []TransactionArgs
+}
+
+// simCallResult is the result of a simulated call.
+type simCallResult struct {
+	ReturnValue hexutil.Bytes  `json:"returnData"`
+	Logs        []*types.Log   `json:"logs"`
+	GasUsed     hexutil.Uint64 `json:"gasUsed"`
+	Status      hexutil.Uint64 `json:"status"`
+	Error       *callError     `json:"error,omitempty"`
+}
+
+// simOpts are the inputs to eth_simulateV1.
+type simOpts struct {
+	BlockStateCalls        []simBlock
+	TraceTransfers         bool
+	Validation             bool
+	ReturnFullTransactions bool
+}
+
+// simBlockResult is the result of a simulated block.
+type simBlockResult struct {
+	fullTx      bool
+	chainConfig ctypes.ChainConfigurator
+	Block       *types.Block
+	Calls       []simCallResult
+	senders     []common.Address // Senders of the unsigned transactions
+}
+
+func (r *simBlockResult) MarshalJSON() ([]byte, error) {
+	fields := RPCMarshalBlock(r.Block, true, r.fullTx, r.chainConfig)
+	if r.fullTx {
+		// The simulated transactions are not signed, the senders can't be
+		// recovered from them
+		for i, tx := range fields.Transactions {
+			tx.(*RPCTransaction).From = r.senders[i]
+		}
+	}
+	enc, err := json.Marshal(fields)
+	if err != nil {
+		return nil, err
+	}
+	var out map[string]json.RawMessage
+	if err := json.Unmarshal(enc, &out); err != nil {
+		return nil, err
+	}
+	if out["calls"], err = json.Marshal(r.Calls); err != nil {
+		return nil, err
+	}
+	return json.Marshal(out)
+}
+
+// simulator is a stateful object that simulates a series of blocks.
+// it is not safe for concurrent use.
+type simulator struct {
+	b              Backend
+	state          *state.StateDB
+	base           *types.Header
+	chainConfig    ctypes.ChainConfigurator
+	gp             *core.GasPool
+	traceTransfers bool
+	validate       bool
+	fullTx         bool
+}
+
+// execute runs the simulation of a series of blocks.
+func (sim *simulator) execute(ctx context.Context, blocks []simBlock) ([]*simBlockResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	var (
+		cancel  context.CancelFunc
+		timeout = sim.b.RPCEVMTimeout()
+	)
+	if timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+	} else {
+		ctx, cancel = context.WithCancel(ctx)
+	}
+	// Make sure the context is cancelled when the call has completed
+	// this makes sure resources are cleaned up.
+	defer cancel()
+
+	blocks, err := sim.sanitizeChain(blocks)
+	if err != nil {
+		return nil, err
+	}
+	// Prepare block headers with preliminary fields for the response.
+	headers := sim.makeHeaders(blocks)
+	var (
+		results = make([]*simBlockResult, len(blocks))
+		parent  = sim.base
+	)
+	for bi, block := range blocks {
+		result, callResults, senders, err := sim.processBlock(ctx, &block, headers[bi], parent, headers[:bi], timeout)
+		if err != nil {
+			return nil, err
+		}
+		headers[bi] = result.Header()
+		results[bi] = &simBlockResult{fullTx: sim.fullTx, chainConfig: sim.chainConfig, Block: result, Calls: callResults, senders: senders}
+		parent = headers[bi]
+	}
+	return results, nil
+}
+
+// processBlock runs the calls of a block on top of the state and assembles the
+// resulting block. The block rewards are not applied.
+func (sim *simulator) processBlock(ctx context.Context, block *simBlock, header, parent *types.Header, headers []*types.Header, timeout time.Duration) (*types.Block, []simCallResult, []common.Address, error) {
+	// Set the header fields depending on the parent block, the parent hash is
+	// needed for the BLOCKHASH lookups
+	header.ParentHash = parent.Hash()
+	config := sim.chainConfig
+	if config.IsEnabled(config.GetEIP1559Transition, header.Number) {
+		// Without validation the base fee is zero unless overridden, as calls
+		// with a gas price below it would fail otherwise
+		if header.BaseFee == nil {
+			if sim.validate {
+				header.BaseFee = eip1559.CalcBaseFee(config, parent)
+			} else {
+				header.BaseFee = new(big.Int)
+			}
+		}
+	}
+	if config.IsEnabledByTime(config.GetEIP4844TransitionTime, &header.Time) || config.IsEnabled(config.GetEIP4844Transition, header.Number) {
+		var excess uint64
+		if parent.ExcessBlobGas != nil {
+			excess = eip4844.CalcExcessBlobGas(*parent.ExcessBlobGas, *parent.BlobGasUsed)
+		}
+		header.ExcessBlobGas = &excess
+	}
+	if config.IsEnabledByTime(config.GetEIP4788TransitionTime, &header.Time) || config.IsEnabled(config.GetEIP4788Transition, header.Number) {
+		header.ParentBeaconRoot = new(common.Hash)
+	}
+	withdrawals := config.IsEnabledByTime(config.GetEIP4895TransitionTime, &header.Time) || config.IsEnabled(config.GetEIP4895Transition, header.Number)
+
+	blockContext := core.NewEVMBlockContext(header, NewChainContext(ctx, &simBackend{b: sim.b, base: sim.base, headers: headers}), nil)
+	if block.BlockOverrides.BlobBaseFee != nil {
+		blockContext.BlobBaseFee = block.BlockOverrides.BlobBaseFee.ToInt()
+	}
+	if err := block.StateOverrides.Apply(sim.state); err != nil {
+		return nil, nil, nil, err
+	}
+	var (
+		gasUsed, blobGasUsed uint64
+		txes                 = make([]*types.Transaction, len(block.Calls))
+		callResults          = make([]simCallResult, len(block.Calls))
+		receipts             = make([]*types.Receipt, len(block.Calls))
+		senders              = make([]common.Address, len(block.Calls))
+
+		txHash   common.Hash
+		tracer   *transferTracer
+		vmConfig = vm.Config{NoBaseFee: !sim.validate}
+	)
+	if sim.traceTransfers {
+		tracer = newTransferTracer(func() int {
+			return len(sim.state.GetLogs(txHash, header.Number.Uint64(), common.Hash{}))
+		})
+		vmConfig.Tracer = tracer
+	}
+	evm := vm.NewEVM(blockContext, vm.TxContext{GasPrice: new(big.Int)}, sim.state, config, vmConfig)
+	if beaconRoot := header.ParentBeaconRoot; beaconRoot != nil {
+		core.ProcessBeaconBlockRoot(*beaconRoot, evm, sim.state)
+	}
+	for i, call := range block.Calls {
+		if err := ctx.Err(); err != nil {
+			return nil, nil, nil, err
+		}
+		if err := sim.sanitizeCall(&call, header, gasUsed); err != nil {
+			return nil, nil, nil, err
+		}
+		tx := call.toTransaction()
+		txes[i], senders[i], txHash = tx, call.from(), tx.Hash()
+		sim.state.SetTxContext(txHash, i)
+
+		// The gas was capped by the sanitization already
+		msg, err := call.ToMessage(0, header.BaseFee)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		msg.Nonce = uint64(*call.Nonce)
+		msg.SkipAccountChecks = !sim.validate
+
+		evm.Reset(core.NewEVMTxContext(msg), sim.state)
+		if tracer != nil {
+			tracer.reset()
+		}
+		result, err := applyMessageWithEVM(ctx, evm, msg, sim.state, timeout, sim.gp)
+		if err != nil {
+			return nil, nil, nil, txValidationError(err)
+		}
+		// Update the state with pending changes
+		var root []byte
+		eip161d := config.IsEnabled(config.GetEIP161dTransition, header.Number)
+		if config.IsEnabled(config.GetEIP658Transition, header.Number) {
+			sim.state.Finalise(eip161d)
+		} else {
+			root = sim.state.IntermediateRoot(eip161d).Bytes()
+		}
+		gasUsed += result.UsedGas
+		receipts[i] = core.MakeReceipt(evm, result, sim.state, header.Number, common.Hash{}, tx, gasUsed, root)
+		blobGasUsed += receipts[i].BlobGasUsed
+
+		logs := receipts[i].Logs
+		if tracer != nil {
+			logs = tracer.logs(logs)
+		}
+		if logs == nil {
+			logs = []*types.Log{}
+		}
+		callRes := simCallResult{ReturnValue: result.Return(), Logs: logs, GasUsed: hexutil.Uint64(result.UsedGas)}
+		if result.Failed() {
+			callRes.Status = hexutil.Uint64(types.ReceiptStatusFailed)
+			if errors.Is(result.Err, vm.ErrExecutionReverted) {
+				revertErr := newRevertError(result.Revert())
+				callRes.Error = &callError{Message: revertErr.Error(), Code: errCodeReverted, Data: revertErr.reason}
+			} else {
+				callRes.Error = &callError{Message: result.Err.Error(), Code: errCodeVMError}
+			}
+		} else {
+			callRes.Status = hexutil.Uint64(types.ReceiptStatusSuccessful)
+		}
+		callResults[i] = callRes
+	}
+	header.Root = sim.state.IntermediateRoot(config.IsEnabled(config.GetEIP161dTransition, header.Number))
+	header.GasUsed = gasUsed
+	if header.ExcessBlobGas != nil {
+		header.BlobGasUsed = &blobGasUsed
+	}
+	var b *types.Block
+	if withdrawals {
+		b = types.NewBlockWithWithdrawals(header, txes, nil, receipts, types.Withdrawals{}, trie.NewStackTrie(nil))
+	} else {
+		b = types.NewBlock(header, txes, nil, receipts, trie.NewStackTrie(nil))
+	}
+	// The logs were created before the block hash was known, and the transfer
+	// logs are not known to the state at all
+	var logIndex uint
+	for i, res := range callResults {
+		for _, log := range res.Logs {
+			log.BlockNumber = b.NumberU64()
+			log.BlockHash = b.Hash()
+			log.TxHash = txes[i].Hash()
+			log.TxIndex = uint(i)
+			log.Index = logIndex
+			logIndex++
+		}
+	}
+	return b, callResults, senders, nil
+}
+
+// sanitizeCall fills the missing fields of a call, checking it fits in the gas
+// left in the block.
+func (sim *simulator) sanitizeCall(call *TransactionArgs, header *types.Header, gasUsed uint64) error {
+	if call.Nonce == nil {
+		nonce := sim.state.GetNonce(call.from())
+		call.Nonce = (*hexutil.Uint64)(&nonce)
+	}
+	// Let the call use all the gas left in the block unless specified
+	if call.Gas == nil {
+		remaining := header.GasLimit - gasUsed
+		call.Gas = (*hexutil.Uint64)(&remaining)
+	}
+	if gasUsed+uint64(*call.Gas) > header.GasLimit {
+		return &blockGasLimitReachedError{fmt.Sprintf("block gas limit reached: %d >= %d", gasUsed, header.GasLimit)}
+	}
+	return call.callDefaults(sim.gp.Gas(), header.BaseFee, sim.chainConfig.GetChainID())
+}
+
+// sanitizeChain checks that the block numbers and timestamps are strictly
+// increasing, setting the missing ones. The gaps between block numbers are
+// filled with empty blocks.
+func (sim *simulator) sanitizeChain(blocks []simBlock) ([]simBlock, error) {
+	var (
+		res           = make([]simBlock, 0, len(blocks))
+		base          = sim.base
+		prevNumber    = base.Number
+		prevTimestamp = base.Time
+	)
+	for _, block := range blocks {
+		if block.BlockOverrides == nil {
+			block.BlockOverrides = new(BlockOverrides)
+		}
+		if block.BlockOverrides.Number == nil {
+			n := new(big.Int).Add(prevNumber, common.Big1)
+			block.BlockOverrides.Number = (*hexutil.Big)(n)
+		}
+		number := block.BlockOverrides.Number.ToInt()
+		diff := new(big.Int).Sub(number, prevNumber)
+		if diff.Sign() <= 0 {
+			return nil, &invalidBlockNumberError{fmt.Sprintf("block numbers must be in order: %d <= %d", number, prevNumber)}
+		}
+		if total := new(big.Int).Sub(number, base.Number); total.Cmp(big.NewInt(maxSimulateBlocks)) > 0 {
+			return nil, &clientLimitExceededError{message: "too many blocks"}
+		}
+		// Fill the gap with empty blocks
+		for i := uint64(1); i < diff.Uint64(); i++ {
+			n := new(big.Int).Add(prevNumber, new(big.Int).SetUint64(i))
+			t := prevTimestamp + timestampIncrement
+			res = append(res, simBlock{BlockOverrides: &BlockOverrides{Number: (*hexutil.Big)(n), Time: (*hexutil.Uint64)(&t)}})
+			prevTimestamp = t
+		}
+		prevNumber = number
+
+		var t uint64
+		if block.BlockOverrides.Time == nil {
+			t = prevTimestamp + timestampIncrement
+			block.BlockOverrides.Time = (*hexutil.Uint64)(&t)
+		} else {
+			t = uint64(*block.BlockOverrides.Time)
+			if t <= prevTimestamp {
+				return nil, &invalidBlockTimestampError{fmt.Sprintf("block timestamps must be in order: %d <= %d", t, prevTimestamp)}
+			}
+		}
+		prevTimestamp = t
+		res = append(res, block)
+	}
+	return res, nil
+}
+
+// makeHeaders makes the preliminary headers of the blocks, with the fields
+// inherited from the base block and the overrides. The fields depending on the
+// parent block are set while processing the blocks.
+func (sim *simulator) makeHeaders(blocks []simBlock) []*types.Header {
+	res := make([]*types.Header, len(blocks))
+	for bi, block := range blocks {
+		res[bi] = block.BlockOverrides.MakeHeader(&types.Header{
+			UncleHash:   types.EmptyUncleHash,
+			ReceiptHash: types.EmptyReceiptsHash,
+			TxHash:      types.EmptyTxsHash,
+			Coinbase:    sim.base.Coinbase,
+			Difficulty:  sim.base.Difficulty,
+			GasLimit:    sim.base.GasLimit,
+		})
+	}
+	return res
+}
+
+// simBackend resolves the headers of the simulated blocks on top of the
+// canonical ones, for the BLOCKHASH lookups.
+type simBackend struct {
+	b       ChainContextBackend
+	base    *types.Header
+	headers []*types.Header
+}
+
+func (b *simBackend) Engine() consensus.Engine {
+	return b.b.Engine()
+}
+
+func (b *simBackend) HeaderByNumber(ctx context.Context, number rpc.BlockNumber) (*types.Header, error) {
+	if uint64(number) == b.base.Number.Uint64() {
+		return b.base, nil
+	}
+	if uint64(number) < b.base.Number.Uint64() {
+		return b.b.HeaderByNumber(ctx, number)
+	}
+	for _, header := range b.headers {
+		if header.Number.Uint64() == uint64(number) {
+			return header, nil
+		}
+	}
+	return nil, errors.New("header not found")
+}
+
+// SimulateV1 executes series of calls in simulated blocks on top of the state
+// of the given block, returning the resulting blocks along with the results of
+// their calls.
+func (s *BlockChainAPI) SimulateV1(ctx context.Context, opts simOpts, blockNrOrHash *rpc.BlockNumberOrHash) ([]*simBlockResult, error) {
+	if len(opts.BlockStateCalls) == 0 {
+		return nil, &invalidParamsError{message: "empty input"}
+	} else if len(opts.BlockStateCalls) > maxSimulateBlocks {
+		return nil, &clientLimitExceededError{message: "too many blocks"}
+	}
+	if blockNrOrHash == nil {
+		n := rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber)
+		blockNrOrHash = &n
+	}
+	state, base, err := s.b.StateAndHeaderByNumberOrHash(ctx, *blockNrOrHash)
+	if state == nil || err != nil {
+		return nil, err
+	}
+	// The calls of all the blocks share the global gas cap
+	gasCap := s.b.RPCGasCap()
+	if gasCap == 0 {
+		gasCap = math.MaxUint64
+	}
+	sim := &simulator{
+		b:              s.b,
+		state:          state,
+		base:           base,
+		chainConfig:    s.b.ChainConfig(),
+		gp:             new(core.GasPool).AddGas(gasCap),
+		traceTransfers: opts.TraceTransfers,
+		validate:       opts.Validation,
+		fullTx:         opts.ReturnFullTransactions,
+	}
+	return sim.execute(ctx, opts.BlockStateCalls)
+}
@@ -0,0 +1,120 @@
+// Copyright 2025 The core-geth Authors
+// This file is part of the core-geth library.
+//
+// The core-geth library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The core-geth library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the core-geth library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package telemetry implements the OpenTelemetry tracing of block import, mining,
+// sync and RPC handling.
+//
+// The spans are created with the global tracer provider, which is a no-op until
+// the node installs an exporting one, so the tracing costs next to nothing when
+// disabled. The package only depends on the OpenTelemetry API, leaving the SDK
+// and the exporters to the executables.
+package telemetry
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Config contains the configuration of the OpenTelemetry tracing.
+type Config struct {
+	// Enabled turns on the export of the spans.
+	Enabled bool `toml:",omitempty"`
+
+	// Endpoint is the URL of the OTLP/HTTP collector the spans are exported to.
+	Endpoint string `toml:",omitempty"`
+
+	// SampleRatio is the fraction of the traces sampled, between 0 and 1.
+	SampleRatio float64 `toml:",omitempty"`
+
+	// InstanceName is reported as the service instance of the spans.
+	InstanceName string `toml:",omitempty"`
+}
+
+// DefaultConfig is the default tracing configuration, exporting all the traces
+// to a local collector once enabled.
+var DefaultConfig = Config{
+	Enabled:     false,
+	Endpoint:    "http://localhost:4318",
+	SampleRatio: 1,
+}
+
+// tracer creates the spans of all the instrumented packages. The global provider
+// delegates to the one installed later, if any.
+var tracer = otel.Tracer("github.com/ethereum/go-ethereum")
+
+// Tracer returns the tracer of the instrumented packages, for the spans needing
+// more options than StartSpan provides.
+func Tracer() trace.Tracer {
+	return tracer
+}
+
+// StartSpan starts a span with the given name and attributes, as child of the
+// span of the context if any. The returned function ends the span, recording
+// the error pointed to, if any.
+func StartSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, func(*error)) {
+	ctx, span := tracer.Start(ctx, name, trace.WithAttributes(attrs...))
+	return ctx, endFunc(span)
+}
+
+// StartSpanAt starts a span like StartSpan, but with the given start time, for
+// the phases measured before the span is created.
+func StartSpanAt(ctx context.Context, name string, start time.Time, attrs ...attribute.KeyValue) (context.Context, func(*error)) {
+	ctx, span := tracer.Start(ctx, name, trace.WithAttributes(attrs...), trace.WithTimestamp(start))
+	return ctx, endFunc(span)
+}
+
+// endFunc returns the function ending the span.
+func endFunc(span trace.Span) func(*error) {
+	return func(err *error) {
+		if err != nil && *err != nil {
+			RecordError(span, *err)
+		}
+		span.End()
+	}
+}
+
+// RecordError records the error on the span, setting its status to error.
+func RecordError(span trace.Span, err error) {
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
+
+// Attributes of the spans, following the OpenTelemetry semantic conventions for
+// the generic ones.
+var (
+	BlockNumber = attribute.Key("block.number").Int64
+	BlockHash   = attribute.Key("block.hash").String
+	BlockTxs    = attribute.Key("block.txs").Int
+	BlockGas    = attribute.Key("block.gas").Int64
+	ChainLength = attribute.Key("chain.length").Int
+
+	RPCSystem    = semconv.RPCSystemKey.String("jsonrpc")
+	RPCMethod    = semconv.RPCMethod
+	RPCService   = semconv.RPCService
+	RPCTransport = attribute.Key("rpc.transport").String
+	RPCErrorCode = semconv.RPCJsonrpcErrorCode
+
+	MinerInterrupt = attribute.Key("miner.interrupt").String
+
+	PeerID   = attribute.Key("peer.id").String
+	SyncMode = attribute.Key("sync.mode").String
+)
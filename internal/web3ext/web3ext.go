@@ -32,6 +32,7 @@ var Modules = map[string]string{
 	"les":      LESJs,
 	"vflux":    VfluxJs,
 	"dev":      DevJs,
+	"halo":     HaloJs,
 }
 
 const CliqueJs = `
@@ -115,6 +116,11 @@ web3._extend({
 			call: 'ethash_submitHashrate',
 			params: 2,
 		}),
+		new web3._extend.Method({
+			name: 'getStratumWorkers',
+			call: 'ethash_getStratumWorkers',
+			params: 0,
+		}),
 	]
 });
 `
@@ -143,6 +149,21 @@ web3._extend({
 			call: 'admin_removeTrustedPeer',
 			params: 1
 		}),
+		new web3._extend.Method({
+			name: 'banPeer',
+			call: 'admin_banPeer',
+			params: 2,
+			inputFormatter: [null, null]
+		}),
+		new web3._extend.Method({
+			name: 'unbanPeer',
+			call: 'admin_unbanPeer',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'listBans',
+			call: 'admin_listBans'
+		}),
 		new web3._extend.Method({
 			name: 'exportChain',
 			call: 'admin_exportChain',
@@ -831,6 +852,27 @@ web3._extend({
 				});
 			}, web3._extend.formatters.inputBlockNumberFormatter, null]
 		}),
+		new web3._extend.Method({
+			name: 'replayTransaction',
+			call: 'trace_replayTransaction',
+			params: 2
+		}),
+		new web3._extend.Method({
+			name: 'replayBlockTransactions',
+			call: 'trace_replayBlockTransactions',
+			params: 2,
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter, null]
+		}),
+		new web3._extend.Method({
+			name: 'rawTransaction',
+			call: 'trace_rawTransaction',
+			params: 2
+		}),
+		new web3._extend.Method({
+			name: 'get',
+			call: 'trace_get',
+			params: 2
+		}),
 	],
 	properties: []
 });
@@ -864,6 +906,11 @@ web3._extend({
 			call: 'txpool_contentFrom',
 			params: 1,
 		}),
+		new web3._extend.Method({
+			name: 'history',
+			call: 'txpool_history',
+			params: 1,
+		}),
 	]
 });
 `
@@ -971,3 +1018,30 @@ web3._extend({
 	],
 });
 `
+
+const HaloJs = `
+web3._extend({
+	property: 'halo',
+	methods:
+	[
+		new web3._extend.Method({
+			name: 'getBlockRewards',
+			call: 'halo_getBlockRewards',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getFundBalances',
+			call: 'halo_getFundBalances',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getSupplyDelta',
+			call: 'halo_getSupplyDelta',
+			params: 2,
+			inputFormatter: [web3._extend.utils.fromDecimal, web3._extend.utils.fromDecimal]
+		}),
+	],
+});
+`
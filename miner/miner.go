@@ -50,6 +50,7 @@ type Config struct {
 	Etherbase  common.Address `toml:",omitempty"` // Public address for block mining rewards
 	Notify     []string       `toml:",omitempty"` // HTTP URL list to be notified of new work packages (only useful in ethash).
 	NotifyFull bool           `toml:",omitempty"` // Notify with pending block headers instead of work packages
+	Stratum    string         `toml:",omitempty"` // TCP listen address of the stratum server for remote miners (only useful in ethash and lyra2).
 	ExtraData  hexutil.Bytes  `toml:",omitempty"` // Block extra data set by the miner
 	GasFloor   uint64         // Target gas floor for mined blocks.
 	GasCeil    uint64         // Target gas ceiling for mined blocks.
@@ -57,6 +58,14 @@ type Config struct {
 	Recommit   time.Duration  // The time interval for miner to re-create mining work.
 	Noverify   bool           // Disable remote mining solution verification(only useful in ethash).
 
+	StratumDifficulty    uint64 `toml:",omitempty"` // Initial share difficulty of the stratum connections
+	StratumMinDifficulty uint64 `toml:",omitempty"` // Minimum share difficulty of the stratum connections
+	StratumPassword      string `toml:",omitempty"` // Password the stratum workers must authorize with
+
+	TxOrdering        string           `toml:",omitempty"` // Transaction ordering policy of mined blocks (price, fifo or priority)
+	PriorityAddresses []common.Address `toml:",omitempty"` // Senders whose transactions are included first by the priority ordering
+	MaxSenderGasShare uint64           `toml:",omitempty"` // Maximum percentage of the block gas limit a single sender may use (0 = uncapped)
+
 	NewPayloadTimeout time.Duration // The maximum time allowance for creating a new payload
 }
 
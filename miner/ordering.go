@@ -18,6 +18,7 @@ package miner
 
 import (
 	"container/heap"
+	"fmt"
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -54,71 +55,147 @@ func newTxWithMinerFee(tx *txpool.LazyTransaction, from common.Address, baseFee
 	}, nil
 }
 
-// txByPriceAndTime implements both the sort and the heap interface, making it useful
-// for all at once sorting as well as individually adding and removing elements.
-type txByPriceAndTime []*txWithMinerFee
+// Names of the built-in transaction ordering policies.
+const (
+	OrderingPrice    = "price"    // Highest effective tip first, then first seen
+	OrderingFIFO     = "fifo"     // First seen first, regardless of the tip
+	OrderingPriority = "priority" // Priority senders first, then by price
+)
+
+// txOrdering is a block building policy deciding in which order the heads of
+// the per-account nonce sorted transaction lists are included in a block.
+type txOrdering interface {
+	// less reports whether transaction a should be included before transaction b.
+	less(a, b *txWithMinerFee) bool
+}
+
+// newTxOrdering creates the transaction ordering policy selected in the miner
+// configuration.
+func newTxOrdering(config *Config) (txOrdering, error) {
+	switch config.TxOrdering {
+	case "", OrderingPrice:
+		return priceOrdering{}, nil
+	case OrderingFIFO:
+		return fifoOrdering{}, nil
+	case OrderingPriority:
+		priority := make(map[common.Address]struct{}, len(config.PriorityAddresses))
+		for _, addr := range config.PriorityAddresses {
+			priority[addr] = struct{}{}
+		}
+		return priorityOrdering{priority: priority}, nil
+	default:
+		return nil, fmt.Errorf("unknown transaction ordering %q", config.TxOrdering)
+	}
+}
+
+// priceOrdering maximizes the profit of the miner, including the transactions
+// with the highest effective tip first.
+type priceOrdering struct{}
 
-func (s txByPriceAndTime) Len() int { return len(s) }
-func (s txByPriceAndTime) Less(i, j int) bool {
+func (priceOrdering) less(a, b *txWithMinerFee) bool {
 	// If the prices are equal, use the time the transaction was first seen for
 	// deterministic sorting
-	cmp := s[i].fees.Cmp(s[j].fees)
+	cmp := a.fees.Cmp(b.fees)
 	if cmp == 0 {
-		return s[i].tx.Time.Before(s[j].tx.Time)
+		return a.tx.Time.Before(b.tx.Time)
 	}
 	return cmp > 0
 }
-func (s txByPriceAndTime) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
 
-func (s *txByPriceAndTime) Push(x interface{}) {
-	*s = append(*s, x.(*txWithMinerFee))
+// fifoOrdering includes the transactions in the order they were first seen,
+// so that paying a higher tip doesn't allow jumping the queue.
+type fifoOrdering struct{}
+
+func (fifoOrdering) less(a, b *txWithMinerFee) bool {
+	// If the transactions were seen at the same time, prefer the highest price
+	// for deterministic sorting
+	if a.tx.Time.Equal(b.tx.Time) {
+		return a.fees.Gt(b.fees)
+	}
+	return a.tx.Time.Before(b.tx.Time)
+}
+
+// priorityOrdering includes the transactions of a set of priority senders
+// first, ordering by price within both priority and regular senders.
+type priorityOrdering struct {
+	priority map[common.Address]struct{}
 }
 
-func (s *txByPriceAndTime) Pop() interface{} {
-	old := *s
+func (o priorityOrdering) less(a, b *txWithMinerFee) bool {
+	_, ap := o.priority[a.from]
+	_, bp := o.priority[b.from]
+	if ap != bp {
+		return ap
+	}
+	return priceOrdering{}.less(a, b)
+}
+
+// txHeads implements both the sort and the heap interface over the next
+// transactions of each account, ordered by a transaction ordering policy.
+type txHeads struct {
+	txs   []*txWithMinerFee
+	order txOrdering
+}
+
+func (s *txHeads) Len() int           { return len(s.txs) }
+func (s *txHeads) Less(i, j int) bool { return s.order.less(s.txs[i], s.txs[j]) }
+func (s *txHeads) Swap(i, j int)      { s.txs[i], s.txs[j] = s.txs[j], s.txs[i] }
+
+func (s *txHeads) Push(x interface{}) {
+	s.txs = append(s.txs, x.(*txWithMinerFee))
+}
+
+func (s *txHeads) Pop() interface{} {
+	old := s.txs
 	n := len(old)
 	x := old[n-1]
 	old[n-1] = nil
-	*s = old[0 : n-1]
+	s.txs = old[0 : n-1]
 	return x
 }
 
-// transactionsByPriceAndNonce represents a set of transactions that can return
-// transactions in a profit-maximizing sorted order, while supporting removing
-// entire batches of transactions for non-executable accounts.
-type transactionsByPriceAndNonce struct {
+// orderedTransactions represents a set of transactions that can return
+// transactions in the order of a transaction ordering policy, while supporting
+// removing entire batches of transactions for non-executable accounts.
+type orderedTransactions struct {
 	txs     map[common.Address][]*txpool.LazyTransaction // Per account nonce-sorted list of transactions
-	heads   txByPriceAndTime                             // Next transaction for each unique account (price heap)
+	heads   *txHeads                                     // Next transaction for each unique account (ordering heap)
 	signer  types.Signer                                 // Signer for the set of transactions
 	baseFee *uint256.Int                                 // Current base fee
 }
 
 // newTransactionsByPriceAndNonce creates a transaction set that can retrieve
 // price sorted transactions in a nonce-honouring way.
+func newTransactionsByPriceAndNonce(signer types.Signer, txs map[common.Address][]*txpool.LazyTransaction, baseFee *big.Int) *orderedTransactions {
+	return newOrderedTransactions(priceOrdering{}, signer, txs, baseFee)
+}
+
+// newOrderedTransactions creates a transaction set that can retrieve transactions
+// sorted by the given ordering policy in a nonce-honouring way.
 //
 // Note, the input map is reowned so the caller should not interact any more with
 // if after providing it to the constructor.
-func newTransactionsByPriceAndNonce(signer types.Signer, txs map[common.Address][]*txpool.LazyTransaction, baseFee *big.Int) *transactionsByPriceAndNonce {
+func newOrderedTransactions(order txOrdering, signer types.Signer, txs map[common.Address][]*txpool.LazyTransaction, baseFee *big.Int) *orderedTransactions {
 	// Convert the basefee from header format to uint256 format
 	var baseFeeUint *uint256.Int
 	if baseFee != nil {
 		baseFeeUint = uint256.MustFromBig(baseFee)
 	}
-	// Initialize a price and received time based heap with the head transactions
-	heads := make(txByPriceAndTime, 0, len(txs))
+	// Initialize a heap ordered by the policy with the head transactions
+	heads := &txHeads{txs: make([]*txWithMinerFee, 0, len(txs)), order: order}
 	for from, accTxs := range txs {
 		wrapped, err := newTxWithMinerFee(accTxs[0], from, baseFeeUint)
 		if err != nil {
 			delete(txs, from)
 			continue
 		}
-		heads = append(heads, wrapped)
+		heads.txs = append(heads.txs, wrapped)
 		txs[from] = accTxs[1:]
 	}
-	heap.Init(&heads)
+	heap.Init(heads)
 
 	// Assemble and return the transaction set
-	return &transactionsByPriceAndNonce{
+	return &orderedTransactions{
 		txs:     txs,
 		heads:   heads,
 		signer:  signer,
@@ -126,41 +203,47 @@ func newTransactionsByPriceAndNonce(signer types.Signer, txs map[common.Address]
 	}
 }
 
-// Peek returns the next transaction by price.
-func (t *transactionsByPriceAndNonce) Peek() (*txpool.LazyTransaction, *uint256.Int) {
-	if len(t.heads) == 0 {
+// Peek returns the next transaction in order.
+func (t *orderedTransactions) Peek() (*txpool.LazyTransaction, *uint256.Int) {
+	if t.heads.Len() == 0 {
 		return nil, nil
 	}
-	return t.heads[0].tx, t.heads[0].fees
+	return t.heads.txs[0].tx, t.heads.txs[0].fees
 }
 
 // Shift replaces the current best head with the next one from the same account.
-func (t *transactionsByPriceAndNonce) Shift() {
-	acc := t.heads[0].from
+func (t *orderedTransactions) Shift() {
+	acc := t.heads.txs[0].from
 	if txs, ok := t.txs[acc]; ok && len(txs) > 0 {
 		if wrapped, err := newTxWithMinerFee(txs[0], acc, t.baseFee); err == nil {
-			t.heads[0], t.txs[acc] = wrapped, txs[1:]
-			heap.Fix(&t.heads, 0)
+			t.heads.txs[0], t.txs[acc] = wrapped, txs[1:]
+			heap.Fix(t.heads, 0)
 			return
 		}
 	}
-	heap.Pop(&t.heads)
+	heap.Pop(t.heads)
 }
 
 // Pop removes the best transaction, *not* replacing it with the next one from
 // the same account. This should be used when a transaction cannot be executed
 // and hence all subsequent ones should be discarded from the same account.
-func (t *transactionsByPriceAndNonce) Pop() {
-	heap.Pop(&t.heads)
+func (t *orderedTransactions) Pop() {
+	heap.Pop(t.heads)
 }
 
-// Empty returns if the price heap is empty. It can be used to check it simpler
+// Empty returns if the ordering heap is empty. It can be used to check it simpler
 // than calling peek and checking for nil return.
-func (t *transactionsByPriceAndNonce) Empty() bool {
-	return len(t.heads) == 0
+func (t *orderedTransactions) Empty() bool {
+	return t.heads.Len() == 0
 }
 
 // Clear removes the entire content of the heap.
-func (t *transactionsByPriceAndNonce) Clear() {
-	t.heads, t.txs = nil, nil
+func (t *orderedTransactions) Clear() {
+	t.heads.txs, t.txs = nil, nil
+}
+
+// before reports whether the next transaction of the set should be included
+// before the next transaction of the other set. Both sets must not be empty.
+func (t *orderedTransactions) before(other *orderedTransactions) bool {
+	return !t.heads.order.less(other.heads.txs[0], t.heads.txs[0])
 }
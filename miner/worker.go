@@ -18,6 +18,7 @@ package miner
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"math/big"
@@ -36,12 +37,14 @@ import (
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/internal/telemetry"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/params/mutations"
 	"github.com/ethereum/go-ethereum/params/types/ctypes"
 	"github.com/ethereum/go-ethereum/params/vars"
 	"github.com/ethereum/go-ethereum/trie"
 	"github.com/holiman/uint256"
+	"go.opentelemetry.io/otel/trace"
 )
 
 const (
@@ -108,6 +111,8 @@ type environment struct {
 	uncles   map[common.Hash]*types.Header
 	sidecars []*types.BlobTxSidecar
 	blobs    int
+
+	senderGas map[common.Address]uint64 // gas used by each sender, tracked if capped
 }
 
 // copy creates a deep copy of environment.
@@ -138,6 +143,12 @@ func (env *environment) copy() *environment {
 	for hash, uncle := range env.uncles {
 		cpy.uncles[hash] = uncle
 	}
+	if env.senderGas != nil {
+		cpy.senderGas = make(map[common.Address]uint64, len(env.senderGas))
+		for sender, gas := range env.senderGas {
+			cpy.senderGas[sender] = gas
+		}
+	}
 	return cpy
 }
 
@@ -276,6 +287,14 @@ type worker struct {
 	// payload in proof-of-stake stage.
 	recommit time.Duration
 
+	// ordering is the policy deciding the order in which pending transactions
+	// are included in the sealing block.
+	ordering txOrdering
+
+	// maxSenderGasShare is the maximum percentage of the block gas limit the
+	// transactions of a single sender may use, zero if uncapped.
+	maxSenderGasShare uint64
+
 	// External functions
 	isLocalBlock func(header *types.Header) bool // Function used to determine whether the specified block is mined by local miner.
 
@@ -339,6 +358,21 @@ func newWorker(config *Config, chainConfig ctypes.ChainConfigurator, engine cons
 	}
 	worker.newpayloadTimeout = newpayloadTimeout
 
+	// Sanitize the transaction ordering policy and the per sender gas cap.
+	ordering, err := newTxOrdering(worker.config)
+	if err != nil {
+		log.Warn("Sanitizing transaction ordering to default", "provided", worker.config.TxOrdering, "updated", OrderingPrice, "err", err)
+		ordering = priceOrdering{}
+	}
+	worker.ordering = ordering
+
+	maxSenderGasShare := worker.config.MaxSenderGasShare
+	if maxSenderGasShare >= 100 {
+		log.Warn("Sanitizing sender gas share to uncapped", "provided", maxSenderGasShare, "updated", 0)
+		maxSenderGasShare = 0
+	}
+	worker.maxSenderGasShare = maxSenderGasShare
+
 	worker.wg.Add(4)
 	go worker.mainLoop()
 	go worker.newWorkLoop(recommit)
@@ -662,8 +696,8 @@ func (w *worker) mainLoop() {
 						BlobGas:   tx.BlobGas(),
 					})
 				}
-				plainTxs := newTransactionsByPriceAndNonce(w.current.signer, txs, w.current.header.BaseFee) // Mixed bag of everrything, yolo
-				blobTxs := newTransactionsByPriceAndNonce(w.current.signer, nil, w.current.header.BaseFee)  // Empty bag, don't bother optimising
+				plainTxs := newOrderedTransactions(w.ordering, w.current.signer, txs, w.current.header.BaseFee) // Mixed bag of everrything, yolo
+				blobTxs := newOrderedTransactions(w.ordering, w.current.signer, nil, w.current.header.BaseFee)  // Empty bag, don't bother optimising
 
 				tcount := w.current.tcount
 				w.commitTransactions(w.current, plainTxs, blobTxs, nil)
@@ -942,11 +976,19 @@ func (w *worker) applyTransaction(env *environment, tx *types.Transaction) (*typ
 	return receipt, err
 }
 
-func (w *worker) commitTransactions(env *environment, plainTxs, blobTxs *transactionsByPriceAndNonce, interrupt *atomic.Int32) error {
+func (w *worker) commitTransactions(env *environment, plainTxs, blobTxs *orderedTransactions, interrupt *atomic.Int32) error {
 	gasLimit := env.header.GasLimit
 	if env.gasPool == nil {
 		env.gasPool = new(core.GasPool).AddGas(gasLimit)
 	}
+	// Cap the gas each sender may use, so a single account can't fill the block
+	var senderGasCap uint64
+	if w.maxSenderGasShare > 0 {
+		senderGasCap = gasLimit / 100 * w.maxSenderGasShare
+		if env.senderGas == nil {
+			env.senderGas = make(map[common.Address]uint64)
+		}
+	}
 	var coalescedLogs []*types.Log
 
 	for {
@@ -971,10 +1013,10 @@ func (w *worker) commitTransactions(env *environment, plainTxs, blobTxs *transac
 		// Retrieve the next transaction and abort if all done.
 		var (
 			ltx *txpool.LazyTransaction
-			txs *transactionsByPriceAndNonce
+			txs *orderedTransactions
 		)
-		pltx, ptip := plainTxs.Peek()
-		bltx, btip := blobTxs.Peek()
+		pltx, _ := plainTxs.Peek()
+		bltx, _ := blobTxs.Peek()
 
 		switch {
 		case pltx == nil:
@@ -982,10 +1024,10 @@ func (w *worker) commitTransactions(env *environment, plainTxs, blobTxs *transac
 		case bltx == nil:
 			txs, ltx = plainTxs, pltx
 		default:
-			if ptip.Lt(btip) {
-				txs, ltx = blobTxs, bltx
-			} else {
+			if plainTxs.before(blobTxs) {
 				txs, ltx = plainTxs, pltx
+			} else {
+				txs, ltx = blobTxs, bltx
 			}
 		}
 		if ltx == nil {
@@ -1020,6 +1062,12 @@ func (w *worker) commitTransactions(env *environment, plainTxs, blobTxs *transac
 			txs.Pop()
 			continue
 		}
+		// If the sender used up its share of the block, skip the account.
+		if senderGasCap > 0 && env.senderGas[from]+ltx.Gas > senderGasCap {
+			log.Trace("Sender gas share exhausted", "hash", ltx.Hash, "sender", from, "used", env.senderGas[from], "needed", ltx.Gas, "cap", senderGasCap)
+			txs.Pop()
+			continue
+		}
 		// Start executing the transaction
 		env.state.SetTxContext(tx.Hash(), env.tcount)
 
@@ -1034,6 +1082,9 @@ func (w *worker) commitTransactions(env *environment, plainTxs, blobTxs *transac
 			// Everything ok, collect the logs and shift in the next transaction from the same account
 			coalescedLogs = append(coalescedLogs, logs...)
 			env.tcount++
+			if senderGasCap > 0 {
+				env.senderGas[from] += env.receipts[len(env.receipts)-1].GasUsed
+			}
 			txs.Shift()
 
 		default:
@@ -1200,8 +1251,8 @@ func (w *worker) prepareWork(genParams *generateParams) (*environment, error) {
 }
 
 // fillTransactions retrieves the pending transactions from the txpool and fills them
-// into the given sealing block. The transaction selection and ordering strategy can
-// be customized with the plugin in the future.
+// into the given sealing block. The transactions are ordered by the configured
+// transaction ordering policy, local ones first.
 func (w *worker) fillTransactions(interrupt *atomic.Int32, env *environment) error {
 	w.mu.RLock()
 	tip := w.tip
@@ -1239,16 +1290,16 @@ func (w *worker) fillTransactions(interrupt *atomic.Int32, env *environment) err
 	}
 	// Fill the block with all available pending transactions.
 	if len(localPlainTxs) > 0 || len(localBlobTxs) > 0 {
-		plainTxs := newTransactionsByPriceAndNonce(env.signer, localPlainTxs, env.header.BaseFee)
-		blobTxs := newTransactionsByPriceAndNonce(env.signer, localBlobTxs, env.header.BaseFee)
+		plainTxs := newOrderedTransactions(w.ordering, env.signer, localPlainTxs, env.header.BaseFee)
+		blobTxs := newOrderedTransactions(w.ordering, env.signer, localBlobTxs, env.header.BaseFee)
 
 		if err := w.commitTransactions(env, plainTxs, blobTxs, interrupt); err != nil {
 			return err
 		}
 	}
 	if len(remotePlainTxs) > 0 || len(remoteBlobTxs) > 0 {
-		plainTxs := newTransactionsByPriceAndNonce(env.signer, remotePlainTxs, env.header.BaseFee)
-		blobTxs := newTransactionsByPriceAndNonce(env.signer, remoteBlobTxs, env.header.BaseFee)
+		plainTxs := newOrderedTransactions(w.ordering, env.signer, remotePlainTxs, env.header.BaseFee)
+		blobTxs := newOrderedTransactions(w.ordering, env.signer, remoteBlobTxs, env.header.BaseFee)
 
 		if err := w.commitTransactions(env, plainTxs, blobTxs, interrupt); err != nil {
 			return err
@@ -1257,13 +1308,42 @@ func (w *worker) fillTransactions(interrupt *atomic.Int32, env *environment) err
 	return nil
 }
 
+// traceFillTransactions runs fillTransactions within a telemetry span, the
+// interruption of the block building being recorded as an attribute.
+func (w *worker) traceFillTransactions(ctx context.Context, interrupt *atomic.Int32, env *environment) error {
+	ctx, endFill := telemetry.StartSpan(ctx, "miner.fillTransactions")
+	defer endFill(nil)
+
+	err := w.fillTransactions(interrupt, env)
+	span := trace.SpanFromContext(ctx)
+	span.SetAttributes(telemetry.BlockTxs(len(env.txs)))
+	if err != nil {
+		span.SetAttributes(telemetry.MinerInterrupt(err.Error()))
+	}
+	return err
+}
+
+// traceCommit runs commit within a telemetry span.
+func (w *worker) traceCommit(ctx context.Context, env *environment, interval func(), update bool, start time.Time) error {
+	_, endCommit := telemetry.StartSpan(ctx, "miner.commit", telemetry.BlockTxs(len(env.txs)))
+	err := w.commit(env, interval, update, start)
+	endCommit(&err)
+	return err
+}
+
 // generateWork generates a sealing block based on the given parameters.
-func (w *worker) generateWork(params *generateParams) *newPayloadResult {
+func (w *worker) generateWork(params *generateParams) (result *newPayloadResult) {
+	ctx, endWork := telemetry.StartSpan(context.Background(), "miner.generateWork")
+	defer func() { endWork(&result.err) }()
+
+	_, endPrepare := telemetry.StartSpan(ctx, "miner.prepareWork")
 	work, err := w.prepareWork(params)
+	endPrepare(&err)
 	if err != nil {
 		return &newPayloadResult{err: err}
 	}
 	defer work.discard()
+	trace.SpanFromContext(ctx).SetAttributes(telemetry.BlockNumber(work.header.Number.Int64()))
 
 	if !params.noTxs {
 		interrupt := new(atomic.Int32)
@@ -1272,12 +1352,14 @@ func (w *worker) generateWork(params *generateParams) *newPayloadResult {
 		})
 		defer timer.Stop()
 
-		err := w.fillTransactions(interrupt, work)
+		err := w.traceFillTransactions(ctx, interrupt, work)
 		if errors.Is(err, errBlockInterruptedByTimeout) {
 			log.Warn("Block building is interrupted", "allowance", common.PrettyDuration(w.newpayloadTimeout))
 		}
 	}
+	_, endAssemble := telemetry.StartSpan(ctx, "miner.finalizeAndAssemble", telemetry.BlockTxs(len(work.txs)))
 	block, err := w.engine.FinalizeAndAssemble(w.chain, work.header, work.state, work.txs, work.unclelist(), work.receipts, params.withdrawals)
+	endAssemble(&err)
 	if err != nil {
 		return &newPayloadResult{err: err}
 	}
@@ -1297,6 +1379,9 @@ func (w *worker) commitWork(interrupt *atomic.Int32, noempty bool, timestamp int
 	}
 	start := time.Now()
 
+	ctx, endWork := telemetry.StartSpan(context.Background(), "miner.commitWork")
+	defer endWork(nil)
+
 	// Set the coinbase if the worker is running or it's required
 	var coinbase common.Address
 	if w.isRunning() {
@@ -1306,20 +1391,24 @@ func (w *worker) commitWork(interrupt *atomic.Int32, noempty bool, timestamp int
 			return
 		}
 	}
+	_, endPrepare := telemetry.StartSpan(ctx, "miner.prepareWork")
 	work, err := w.prepareWork(&generateParams{
 		timestamp: uint64(timestamp),
 		coinbase:  coinbase,
 	})
+	endPrepare(&err)
 	if err != nil {
 		return
 	}
+	trace.SpanFromContext(ctx).SetAttributes(telemetry.BlockNumber(work.header.Number.Int64()))
+
 	// Create an empty block based on temporary copied state for
 	// sealing in advance without waiting block execution finished.
 	if !noempty && !w.noempty.Load() {
-		w.commit(work.copy(), nil, false, start)
+		w.traceCommit(ctx, work.copy(), nil, false, start)
 	}
 	// Fill pending transactions from the txpool into the block.
-	err = w.fillTransactions(interrupt, work)
+	err = w.traceFillTransactions(ctx, interrupt, work)
 	switch {
 	case err == nil:
 		// The entire block is filled, decrease resubmit interval in case
@@ -1348,7 +1437,7 @@ func (w *worker) commitWork(interrupt *atomic.Int32, noempty bool, timestamp int
 		return
 	}
 	// Submit the generated block for consensus sealing.
-	w.commit(work.copy(), w.fullTaskHook, true, start)
+	w.traceCommit(ctx, work.copy(), w.fullTaskHook, true, start)
 
 	// Swap out the old work with the new one, terminating any leftover
 	// prefetcher processes in the mean time and starting a new one.
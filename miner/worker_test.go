@@ -17,6 +17,7 @@
 package miner
 
 import (
+	"crypto/ecdsa"
 	"crypto/rand"
 	"math/big"
 	"sync/atomic"
@@ -707,3 +708,141 @@ func testGetSealingWork(t *testing.T, chainConfig ctypes.ChainConfigurator, engi
 		}
 	}
 }
+
+// Tests that the transactions are committed in the order of the configured
+// transaction ordering policy.
+func TestCommitTransactionsOrdering(t *testing.T) {
+	t.Parallel()
+
+	var (
+		keys  = make([]*ecdsa.PrivateKey, 3)
+		addrs = make([]common.Address, 3)
+	)
+	for i := range keys {
+		keys[i], _ = crypto.GenerateKey()
+		addrs[i] = crypto.PubkeyToAddress(keys[i].PublicKey)
+	}
+	// Sender 0 was seen first with the lowest tip, sender 1 pays the most
+	tips := []int64{1, 3, 2}
+
+	tests := []struct {
+		ordering string
+		priority []common.Address
+		want     []common.Address
+	}{
+		{OrderingPrice, nil, []common.Address{addrs[1], addrs[2], addrs[0]}},
+		{OrderingFIFO, nil, []common.Address{addrs[0], addrs[1], addrs[2]}},
+		{OrderingPriority, []common.Address{addrs[2]}, []common.Address{addrs[2], addrs[1], addrs[0]}},
+		{OrderingPriority, nil, []common.Address{addrs[1], addrs[2], addrs[0]}},
+	}
+	for i, tt := range tests {
+		config := *testConfig
+		config.TxOrdering, config.PriorityAddresses = tt.ordering, tt.priority
+
+		w, env := newTestOrderingWorker(t, &config, addrs)
+		txs := make(map[common.Address][]*txpool.LazyTransaction)
+		for j, key := range keys {
+			txs[addrs[j]] = []*txpool.LazyTransaction{
+				newTestLazyTx(env, key, 0, tips[j], time.Unix(int64(j), 0)),
+			}
+		}
+		if err := w.commitTransactions(env, newOrderedTransactions(w.ordering, env.signer, txs, env.header.BaseFee), newOrderedTransactions(w.ordering, env.signer, nil, env.header.BaseFee), nil); err != nil {
+			t.Fatalf("test %d: failed to commit transactions: %v", i, err)
+		}
+		if len(env.txs) != len(tt.want) {
+			t.Fatalf("test %d: committed transactions mismatch: have %d, want %d", i, len(env.txs), len(tt.want))
+		}
+		for j, tx := range env.txs {
+			if from, _ := types.Sender(env.signer, tx); from != tt.want[j] {
+				t.Errorf("test %d: transaction %d sender mismatch: have %x, want %x", i, j, from, tt.want[j])
+			}
+		}
+		w.close()
+	}
+}
+
+// Tests that a single sender can't use more than its share of the block gas,
+// while the other senders are still included.
+func TestCommitTransactionsSenderGasShare(t *testing.T) {
+	t.Parallel()
+
+	var (
+		spamKey, _ = crypto.GenerateKey()
+		userKey, _ = crypto.GenerateKey()
+		spammer    = crypto.PubkeyToAddress(spamKey.PublicKey)
+		user       = crypto.PubkeyToAddress(userKey.PublicKey)
+	)
+	config := *testConfig
+	config.MaxSenderGasShare = 1
+
+	w, env := newTestOrderingWorker(t, &config, []common.Address{spammer, user})
+	defer w.close()
+
+	// The spammer pays more, but may only use 1% of the block gas limit
+	txs := map[common.Address][]*txpool.LazyTransaction{
+		user: {newTestLazyTx(env, userKey, 0, 1, time.Unix(0, 0))},
+	}
+	for i := 0; i < 10; i++ {
+		txs[spammer] = append(txs[spammer], newTestLazyTx(env, spamKey, uint64(i), 10, time.Unix(0, 0)))
+	}
+	if err := w.commitTransactions(env, newOrderedTransactions(w.ordering, env.signer, txs, env.header.BaseFee), newOrderedTransactions(w.ordering, env.signer, nil, env.header.BaseFee), nil); err != nil {
+		t.Fatalf("failed to commit transactions: %v", err)
+	}
+	included := make(map[common.Address]int)
+	for _, tx := range env.txs {
+		from, _ := types.Sender(env.signer, tx)
+		included[from]++
+	}
+	want := int(env.header.GasLimit / 100 / vars.TxGas)
+	if included[spammer] != want {
+		t.Errorf("spammer transactions mismatch: have %d, want %d", included[spammer], want)
+	}
+	if included[user] != 1 {
+		t.Errorf("user transactions mismatch: have %d, want 1", included[user])
+	}
+	if env.senderGas[spammer] != uint64(want)*vars.TxGas {
+		t.Errorf("spammer gas mismatch: have %d, want %d", env.senderGas[spammer], uint64(want)*vars.TxGas)
+	}
+}
+
+// newTestOrderingWorker creates a worker with the given configuration and a
+// sealing environment on top of the genesis block, funding the given accounts.
+func newTestOrderingWorker(t *testing.T, config *Config, funded []common.Address) (*worker, *environment) {
+	engine := ethash.NewFaker()
+	t.Cleanup(func() { engine.Close() })
+
+	backend := newTestWorkerBackend(t, ethashChainConfig, engine, rawdb.NewMemoryDatabase(), 0)
+	w := newWorker(config, ethashChainConfig, engine, backend, new(event.TypeMux), nil, false)
+
+	env, err := w.prepareWork(&generateParams{timestamp: uint64(time.Now().Unix()), coinbase: testBankAddress, noTxs: true})
+	if err != nil {
+		w.close()
+		t.Fatalf("failed to prepare work: %v", err)
+	}
+	for _, addr := range funded {
+		env.state.AddBalance(addr, uint256.NewInt(vars.Ether))
+	}
+	return w, env
+}
+
+// newTestLazyTx creates a signed value transfer with the given tip, first seen
+// at the given time.
+func newTestLazyTx(env *environment, key *ecdsa.PrivateKey, nonce uint64, tip int64, seen time.Time) *txpool.LazyTransaction {
+	tx := types.MustSignNewTx(key, env.signer, &types.DynamicFeeTx{
+		ChainID:   params.TestChainConfig.ChainID,
+		Nonce:     nonce,
+		GasTipCap: big.NewInt(tip),
+		GasFeeCap: new(big.Int).Add(env.header.BaseFee, big.NewInt(tip)),
+		Gas:       vars.TxGas,
+		To:        &testUserAddress,
+		Value:     big.NewInt(1),
+	})
+	return &txpool.LazyTransaction{
+		Hash:      tx.Hash(),
+		Tx:        tx,
+		Time:      seen,
+		GasFeeCap: uint256.MustFromBig(tx.GasFeeCap()),
+		GasTipCap: uint256.MustFromBig(tx.GasTipCap()),
+		Gas:       tx.Gas(),
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/crypto"
@@ -120,6 +121,66 @@ func (api *adminAPI) RemoveTrustedPeer(url string) (bool, error) {
 	return true, nil
 }
 
+// BanPeer bans a remote node, given by its URL or ID, disconnecting it if the
+// connection exists. The ban lasts the given number of seconds, or forever if
+// no duration is given.
+func (api *adminAPI) BanPeer(url string, duration *uint64) (bool, error) {
+	// Make sure the server is running, fail otherwise
+	server := api.node.Server()
+	if server == nil {
+		return false, ErrNodeStopped
+	}
+	id, err := parseNodeID(url)
+	if err != nil {
+		return false, err
+	}
+	var expiry time.Time
+	if duration != nil && *duration > 0 {
+		expiry = time.Now().Add(time.Duration(*duration) * time.Second)
+	}
+	if err := server.BanPeer(id, expiry, "banned by admin"); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// UnbanPeer lifts the ban of a remote node, given by its URL or ID. It returns
+// whether the node was banned.
+func (api *adminAPI) UnbanPeer(url string) (bool, error) {
+	// Make sure the server is running, fail otherwise
+	server := api.node.Server()
+	if server == nil {
+		return false, ErrNodeStopped
+	}
+	id, err := parseNodeID(url)
+	if err != nil {
+		return false, err
+	}
+	return server.UnbanPeer(id)
+}
+
+// ListBans retrieves the banned nodes, either banned by the admin or banned for
+// misbehaving.
+func (api *adminAPI) ListBans() ([]*p2p.BanInfo, error) {
+	server := api.node.Server()
+	if server == nil {
+		return nil, ErrNodeStopped
+	}
+	return server.Bans(), nil
+}
+
+// parseNodeID parses a node URL or a hex node ID.
+func parseNodeID(url string) (enode.ID, error) {
+	if id, err := enode.ParseID(url); err == nil {
+		return id, nil
+	}
+	node, err := enode.Parse(enode.ValidSchemes, url)
+	if err != nil {
+		return enode.ID{}, fmt.Errorf("invalid enode: %v", err)
+	}
+	return node.ID(), nil
+}
+
 // PeerEvents creates an RPC subscription which receives peer events from the
 // node's p2p.Server
 func (api *adminAPI) PeerEvents(ctx context.Context) (*rpc.Subscription, error) {
@@ -183,6 +244,7 @@ func (api *adminAPI) StartHTTP(host *string, port *int, cors *string, apis *stri
 		rpcEndpointConfig: rpcEndpointConfig{
 			batchItemLimit:         api.node.config.BatchRequestLimit,
 			batchResponseSizeLimit: api.node.config.BatchResponseMaxSize,
+			access:                 api.node.config.RPCAccess,
 		},
 	}
 	if cors != nil {
@@ -261,6 +323,7 @@ func (api *adminAPI) StartWS(host *string, port *int, allowedOrigins *string, ap
 		rpcEndpointConfig: rpcEndpointConfig{
 			batchItemLimit:         api.node.config.BatchRequestLimit,
 			batchResponseSizeLimit: api.node.config.BatchResponseMaxSize,
+			access:                 api.node.config.RPCAccess,
 		},
 	}
 	if apis != nil {
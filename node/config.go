@@ -206,6 +206,12 @@ type Config struct {
 	// BatchResponseMaxSize is the maximum number of bytes returned from a batched rpc call.
 	BatchResponseMaxSize int `toml:",omitempty"`
 
+	// RPCAccess configures the API keys, namespace allowlists and rate limits of
+	// the HTTP and WebSocket RPC endpoints, and of the GraphQL endpoint, whose
+	// queries are charged as calls of graphql_query, and traced blocks as calls
+	// of graphql_trace. It doesn't apply to IPC and to the authenticated endpoints.
+	RPCAccess *rpc.AccessConfig `toml:",omitempty"`
+
 	// JWTSecret is the path to the hex-encoded jwt secret.
 	JWTSecret string `toml:",omitempty"`
 
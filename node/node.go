@@ -489,6 +489,7 @@ func (n *Node) startRPC() error {
 	rpcConfig := rpcEndpointConfig{
 		batchItemLimit:         n.config.BatchRequestLimit,
 		batchResponseSizeLimit: n.config.BatchResponseMaxSize,
+		access:                 n.config.RPCAccess,
 	}
 
 	initHttp := func(server *httpServer, port int) error {
@@ -856,6 +857,38 @@ func (n *Node) OpenDatabaseWithFreezer(name string, cache, handles int, ancient
 	return db, err
 }
 
+// OpenDatabaseWithRemoteFreezer opens an existing database with the given name
+// (or creates one if no previous can be found) from within the node's data
+// directory, also attaching the chain freezer served at the given endpoint to
+// it, which moves ancient chain data from the database to a remote store. If
+// the remote store is owned by another node, remoteReadonly keeps this node
+// from writing to it. An ephemeral node can't use a remote store.
+func (n *Node) OpenDatabaseWithRemoteFreezer(name string, cache, handles int, ancient string, endpoint string, remoteReadonly bool, namespace string, readonly bool) (ethdb.Database, error) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	if n.state == closedState {
+		return nil, ErrNodeStopped
+	}
+	if n.config.DataDir == "" {
+		return nil, ErrEphemeralRemoteFreezer
+	}
+	db, err := rawdb.Open(rawdb.OpenOptions{
+		Type:                   n.config.DBEngine,
+		Directory:              n.ResolvePath(name),
+		AncientsDirectory:      n.ResolveAncient(name, ancient),
+		AncientsRemote:         endpoint,
+		AncientsRemoteReadOnly: remoteReadonly,
+		Namespace:              namespace,
+		Cache:                  cache,
+		Handles:                handles,
+		ReadOnly:               readonly,
+	})
+	if err == nil {
+		db = n.wrapDatabase(db)
+	}
+	return db, err
+}
+
 // ResolvePath returns the absolute path of a resource in the instance directory.
 func (n *Node) ResolvePath(x string) string {
 	return n.config.ResolvePath(x)
@@ -361,6 +361,17 @@ func TestNodeCloseClosesDB(t *testing.T) {
 	}
 }
 
+// This test checks that an ephemeral node refuses a remote chain freezer, which
+// it couldn't combine with its memory database.
+func TestNodeOpenDatabaseWithRemoteFreezerEphemeral(t *testing.T) {
+	stack, _ := New(testNodeConfig())
+	defer stack.Close()
+
+	if _, err := stack.OpenDatabaseWithRemoteFreezer("mydb", 0, 0, "", "mock-freezer.ipc", false, "", false); err != ErrEphemeralRemoteFreezer {
+		t.Fatalf("error mismatch: have %v, want %v", err, ErrEphemeralRemoteFreezer)
+	}
+}
+
 // This test checks that OpenDatabase can be used from within a Lifecycle Start method.
 func TestNodeOpenDatabaseFromLifecycleStart(t *testing.T) {
 	stack, _ := New(testNodeConfig())
@@ -58,6 +58,7 @@ type rpcEndpointConfig struct {
 	batchItemLimit         int
 	batchResponseSizeLimit int
 	httpBodyLimit          int
+	access                 *rpc.AccessConfig // optional authentication and rate limiting
 }
 
 type rpcHandler struct {
@@ -200,10 +201,19 @@ func (h *httpServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	// check if ws request and serve if ws enabled
 	ws := h.wsHandler.Load().(*rpcHandler)
 	if ws != nil && isWebsocket(r) {
+		if kr, ok := withPathAPIKey(r, h.wsConfig.prefix, h.wsConfig.access); ok {
+			ws.ServeHTTP(w, kr)
+			return
+		}
 		if checkPath(r, h.wsConfig.prefix) {
 			ws.ServeHTTP(w, r)
+			return
+		}
+		// Websocket requests to other paths may target the handlers registered
+		// via Node.RegisterHandler, like the GraphQL subscriptions.
+		if _, pattern := h.mux.Handler(r); pattern == "" {
+			return
 		}
-		return
 	}
 
 	// if http-rpc is enabled, try to serve request
@@ -219,6 +229,10 @@ func (h *httpServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
+		if kr, ok := withPathAPIKey(r, h.httpConfig.prefix, h.httpConfig.access); ok {
+			rpc.ServeHTTP(w, kr)
+			return
+		}
 		if checkPath(r, h.httpConfig.prefix) {
 			rpc.ServeHTTP(w, r)
 			return
@@ -237,6 +251,33 @@ func checkPath(r *http.Request, path string) bool {
 	return len(r.URL.Path) >= len(path) && r.URL.Path[:len(path)] == path
 }
 
+// withPathAPIKey checks whether the request URL is the given path prefix followed
+// by a configured API key, returning the request to the prefix with the API key
+// moved to the API key header.
+func withPathAPIKey(r *http.Request, prefix string, access *rpc.AccessConfig) (*http.Request, bool) {
+	if access == nil || len(access.APIKeys) == 0 {
+		return nil, false
+	}
+	path := strings.TrimSuffix(prefix, "/")
+	if !strings.HasPrefix(r.URL.Path, path+"/") {
+		return nil, false
+	}
+	key := r.URL.Path[len(path)+1:]
+	for _, k := range access.APIKeys {
+		if k.Key != key {
+			continue
+		}
+		kr := r.Clone(r.Context())
+		kr.URL.Path, kr.URL.RawPath = path, ""
+		if kr.URL.Path == "" {
+			kr.URL.Path = "/"
+		}
+		kr.Header.Set(rpc.APIKeyHeader, key)
+		return kr, true
+	}
+	return nil, false
+}
+
 // validatePrefix checks if 'path' is a valid configuration value for the RPC prefix option.
 func validatePrefix(what, path string) error {
 	if path == "" {
@@ -309,6 +350,11 @@ func (h *httpServer) enableRPC(apis []rpc.API, config httpConfig) error {
 	if config.httpBodyLimit > 0 {
 		srv.SetHTTPBodyLimit(config.httpBodyLimit)
 	}
+	if config.access != nil {
+		if err := srv.SetAccessControl(config.access); err != nil {
+			return err
+		}
+	}
 	if err := RegisterApis(apis, config.Modules, srv); err != nil {
 		return err
 	}
@@ -344,6 +390,11 @@ func (h *httpServer) enableWS(apis []rpc.API, config wsConfig) error {
 	if config.httpBodyLimit > 0 {
 		srv.SetHTTPBodyLimit(config.httpBodyLimit)
 	}
+	if config.access != nil {
+		if err := srv.SetAccessControl(config.access); err != nil {
+			return err
+		}
+	}
 	if err := RegisterApis(apis, config.Modules, srv); err != nil {
 		return err
 	}
@@ -404,6 +455,26 @@ func NewHTTPHandlerStack(srv http.Handler, cors []string, vhosts []string, jwtSe
 	return newGzipHandler(handler)
 }
 
+// NewAccessHandlerStack returns a handler enforcing the access configuration of
+// the RPC endpoints on a handler registered via Node.RegisterHandler at the given
+// path, its requests being charged as calls of the given method. Like on the RPC
+// endpoints, the API key can be given as the last segment of the URL.
+func NewAccessHandlerStack(srv http.Handler, path string, access *rpc.AccessConfig, method string) (http.Handler, error) {
+	if access == nil {
+		return srv, nil
+	}
+	handler, err := rpc.NewAccessHandler(access, method, srv)
+	if err != nil {
+		return nil, err
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if kr, ok := withPathAPIKey(r, path, access); ok {
+			r = kr
+		}
+		handler.ServeHTTP(w, r)
+	}), nil
+}
+
 // NewWSHandlerStack returns a wrapped ws-related handler.
 func NewWSHandlerStack(srv http.Handler, jwtSecret []byte) http.Handler {
 	if len(jwtSecret) != 0 {
@@ -572,7 +643,8 @@ func (w *gzipResponseWriter) close() {
 
 func newGzipHandler(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if !strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
+		// Websocket upgrades hijack the connection, which can't be compressed
+		if !strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") || isWebsocket(r) {
 			next.ServeHTTP(w, r)
 			return
 		}
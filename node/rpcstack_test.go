@@ -165,6 +165,45 @@ func TestWebsocketOrigins(t *testing.T) {
 	}
 }
 
+// TestAPIKeyPath tests that the API keys can be given as last segment of the
+// endpoint URL.
+func TestAPIKeyPath(t *testing.T) {
+	access := &rpc.AccessConfig{
+		APIKeys:     []rpc.APIKeyConfig{{Key: "secret"}},
+		KeyRequired: true,
+	}
+	srv := createAndStartServer(t,
+		&httpConfig{prefix: "/rpc", rpcEndpointConfig: rpcEndpointConfig{access: access}}, true,
+		&wsConfig{Origins: []string{"*"}, prefix: "/ws", rpcEndpointConfig: rpcEndpointConfig{access: access}}, nil)
+	defer srv.stop()
+
+	httpURL := fmt.Sprintf("http://%v/rpc", srv.listenAddr())
+	tests := []struct {
+		url     string
+		headers []string
+		code    int
+	}{
+		{url: httpURL, code: http.StatusUnauthorized},
+		{url: httpURL + "/wrong", code: http.StatusUnauthorized},
+		{url: httpURL + "/secret", code: http.StatusOK},
+		{url: httpURL, headers: []string{rpc.APIKeyHeader, "secret"}, code: http.StatusOK},
+	}
+	for _, tt := range tests {
+		resp := rpcRequest(t, tt.url, "test_greet", tt.headers...)
+		resp.Body.Close()
+		if resp.StatusCode != tt.code {
+			t.Errorf("%s %v: wrong status code: have %d, want %d", tt.url, tt.headers, resp.StatusCode, tt.code)
+		}
+	}
+	wsURL := fmt.Sprintf("ws://%v/ws", srv.listenAddr())
+	if err := wsRequest(t, wsURL); err == nil {
+		t.Error("websocket connection without key accepted")
+	}
+	if err := wsRequest(t, wsURL+"/secret"); err != nil {
+		t.Errorf("websocket connection with path key failed: %v", err)
+	}
+}
+
 // TestIsWebsocket tests if an incoming websocket upgrade request is handled properly.
 func TestIsWebsocket(t *testing.T) {
 	r, _ := http.NewRequest(http.MethodGet, "/", nil)
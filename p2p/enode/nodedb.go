@@ -41,6 +41,7 @@ const (
 	dbVersionKey   = "version" // Version of the database to flush if changes
 	dbNodePrefix   = "n:"      // Identifier to prefix node entries with
 	dbLocalPrefix  = "local:"
+	dbBanPrefix    = "ban:" // Identifier to prefix node bans with
 	dbDiscoverRoot = "v4"
 	dbDiscv5Root   = "v5"
 
@@ -442,6 +443,70 @@ func (db *DB) storeLocalSeq(id ID, n uint64) {
 	db.storeUint64(localItemKey(id, dbLocalSeq), n)
 }
 
+// Ban is a ban of a node, stored in the node database.
+type Ban struct {
+	ID     ID
+	Expiry time.Time // Zero for permanent bans
+	Reason string
+}
+
+// banRecord is the database encoding of a ban.
+type banRecord struct {
+	Expiry uint64 // Unix time in seconds, zero for permanent bans
+	Reason string
+}
+
+// banKey returns the database key of a node ban.
+func banKey(id ID) []byte {
+	return append([]byte(dbBanPrefix), id[:]...)
+}
+
+// Bans retrieves all the node bans, including the expired ones.
+func (db *DB) Bans() []Ban {
+	it := db.lvl.NewIterator(util.BytesPrefix([]byte(dbBanPrefix)), nil)
+	defer it.Release()
+
+	var bans []Ban
+	for it.Next() {
+		var (
+			ban Ban
+			rec banRecord
+		)
+		if len(it.Key()) != len(dbBanPrefix)+len(ban.ID) {
+			continue
+		}
+		if err := rlp.DecodeBytes(it.Value(), &rec); err != nil {
+			continue
+		}
+		copy(ban.ID[:], it.Key()[len(dbBanPrefix):])
+		if rec.Expiry != 0 {
+			ban.Expiry = time.Unix(int64(rec.Expiry), 0)
+		}
+		ban.Reason = rec.Reason
+		bans = append(bans, ban)
+	}
+	return bans
+}
+
+// UpdateBan stores the ban of a node, replacing its previous ban.
+func (db *DB) UpdateBan(ban Ban) error {
+	var rec banRecord
+	if !ban.Expiry.IsZero() {
+		rec.Expiry = uint64(ban.Expiry.Unix())
+	}
+	rec.Reason = ban.Reason
+	blob, err := rlp.EncodeToBytes(&rec)
+	if err != nil {
+		return err
+	}
+	return db.lvl.Put(banKey(ban.ID), blob, nil)
+}
+
+// DeleteBan deletes the ban of a node.
+func (db *DB) DeleteBan(id ID) error {
+	return db.lvl.Delete(banKey(id), nil)
+}
+
 // QuerySeeds retrieves random nodes to be used as potential seed nodes
 // for bootstrapping.
 func (db *DB) QuerySeeds(n int, maxAge time.Duration) []*Node {
@@ -329,6 +329,45 @@ func TestDBPersistency(t *testing.T) {
 	db.Close()
 }
 
+func TestDBBans(t *testing.T) {
+	root := t.TempDir()
+
+	db, err := OpenDB(filepath.Join(root, "database"))
+	if err != nil {
+		t.Fatalf("failed to create persistent database: %v", err)
+	}
+	bans := []Ban{
+		{ID: ID{0x01}, Expiry: time.Unix(1700000000, 0), Reason: "invalid block"},
+		{ID: ID{0x02}, Reason: "manual"},
+	}
+	for _, ban := range bans {
+		if err := db.UpdateBan(ban); err != nil {
+			t.Fatalf("failed to store ban: %v", err)
+		}
+	}
+	// Node records must not be mistaken for bans
+	if err := db.UpdateNode(nodeDBExpirationNodes[0].node); err != nil {
+		t.Fatalf("failed to store node: %v", err)
+	}
+	db.Close()
+
+	// Reopen the database and check the bans
+	db, err = OpenDB(filepath.Join(root, "database"))
+	if err != nil {
+		t.Fatalf("failed to open persistent database: %v", err)
+	}
+	defer db.Close()
+	if have := db.Bans(); !reflect.DeepEqual(have, bans) {
+		t.Fatalf("bans mismatch:\nhave %v\nwant %v", have, bans)
+	}
+	if err := db.DeleteBan(bans[0].ID); err != nil {
+		t.Fatalf("failed to delete ban: %v", err)
+	}
+	if have := db.Bans(); !reflect.DeepEqual(have, bans[1:]) {
+		t.Fatalf("bans mismatch after deletion:\nhave %v\nwant %v", have, bans[1:])
+	}
+}
+
 var nodeDBExpirationNodes = []struct {
 	node      *Node
 	pong      time.Time
@@ -118,6 +118,9 @@ type Peer struct {
 	// events receives message send / receive events if set
 	events   *event.Feed
 	testPipe *MsgPipeRW // for testing
+
+	// reputation tracks the misbehaviours of the peer if set
+	reputation *reputation
 }
 
 // NewPeer returns a peer for testing purposes.
@@ -214,6 +217,19 @@ func (p *Peer) Disconnect(reason DiscReason) {
 	}
 }
 
+// Penalize lowers the reputation of the peer for the given misbehaviour. The
+// peer is disconnected and temporarily banned once its reputation gets too low,
+// unless it's a trusted peer.
+func (p *Peer) Penalize(event ScoreEvent) {
+	if p.reputation == nil || p.rw.is(trustedConn) {
+		return
+	}
+	if ban, banned := p.reputation.penalize(p.ID(), event); banned {
+		p.log.Warn("Banning misbehaving peer", "event", event, "expiry", ban.Expiry)
+		p.Disconnect(DiscUselessPeer)
+	}
+}
+
 // String implements fmt.Stringer.
 func (p *Peer) String() string {
 	id := p.ID()
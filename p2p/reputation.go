@@ -0,0 +1,268 @@
+// Copyright 2025 The core-geth Authors
+// This file is part of the core-geth library.
+//
+// The core-geth library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The core-geth library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the core-geth library. If not, see <http://www.gnu.org/licenses/>.
+
+package p2p
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/mclock"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+)
+
+const (
+	// banScore is the misbehaviour score at which a peer gets banned.
+	banScore = 100
+
+	// scoreHalfLife is the time after which the misbehaviour score of a peer is
+	// halved, letting the occasional misbehaviours fade away.
+	scoreHalfLife = 15 * time.Minute
+
+	// minBanDuration is the duration of the first automatic ban of a node, the
+	// duration doubling on each subsequent ban up to maxBanDuration.
+	minBanDuration = 30 * time.Minute
+	maxBanDuration = 24 * time.Hour
+)
+
+// ScoreEvent is a misbehaviour of a peer, lowering its reputation.
+type ScoreEvent int
+
+const (
+	ScoreTimeout        ScoreEvent = iota // Request not answered in time
+	ScoreSpam                             // Unsolicited data beyond the allowed limits
+	ScoreInvalidMessage                   // Malformed or inconsistent message
+	ScoreInvalidBlock                     // Block or header failing validation
+)
+
+// scoreEventPenalties are the misbehaviour scores of the events. The timeouts
+// are mostly caused by slow or overloaded honest peers, disconnected on each of
+// them anyway, so only a peer stalling repeatedly right after reconnecting gets
+// banned for them.
+var scoreEventPenalties = [...]float64{
+	ScoreTimeout:        2,
+	ScoreSpam:           5,
+	ScoreInvalidMessage: 25,
+	ScoreInvalidBlock:   50,
+}
+
+var scoreEventNames = [...]string{
+	ScoreTimeout:        "timeout",
+	ScoreSpam:           "spam",
+	ScoreInvalidMessage: "invalid message",
+	ScoreInvalidBlock:   "invalid block",
+}
+
+func (e ScoreEvent) String() string {
+	if e < 0 || int(e) >= len(scoreEventNames) {
+		return fmt.Sprintf("unknown event %d", int(e))
+	}
+	return scoreEventNames[e]
+}
+
+// penalty returns the misbehaviour score of the event.
+func (e ScoreEvent) penalty() float64 {
+	if e < 0 || int(e) >= len(scoreEventPenalties) {
+		return 0
+	}
+	return scoreEventPenalties[e]
+}
+
+// BanInfo represents a short summary of a banned node.
+type BanInfo struct {
+	ID     string     `json:"id"`               // Unique node identifier
+	Enode  string     `json:"enode,omitempty"`  // Node URL, if the node is known
+	Expiry *time.Time `json:"expiry,omitempty"` // Expiry of the ban, permanent if absent
+	Reason string     `json:"reason"`           // Reason of the ban
+}
+
+// peerScore is the misbehaviour score of a node.
+type peerScore struct {
+	value   float64        // Score at the time of the last update
+	updated mclock.AbsTime // Time of the last update
+	bans    int            // Number of automatic bans of the node
+}
+
+// decay returns the score at the given time.
+func (s *peerScore) decay(now mclock.AbsTime) float64 {
+	elapsed := time.Duration(now - s.updated)
+	return s.value * math.Pow(0.5, float64(elapsed)/float64(scoreHalfLife))
+}
+
+// reputation tracks the misbehaviour scores of the peers, banning the nodes
+// whose score reaches the ban score. The bans are kept in the node database,
+// surviving restarts, while the scores are only kept in memory.
+type reputation struct {
+	db    *enode.DB
+	clock mclock.Clock
+	now   func() time.Time // Wall clock for the ban expiries
+	log   log.Logger
+
+	lock      sync.Mutex
+	scores    map[enode.ID]*peerScore
+	bans      map[enode.ID]enode.Ban
+	lastPrune mclock.AbsTime
+}
+
+// newReputation creates the reputation tracker, loading the bans from the node
+// database and dropping the expired ones.
+func newReputation(db *enode.DB, clock mclock.Clock, logger log.Logger) *reputation {
+	r := &reputation{
+		db:        db,
+		clock:     clock,
+		now:       time.Now,
+		log:       logger,
+		scores:    make(map[enode.ID]*peerScore),
+		bans:      make(map[enode.ID]enode.Ban),
+		lastPrune: clock.Now(),
+	}
+	now := r.now()
+	for _, ban := range db.Bans() {
+		if !ban.Expiry.IsZero() && !ban.Expiry.After(now) {
+			db.DeleteBan(ban.ID)
+			continue
+		}
+		r.bans[ban.ID] = ban
+	}
+	if len(r.bans) > 0 {
+		r.log.Info("Loaded banned nodes", "count", len(r.bans))
+	}
+	return r
+}
+
+// penalize raises the misbehaviour score of the node by the penalty of the
+// event, banning the node if the ban score is reached. The ban is returned if
+// the node got banned.
+func (r *reputation) penalize(id enode.ID, event ScoreEvent) (enode.Ban, bool) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	now := r.clock.Now()
+	r.prune(now)
+
+	score := r.scores[id]
+	if score == nil {
+		score = new(peerScore)
+		r.scores[id] = score
+	}
+	score.value = score.decay(now) + event.penalty()
+	score.updated = now
+	if score.value < banScore {
+		return enode.Ban{}, false
+	}
+	// The score reached the ban score, ban the node for a duration growing with
+	// its number of bans
+	duration := minBanDuration << score.bans
+	if duration > maxBanDuration || duration <= 0 {
+		duration = maxBanDuration
+	}
+	score.value = 0
+	score.bans++
+
+	ban := enode.Ban{ID: id, Expiry: r.now().Add(duration), Reason: event.String()}
+	if err := r.store(ban); err != nil {
+		r.log.Warn("Failed to store node ban", "id", id, "err", err)
+	}
+	peerBanMeter.Mark(1)
+	return ban, true
+}
+
+// prune drops the scores decayed to nothing, keeping the nodes banned before
+// to remember their number of bans. It runs at most once per score half-life.
+func (r *reputation) prune(now mclock.AbsTime) {
+	if time.Duration(now-r.lastPrune) < scoreHalfLife {
+		return
+	}
+	r.lastPrune = now
+	for id, score := range r.scores {
+		if score.bans == 0 && score.decay(now) < 1 {
+			delete(r.scores, id)
+		}
+	}
+}
+
+// ban bans the node, replacing its current ban.
+func (r *reputation) ban(ban enode.Ban) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	return r.store(ban)
+}
+
+// store records the ban, both in memory and in the node database.
+func (r *reputation) store(ban enode.Ban) error {
+	r.bans[ban.ID] = ban
+	return r.db.UpdateBan(ban)
+}
+
+// unban lifts the ban of the node and clears its misbehaviour score. It returns
+// whether the node was banned.
+func (r *reputation) unban(id enode.ID) (bool, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	delete(r.scores, id)
+	if !r.isBanned(id) {
+		return false, nil
+	}
+	delete(r.bans, id)
+	return true, r.db.DeleteBan(id)
+}
+
+// banned reports whether the node is banned.
+func (r *reputation) banned(id enode.ID) bool {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	return r.isBanned(id)
+}
+
+// isBanned reports whether the node is banned, dropping its ban if expired.
+// The lock must be held.
+func (r *reputation) isBanned(id enode.ID) bool {
+	ban, ok := r.bans[id]
+	if !ok {
+		return false
+	}
+	if !ban.Expiry.IsZero() && !ban.Expiry.After(r.now()) {
+		delete(r.bans, id)
+		r.db.DeleteBan(id)
+		return false
+	}
+	return true
+}
+
+// list returns the current bans, sorted by node ID.
+func (r *reputation) list() []enode.Ban {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	bans := make([]enode.Ban, 0, len(r.bans))
+	for id := range r.bans {
+		if r.isBanned(id) {
+			bans = append(bans, r.bans[id])
+		}
+	}
+	sort.Slice(bans, func(i, j int) bool {
+		return bytes.Compare(bans[i].ID[:], bans[j].ID[:]) < 0
+	})
+	return bans
+}
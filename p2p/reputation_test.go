@@ -0,0 +1,135 @@
+// Copyright 2025 The core-geth Authors
+// This file is part of the core-geth library.
+//
+// The core-geth library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The core-geth library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the core-geth library. If not, see <http://www.gnu.org/licenses/>.
+
+package p2p
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/mclock"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+)
+
+func TestReputationBan(t *testing.T) {
+	db, _ := enode.OpenDB("")
+	defer db.Close()
+
+	var (
+		clock = new(mclock.Simulated)
+		r     = newReputation(db, clock, log.Root())
+		id    = randomID()
+	)
+	// A single invalid block doesn't ban the peer, and is forgotten over time
+	if _, banned := r.penalize(id, ScoreInvalidBlock); banned {
+		t.Fatal("peer banned after a single invalid block")
+	}
+	clock.Run(4 * scoreHalfLife)
+	if _, banned := r.penalize(id, ScoreInvalidBlock); banned {
+		t.Fatal("peer banned despite the score decay")
+	}
+	// Repeated misbehaviours ban the peer
+	ban, banned := r.penalize(id, ScoreInvalidBlock)
+	if !banned {
+		t.Fatal("peer not banned after repeated invalid blocks")
+	}
+	if ban.Reason != ScoreInvalidBlock.String() {
+		t.Errorf("wrong ban reason: have %q, want %q", ban.Reason, ScoreInvalidBlock.String())
+	}
+	if !r.banned(id) {
+		t.Fatal("banned peer not reported as banned")
+	}
+	// The ban expires, the next ban lasting longer
+	now := time.Now()
+	r.now = func() time.Time { return now.Add(minBanDuration + time.Second) }
+	if r.banned(id) {
+		t.Fatal("ban didn't expire")
+	}
+	if len(db.Bans()) != 0 {
+		t.Fatal("expired ban not deleted from the database")
+	}
+	r.penalize(id, ScoreInvalidBlock)
+	if ban, _ = r.penalize(id, ScoreInvalidBlock); ban.Expiry.Sub(r.now()) != 2*minBanDuration {
+		t.Errorf("wrong second ban duration: have %v, want %v", ban.Expiry.Sub(r.now()), 2*minBanDuration)
+	}
+	// Lifting the ban clears the score
+	if unbanned, err := r.unban(id); !unbanned || err != nil {
+		t.Fatalf("failed to unban peer: %v %v", unbanned, err)
+	}
+	if _, banned := r.penalize(id, ScoreInvalidBlock); banned || r.banned(id) {
+		t.Fatal("peer banned after being unbanned")
+	}
+}
+
+// Tests that a slow peer timing out regularly, and getting disconnected for it,
+// isn't banned.
+func TestReputationTimeouts(t *testing.T) {
+	db, _ := enode.OpenDB("")
+	defer db.Close()
+
+	var (
+		clock = new(mclock.Simulated)
+		r     = newReputation(db, clock, log.Root())
+		id    = randomID()
+	)
+	for i := 0; i < 120; i++ {
+		if _, banned := r.penalize(id, ScoreTimeout); banned {
+			t.Fatalf("peer banned after %d timeouts a minute apart", i+1)
+		}
+		clock.Run(time.Minute)
+	}
+}
+
+func TestReputationPersistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nodes")
+	db, err := enode.OpenDB(path)
+	if err != nil {
+		t.Fatalf("failed to open node database: %v", err)
+	}
+	var (
+		r         = newReputation(db, new(mclock.Simulated), log.Root())
+		permanent = randomID()
+		temporary = randomID()
+		expired   = randomID()
+	)
+	r.ban(enode.Ban{ID: permanent, Reason: "manual"})
+	r.ban(enode.Ban{ID: temporary, Expiry: time.Now().Add(time.Hour)})
+	r.ban(enode.Ban{ID: expired, Expiry: time.Now().Add(-time.Hour)})
+	db.Close()
+
+	// Reopen the database, the expired ban is dropped
+	db, err = enode.OpenDB(path)
+	if err != nil {
+		t.Fatalf("failed to reopen node database: %v", err)
+	}
+	defer db.Close()
+	r = newReputation(db, new(mclock.Simulated), log.Root())
+
+	if !r.banned(permanent) || !r.banned(temporary) {
+		t.Fatal("bans not restored from the database")
+	}
+	if r.banned(expired) {
+		t.Fatal("expired ban restored from the database")
+	}
+	if bans := r.list(); len(bans) != 2 {
+		t.Fatalf("wrong number of bans: have %d, want 2", len(bans))
+	}
+	if len(db.Bans()) != 2 {
+		t.Fatal("expired ban not deleted from the database")
+	}
+}
@@ -189,12 +189,13 @@ type Server struct {
 	peerFeed     event.Feed
 	log          log.Logger
 
-	nodedb    *enode.DB
-	localnode *enode.LocalNode
-	discv4    *discover.UDPv4
-	discv5    *discover.UDPv5
-	discmix   *enode.FairMix
-	dialsched *dialScheduler
+	nodedb     *enode.DB
+	localnode  *enode.LocalNode
+	reputation *reputation
+	discv4     *discover.UDPv4
+	discv5     *discover.UDPv5
+	discmix    *enode.FairMix
+	dialsched  *dialScheduler
 
 	// This is read by the NAT port mapping loop.
 	portMappingRegister chan *portMapping
@@ -367,6 +368,55 @@ func (srv *Server) RemovePeer(node *enode.Node) {
 	}
 }
 
+// BanPeer bans the given node until the expiry, disconnecting it if connected.
+// A zero expiry bans the node permanently. The ban is kept in the node database.
+func (srv *Server) BanPeer(id enode.ID, expiry time.Time, reason string) error {
+	if srv.reputation == nil {
+		return errServerStopped
+	}
+	if err := srv.reputation.ban(enode.Ban{ID: id, Expiry: expiry, Reason: reason}); err != nil {
+		return err
+	}
+	srv.doPeerOp(func(peers map[enode.ID]*Peer) {
+		if peer := peers[id]; peer != nil {
+			peer.Disconnect(DiscUselessPeer)
+		}
+	})
+	return nil
+}
+
+// UnbanPeer lifts the ban of the given node, returning whether it was banned.
+func (srv *Server) UnbanPeer(id enode.ID) (bool, error) {
+	if srv.reputation == nil {
+		return false, errServerStopped
+	}
+	return srv.reputation.unban(id)
+}
+
+// Bans returns the banned nodes.
+func (srv *Server) Bans() []*BanInfo {
+	if srv.reputation == nil {
+		return nil
+	}
+	bans := srv.reputation.list()
+	infos := make([]*BanInfo, 0, len(bans))
+	for _, ban := range bans {
+		info := &BanInfo{
+			ID:     ban.ID.String(),
+			Reason: ban.Reason,
+		}
+		if n := srv.nodedb.Node(ban.ID); n != nil {
+			info.Enode = n.URLv4()
+		}
+		if !ban.Expiry.IsZero() {
+			expiry := ban.Expiry
+			info.Expiry = &expiry
+		}
+		infos = append(infos, info)
+	}
+	return infos
+}
+
 // AddTrustedPeer adds the given node to a reserved trusted list which allows the
 // node to always connect, even if the slot are full.
 func (srv *Server) AddTrustedPeer(node *enode.Node) {
@@ -529,6 +579,7 @@ func (srv *Server) setupLocalNode() error {
 		return err
 	}
 	srv.nodedb = db
+	srv.reputation = newReputation(db, srv.clock, srv.log)
 	srv.localnode = enode.NewLocalNode(db, srv.PrivateKey)
 	srv.localnode.SetFallbackIP(net.IP{127, 0, 0, 1})
 	// TODO: check conflicts
@@ -610,6 +661,7 @@ func (srv *Server) setupDialScheduler() {
 		maxActiveDials: srv.MaxPendingPeers,
 		log:            srv.Logger,
 		netRestrict:    srv.NetRestrict,
+		banned:         srv.reputation.banned,
 		dialer:         srv.Dialer,
 		clock:          srv.clock,
 	}
@@ -840,6 +892,8 @@ func (srv *Server) postHandshakeChecks(peers map[enode.ID]*Peer, inboundCount in
 		return DiscAlreadyConnected
 	case c.node.ID() == srv.localnode.ID():
 		return DiscSelf
+	case srv.reputation.banned(c.node.ID()):
+		return DiscUselessPeer
 	default:
 		return nil
 	}
@@ -1044,6 +1098,7 @@ func (srv *Server) checkpoint(c *conn, stage chan<- *conn) error {
 
 func (srv *Server) launchPeer(c *conn) *Peer {
 	p := newPeer(srv.log, c, srv.Protocols)
+	p.reputation = srv.reputation
 	if srv.EnableMsgEvents {
 		// If message events are enabled, pass the peerFeed
 		// to the peer.
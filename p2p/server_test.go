@@ -310,6 +310,51 @@ func TestServerAtCap(t *testing.T) {
 	}
 }
 
+func TestServerBannedPeer(t *testing.T) {
+	remoteKey := newkey()
+	srv := &Server{
+		Config: Config{
+			PrivateKey:  newkey(),
+			MaxPeers:    10,
+			NoDial:      true,
+			NoDiscovery: true,
+			Logger:      testlog.Logger(t, log.LvlTrace),
+		},
+	}
+	if err := srv.Start(); err != nil {
+		t.Fatalf("could not start: %v", err)
+	}
+	defer srv.Stop()
+
+	newconn := func(id enode.ID) *conn {
+		fd, _ := net.Pipe()
+		tx := newTestTransport(&remoteKey.PublicKey, fd, nil)
+		node := enode.SignNull(new(enr.Record), id)
+		return &conn{fd: fd, transport: tx, flags: inboundConn, node: node, cont: make(chan error)}
+	}
+	bannedID := randomID()
+	if err := srv.BanPeer(bannedID, time.Time{}, "test"); err != nil {
+		t.Fatalf("could not ban peer: %v", err)
+	}
+	if err := srv.checkpoint(newconn(bannedID), srv.checkpointPostHandshake); err != DiscUselessPeer {
+		t.Error("wrong error for banned conn:", err)
+	}
+	bans := srv.Bans()
+	if len(bans) != 1 || bans[0].ID != bannedID.String() || bans[0].Expiry != nil || bans[0].Reason != "test" {
+		t.Fatalf("wrong bans: %v", bans)
+	}
+	// Lift the ban and try again
+	if unbanned, err := srv.UnbanPeer(bannedID); !unbanned || err != nil {
+		t.Fatalf("could not unban peer: %v %v", unbanned, err)
+	}
+	if err := srv.checkpoint(newconn(bannedID), srv.checkpointPostHandshake); err != nil {
+		t.Error("unexpected error for unbanned conn:", err)
+	}
+	if bans := srv.Bans(); len(bans) != 0 {
+		t.Fatalf("wrong bans after unban: %v", bans)
+	}
+}
+
 func TestServerPeerLimits(t *testing.T) {
 	srvkey := newkey()
 	clientkey := newkey()
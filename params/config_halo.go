@@ -99,12 +99,31 @@ var (
 		// At block 1,000,000: 2 HALO per block
 		DisposalBlock: big.NewInt(0), // Defuse difficulty bomb from genesis
 
-		// Uncle rewards depth configuration
-		// MaxUncles = 1 (enforced in consensus)
-		// MaxUnclesDepth = 2
-		// UncleRewardDepth1 = 875/1000 of block reward
-		// UncleRewardDepth2 = 750/1000 of block reward
-		// NephewReward = 31/1000 of block reward per uncle
+		// Halo protocol rules
+		// MaxUncles = 1, MaxUnclesDepth = 2
+		// Future block tolerance = 30s
+		// Timestamps must exceed the median of the last 11 blocks
+		// Halo difficulty algorithm, reward schedule and 4-way base fee split
+		HaloUnclesFBlock:          big.NewInt(0),
+		HaloFutureBlockTimeFBlock: big.NewInt(0),
+		HaloMedianTimePastFBlock:  big.NewInt(0),
+		HaloDifficultyFBlock:      big.NewInt(0),
+		HaloRewardsFBlock:         big.NewInt(0),
+		HaloFeeDistributionFBlock: big.NewInt(0),
+
+		// Base fee split: 40% burn, 30% miner, 20% ecosystem fund, 10% reserve fund
+		HaloFeeDistributionSchedule: ctypes.FeeDistributionSchedule{
+			0: {
+				Total: 1000,
+				Burn:  400,
+				Miner: 300,
+				Shares: []ctypes.FeeDistributionShare{
+					{Recipient: HaloEcosystemFundAddress, Ratio: 200},
+					{Recipient: HaloReserveFundAddress, Ratio: 100},
+				},
+				ContractFeeShareFund: &HaloEcosystemFundAddress,
+			},
+		},
 
 		RequireBlockHashes: map[uint64]common.Hash{},
 	}
@@ -11,8 +11,8 @@ func TestHaloChainConfig(t *testing.T) {
 		t.Fatalf("expected chain ID 12000, got %d", HaloChainConfig.GetChainID().Uint64())
 	}
 
-	if HaloChainConfig.GetNetworkID() != 12000 {
-		t.Fatalf("expected network ID 12000, got %d", HaloChainConfig.GetNetworkID())
+	if *HaloChainConfig.GetNetworkID() != 12000 {
+		t.Fatalf("expected network ID 12000, got %d", *HaloChainConfig.GetNetworkID())
 	}
 
 	if HaloChainConfig.GetConsensusEngineType() != ctypes.ConsensusEngineT_Ethash {
@@ -20,18 +20,39 @@ func TestHaloChainConfig(t *testing.T) {
 	}
 
 	// Test EIP-1559 is enabled from genesis
-	if HaloChainConfig.GetEIP1559Transition() == nil || HaloChainConfig.GetEIP1559Transition().Uint64() != 0 {
+	if HaloChainConfig.GetEIP1559Transition() == nil || *HaloChainConfig.GetEIP1559Transition() != 0 {
 		t.Fatalf("expected EIP-1559 enabled from genesis")
 	}
 
 	// Test modern EIPs are enabled from genesis
-	if HaloChainConfig.GetEIP155Transition() == nil || HaloChainConfig.GetEIP155Transition().Uint64() != 0 {
+	if HaloChainConfig.GetEIP155Transition() == nil || *HaloChainConfig.GetEIP155Transition() != 0 {
 		t.Fatalf("expected EIP-155 enabled from genesis")
 	}
 
-	if HaloChainConfig.GetEIP1344Transition() == nil || HaloChainConfig.GetEIP1344Transition().Uint64() != 0 {
+	if HaloChainConfig.GetEIP1344Transition() == nil || *HaloChainConfig.GetEIP1344Transition() != 0 {
 		t.Fatalf("expected EIP-1344 (CHAINID) enabled from genesis")
 	}
+
+	// Test Halo protocol rules are enabled from genesis
+	for _, fn := range []func() *uint64{
+		HaloChainConfig.GetHaloUnclesTransition,
+		HaloChainConfig.GetHaloFutureBlockTimeTransition,
+		HaloChainConfig.GetHaloMedianTimePastTransition,
+		HaloChainConfig.GetHaloDifficultyTransition,
+		HaloChainConfig.GetHaloRewardsTransition,
+		HaloChainConfig.GetHaloFeeDistributionTransition,
+	} {
+		if n := fn(); n == nil || *n != 0 {
+			t.Fatalf("expected Halo rules enabled from genesis, got %v", n)
+		}
+	}
+	// Test the base fee split is configured from genesis
+	if err := HaloChainConfig.GetHaloFeeDistributionSchedule().Validate(); err != nil {
+		t.Fatalf("invalid fee distribution schedule: %v", err)
+	}
+	if split := HaloChainConfig.GetHaloFeeDistributionSchedule().ForBlock(0); split == nil || split.Burn != 400 || split.Miner != 300 {
+		t.Fatalf("unexpected fee distribution at genesis: %v", split)
+	}
 }
 
 func TestHaloGenesisAddressesNotZero(t *testing.T) {
@@ -173,6 +173,12 @@ func IsValid(conf ctypes.ChainConfigurator, head *uint64) *ConfigValidError {
 	if conf.GetNetworkID() == nil {
 		return NewValidErr("NetworkID cannot be nil", "!=nil", conf.GetNetworkID())
 	}
+	if err := conf.GetHaloFeeDistributionSchedule().Validate(); err != nil {
+		return NewValidErr("invalid Halo fee distribution schedule", err, conf.GetHaloFeeDistributionSchedule())
+	}
+	if err := ValidateHaloContractFeeSharing(conf); err != nil {
+		return err.(*ConfigValidError)
+	}
 	if head == nil {
 		return nil
 	}
@@ -187,6 +193,20 @@ func IsValid(conf ctypes.ChainConfigurator, head *uint64) *ConfigValidError {
 	return nil
 }
 
+// ValidateHaloContractFeeSharing checks that the Halo contract fee sharing isn't
+// activated before the Halo fee distribution, as the contract fee shares are paid
+// out of the base fee credit of the fund, which would otherwise be minted.
+func ValidateHaloContractFeeSharing(conf ctypes.ChainConfigurator) error {
+	sharing, distribution := conf.GetHaloContractFeeSharingTransition(), conf.GetHaloFeeDistributionTransition()
+	if sharing == nil {
+		return nil
+	}
+	if distribution == nil || *distribution > *sharing {
+		return NewValidErr("Halo contract fee sharing requires the Halo fee distribution. A:HaloContractFeeSharing/B:HaloFeeDistribution", sharing, distribution)
+	}
+	return nil
+}
+
 // Compatible checks whether the two configurations are compatible with each other.
 // It returns an error if the configurations are incompatible, or nil if they are.
 // If headBlock is nil, it will only check the time-based fork configurations.
@@ -262,6 +282,9 @@ func compatible(headBlock *big.Int, headTime *uint64, a, b ctypes.ChainConfigura
 				return newBlockCompatError("mismatching chain ids after EIP155 transition", tai, tbi)
 			}
 		}
+		if err := checkFeeDistributionCompatible(a.GetHaloFeeDistributionSchedule(), b.GetHaloFeeDistributionSchedule(), headBlock); err != nil {
+			return err
+		}
 	}
 
 	// Handle forks by time.
@@ -293,6 +316,25 @@ func compatible(headBlock *big.Int, headTime *uint64, a, b ctypes.ChainConfigura
 	return nil
 }
 
+// checkFeeDistributionCompatible returns an error if the fee distribution schedules
+// disagree on the split of any block up to head.
+func checkFeeDistributionCompatible(a, b ctypes.FeeDistributionSchedule, head *big.Int) *ConfigCompatError {
+	forks := append(a.Forks(), b.Forks()...)
+	sort.Slice(forks, func(i, j int) bool {
+		return forks[i] < forks[j]
+	})
+	for _, fork := range forks {
+		block := new(big.Int).SetUint64(fork)
+		if !isBlockForked(block, head) {
+			break
+		}
+		if !reflect.DeepEqual(a.ForBlock(fork), b.ForBlock(fork)) {
+			return newBlockCompatError("incompatible Halo fee distribution schedule", block, block)
+		}
+	}
+	return nil
+}
+
 // isBigNilOrMaxed returns true if the given big.Int is nil or has a value of
 // any math max value (uint64, int64, int, int32, int16, int8).
 func isBigNilOrMaxed(b *big.Int) bool {
@@ -482,6 +524,13 @@ func BlockForks(conf ctypes.ChainConfigurator) []uint64 {
 			forksM[*response] = struct{}{}
 		}
 	}
+	// Changes of the fee distribution schedule are hard forks as well.
+	for _, fork := range conf.GetHaloFeeDistributionSchedule().Forks() {
+		if _, ok := forksM[fork]; !ok && fork != 0 {
+			forks = append(forks, fork)
+			forksM[fork] = struct{}{}
+		}
+	}
 	sort.Slice(forks, func(i, j int) bool {
 		return forks[i] < forks[j]
 	})
@@ -144,6 +144,16 @@ func Crush(dest, source interface{}, crushZeroValues bool) error {
 		}
 	}
 next:
+	// Are both interfaces HaloConfigurators?
+	// These are set after the consensus engine, since most of the Halo rules are ethash rules.
+	fromHaloConfigurator, fromHCOK := source.(ctypes.HaloConfigurator)
+	toHaloConfigurator, toHCOK := dest.(ctypes.HaloConfigurator)
+	if fromHCOK && toHCOK {
+		k := reflect.TypeOf((*ctypes.HaloConfigurator)(nil)).Elem()
+		if err := crush(k, fromHaloConfigurator, toHaloConfigurator, crushZeroValues); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -168,7 +178,7 @@ methodsLoop:
 		if !crushZeroValues {
 			allNil := true
 			for _, r := range response {
-				if r.Kind() == reflect.Ptr && !r.IsNil() {
+				if (r.Kind() == reflect.Ptr || r.Kind() == reflect.Map) && !r.IsNil() {
 					allNil = false
 					break
 				}
@@ -212,6 +212,52 @@ func TestCompatible(t *testing.T) {
 	t.Log(fns)
 }
 
+func TestHaloFeeDistributionSchedule(t *testing.T) {
+	burnAll := &ctypes.FeeDistribution{Total: 1, Burn: 1}
+	mineAll := &ctypes.FeeDistribution{Total: 1, Miner: 1}
+	newConfig := func(schedule ctypes.FeeDistributionSchedule) *coregeth.CoreGethChainConfig {
+		return &coregeth.CoreGethChainConfig{
+			NetworkID:                   1337,
+			ChainID:                     big.NewInt(1337),
+			Ethash:                      &ctypes.EthashConfig{},
+			HaloRewardsFBlock:           big.NewInt(100),
+			HaloFeeDistributionSchedule: schedule,
+		}
+	}
+	a := newConfig(ctypes.FeeDistributionSchedule{0: burnAll, 500: mineAll})
+
+	if forks := confp.BlockForks(a); !reflect.DeepEqual(forks, []uint64{100, 500}) {
+		t.Errorf("block forks: have %v, want [100 500]", forks)
+	}
+	cloned, err := confp.CloneChainConfigurator(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diffs := confp.Equal(reflect.TypeOf((*ctypes.ChainConfigurator)(nil)), a, cloned); len(diffs) != 0 {
+		t.Errorf("clone mismatch: %v", diffs)
+	}
+	if !reflect.DeepEqual(cloned.GetHaloFeeDistributionSchedule(), a.HaloFeeDistributionSchedule) {
+		t.Errorf("clone lost fee distribution schedule: %v", cloned.GetHaloFeeDistributionSchedule())
+	}
+
+	// Rescheduling the split is only compatible before the affected blocks
+	b := newConfig(ctypes.FeeDistributionSchedule{0: burnAll, 800: mineAll})
+	if err := confp.Compatible(big.NewInt(499), nil, a, b); err != nil {
+		t.Errorf("unexpected incompatibility: %v", err)
+	}
+	compatErr := confp.Compatible(big.NewInt(600), nil, a, b)
+	if compatErr == nil {
+		t.Fatal("expected incompatibility")
+	}
+	if compatErr.RewindToBlock != 499 {
+		t.Errorf("rewind mismatch: have %d, want 499", compatErr.RewindToBlock)
+	}
+
+	if err := confp.IsValid(newConfig(ctypes.FeeDistributionSchedule{0: {Total: 2, Burn: 1}}), nil); err == nil {
+		t.Error("expected invalid fee distribution schedule")
+	}
+}
+
 func isJSONEqual(a, b interface{}) bool {
 	aa, err := json.Marshal(a)
 	if err != nil {
@@ -8,8 +8,8 @@ import (
 	"github.com/ethereum/go-ethereum/params/types/genesisT"
 )
 
-// HaloGenesisHash - This will be updated after first genesis initialization
-var HaloGenesisHash = common.HexToHash("0x0000000000000000000000000000000000000000000000000000000000000000")
+// HaloGenesisHash is the hash of the Halo network genesis block.
+var HaloGenesisHash = common.HexToHash("0x26f077f76c15c59fe9947f09f7bbda72a100a28519c14fd74edb39c43281910e")
 
 // Halo fund addresses
 var (
@@ -8,6 +8,8 @@ import (
 	"testing"
 
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params/types/coregeth"
+	"github.com/ethereum/go-ethereum/params/types/ctypes"
 	"github.com/holiman/uint256"
 )
 
@@ -271,3 +273,23 @@ func TestHaloTokenomics(t *testing.T) {
 			expectedMaxSupply.String(), maxSupply.String())
 	}
 }
+
+// TestHaloRewardsActivation tests that the Halo reward schedule only applies from
+// the Halo rewards fork.
+func TestHaloRewardsActivation(t *testing.T) {
+	fork := uint64(10)
+	config := &coregeth.CoreGethChainConfig{
+		ChainID: big.NewInt(1337),
+		Ethash:  &ctypes.EthashConfig{},
+	}
+	config.SetHaloRewardsTransition(&fork)
+
+	before := &types.Header{Number: big.NewInt(9)}
+	if have, _ := GetRewards(config, before, nil); !have.Eq(ctypes.EthashBlockReward(config, before.Number)) {
+		t.Errorf("reward before fork: have %v, want %v", have, ctypes.EthashBlockReward(config, before.Number))
+	}
+	after := &types.Header{Number: big.NewInt(10)}
+	if have, _ := GetRewards(config, after, nil); !have.Eq(GetHaloBlockReward(after.Number)) {
+		t.Errorf("reward after fork: have %v, want %v", have, GetHaloBlockReward(after.Number))
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright 2025 The core-geth Authors
+// This file is part of the core-geth library.
+//
+// The core-geth library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The core-geth library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the core-geth library. If not, see <http://www.gnu.org/licenses/>.
+
+package mutations
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/holiman/uint256"
+)
+
+// Lyra2 (MINTME) reward schedule constants.
+var (
+	lyra2MaximumBlockReward     = new(big.Int).Mul(big.NewInt(5e+18), big.NewInt(10)) // 50 WEB
+	lyra2EraLength              = big.NewInt(100000)
+	lyra2EraOffset              = big.NewInt(72)  // Eras elapsed before the genesis of the network
+	lyra2RewardEraOffset        = big.NewInt(865) // Eras skipped due to the reward decrease
+	lyra2DisinflationQuotient   = big.NewInt(249)
+	lyra2DisinflationDivisor    = big.NewInt(250)
+	lyra2UncleRewardDenominator = uint256.NewInt(32)
+)
+
+// lyra2BlockReward calculates the rewards of the lyra2 (MINTME) network: the
+// winner reward decreases by 0.4% every era, and every uncle is rewarded 1/32
+// of it. Including uncles does not increase the winner reward.
+func lyra2BlockReward(header *types.Header, uncles []*types.Header) (*uint256.Int, []*uint256.Int) {
+	era := GetBlockEra(header.Number, lyra2EraLength)
+	era.Add(era, lyra2EraOffset)
+
+	minerReward := GetLyra2BlockWinnerRewardByEra(era)
+	uncleReward := new(uint256.Int).Div(minerReward, lyra2UncleRewardDenominator)
+
+	uncleRewards := make([]*uint256.Int, len(uncles))
+	for i := range uncles {
+		uncleRewards[i] = new(uint256.Int).Set(uncleReward)
+	}
+	return minerReward, uncleRewards
+}
+
+// GetLyra2BlockWinnerRewardByEra gets the lyra2 block reward of an era, at the
+// disinflation rate of 249/250 per era.
+func GetLyra2BlockWinnerRewardByEra(era *big.Int) *uint256.Int {
+	if era.Sign() == 0 {
+		return uint256.MustFromBig(lyra2MaximumBlockReward)
+	}
+	e := new(big.Int).Add(era, lyra2RewardEraOffset)
+
+	// MaxBlockReward _r_ * (249/250)**era == MaxBlockReward * (249**era) / (250**era)
+	// since (q/d)**n == q**n / d**n
+	// The powers overflow 256 bits, hence the big integer arithmetic.
+	q := new(big.Int).Exp(lyra2DisinflationQuotient, e, nil)
+	d := new(big.Int).Exp(lyra2DisinflationDivisor, e, nil)
+
+	r := new(big.Int).Mul(lyra2MaximumBlockReward, q)
+	return uint256.MustFromBig(r.Div(r, d))
+}
@@ -0,0 +1,57 @@
+// Copyright 2025 The core-geth Authors
+// This file is part of the core-geth library.
+//
+// The core-geth library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The core-geth library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the core-geth library. If not, see <http://www.gnu.org/licenses/>.
+
+package mutations
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/holiman/uint256"
+)
+
+func TestLyra2Rewards(t *testing.T) {
+	tests := []struct {
+		number uint64
+		reward string // 50 WEB * (249/250)^(era + 72 + 865)
+	}{
+		{1, "1169418896691735179"},
+		{100000, "1169418896691735179"},
+		{100001, "1164741221104968239"},
+	}
+	for _, tt := range tests {
+		header := &types.Header{Number: new(big.Int).SetUint64(tt.number)}
+		uncles := []*types.Header{
+			{Number: new(big.Int).SetUint64(tt.number - 1)},
+			{Number: new(big.Int).SetUint64(tt.number - 1)},
+		}
+		want := uint256.MustFromDecimal(tt.reward)
+
+		minerReward, uncleRewards := GetRewards(params.MintMeChainConfig, header, uncles)
+		if minerReward.Cmp(want) != 0 {
+			t.Errorf("block %d: miner reward mismatch: have %v, want %v", tt.number, minerReward, want)
+		}
+		// Uncles get 1/32 of the block reward, and don't reward the miner
+		wantUncle := new(uint256.Int).Div(want, uint256.NewInt(32))
+		for i, reward := range uncleRewards {
+			if reward.Cmp(wantUncle) != 0 {
+				t.Errorf("block %d: uncle %d reward mismatch: have %v, want %v", tt.number, i, reward, wantUncle)
+			}
+		}
+	}
+}
@@ -281,6 +281,20 @@ type CoreGethChainConfig struct {
 	RequireBlockHashes map[uint64]common.Hash `json:"requireBlockHashes"`
 
 	Lyra2NonceTransitionBlock *big.Int `json:"lyra2NonceTransitionBlock,omitempty"`
+
+	// Halo network protocol rules
+	HaloUnclesFBlock          *big.Int `json:"haloUnclesFBlock,omitempty"`          // Max 1 uncle, max uncle depth 2
+	HaloFutureBlockTimeFBlock *big.Int `json:"haloFutureBlockTimeFBlock,omitempty"` // 30s future block tolerance
+	HaloMedianTimePastFBlock  *big.Int `json:"haloMedianTimePastFBlock,omitempty"`  // Median time past timestamp validation
+	HaloDifficultyFBlock      *big.Int `json:"haloDifficultyFBlock,omitempty"`      // Halo difficulty adjustment algorithm
+	HaloDifficultyV2FBlock    *big.Int `json:"haloDifficultyV2FBlock,omitempty"`    // Deterministic Halo difficulty adjustment algorithm
+	HaloRewardsFBlock         *big.Int `json:"haloRewardsFBlock,omitempty"`         // Halo block reward schedule
+	HaloFeeDistributionFBlock *big.Int `json:"haloFeeDistributionFBlock,omitempty"` // EIP-1559 base fee distribution
+
+	HaloContractFeeSharingFBlock *big.Int `json:"haloContractFeeSharingFBlock,omitempty"` // Per-contract fee sharing
+	HaloSponsoredTxFBlock        *big.Int `json:"haloSponsoredTxFBlock,omitempty"`        // Sponsored transactions
+
+	HaloFeeDistributionSchedule ctypes.FeeDistributionSchedule `json:"haloFeeDistributionSchedule,omitempty"`
 }
 
 // String implements the fmt.Stringer interface.
@@ -1413,3 +1413,180 @@ func (c *CoreGethChainConfig) SetLyra2NonceTransition(n *uint64) error {
 
 	return nil
 }
+
+func (c *CoreGethChainConfig) GetLyra2TCost() uint64 {
+	if c.Lyra2 == nil {
+		return 0
+	}
+	return c.Lyra2.TCost
+}
+
+func (c *CoreGethChainConfig) SetLyra2TCost(n uint64) error {
+	if c.Lyra2 == nil {
+		return ctypes.ErrUnsupportedConfigFatal
+	}
+	c.Lyra2.TCost = n
+	return nil
+}
+
+func (c *CoreGethChainConfig) GetLyra2MinimumDifficulty() *big.Int {
+	if c.Lyra2 == nil {
+		return nil
+	}
+	return c.Lyra2.MinimumDifficulty
+}
+
+func (c *CoreGethChainConfig) SetLyra2MinimumDifficulty(i *big.Int) error {
+	if c.Lyra2 == nil {
+		return ctypes.ErrUnsupportedConfigFatal
+	}
+	c.Lyra2.MinimumDifficulty = i
+	return nil
+}
+
+func (c *CoreGethChainConfig) GetLyra2DifficultyBoundDivisor() *big.Int {
+	if c.Lyra2 == nil {
+		return nil
+	}
+	return c.Lyra2.DifficultyBoundDivisor
+}
+
+func (c *CoreGethChainConfig) SetLyra2DifficultyBoundDivisor(i *big.Int) error {
+	if c.Lyra2 == nil {
+		return ctypes.ErrUnsupportedConfigFatal
+	}
+	c.Lyra2.DifficultyBoundDivisor = i
+	return nil
+}
+
+func (c *CoreGethChainConfig) GetLyra2DurationLimit() *big.Int {
+	if c.Lyra2 == nil {
+		return nil
+	}
+	return c.Lyra2.DurationLimit
+}
+
+func (c *CoreGethChainConfig) SetLyra2DurationLimit(i *big.Int) error {
+	if c.Lyra2 == nil {
+		return ctypes.ErrUnsupportedConfigFatal
+	}
+	c.Lyra2.DurationLimit = i
+	return nil
+}
+
+func (c *CoreGethChainConfig) GetHaloUnclesTransition() *uint64 {
+	return bigNewU64(c.HaloUnclesFBlock)
+}
+
+func (c *CoreGethChainConfig) SetHaloUnclesTransition(n *uint64) error {
+	c.HaloUnclesFBlock = setBig(c.HaloUnclesFBlock, n)
+	return nil
+}
+
+func (c *CoreGethChainConfig) GetHaloFutureBlockTimeTransition() *uint64 {
+	return bigNewU64(c.HaloFutureBlockTimeFBlock)
+}
+
+func (c *CoreGethChainConfig) SetHaloFutureBlockTimeTransition(n *uint64) error {
+	c.HaloFutureBlockTimeFBlock = setBig(c.HaloFutureBlockTimeFBlock, n)
+	return nil
+}
+
+func (c *CoreGethChainConfig) GetHaloMedianTimePastTransition() *uint64 {
+	return bigNewU64(c.HaloMedianTimePastFBlock)
+}
+
+func (c *CoreGethChainConfig) SetHaloMedianTimePastTransition(n *uint64) error {
+	c.HaloMedianTimePastFBlock = setBig(c.HaloMedianTimePastFBlock, n)
+	return nil
+}
+
+func (c *CoreGethChainConfig) GetHaloDifficultyTransition() *uint64 {
+	if c.GetConsensusEngineType() != ctypes.ConsensusEngineT_Ethash {
+		return nil
+	}
+	return bigNewU64(c.HaloDifficultyFBlock)
+}
+
+func (c *CoreGethChainConfig) SetHaloDifficultyTransition(n *uint64) error {
+	if c.Ethash == nil {
+		if n == nil {
+			return nil
+		}
+		return ctypes.ErrUnsupportedConfigFatal
+	}
+	c.HaloDifficultyFBlock = setBig(c.HaloDifficultyFBlock, n)
+	return nil
+}
+
+func (c *CoreGethChainConfig) GetHaloDifficultyV2Transition() *uint64 {
+	if c.GetConsensusEngineType() != ctypes.ConsensusEngineT_Ethash {
+		return nil
+	}
+	return bigNewU64(c.HaloDifficultyV2FBlock)
+}
+
+func (c *CoreGethChainConfig) SetHaloDifficultyV2Transition(n *uint64) error {
+	if c.Ethash == nil {
+		if n == nil {
+			return nil
+		}
+		return ctypes.ErrUnsupportedConfigFatal
+	}
+	c.HaloDifficultyV2FBlock = setBig(c.HaloDifficultyV2FBlock, n)
+	return nil
+}
+
+func (c *CoreGethChainConfig) GetHaloRewardsTransition() *uint64 {
+	if c.GetConsensusEngineType() != ctypes.ConsensusEngineT_Ethash {
+		return nil
+	}
+	return bigNewU64(c.HaloRewardsFBlock)
+}
+
+func (c *CoreGethChainConfig) SetHaloRewardsTransition(n *uint64) error {
+	if c.Ethash == nil {
+		if n == nil {
+			return nil
+		}
+		return ctypes.ErrUnsupportedConfigFatal
+	}
+	c.HaloRewardsFBlock = setBig(c.HaloRewardsFBlock, n)
+	return nil
+}
+
+func (c *CoreGethChainConfig) GetHaloFeeDistributionTransition() *uint64 {
+	return bigNewU64(c.HaloFeeDistributionFBlock)
+}
+
+func (c *CoreGethChainConfig) SetHaloFeeDistributionTransition(n *uint64) error {
+	c.HaloFeeDistributionFBlock = setBig(c.HaloFeeDistributionFBlock, n)
+	return nil
+}
+
+func (c *CoreGethChainConfig) GetHaloContractFeeSharingTransition() *uint64 {
+	return bigNewU64(c.HaloContractFeeSharingFBlock)
+}
+
+func (c *CoreGethChainConfig) SetHaloContractFeeSharingTransition(n *uint64) error {
+	c.HaloContractFeeSharingFBlock = setBig(c.HaloContractFeeSharingFBlock, n)
+	return nil
+}
+
+func (c *CoreGethChainConfig) GetHaloSponsoredTxTransition() *uint64 {
+	return bigNewU64(c.HaloSponsoredTxFBlock)
+}
+
+func (c *CoreGethChainConfig) SetHaloSponsoredTxTransition(n *uint64) error {
+	c.HaloSponsoredTxFBlock = setBig(c.HaloSponsoredTxFBlock, n)
+	return nil
+}
+
+func (c *CoreGethChainConfig) GetHaloFeeDistributionSchedule() ctypes.FeeDistributionSchedule {
+	return c.HaloFeeDistributionSchedule
+}
+
+func (c *CoreGethChainConfig) SetHaloFeeDistributionSchedule(s ctypes.FeeDistributionSchedule) error {
+	c.HaloFeeDistributionSchedule = s
+	return nil
+}
@@ -47,6 +47,7 @@ type ChainConfigurator interface {
 	ProtocolSpecifier
 	Forker
 	ConsensusEnginator // Consensus Engine
+	HaloConfigurator
 	// CHTer
 }
 
@@ -371,6 +372,63 @@ type CliqueConfigurator interface {
 type Lyra2Configurator interface {
 	GetLyra2NonceTransition() *uint64
 	SetLyra2NonceTransition(n *uint64) error
+	GetLyra2TCost() uint64
+	SetLyra2TCost(n uint64) error
+	GetLyra2MinimumDifficulty() *big.Int
+	SetLyra2MinimumDifficulty(i *big.Int) error
+	GetLyra2DifficultyBoundDivisor() *big.Int
+	SetLyra2DifficultyBoundDivisor(i *big.Int) error
+	GetLyra2DurationLimit() *big.Int
+	SetLyra2DurationLimit(i *big.Int) error
+}
+
+// HaloConfigurator defines the Halo network protocol rules.
+// Each rule is activated independently by block number, so that
+// networks other than Halo mainnet can opt in to them.
+type HaloConfigurator interface {
+	// GetHaloUnclesTransition limits blocks to a single uncle of at most depth 2.
+	GetHaloUnclesTransition() *uint64
+	SetHaloUnclesTransition(n *uint64) error
+
+	// GetHaloFutureBlockTimeTransition extends the allowed future block time to 30 seconds.
+	GetHaloFutureBlockTimeTransition() *uint64
+	SetHaloFutureBlockTimeTransition(n *uint64) error
+
+	// GetHaloMedianTimePastTransition requires block timestamps to exceed the median of the last 11 blocks.
+	GetHaloMedianTimePastTransition() *uint64
+	SetHaloMedianTimePastTransition(n *uint64) error
+
+	// GetHaloDifficultyTransition enables the Halo difficulty adjustment algorithm.
+	GetHaloDifficultyTransition() *uint64
+	SetHaloDifficultyTransition(n *uint64) error
+
+	// GetHaloDifficultyV2Transition enables the deterministic Halo difficulty adjustment algorithm,
+	// which depends only on the header and its ancestors. It supersedes HaloDifficultyTransition.
+	GetHaloDifficultyV2Transition() *uint64
+	SetHaloDifficultyV2Transition(n *uint64) error
+
+	// GetHaloRewardsTransition enables the Halo block, uncle and nephew reward schedule.
+	GetHaloRewardsTransition() *uint64
+	SetHaloRewardsTransition(n *uint64) error
+
+	// GetHaloFeeDistributionTransition distributes the EIP-1559 base fee instead of burning all of it.
+	GetHaloFeeDistributionTransition() *uint64
+	SetHaloFeeDistributionTransition(n *uint64) error
+
+	// GetHaloContractFeeSharingTransition redirects part of the base fee paid by transactions
+	// to contracts which opted in to fee sharing.
+	GetHaloContractFeeSharingTransition() *uint64
+	SetHaloContractFeeSharingTransition(n *uint64) error
+
+	// GetHaloSponsoredTxTransition enables sponsored transactions, whose gas is paid
+	// by a sponsor countersigning the transaction of the sender.
+	GetHaloSponsoredTxTransition() *uint64
+	SetHaloSponsoredTxTransition(n *uint64) error
+
+	// GetHaloFeeDistributionSchedule returns the base fee splits applied once
+	// HaloFeeDistributionTransition is activated, keyed by fork block.
+	GetHaloFeeDistributionSchedule() FeeDistributionSchedule
+	SetHaloFeeDistributionSchedule(s FeeDistributionSchedule) error
 }
 
 type BlockSealer interface {
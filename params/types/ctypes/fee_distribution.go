@@ -0,0 +1,171 @@
+// Copyright 2025 The core-geth Authors
+// This file is part of the core-geth library.
+//
+// The core-geth library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The core-geth library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the core-geth library. If not, see <http://www.gnu.org/licenses/>.
+
+package ctypes
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+)
+
+var (
+	ErrFeeDistributionZeroTotal     = errors.New("fee distribution total must be positive")
+	ErrFeeDistributionRatioSum      = errors.New("fee distribution ratios do not sum to total")
+	ErrFeeDistributionZeroRecipient = errors.New("fee distribution recipient cannot be zero address")
+	ErrFeeDistributionDupRecipient  = errors.New("duplicate fee distribution recipient")
+	ErrFeeDistributionMissingSplit  = errors.New("missing fee distribution")
+	ErrFeeDistributionRatioOverflow = errors.New("fee distribution ratios overflow")
+	ErrFeeDistributionUnknownFund   = errors.New("contract fee share fund is not a fee distribution recipient")
+)
+
+// FeeDistributionShare is a share of the base fee credited to a fixed recipient.
+type FeeDistributionShare struct {
+	Recipient common.Address `json:"recipient"`
+	Ratio     uint64         `json:"ratio"`
+}
+
+// FeeDistribution splits the EIP-1559 base fee of a block. Each part receives
+// ratio/Total of the base fee; the burned part is the remainder after rounding
+// down the others, so that no wei is created.
+//
+// If ContractFeeShareFund is set, per-contract fee sharing is paid out of the
+// share of that recipient.
+type FeeDistribution struct {
+	Total                uint64                 `json:"total"`
+	Burn                 uint64                 `json:"burn"`
+	Miner                uint64                 `json:"miner"` // Credited to the block's coinbase
+	Shares               []FeeDistributionShare `json:"shares,omitempty"`
+	ContractFeeShareFund *common.Address        `json:"contractFeeShareFund,omitempty"`
+}
+
+// Share returns the ratio of the given recipient, or 0 if it has no share.
+func (d *FeeDistribution) Share(recipient common.Address) uint64 {
+	for _, share := range d.Shares {
+		if share.Recipient == recipient {
+			return share.Ratio
+		}
+	}
+	return 0
+}
+
+// Validate checks that the ratios sum to the total and that the recipients are
+// unique, non-zero addresses.
+func (d *FeeDistribution) Validate() error {
+	if d == nil {
+		return ErrFeeDistributionMissingSplit
+	}
+	if d.Total == 0 {
+		return ErrFeeDistributionZeroTotal
+	}
+	sum, overflow := math.SafeAdd(d.Burn, d.Miner)
+	seen := make(map[common.Address]struct{}, len(d.Shares))
+	for _, share := range d.Shares {
+		if share.Recipient == (common.Address{}) {
+			return ErrFeeDistributionZeroRecipient
+		}
+		if _, ok := seen[share.Recipient]; ok {
+			return fmt.Errorf("%w: %v", ErrFeeDistributionDupRecipient, share.Recipient)
+		}
+		seen[share.Recipient] = struct{}{}
+
+		var o bool
+		sum, o = math.SafeAdd(sum, share.Ratio)
+		overflow = overflow || o
+	}
+	if overflow {
+		return ErrFeeDistributionRatioOverflow
+	}
+	if sum != d.Total {
+		return fmt.Errorf("%w: have %d, want %d", ErrFeeDistributionRatioSum, sum, d.Total)
+	}
+	if d.ContractFeeShareFund != nil {
+		if _, ok := seen[*d.ContractFeeShareFund]; !ok {
+			return fmt.Errorf("%w: %v", ErrFeeDistributionUnknownFund, d.ContractFeeShareFund)
+		}
+	}
+	return nil
+}
+
+// FeeDistributionSchedule maps fork blocks to the fee distribution in effect from that block on.
+type FeeDistributionSchedule map[uint64]*FeeDistribution
+
+// ForBlock returns the fee distribution in effect at block n, or nil if none is scheduled.
+func (s FeeDistributionSchedule) ForBlock(n uint64) *FeeDistribution {
+	var (
+		found bool
+		at    uint64
+	)
+	for k := range s {
+		if k <= n && (!found || k > at) {
+			found, at = true, k
+		}
+	}
+	if !found {
+		return nil
+	}
+	return s[at]
+}
+
+// Forks returns the sorted fork blocks of the schedule.
+func (s FeeDistributionSchedule) Forks() []uint64 {
+	forks := make([]uint64, 0, len(s))
+	for k := range s {
+		forks = append(forks, k)
+	}
+	sort.Slice(forks, func(i, j int) bool {
+		return forks[i] < forks[j]
+	})
+	return forks
+}
+
+// Validate checks every fee distribution of the schedule.
+func (s FeeDistributionSchedule) Validate() error {
+	for _, k := range s.Forks() {
+		if err := s[k].Validate(); err != nil {
+			return fmt.Errorf("fee distribution at block %d: %w", k, err)
+		}
+	}
+	return nil
+}
+
+// UnmarshalJSON implements the json Unmarshaler interface.
+// Fork blocks may be given as hex or decimal strings.
+func (s *FeeDistributionSchedule) UnmarshalJSON(input []byte) error {
+	m := make(map[math.HexOrDecimal64]*FeeDistribution)
+	if err := json.Unmarshal(input, &m); err != nil {
+		return err
+	}
+	schedule := make(FeeDistributionSchedule, len(m))
+	for k, v := range m {
+		schedule[uint64(k)] = v
+	}
+	*s = schedule
+	return nil
+}
+
+// MarshalJSON implements the json Marshaler interface.
+func (s FeeDistributionSchedule) MarshalJSON() ([]byte, error) {
+	m := make(map[math.HexOrDecimal64]*FeeDistribution, len(s))
+	for k, v := range s {
+		m[math.HexOrDecimal64(k)] = v
+	}
+	return json.Marshal(m)
+}
@@ -0,0 +1,118 @@
+// Copyright 2025 The core-geth Authors
+// This file is part of the core-geth library.
+//
+// The core-geth library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The core-geth library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the core-geth library. If not, see <http://www.gnu.org/licenses/>.
+
+package ctypes
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var (
+	feeRecipientA = common.HexToAddress("0xaaaa")
+	feeRecipientB = common.HexToAddress("0xbbbb")
+)
+
+func TestFeeDistribution_Validate(t *testing.T) {
+	cases := []struct {
+		split *FeeDistribution
+		err   error
+	}{
+		{&FeeDistribution{Total: 1000, Burn: 400, Miner: 300, Shares: []FeeDistributionShare{{feeRecipientA, 200}, {feeRecipientB, 100}}}, nil},
+		{&FeeDistribution{Total: 100, Burn: 100}, nil},
+		{&FeeDistribution{Total: 10, Miner: 5, Shares: []FeeDistributionShare{{feeRecipientA, 5}}}, nil},
+		{nil, ErrFeeDistributionMissingSplit},
+		{&FeeDistribution{}, ErrFeeDistributionZeroTotal},
+		{&FeeDistribution{Total: 1000, Burn: 400, Miner: 300, Shares: []FeeDistributionShare{{feeRecipientA, 200}}}, ErrFeeDistributionRatioSum},
+		{&FeeDistribution{Total: 100, Burn: 50, Miner: 60}, ErrFeeDistributionRatioSum},
+		{&FeeDistribution{Total: 100, Burn: 50, Shares: []FeeDistributionShare{{common.Address{}, 50}}}, ErrFeeDistributionZeroRecipient},
+		{&FeeDistribution{Total: 100, Burn: 50, Shares: []FeeDistributionShare{{feeRecipientA, 25}, {feeRecipientA, 25}}}, ErrFeeDistributionDupRecipient},
+		{&FeeDistribution{Total: 1, Burn: math.MaxUint64, Shares: []FeeDistributionShare{{feeRecipientA, 2}}}, ErrFeeDistributionRatioOverflow},
+		{&FeeDistribution{Total: 100, Burn: 50, Shares: []FeeDistributionShare{{feeRecipientA, 50}}, ContractFeeShareFund: &feeRecipientA}, nil},
+		{&FeeDistribution{Total: 100, Burn: 50, Shares: []FeeDistributionShare{{feeRecipientA, 50}}, ContractFeeShareFund: &feeRecipientB}, ErrFeeDistributionUnknownFund},
+	}
+	for i, c := range cases {
+		if err := c.split.Validate(); !errors.Is(err, c.err) {
+			t.Errorf("case %d: have error %v, want %v", i, err, c.err)
+		}
+	}
+}
+
+func TestFeeDistributionSchedule_ForBlock(t *testing.T) {
+	first := &FeeDistribution{Total: 100, Burn: 100}
+	second := &FeeDistribution{Total: 100, Miner: 100}
+	schedule := FeeDistributionSchedule{10: first, 20: second}
+
+	for _, c := range []struct {
+		number uint64
+		want   *FeeDistribution
+	}{
+		{0, nil},
+		{9, nil},
+		{10, first},
+		{19, first},
+		{20, second},
+		{math.MaxUint64, second},
+	} {
+		if have := schedule.ForBlock(c.number); have != c.want {
+			t.Errorf("block %d: have %v, want %v", c.number, have, c.want)
+		}
+	}
+	if have := FeeDistributionSchedule(nil).ForBlock(10); have != nil {
+		t.Errorf("nil schedule: have %v, want nil", have)
+	}
+	if have, want := schedule.Forks(), []uint64{10, 20}; !reflect.DeepEqual(have, want) {
+		t.Errorf("forks: have %v, want %v", have, want)
+	}
+}
+
+func TestFeeDistributionSchedule_JSON(t *testing.T) {
+	input := []byte(`{
+		"0x0": {"total": 1000, "burn": 400, "miner": 300, "shares": [{"recipient": "0x000000000000000000000000000000000000aaaa", "ratio": 300}]},
+		"1000": {"total": 1000, "burn": 1000}
+	}`)
+	var schedule FeeDistributionSchedule
+	if err := json.Unmarshal(input, &schedule); err != nil {
+		t.Fatal(err)
+	}
+	want := FeeDistributionSchedule{
+		0:    {Total: 1000, Burn: 400, Miner: 300, Shares: []FeeDistributionShare{{feeRecipientA, 300}}},
+		1000: {Total: 1000, Burn: 1000},
+	}
+	if !reflect.DeepEqual(schedule, want) {
+		t.Fatalf("unmarshal mismatch: have %v, want %v", schedule, want)
+	}
+	if err := schedule.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := json.Marshal(schedule)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded FeeDistributionSchedule
+	if err := json.Unmarshal(b, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded, want) {
+		t.Fatalf("round trip mismatch: have %v, want %v (json: %s)", decoded, want, b)
+	}
+}
@@ -21,6 +21,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math/big"
 	"sort"
 	"strconv"
 
@@ -382,7 +383,13 @@ func (c *CliqueConfig) String() string {
 }
 
 // Lyra2Config is the consensus engine configs for MINTME network.
-type Lyra2Config struct{}
+// Unset parameters take the values of the MINTME network.
+type Lyra2Config struct {
+	TCost                  uint64   `json:"tcost,omitempty"`                  // Time cost of the Lyra2 hash
+	MinimumDifficulty      *big.Int `json:"minimumDifficulty,omitempty"`      // The minimum that the difficulty may ever be
+	DifficultyBoundDivisor *big.Int `json:"difficultyBoundDivisor,omitempty"` // The bound divisor of the difficulty, used in the update calculations
+	DurationLimit          *big.Int `json:"durationLimit,omitempty"`          // Block time (seconds) above which the difficulty decreases
+}
 
 // String implements the stringer interface, returning the consensus engine details.
 func (c *Lyra2Config) String() string {
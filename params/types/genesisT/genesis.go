@@ -1153,6 +1153,118 @@ func (g *Genesis) SetLyra2NonceTransition(n *uint64) error {
 	return g.Config.SetLyra2NonceTransition(n)
 }
 
+func (g *Genesis) GetLyra2TCost() uint64 {
+	return g.Config.GetLyra2TCost()
+}
+
+func (g *Genesis) SetLyra2TCost(n uint64) error {
+	return g.Config.SetLyra2TCost(n)
+}
+
+func (g *Genesis) GetLyra2MinimumDifficulty() *big.Int {
+	return g.Config.GetLyra2MinimumDifficulty()
+}
+
+func (g *Genesis) SetLyra2MinimumDifficulty(i *big.Int) error {
+	return g.Config.SetLyra2MinimumDifficulty(i)
+}
+
+func (g *Genesis) GetLyra2DifficultyBoundDivisor() *big.Int {
+	return g.Config.GetLyra2DifficultyBoundDivisor()
+}
+
+func (g *Genesis) SetLyra2DifficultyBoundDivisor(i *big.Int) error {
+	return g.Config.SetLyra2DifficultyBoundDivisor(i)
+}
+
+func (g *Genesis) GetLyra2DurationLimit() *big.Int {
+	return g.Config.GetLyra2DurationLimit()
+}
+
+func (g *Genesis) SetLyra2DurationLimit(i *big.Int) error {
+	return g.Config.SetLyra2DurationLimit(i)
+}
+
+func (g *Genesis) GetHaloUnclesTransition() *uint64 {
+	return g.Config.GetHaloUnclesTransition()
+}
+
+func (g *Genesis) SetHaloUnclesTransition(n *uint64) error {
+	return g.Config.SetHaloUnclesTransition(n)
+}
+
+func (g *Genesis) GetHaloFutureBlockTimeTransition() *uint64 {
+	return g.Config.GetHaloFutureBlockTimeTransition()
+}
+
+func (g *Genesis) SetHaloFutureBlockTimeTransition(n *uint64) error {
+	return g.Config.SetHaloFutureBlockTimeTransition(n)
+}
+
+func (g *Genesis) GetHaloMedianTimePastTransition() *uint64 {
+	return g.Config.GetHaloMedianTimePastTransition()
+}
+
+func (g *Genesis) SetHaloMedianTimePastTransition(n *uint64) error {
+	return g.Config.SetHaloMedianTimePastTransition(n)
+}
+
+func (g *Genesis) GetHaloDifficultyTransition() *uint64 {
+	return g.Config.GetHaloDifficultyTransition()
+}
+
+func (g *Genesis) SetHaloDifficultyTransition(n *uint64) error {
+	return g.Config.SetHaloDifficultyTransition(n)
+}
+
+func (g *Genesis) GetHaloDifficultyV2Transition() *uint64 {
+	return g.Config.GetHaloDifficultyV2Transition()
+}
+
+func (g *Genesis) SetHaloDifficultyV2Transition(n *uint64) error {
+	return g.Config.SetHaloDifficultyV2Transition(n)
+}
+
+func (g *Genesis) GetHaloRewardsTransition() *uint64 {
+	return g.Config.GetHaloRewardsTransition()
+}
+
+func (g *Genesis) SetHaloRewardsTransition(n *uint64) error {
+	return g.Config.SetHaloRewardsTransition(n)
+}
+
+func (g *Genesis) GetHaloFeeDistributionTransition() *uint64 {
+	return g.Config.GetHaloFeeDistributionTransition()
+}
+
+func (g *Genesis) SetHaloFeeDistributionTransition(n *uint64) error {
+	return g.Config.SetHaloFeeDistributionTransition(n)
+}
+
+func (g *Genesis) GetHaloContractFeeSharingTransition() *uint64 {
+	return g.Config.GetHaloContractFeeSharingTransition()
+}
+
+func (g *Genesis) SetHaloContractFeeSharingTransition(n *uint64) error {
+	return g.Config.SetHaloContractFeeSharingTransition(n)
+}
+
+func (g *Genesis) GetHaloSponsoredTxTransition() *uint64 {
+	return g.Config.GetHaloSponsoredTxTransition()
+}
+
+func (g *Genesis) SetHaloSponsoredTxTransition(n *uint64) error {
+	return g.Config.SetHaloSponsoredTxTransition(n)
+}
+
+func (g *Genesis) GetHaloFeeDistributionSchedule() ctypes.FeeDistributionSchedule {
+	return g.Config.GetHaloFeeDistributionSchedule()
+}
+
+func (g *Genesis) SetHaloFeeDistributionSchedule(s ctypes.FeeDistributionSchedule) error {
+	return g.Config.SetHaloFeeDistributionSchedule(s)
+}
+
 func (g *Genesis) String() string {
 	j, _ := json.MarshalIndent(g, "", "    ")
 	return "Genesis: " + string(j)
@@ -1243,3 +1243,173 @@ func (c *ChainConfig) SetLyra2NonceTransition(n *uint64) error {
 
 	return nil
 }
+
+func (c *ChainConfig) GetLyra2TCost() uint64 {
+	if c.Lyra2 == nil {
+		return 0
+	}
+	return c.Lyra2.TCost
+}
+
+func (c *ChainConfig) SetLyra2TCost(n uint64) error {
+	if c.Lyra2 == nil {
+		return ctypes.ErrUnsupportedConfigFatal
+	}
+	c.Lyra2.TCost = n
+	return nil
+}
+
+func (c *ChainConfig) GetLyra2MinimumDifficulty() *big.Int {
+	if c.Lyra2 == nil {
+		return nil
+	}
+	return c.Lyra2.MinimumDifficulty
+}
+
+func (c *ChainConfig) SetLyra2MinimumDifficulty(i *big.Int) error {
+	if c.Lyra2 == nil {
+		return ctypes.ErrUnsupportedConfigFatal
+	}
+	c.Lyra2.MinimumDifficulty = i
+	return nil
+}
+
+func (c *ChainConfig) GetLyra2DifficultyBoundDivisor() *big.Int {
+	if c.Lyra2 == nil {
+		return nil
+	}
+	return c.Lyra2.DifficultyBoundDivisor
+}
+
+func (c *ChainConfig) SetLyra2DifficultyBoundDivisor(i *big.Int) error {
+	if c.Lyra2 == nil {
+		return ctypes.ErrUnsupportedConfigFatal
+	}
+	c.Lyra2.DifficultyBoundDivisor = i
+	return nil
+}
+
+func (c *ChainConfig) GetLyra2DurationLimit() *big.Int {
+	if c.Lyra2 == nil {
+		return nil
+	}
+	return c.Lyra2.DurationLimit
+}
+
+func (c *ChainConfig) SetLyra2DurationLimit(i *big.Int) error {
+	if c.Lyra2 == nil {
+		return ctypes.ErrUnsupportedConfigFatal
+	}
+	c.Lyra2.DurationLimit = i
+	return nil
+}
+
+func (c *ChainConfig) GetHaloUnclesTransition() *uint64 {
+	return nil
+}
+
+func (c *ChainConfig) SetHaloUnclesTransition(n *uint64) error {
+	if n == nil {
+		return nil
+	}
+	return ctypes.ErrUnsupportedConfigFatal
+}
+
+func (c *ChainConfig) GetHaloFutureBlockTimeTransition() *uint64 {
+	return nil
+}
+
+func (c *ChainConfig) SetHaloFutureBlockTimeTransition(n *uint64) error {
+	if n == nil {
+		return nil
+	}
+	return ctypes.ErrUnsupportedConfigFatal
+}
+
+func (c *ChainConfig) GetHaloMedianTimePastTransition() *uint64 {
+	return nil
+}
+
+func (c *ChainConfig) SetHaloMedianTimePastTransition(n *uint64) error {
+	if n == nil {
+		return nil
+	}
+	return ctypes.ErrUnsupportedConfigFatal
+}
+
+func (c *ChainConfig) GetHaloDifficultyTransition() *uint64 {
+	return nil
+}
+
+func (c *ChainConfig) SetHaloDifficultyTransition(n *uint64) error {
+	if n == nil {
+		return nil
+	}
+	return ctypes.ErrUnsupportedConfigFatal
+}
+
+func (c *ChainConfig) GetHaloDifficultyV2Transition() *uint64 {
+	return nil
+}
+
+func (c *ChainConfig) SetHaloDifficultyV2Transition(n *uint64) error {
+	if n == nil {
+		return nil
+	}
+	return ctypes.ErrUnsupportedConfigFatal
+}
+
+func (c *ChainConfig) GetHaloRewardsTransition() *uint64 {
+	return nil
+}
+
+func (c *ChainConfig) SetHaloRewardsTransition(n *uint64) error {
+	if n == nil {
+		return nil
+	}
+	return ctypes.ErrUnsupportedConfigFatal
+}
+
+func (c *ChainConfig) GetHaloFeeDistributionTransition() *uint64 {
+	return nil
+}
+
+func (c *ChainConfig) SetHaloFeeDistributionTransition(n *uint64) error {
+	if n == nil {
+		return nil
+	}
+	return ctypes.ErrUnsupportedConfigFatal
+}
+
+func (c *ChainConfig) GetHaloContractFeeSharingTransition() *uint64 {
+	return nil
+}
+
+func (c *ChainConfig) SetHaloContractFeeSharingTransition(n *uint64) error {
+	if n == nil {
+		return nil
+	}
+	return ctypes.ErrUnsupportedConfigFatal
+}
+
+func (c *ChainConfig) GetHaloSponsoredTxTransition() *uint64 {
+	return nil
+}
+
+func (c *ChainConfig) SetHaloSponsoredTxTransition(n *uint64) error {
+	if n == nil {
+		return nil
+	}
+	return ctypes.ErrUnsupportedConfigFatal
+}
+
+func (c *ChainConfig) GetHaloFeeDistributionSchedule() ctypes.FeeDistributionSchedule {
+	return nil
+}
+
+func (c *ChainConfig) SetHaloFeeDistributionSchedule(s ctypes.FeeDistributionSchedule) error {
+	if len(s) == 0 {
+		return nil
+	}
+	return ctypes.ErrUnsupportedConfigFatal
+}
@@ -42,9 +42,7 @@ var (
 	HaloBaseFeeChangeDenominator    = uint64(8)          // Base fee change denominator
 	HaloElasticityMultiplier        = uint64(2)          // Elasticity multiplier
 
-	// Cache and database settings
-	HaloStateCacheSize      = 1000000              // 1M state cache entries
-	HaloCodeCacheSize       = 100000               // 100K code cache entries
+	// Trie and database cache settings
 	HaloTrieCleanCacheSize  = 512                  // 512 MB trie clean cache
 	HaloTrieDirtyCacheSize  = 256                  // 256 MB trie dirty cache
 	HaloDatabaseCache       = 2048                 // 2 GB database cache
@@ -56,7 +54,6 @@ var (
 	HaloAccountQueue    = uint64(64)               // 64 queue slots per account
 
 	// Network settings
-	HaloMaxMessageSize  = 10 * 1024 * 1024         // 10 MB max message size
 	HaloMaxPeers        = 50                       // 50 max peers
 )
 
@@ -36,6 +36,14 @@ const (
 	// considered probably final and its rotated bits are calculated.
 	BloomConfirms = 256
 
+	// LogIndexBlocks is the number of blocks a single section of the log index
+	// contains.
+	LogIndexBlocks uint64 = 4096
+
+	// LogIndexConfirms is the number of confirmation blocks before a section of
+	// the log index is considered probably final and indexed.
+	LogIndexConfirms = 256
+
 	// CHTFrequency is the block frequency for creating CHTs
 	CHTFrequency = 32768
 
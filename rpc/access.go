@@ -0,0 +1,341 @@
+// Copyright 2025 The core-geth Authors
+// This file is part of the core-geth library.
+//
+// The core-geth library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The core-geth library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the core-geth library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/lru"
+	"github.com/gorilla/websocket"
+	"golang.org/x/time/rate"
+)
+
+// APIKeyHeader is the HTTP header carrying the API key of a client.
+const APIKeyHeader = "X-Api-Key"
+
+// maxIPLimiters is the number of client IPs whose rate limiter is retained.
+const maxIPLimiters = 16384
+
+var (
+	errMissingAPIKey = errors.New("missing API key")
+	errInvalidAPIKey = errors.New("invalid API key")
+)
+
+// APIKeyConfig configures the access of the clients identified by an API key.
+type APIKeyConfig struct {
+	// Key is the API key, given by the clients in the X-Api-Key header, or as
+	// the last segment of the endpoint URL.
+	Key string
+
+	// Namespaces are the API namespaces the key gives access to, all the
+	// namespaces of the endpoint if empty.
+	Namespaces []string `toml:",omitempty"`
+
+	// Rate is the number of request cost units per second allowed to the key,
+	// unlimited if zero.
+	Rate float64 `toml:",omitempty"`
+
+	// Burst is the number of request cost units the key can spend at once,
+	// defaulting to the rate.
+	Burst int `toml:",omitempty"`
+}
+
+// AccessConfig configures the authentication and the rate limiting of the
+// clients of a server.
+//
+// The requests made with an API key are limited by the rate of the key, and the
+// other requests by the rate of the client IP. The client IP is the remote
+// address of the connection, the forwarding headers being ignored as they can
+// be forged: behind a reverse proxy, all the clients without API key share the
+// rate of the proxy, so the proxy must limit the rate of its clients itself,
+// or the clients be given API keys.
+type AccessConfig struct {
+	// APIKeys are the API keys accepted by the server.
+	APIKeys []APIKeyConfig `toml:",omitempty"`
+
+	// KeyRequired rejects the requests without API key.
+	KeyRequired bool `toml:",omitempty"`
+
+	// IPRate is the number of request cost units per second allowed to each
+	// client IP without API key, unlimited if zero.
+	IPRate float64 `toml:",omitempty"`
+
+	// IPBurst is the number of request cost units a client IP can spend at
+	// once, defaulting to the rate.
+	IPBurst int `toml:",omitempty"`
+
+	// MethodCosts are the costs of the methods, given by name or by namespace
+	// as "namespace_*". The other methods cost one unit.
+	MethodCosts map[string]int `toml:",omitempty"`
+}
+
+// apiKey is the access of the clients identified by an API key.
+type apiKey struct {
+	namespaces map[string]bool // Allowed namespaces, all if nil
+	limiter    *rate.Limiter   // Rate limiter of the key, nil if unlimited
+}
+
+// accessControl authenticates the clients of a server and limits the rate of
+// their requests with token buckets.
+type accessControl struct {
+	keys        map[string]*apiKey
+	keyRequired bool
+	costs       map[string]int
+	maxCost     int
+
+	ipRate  rate.Limit
+	ipBurst int
+	ipLock  sync.Mutex
+	ips     lru.BasicLRU[string, *rate.Limiter]
+}
+
+// newAccessControl validates the access configuration and creates the access
+// control enforcing it.
+func newAccessControl(config *AccessConfig) (*accessControl, error) {
+	ac := &accessControl{
+		keys:        make(map[string]*apiKey),
+		keyRequired: config.KeyRequired,
+		costs:       make(map[string]int),
+		maxCost:     1,
+		ips:         lru.NewBasicLRU[string, *rate.Limiter](maxIPLimiters),
+	}
+	for method, cost := range config.MethodCosts {
+		if cost < 0 {
+			return nil, fmt.Errorf("negative cost %d for method %q", cost, method)
+		}
+		ac.costs[method] = cost
+		if cost > ac.maxCost {
+			ac.maxCost = cost
+		}
+	}
+	for _, key := range config.APIKeys {
+		if key.Key == "" || strings.Contains(key.Key, "/") {
+			return nil, fmt.Errorf("invalid API key %q", key.Key)
+		}
+		if ac.keys[key.Key] != nil {
+			return nil, fmt.Errorf("duplicate API key %q", key.Key)
+		}
+		if key.Rate < 0 || key.Burst < 0 {
+			return nil, fmt.Errorf("negative rate limit for API key %q", key.Key)
+		}
+		k := new(apiKey)
+		if len(key.Namespaces) > 0 {
+			k.namespaces = make(map[string]bool)
+			for _, ns := range key.Namespaces {
+				k.namespaces[ns] = true
+			}
+		}
+		if key.Rate > 0 {
+			k.limiter = rate.NewLimiter(rate.Limit(key.Rate), ac.burst(key.Rate, key.Burst))
+		}
+		ac.keys[key.Key] = k
+	}
+	if config.KeyRequired && len(ac.keys) == 0 {
+		return nil, errors.New("API key required, but no API key configured")
+	}
+	if config.IPRate < 0 || config.IPBurst < 0 {
+		return nil, errors.New("negative IP rate limit")
+	}
+	if config.IPRate > 0 {
+		ac.ipRate = rate.Limit(config.IPRate)
+		ac.ipBurst = ac.burst(config.IPRate, config.IPBurst)
+	}
+	return ac, nil
+}
+
+// burst returns the burst of a token bucket, defaulting to the rate, and large
+// enough for the most costly method.
+func (ac *accessControl) burst(r float64, burst int) int {
+	if burst == 0 {
+		burst = int(math.Ceil(r))
+	}
+	if burst < ac.maxCost {
+		burst = ac.maxCost
+	}
+	return burst
+}
+
+// authenticate checks the API key of a client connecting with the given HTTP
+// request, returning the key.
+func (ac *accessControl) authenticate(r *http.Request) (string, error) {
+	key := r.Header.Get(APIKeyHeader)
+	if key == "" {
+		if ac.keyRequired {
+			return "", errMissingAPIKey
+		}
+		return "", nil
+	}
+	if ac.keys[key] == nil {
+		return "", errInvalidAPIKey
+	}
+	return key, nil
+}
+
+// allow checks whether the client can call the method, charging its cost to the
+// rate limiter of the client.
+func (ac *accessControl) allow(info PeerInfo, method string) error {
+	var limiter *rate.Limiter
+	if key := ac.keys[info.apiKey]; key != nil {
+		if ns, _, _ := elementizeMethodName(method); key.namespaces != nil && !key.namespaces[ns] && ns != MetadataApi {
+			return &methodNotAllowedError{method: method}
+		}
+		limiter = key.limiter
+	} else if ac.ipRate > 0 {
+		limiter = ac.ipLimiter(info.RemoteAddr)
+	}
+	if limiter == nil {
+		return nil
+	}
+	cost := ac.cost(method)
+	if cost == 0 {
+		return nil
+	}
+	now := time.Now()
+	res := limiter.ReserveN(now, cost)
+	if !res.OK() {
+		return &limitExceededError{}
+	}
+	if delay := res.DelayFrom(now); delay > 0 {
+		res.CancelAt(now)
+		return &limitExceededError{retryAfter: delay}
+	}
+	return nil
+}
+
+// cost returns the cost of the method.
+func (ac *accessControl) cost(method string) int {
+	if cost, ok := ac.costs[method]; ok {
+		return cost
+	}
+	if ns, _, err := elementizeMethodName(method); err == nil {
+		if cost, ok := ac.costs[ns+"_*"]; ok {
+			return cost
+		}
+	}
+	return 1
+}
+
+// ipLimiter returns the rate limiter of the client IP of the given address.
+func (ac *accessControl) ipLimiter(addr string) *rate.Limiter {
+	ip := addr
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		ip = host
+	}
+	ac.ipLock.Lock()
+	defer ac.ipLock.Unlock()
+
+	limiter, ok := ac.ips.Get(ip)
+	if !ok {
+		limiter = rate.NewLimiter(ac.ipRate, ac.ipBurst)
+		ac.ips.Add(ip, limiter)
+	}
+	return limiter
+}
+
+// accessContextKey is the context key of the access control of the requests
+// served by an access handler.
+type accessContextKey struct{}
+
+// NewAccessHandler returns a handler enforcing the access configuration on the
+// requests of a protocol served alongside JSON-RPC, like GraphQL, before passing
+// them on to the given handler. Every request, WebSocket upgrades included, is
+// charged as a call of the given method, and the handler can charge its clients
+// for further calls with CheckAccess.
+func NewAccessHandler(config *AccessConfig, method string, next http.Handler) (http.Handler, error) {
+	ac, err := newAccessControl(config)
+	if err != nil {
+		return nil, err
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key, err := ac.authenticate(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		info := PeerInfo{Transport: "http", RemoteAddr: r.RemoteAddr}
+		if websocket.IsWebSocketUpgrade(r) {
+			info.Transport = "ws"
+		} else {
+			info.HTTP.Version = r.Proto
+		}
+		info.HTTP.Host = r.Host
+		info.HTTP.Origin = r.Header.Get("Origin")
+		info.HTTP.UserAgent = r.Header.Get("User-Agent")
+		info.apiKey = key
+
+		ctx := context.WithValue(r.Context(), peerInfoContextKey{}, info)
+		ctx = context.WithValue(ctx, accessContextKey{}, ac)
+		if err := CheckAccess(ctx, method); err != nil {
+			var limited *limitExceededError
+			if errors.As(err, &limited) {
+				w.Header().Set("retry-after", strconv.Itoa(int(math.Ceil(limited.retryAfter.Seconds()))))
+				http.Error(w, err.Error(), http.StatusTooManyRequests)
+				return
+			}
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(ctx))
+	}), nil
+}
+
+// CheckAccess charges the call of the method to the client of a request served
+// by an access handler, returning an error if the API key of the client doesn't
+// give access to the namespace of the method, or if the client exceeds its rate
+// limit. Nothing is checked outside of an access handler.
+func CheckAccess(ctx context.Context, method string) error {
+	ac, ok := ctx.Value(accessContextKey{}).(*accessControl)
+	if !ok {
+		return nil
+	}
+	return ac.allow(PeerInfoFromContext(ctx), method)
+}
+
+// limitExceeded reports whether the response, single or batch, is made of rate
+// limit errors only, returning the number of seconds after which the requests
+// can be retried.
+func limitExceeded(v any) (int, bool) {
+	var msgs []*jsonrpcMessage
+	switch v := v.(type) {
+	case *jsonrpcMessage:
+		msgs = []*jsonrpcMessage{v}
+	case []*jsonrpcMessage:
+		msgs = v
+	}
+	if len(msgs) == 0 {
+		return 0, false
+	}
+	var retryAfter int
+	for _, msg := range msgs {
+		if msg.Error == nil || msg.Error.Code != errcodeLimitExceeded {
+			return 0, false
+		}
+		if data, ok := msg.Error.Data.(*limitExceededData); ok && data.RetryAfter > retryAfter {
+			retryAfter = data.RetryAfter
+		}
+	}
+	return retryAfter, true
+}
@@ -41,6 +41,7 @@ type clientConfig struct {
 	idgen              func() ID
 	batchItemLimit     int
 	batchResponseLimit int
+	access             *accessControl
 }
 
 func (cfg *clientConfig) initHeaders() {
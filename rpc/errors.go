@@ -16,7 +16,11 @@
 
 package rpc
 
-import "fmt"
+import (
+	"fmt"
+	"math"
+	"time"
+)
 
 // HTTPError is returned by client operations when the HTTP status code of the
 // response is not a 2xx status.
@@ -55,12 +59,15 @@ var (
 	_ Error = new(invalidMessageError)
 	_ Error = new(invalidParamsError)
 	_ Error = new(internalServerError)
+	_ Error = new(methodNotAllowedError)
+	_ Error = new(limitExceededError)
 )
 
 const (
 	errcodeDefault          = -32000
 	errcodeTimeout          = -32002
 	errcodeResponseTooLarge = -32003
+	errcodeLimitExceeded    = -32005
 	errcodePanic            = -32603
 	errcodeMarshalError     = -32603
 
@@ -71,6 +78,7 @@ const (
 	errMsgTimeout          = "request timed out"
 	errMsgResponseTooLarge = "response too large"
 	errMsgBatchTooLarge    = "batch too large"
+	errMsgLimitExceeded    = "rate limit exceeded"
 )
 
 type methodNotFoundError struct{ method string }
@@ -154,3 +162,30 @@ type internalServerError struct {
 func (e *internalServerError) ErrorCode() int { return e.code }
 
 func (e *internalServerError) Error() string { return e.message }
+
+// methodNotAllowedError is returned when the API key of the client doesn't give
+// access to the namespace of the method.
+type methodNotAllowedError struct{ method string }
+
+func (e *methodNotAllowedError) ErrorCode() int { return -32601 }
+
+func (e *methodNotAllowedError) Error() string {
+	return fmt.Sprintf("the method %s is not allowed for the API key", e.method)
+}
+
+// limitExceededError is returned when the client exceeds its request rate limit.
+type limitExceededError struct{ retryAfter time.Duration }
+
+// limitExceededData is the data of the rate limit error, giving the number of
+// seconds after which the request can be retried.
+type limitExceededData struct {
+	RetryAfter int `json:"retryAfter"`
+}
+
+func (e *limitExceededError) ErrorCode() int { return errcodeLimitExceeded }
+
+func (e *limitExceededError) Error() string { return errMsgLimitExceeded }
+
+func (e *limitExceededError) ErrorData() interface{} {
+	return &limitExceededData{RetryAfter: int(math.Ceil(e.retryAfter.Seconds()))}
+}
@@ -25,7 +25,9 @@ import (
 	"sync"
 	"time"
 
+	"github.com/ethereum/go-ethereum/internal/telemetry"
 	"github.com/ethereum/go-ethereum/log"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // handler handles JSON-RPC messages. There is one handler per connection. Note that
@@ -62,6 +64,7 @@ type handler struct {
 	allowSubscribe       bool
 	batchRequestLimit    int
 	batchResponseMaxSize int
+	access               *accessControl // authentication and rate limiting, nil if disabled
 
 	subLock    sync.Mutex
 	serverSubs map[ID]*Subscription
@@ -491,6 +494,12 @@ func (h *handler) handleCallMsg(ctx *callProc, msg *jsonrpcMessage) *jsonrpcMess
 
 // handleCall processes method calls.
 func (h *handler) handleCall(cp *callProc, msg *jsonrpcMessage) *jsonrpcMessage {
+	if h.access != nil && !msg.isUnsubscribe() {
+		if err := h.access.allow(PeerInfoFromContext(cp.ctx), msg.Method); err != nil {
+			rpcLimitedRequestMeter.Mark(1)
+			return msg.errorResponse(err)
+		}
+	}
 	if msg.isSubscribe() {
 		return h.handleSubscribe(cp, msg)
 	}
@@ -509,7 +518,7 @@ func (h *handler) handleCall(cp *callProc, msg *jsonrpcMessage) *jsonrpcMessage
 		return msg.errorResponse(&invalidParamsError{err.Error()})
 	}
 	start := time.Now()
-	answer := h.runMethod(cp.ctx, msg, callb, args)
+	answer := h.traceMethod(cp.ctx, msg, callb, args)
 
 	// Collect the statistics for RPC calls if metrics is enabled.
 	// We only care about pure rpc call. Filter out subscription.
@@ -569,6 +578,28 @@ func (h *handler) runMethod(ctx context.Context, msg *jsonrpcMessage, callb *cal
 	return msg.response(result)
 }
 
+// traceMethod runs the method within a telemetry span, unless it's an unsubscribe.
+func (h *handler) traceMethod(ctx context.Context, msg *jsonrpcMessage, callb *callback, args []reflect.Value) *jsonrpcMessage {
+	if callb == h.unsubscribeCb {
+		return h.runMethod(ctx, msg, callb, args)
+	}
+	service, method, _ := elementizeMethodName(msg.Method)
+	ctx, span := telemetry.Tracer().Start(ctx, msg.Method, trace.WithSpanKind(trace.SpanKindServer), trace.WithAttributes(
+		telemetry.RPCSystem,
+		telemetry.RPCService(service),
+		telemetry.RPCMethod(method),
+		telemetry.RPCTransport(PeerInfoFromContext(ctx).Transport),
+	))
+	defer span.End()
+
+	answer := h.runMethod(ctx, msg, callb, args)
+	if answer.Error != nil {
+		span.SetAttributes(telemetry.RPCErrorCode(answer.Error.Code))
+		telemetry.RecordError(span, answer.Error)
+	}
+	return answer
+}
+
 // unsubscribe is the callback function for all *_unsubscribe calls.
 func (h *handler) unsubscribe(ctx context.Context, id ID) (bool, error) {
 	h.subLock.Lock()
@@ -258,7 +258,16 @@ func (s *Server) newHTTPServerConn(r *http.Request, w http.ResponseWriter) Serve
 	conn := &httpServerConn{Reader: body, Writer: w, r: r}
 
 	encoder := func(v any, isErrorResponse bool) error {
+		// Responses made of rate limit errors only are sent with the 429 status
+		// code, which must be written after the other headers.
+		retryAfter, limited := limitExceeded(v)
+		if limited {
+			w.Header().Set("retry-after", strconv.Itoa(retryAfter))
+		}
 		if !isErrorResponse {
+			if limited {
+				w.WriteHeader(http.StatusTooManyRequests)
+			}
 			return json.NewEncoder(conn).Encode(v)
 		}
 
@@ -281,6 +290,9 @@ func (s *Server) newHTTPServerConn(r *http.Request, w http.ResponseWriter) Serve
 		// the final chunk is missing.
 		w.Header().Set("transfer-encoding", "identity")
 
+		if limited {
+			w.WriteHeader(http.StatusTooManyRequests)
+		}
 		_, err = w.Write(encdata)
 		if f, ok := w.(http.Flusher); ok {
 			f.Flush()
@@ -316,6 +328,15 @@ func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, err.Error(), code)
 		return
 	}
+	var apiKey string
+	if s.access != nil {
+		key, err := s.access.authenticate(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		apiKey = key
+	}
 
 	// Create request-scoped context.
 	connInfo := PeerInfo{Transport: "http", RemoteAddr: r.RemoteAddr}
@@ -323,6 +344,7 @@ func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	connInfo.HTTP.Host = r.Host
 	connInfo.HTTP.Origin = r.Header.Get("Origin")
 	connInfo.HTTP.UserAgent = r.Header.Get("User-Agent")
+	connInfo.apiKey = apiKey
 	ctx := r.Context()
 	ctx = context.WithValue(ctx, peerInfoContextKey{}, connInfo)
 
@@ -18,6 +18,8 @@ package rpc
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -243,3 +245,214 @@ func TestNewContextWithHeaders(t *testing.T) {
 		t.Error("call failed:", err)
 	}
 }
+
+// newAccessTestServer starts an HTTP server with the given access configuration.
+func newAccessTestServer(t *testing.T, config *AccessConfig) string {
+	s := newTestServer()
+	if err := s.SetAccessControl(config); err != nil {
+		t.Fatalf("failed to set access control: %v", err)
+	}
+	ts := httptest.NewServer(s)
+	t.Cleanup(func() {
+		ts.Close()
+		s.Stop()
+	})
+	return ts.URL
+}
+
+// dialAccessTestServer dials the server with the given API key.
+func dialAccessTestServer(t *testing.T, url, key string) *Client {
+	c, err := DialHTTP(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(c.Close)
+	if key != "" {
+		c.SetHeader(APIKeyHeader, key)
+	}
+	return c
+}
+
+// confirmRateLimited checks that the call failed with the 429 status code and
+// the rate limit JSON-RPC error.
+func confirmRateLimited(t *testing.T, err error) {
+	t.Helper()
+	var httpErr HTTPError
+	if !errors.As(err, &httpErr) {
+		t.Fatalf("expected HTTP error, got %v", err)
+	}
+	confirmStatusCode(t, httpErr.StatusCode, http.StatusTooManyRequests)
+
+	var resp jsonrpcMessage
+	if err := json.Unmarshal(httpErr.Body, &resp); err != nil {
+		t.Fatalf("invalid response body %q: %v", httpErr.Body, err)
+	}
+	if resp.Error == nil || resp.Error.Code != errcodeLimitExceeded {
+		t.Fatalf("wrong error in response %q", httpErr.Body)
+	}
+	var data limitExceededData
+	if enc, _ := json.Marshal(resp.Error.Data); json.Unmarshal(enc, &data) != nil || data.RetryAfter <= 0 {
+		t.Fatalf("missing retry delay in response %q", httpErr.Body)
+	}
+}
+
+func TestHTTPAccessAuthentication(t *testing.T) {
+	url := newAccessTestServer(t, &AccessConfig{
+		APIKeys:     []APIKeyConfig{{Key: "secret"}},
+		KeyRequired: true,
+	})
+	for _, key := range []string{"", "wrong"} {
+		err := dialAccessTestServer(t, url, key).Call(nil, "test_noArgsRets")
+		var httpErr HTTPError
+		if !errors.As(err, &httpErr) {
+			t.Fatalf("key %q: expected HTTP error, got %v", key, err)
+		}
+		confirmStatusCode(t, httpErr.StatusCode, http.StatusUnauthorized)
+	}
+	if err := dialAccessTestServer(t, url, "secret").Call(nil, "test_noArgsRets"); err != nil {
+		t.Fatalf("call with valid key failed: %v", err)
+	}
+}
+
+func TestHTTPAccessNamespaces(t *testing.T) {
+	url := newAccessTestServer(t, &AccessConfig{
+		APIKeys: []APIKeyConfig{{Key: "nf", Namespaces: []string{"nftest"}}},
+	})
+	c := dialAccessTestServer(t, url, "nf")
+
+	var rpcErr Error
+	if err := c.Call(nil, "test_noArgsRets"); !errors.As(err, &rpcErr) || rpcErr.ErrorCode() != -32601 {
+		t.Fatalf("expected method not allowed error, got %v", err)
+	}
+	// The metadata namespace is always allowed
+	var modules map[string]string
+	if err := c.Call(&modules, "rpc_modules"); err != nil {
+		t.Fatalf("metadata call failed: %v", err)
+	}
+	// Clients without API key have access to all namespaces
+	if err := dialAccessTestServer(t, url, "").Call(nil, "test_noArgsRets"); err != nil {
+		t.Fatalf("call without key failed: %v", err)
+	}
+}
+
+func TestHTTPAccessRateLimit(t *testing.T) {
+	url := newAccessTestServer(t, &AccessConfig{
+		APIKeys: []APIKeyConfig{{Key: "limited", Rate: 0.001, Burst: 1}},
+		IPRate:  0.001,
+		IPBurst: 2,
+	})
+	// The clients without key are limited per IP
+	anon := dialAccessTestServer(t, url, "")
+	for i := 0; i < 2; i++ {
+		if err := anon.Call(nil, "test_noArgsRets"); err != nil {
+			t.Fatalf("call %d failed: %v", i, err)
+		}
+	}
+	confirmRateLimited(t, anon.Call(nil, "test_noArgsRets"))
+
+	// The clients with key have their own bucket
+	keyed := dialAccessTestServer(t, url, "limited")
+	if err := keyed.Call(nil, "test_noArgsRets"); err != nil {
+		t.Fatalf("keyed call failed: %v", err)
+	}
+	confirmRateLimited(t, keyed.Call(nil, "test_noArgsRets"))
+}
+
+func TestHTTPAccessMethodCosts(t *testing.T) {
+	url := newAccessTestServer(t, &AccessConfig{
+		IPRate:      0.001,
+		IPBurst:     5,
+		MethodCosts: map[string]int{"test_*": 2, "test_echo": 3, "rpc_modules": 0},
+	})
+	c := dialAccessTestServer(t, url, "")
+
+	// The free methods don't consume the bucket
+	for i := 0; i < 10; i++ {
+		if err := c.Call(nil, "rpc_modules"); err != nil {
+			t.Fatalf("free call %d failed: %v", i, err)
+		}
+	}
+	var res echoResult
+	if err := c.Call(&res, "test_echo", "x", 1, &echoArgs{"y"}); err != nil {
+		t.Fatalf("echo call failed: %v", err)
+	}
+	if err := c.Call(nil, "test_noArgsRets"); err != nil {
+		t.Fatalf("namespace cost call failed: %v", err)
+	}
+	// The bucket is empty, a batch only partially served isn't rejected with 429
+	batch := []BatchElem{
+		{Method: "rpc_modules", Result: new(map[string]string)},
+		{Method: "test_noArgsRets"},
+	}
+	if err := c.BatchCall(batch); err != nil {
+		t.Fatalf("batch call failed: %v", err)
+	}
+	if batch[0].Error != nil {
+		t.Fatalf("free batch call failed: %v", batch[0].Error)
+	}
+	var rpcErr Error
+	if !errors.As(batch[1].Error, &rpcErr) || rpcErr.ErrorCode() != errcodeLimitExceeded {
+		t.Fatalf("expected rate limit error, got %v", batch[1].Error)
+	}
+	confirmRateLimited(t, c.Call(nil, "test_noArgsRets"))
+}
+
+// Tests that the access handler authenticates and charges the requests of the
+// wrapped handler, which can charge further calls to its clients.
+func TestAccessHandler(t *testing.T) {
+	inner := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := CheckAccess(r.Context(), "gql_trace"); err != nil {
+			http.Error(w, err.Error(), http.StatusTooManyRequests)
+		}
+	})
+	handler, err := NewAccessHandler(&AccessConfig{
+		APIKeys:     []APIKeyConfig{{Key: "secret", Rate: 0.001, Burst: 4}, {Key: "nf", Namespaces: []string{"nftest"}}},
+		MethodCosts: map[string]int{"gql_trace": 2},
+	}, "gql_query", inner)
+	if err != nil {
+		t.Fatalf("failed to create access handler: %v", err)
+	}
+	ts := httptest.NewServer(handler)
+	defer ts.Close()
+
+	request := func(key string) *http.Response {
+		req, _ := http.NewRequest(http.MethodPost, ts.URL, nil)
+		req.Header.Set(APIKeyHeader, key)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		return resp
+	}
+	confirmStatusCode(t, request("wrong").StatusCode, http.StatusUnauthorized)
+	confirmStatusCode(t, request("nf").StatusCode, http.StatusForbidden)
+
+	// A request costs a query and a trace, the second one running out of units
+	confirmStatusCode(t, request("secret").StatusCode, http.StatusOK)
+	confirmStatusCode(t, request("secret").StatusCode, http.StatusTooManyRequests)
+	if resp := request("secret"); resp.Header.Get("retry-after") == "" {
+		t.Fatal("missing retry delay in rate limited response")
+	}
+	// Outside of an access handler, nothing is charged
+	if err := CheckAccess(context.Background(), "gql_trace"); err != nil {
+		t.Fatalf("access checked without access handler: %v", err)
+	}
+}
+
+func TestAccessConfigValidation(t *testing.T) {
+	tests := []*AccessConfig{
+		{APIKeys: []APIKeyConfig{{Key: ""}}},
+		{APIKeys: []APIKeyConfig{{Key: "a/b"}}},
+		{APIKeys: []APIKeyConfig{{Key: "a"}, {Key: "a"}}},
+		{APIKeys: []APIKeyConfig{{Key: "a", Rate: -1}}},
+		{KeyRequired: true},
+		{IPRate: -1},
+		{MethodCosts: map[string]int{"eth_getLogs": -1}},
+	}
+	for i, config := range tests {
+		if err := NewServer().SetAccessControl(config); err == nil {
+			t.Errorf("test %d: invalid config accepted", i)
+		}
+	}
+}
@@ -52,6 +52,7 @@ type Server struct {
 	batchItemLimit     int
 	batchResponseLimit int
 	httpBodyLimit      int
+	access             *accessControl
 }
 
 // NewServer creates a new server instance with no registered handlers.
@@ -87,6 +88,20 @@ func (s *Server) SetHTTPBodyLimit(limit int) {
 	s.httpBodyLimit = limit
 }
 
+// SetAccessControl sets the authentication and rate limiting of the clients,
+// applied to the HTTP and WebSocket connections.
+//
+// This method should be called before processing any requests via ServeHTTP or
+// WebsocketHandler.
+func (s *Server) SetAccessControl(config *AccessConfig) error {
+	ac, err := newAccessControl(config)
+	if err != nil {
+		return err
+	}
+	s.access = ac
+	return nil
+}
+
 // RegisterName creates a service for the given receiver type under the given name. When no
 // methods on the given receiver match the criteria to be either a RPC method or a
 // subscription an error is returned. Otherwise a new service is created and added to the
@@ -112,6 +127,7 @@ func (s *Server) ServeCodec(codec ServerCodec, options CodecOption) {
 		idgen:              s.idgen,
 		batchItemLimit:     s.batchItemLimit,
 		batchResponseLimit: s.batchResponseLimit,
+		access:             s.access,
 	}
 	c := initClient(codec, &s.services, cfg)
 	<-codec.closed()
@@ -147,6 +163,7 @@ func (s *Server) serveSingleRequest(ctx context.Context, codec ServerCodec) {
 
 	h := newHandler(ctx, codec, s.idgen, &s.services, s.batchItemLimit, s.batchResponseLimit)
 	h.allowSubscribe = false
+	h.access = s.access
 	defer h.close(io.EOF, nil)
 
 	reqs, batch, err := codec.readBatch()
@@ -238,6 +255,9 @@ type PeerInfo struct {
 		Origin    string
 		Host      string
 	}
+
+	// apiKey is the API key authenticated by the access control, if any.
+	apiKey string
 }
 
 type peerInfoContextKey struct{}
@@ -19,6 +19,7 @@ package rpc
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"io"
 	"net"
 	"os"
@@ -26,6 +27,12 @@ import (
 	"strings"
 	"testing"
 	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 )
 
 func TestServerRegisterName(t *testing.T) {
@@ -192,3 +199,52 @@ func TestServerBatchResponseSizeLimit(t *testing.T) {
 		}
 	}
 }
+
+func TestServerTracing(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	otel.SetTracerProvider(provider)
+	defer provider.Shutdown(context.Background())
+
+	server := newTestServer()
+	defer server.Stop()
+	client := DialInProc(server)
+	defer client.Close()
+
+	var result echoResult
+	if err := client.Call(&result, "test_echo", "x", 1); err != nil {
+		t.Fatal("call failed:", err)
+	}
+	if err := client.Call(nil, "test_returnError"); err == nil {
+		t.Fatal("expected error from test_returnError")
+	}
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("wrong number of spans: have %d, want 2", len(spans))
+	}
+	for i, want := range []struct {
+		name, method string
+		code         codes.Code
+	}{
+		{"test_echo", "echo", codes.Unset},
+		{"test_returnError", "returnError", codes.Error},
+	} {
+		span := spans[i]
+		if span.Name() != want.name {
+			t.Errorf("span %d: wrong name: have %q, want %q", i, span.Name(), want.name)
+		}
+		if span.Status().Code != want.code {
+			t.Errorf("span %d: wrong status: have %v, want %v", i, span.Status().Code, want.code)
+		}
+		attrs := make(map[attribute.Key]attribute.Value)
+		for _, attr := range span.Attributes() {
+			attrs[attr.Key] = attr.Value
+		}
+		if have := attrs["rpc.service"].AsString(); have != "test" {
+			t.Errorf("span %d: wrong service: have %q, want %q", i, have, "test")
+		}
+		if have := attrs["rpc.method"].AsString(); have != want.method {
+			t.Errorf("span %d: wrong method: have %q, want %q", i, have, want.method)
+		}
+	}
+}
@@ -47,12 +47,16 @@ func TestMain(m *testing.M) {
 
 	if *testEVM != "" {
 		log.Printf("Running tests with %s=%s", "evmc.evm", *testEVM)
-		vm.InitEVMCEVM(*testEVM)
+		if err := vm.InitEVMCEVM(*testEVM); err != nil {
+			log.Fatalf("Failed to load the EVMC EVM: %v", err)
+		}
 	}
 
 	if *testEWASM != "" {
 		log.Printf("Running tests with %s=%s", "evmc.ewasm", *testEWASM)
-		vm.InitEVMCEwasm(*testEWASM)
+		if err := vm.InitEVMCEwasm(*testEWASM); err != nil {
+			log.Fatalf("Failed to load the EVMC Ewasm VM: %v", err)
+		}
 	}
 
 	os.Exit(m.Run())
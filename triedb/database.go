@@ -286,6 +286,17 @@ func (db *Database) Recoverable(root common.Hash) (bool, error) {
 	return pdb.Recoverable(root), nil
 }
 
+// HistoricReader returns a read-only reader of the specified state older than
+// the persisted one, resolved from the state histories. It's only supported by
+// path-based database and will return an error for others.
+func (db *Database) HistoricReader(root common.Hash) (*pathdb.HistoricalStateReader, error) {
+	pdb, ok := db.backend.(*pathdb.Database)
+	if !ok || db.config.IsVerkle {
+		return nil, errors.New("not supported")
+	}
+	return pdb.HistoricReader(root, trie.NewMerkleLoader(db))
+}
+
 // Disable deactivates the database and invalidates all available state layers
 // as stale to prevent access to the persistent state, which is in the syncing
 // stage.
@@ -443,6 +443,106 @@ func TestDatabaseRecoverable(t *testing.T) {
 	}
 }
 
+func TestHistoricReader(t *testing.T) {
+	var (
+		tester = newTester(t, 0)
+		index  = tester.bottomIndex()
+		disk   = tester.roots[index]
+		loader = newHashLoader(tester.snapAccounts[disk], tester.snapStorages[disk])
+	)
+	defer tester.release()
+
+	// The states in the layers are not historic
+	for _, root := range []common.Hash{disk, tester.roots[index+1]} {
+		if _, err := tester.db.HistoricReader(root, loader); err == nil {
+			t.Fatalf("state %x: historic reader created", root)
+		}
+	}
+	for i := 0; i < index; i += 7 {
+		root := tester.roots[i]
+		reader, err := tester.db.HistoricReader(root, loader)
+		if err != nil {
+			t.Fatalf("state %d: failed to create historic reader: %v", i, err)
+		}
+		// Check the accounts existing in the historic state or in the disk layer
+		accounts := copyAccounts(tester.snapAccounts[disk])
+		for addrHash, account := range tester.snapAccounts[root] {
+			accounts[addrHash] = account
+		}
+		for addrHash := range accounts {
+			want := tester.snapAccounts[root][addrHash]
+			account, err := reader.Account(tester.preimages[addrHash])
+			if err != nil {
+				t.Fatalf("state %d: failed to read account %x: %v", i, addrHash, err)
+			}
+			var have []byte
+			if account != nil {
+				have = types.SlimAccountRLP(*account)
+			}
+			if !bytes.Equal(have, want) {
+				t.Fatalf("state %d: account %x mismatch: have %x, want %x", i, addrHash, have, want)
+			}
+			if want == nil {
+				continue
+			}
+			// Check the slots of the account in the historic state or in the disk layer
+			slots := make(map[common.Hash]struct{})
+			for hash := range tester.snapStorages[root][addrHash] {
+				slots[hash] = struct{}{}
+			}
+			for hash := range tester.snapStorages[disk][addrHash] {
+				slots[hash] = struct{}{}
+			}
+			for hash := range slots {
+				var want []byte
+				if blob := tester.snapStorages[root][addrHash][hash]; len(blob) > 0 {
+					_, want, _, _ = rlp.Split(blob)
+				}
+				have, err := reader.Storage(tester.preimages[addrHash], hash)
+				if err != nil {
+					t.Fatalf("state %d: failed to read slot %x of %x: %v", i, hash, addrHash, err)
+				}
+				if !bytes.Equal(have, want) {
+					t.Fatalf("state %d: slot %x of %x mismatch: have %x, want %x", i, hash, addrHash, have, want)
+				}
+			}
+		}
+	}
+}
+
+func TestHistoricReaderLookback(t *testing.T) {
+	defer func(old uint64) { maxHistoricLookback = old }(maxHistoricLookback)
+
+	var (
+		tester = newTester(t, 0)
+		index  = tester.bottomIndex()
+		disk   = tester.roots[index]
+		loader = newHashLoader(tester.snapAccounts[disk], tester.snapStorages[disk])
+	)
+	defer tester.release()
+
+	// The states deeper than the lookback below the disk layer are refused
+	maxHistoricLookback = 4
+	if _, err := tester.db.HistoricReader(tester.roots[index-6], loader); !errors.Is(err, errHistoryTooDeep) {
+		t.Fatalf("deep state: error mismatch: have %v, want %v", err, errHistoryTooDeep)
+	}
+	reader, err := tester.db.HistoricReader(tester.roots[index-4], loader)
+	if err != nil {
+		t.Fatalf("failed to create historic reader: %v", err)
+	}
+	if _, err := reader.Account(common.Address{}); err != nil {
+		t.Fatalf("failed to read account: %v", err)
+	}
+	// A reader falling behind the lookback, as the disk layer moves, is refused
+	maxHistoricLookback = 2
+	if _, err := reader.Account(common.Address{}); !errors.Is(err, errHistoryTooDeep) {
+		t.Fatalf("account: error mismatch: have %v, want %v", err, errHistoryTooDeep)
+	}
+	if _, err := reader.Storage(common.Address{}, common.Hash{}); !errors.Is(err, errHistoryTooDeep) {
+		t.Fatalf("storage: error mismatch: have %v, want %v", err, errHistoryTooDeep)
+	}
+}
+
 func TestDisable(t *testing.T) {
 	tester := newTester(t, 0)
 	defer tester.release()
@@ -0,0 +1,289 @@
+// Copyright 2025 The core-geth Authors
+// This file is part of the core-geth library.
+//
+// The core-geth library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The core-geth library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the core-geth library. If not, see <http://www.gnu.org/licenses/>.
+
+package pathdb
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie/triestate"
+)
+
+var (
+	// errIncompleteHistory is returned if a storage slot is resolved from a state
+	// history missing some storage changes of the account, due to large contract
+	// destruction.
+	errIncompleteHistory = errors.New("incomplete state history")
+
+	// errHistoryTooDeep is returned if a historic state is further below the disk
+	// layer than maxHistoricLookback states.
+	errHistoryTooDeep = errors.New("historic state too deep")
+)
+
+// maxHistoricLookback is the maximum number of state histories walked back from
+// the disk layer to resolve a historic state. The state histories aren't indexed
+// by account, every access to a historic state scans them one by one.
+var maxHistoricLookback uint64 = 8192
+
+// HistoricalStateReader is a read-only reader of a state below the disk layer,
+// within the retained state histories.
+//
+// The state histories hold the values of the states before their modifications,
+// so the state histories are applied backwards from the disk layer: an account
+// or storage slot is resolved from the first state history after the historic
+// state modifying it, or from the disk layer if it's not modified since then.
+type HistoricalStateReader struct {
+	db     *Database
+	loader triestate.TrieLoader // Loader of the disk layer tries
+	root   common.Hash          // Root of the historic state
+	id     uint64               // State id of the historic state
+}
+
+// HistoricReader returns a reader of the given state below the disk layer,
+// resolved from the state histories. The tries of the disk layer are opened
+// with the given loader.
+func (db *Database) HistoricReader(root common.Hash, loader triestate.TrieLoader) (*HistoricalStateReader, error) {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.waitSync {
+		return nil, errDatabaseWaitSync
+	}
+	if db.freezer == nil {
+		return nil, errors.New("state histories are not available")
+	}
+	// Ensure the requested state is a known state below the disk layer, the
+	// states above being available in the layers.
+	root = types.TrieRootHash(root)
+	id := rawdb.ReadStateID(db.diskdb, root)
+	if id == nil {
+		return nil, fmt.Errorf("state %#x is not available", root)
+	}
+	if *id >= db.tree.bottom().stateID() {
+		return nil, fmt.Errorf("state %#x is not historic", root)
+	}
+	if depth := db.tree.bottom().stateID() - *id; depth > maxHistoricLookback {
+		return nil, fmt.Errorf("%w: state %#x, depth %d, limit %d", errHistoryTooDeep, root, depth, maxHistoricLookback)
+	}
+	// Ensure the state history following the state is retained
+	blob := rawdb.ReadStateHistoryMeta(db.freezer, *id+1)
+	if len(blob) == 0 {
+		return nil, fmt.Errorf("state history of %#x is pruned", root)
+	}
+	var m meta
+	if err := m.decode(blob); err != nil {
+		return nil, err
+	}
+	if m.parent != root {
+		return nil, errUnexpectedHistory
+	}
+	return &HistoricalStateReader{db: db, loader: loader, root: root, id: *id}, nil
+}
+
+// Root returns the root of the historic state.
+func (r *HistoricalStateReader) Root() common.Hash {
+	return r.root
+}
+
+// Account returns the account at the historic state, nil if it doesn't exist.
+func (r *HistoricalStateReader) Account(address common.Address) (*types.StateAccount, error) {
+	for {
+		dl := r.db.tree.bottom()
+		account, err := r.account(dl, address)
+
+		// The disk layer may have been merged with the next state meanwhile, in
+		// which case the state history of the merged state was missed, retry
+		if err != nil && r.db.tree.bottom() != dl {
+			continue
+		}
+		return account, err
+	}
+}
+
+// Storage returns the value of the storage slot with the given hash at the
+// historic state, nil if it doesn't exist.
+func (r *HistoricalStateReader) Storage(address common.Address, slot common.Hash) ([]byte, error) {
+	for {
+		dl := r.db.tree.bottom()
+		value, err := r.storage(dl, address, slot)
+		if err != nil && r.db.tree.bottom() != dl {
+			continue
+		}
+		if err != nil || len(value) == 0 {
+			return nil, err
+		}
+		_, content, _, err := rlp.Split(value)
+		return content, err
+	}
+}
+
+// account resolves the account from the state histories up to the given disk
+// layer, or from the disk layer.
+func (r *HistoricalStateReader) account(dl *diskLayer, address common.Address) (*types.StateAccount, error) {
+	if err := r.checkDepth(dl); err != nil {
+		return nil, err
+	}
+	for id := r.id + 1; id <= dl.stateID(); id++ {
+		index, found, err := r.lookupAccount(id, address)
+		if err != nil {
+			return nil, err
+		}
+		if !found {
+			continue
+		}
+		blob, err := r.accountData(id, index)
+		if err != nil || len(blob) == 0 {
+			return nil, err
+		}
+		return types.FullAccount(blob)
+	}
+	return r.diskAccount(dl, crypto.Keccak256Hash(address.Bytes()))
+}
+
+// storage resolves the RLP-encoded storage slot from the state histories up to
+// the given disk layer, or from the disk layer.
+func (r *HistoricalStateReader) storage(dl *diskLayer, address common.Address, slot common.Hash) ([]byte, error) {
+	if err := r.checkDepth(dl); err != nil {
+		return nil, err
+	}
+	for id := r.id + 1; id <= dl.stateID(); id++ {
+		value, found, err := r.historyStorage(id, address, slot)
+		if err != nil {
+			return nil, err
+		}
+		if found {
+			return value, nil
+		}
+	}
+	addrHash := crypto.Keccak256Hash(address.Bytes())
+	account, err := r.diskAccount(dl, addrHash)
+	if err != nil || account == nil || account.Root == types.EmptyRootHash {
+		return nil, err
+	}
+	tr, err := r.loader.OpenStorageTrie(dl.rootHash(), addrHash, account.Root)
+	if err != nil {
+		return nil, err
+	}
+	return tr.Get(slot.Bytes())
+}
+
+// checkDepth ensures the historic state is still within maxHistoricLookback of
+// the given disk layer, which moves up as the chain progresses.
+func (r *HistoricalStateReader) checkDepth(dl *diskLayer) error {
+	if depth := dl.stateID() - r.id; depth > maxHistoricLookback {
+		return fmt.Errorf("%w: state %#x, depth %d, limit %d", errHistoryTooDeep, r.root, depth, maxHistoricLookback)
+	}
+	return nil
+}
+
+// diskAccount retrieves the account from the account trie of the disk layer.
+func (r *HistoricalStateReader) diskAccount(dl *diskLayer, addrHash common.Hash) (*types.StateAccount, error) {
+	tr, err := r.loader.OpenTrie(dl.rootHash())
+	if err != nil {
+		return nil, err
+	}
+	blob, err := tr.Get(addrHash.Bytes())
+	if err != nil || len(blob) == 0 {
+		return nil, err
+	}
+	return types.FullAccount(blob)
+}
+
+// lookupAccount searches the account in the sorted account indexes of the
+// state history with the given id.
+func (r *HistoricalStateReader) lookupAccount(id uint64, address common.Address) (accountIndex, bool, error) {
+	indexes := rawdb.ReadStateAccountIndex(r.db.freezer, id)
+	if len(indexes) == 0 {
+		return accountIndex{}, false, fmt.Errorf("state history not found %d", id)
+	}
+	if len(indexes)%accountIndexSize != 0 {
+		return accountIndex{}, false, fmt.Errorf("invalid account index, len: %d", len(indexes))
+	}
+	n := len(indexes) / accountIndexSize
+	pos := sort.Search(n, func(i int) bool {
+		return bytes.Compare(indexes[i*accountIndexSize:i*accountIndexSize+common.AddressLength], address.Bytes()) >= 0
+	})
+	if pos == n || !bytes.Equal(indexes[pos*accountIndexSize:pos*accountIndexSize+common.AddressLength], address.Bytes()) {
+		return accountIndex{}, false, nil
+	}
+	var index accountIndex
+	index.decode(indexes[pos*accountIndexSize : (pos+1)*accountIndexSize])
+	return index, true, nil
+}
+
+// accountData retrieves the account data located by the index from the state
+// history with the given id.
+func (r *HistoricalStateReader) accountData(id uint64, index accountIndex) ([]byte, error) {
+	data := rawdb.ReadStateAccountHistory(r.db.freezer, id)
+	last := index.offset + uint32(index.length)
+	if uint32(len(data)) < last {
+		return nil, errors.New("account data buffer is corrupted")
+	}
+	return data[index.offset:last], nil
+}
+
+// historyStorage searches the storage slot in the state history with the given
+// id, returning its value before the state transition if it was modified.
+func (r *HistoricalStateReader) historyStorage(id uint64, address common.Address, slot common.Hash) ([]byte, bool, error) {
+	index, found, err := r.lookupAccount(id, address)
+	if err != nil || !found {
+		return nil, false, err
+	}
+	// The storage changes of the destructed large contracts are not recorded
+	var m meta
+	if err := m.decode(rawdb.ReadStateHistoryMeta(r.db.freezer, id)); err != nil {
+		return nil, false, err
+	}
+	for _, incomplete := range m.incomplete {
+		if incomplete == address {
+			return nil, false, fmt.Errorf("%w: %d, account: %x", errIncompleteHistory, id, address)
+		}
+	}
+	if index.storageSlots == 0 {
+		return nil, false, nil
+	}
+	indexes := rawdb.ReadStateStorageIndex(r.db.freezer, id)
+	if uint32(len(indexes)) < (index.storageOffset+index.storageSlots)*uint32(slotIndexSize) {
+		return nil, false, errors.New("storage index buffer is corrupted")
+	}
+	entry := func(i int) []byte {
+		start := (int(index.storageOffset) + i) * slotIndexSize
+		return indexes[start : start+slotIndexSize]
+	}
+	pos := sort.Search(int(index.storageSlots), func(i int) bool {
+		return bytes.Compare(entry(i)[:common.HashLength], slot.Bytes()) >= 0
+	})
+	if pos == int(index.storageSlots) || !bytes.Equal(entry(pos)[:common.HashLength], slot.Bytes()) {
+		return nil, false, nil
+	}
+	var sIndex slotIndex
+	sIndex.decode(entry(pos))
+
+	data := rawdb.ReadStateStorageHistory(r.db.freezer, id)
+	last := sIndex.offset + uint32(sIndex.length)
+	if uint32(len(data)) < last {
+		return nil, false, errors.New("storage data buffer is corrupted")
+	}
+	return data[sIndex.offset:last], true, nil
+}